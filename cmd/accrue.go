@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"hr-system/internal/auth"
+	balance_domain "hr-system/internal/balances/domain"
+	balance_repo "hr-system/internal/balances/repo"
+	balance_service "hr-system/internal/balances/service"
+	"hr-system/internal/common"
+	employee_repo "hr-system/internal/employees/repo"
+	leavetype_repo "hr-system/internal/leavetypes/repo"
+)
+
+// runAccrue runs the accrual engine for a period, e.g. `accrue -period 2024-05`, the previous month by default.
+// Running a period again only writes the entries missing, so it's safe to rerun a failed or partial period.
+func runAccrue(ctx context.Context, logger *common.Logger, db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("accrue", flag.ContinueOnError)
+	period := flags.String("period", balance_domain.PeriodOf(time.Now()).Previous().String(),
+		"the month to run the accrual for, in the format of YYYY-MM")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	p, err := balance_domain.ParsePeriod(*period)
+	if err != nil {
+		return err
+	}
+
+	employeeRepo, err := employee_repo.NewEmployeeRepo(db)
+	if err != nil {
+		return fmt.Errorf("failed to New employeeRepo: %w", err)
+	}
+	balanceRepo, err := balance_repo.NewBalanceRepo(db)
+	if err != nil {
+		return fmt.Errorf("failed to New balanceRepo: %w", err)
+	}
+	if err = balanceRepo.SeedData(ctx); err != nil {
+		return fmt.Errorf("failed to seed data: %w", err)
+	}
+	leaveTypeRepo, err := leavetype_repo.NewTypeRepo(db)
+	if err != nil {
+		return fmt.Errorf("failed to New leaveTypeRepo: %w", err)
+	}
+	if err = leaveTypeRepo.SeedData(ctx); err != nil {
+		return fmt.Errorf("failed to seed data: %w", err)
+	}
+
+	accrualService := balance_service.NewAccrualService(logger, balanceRepo, employeeRepo, leaveTypeRepo)
+	// the run logs what it has written
+	_, err = accrualService.RunPeriod(auth.AsSystem(ctx), p)
+	return err
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -14,21 +17,59 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"hr-system/config"
+	approval_handler "hr-system/internal/approvals/handler"
+	approval_repo "hr-system/internal/approvals/repo"
+	approval_service "hr-system/internal/approvals/service"
+	"hr-system/internal/attachments/blob"
+	attachment_handler "hr-system/internal/attachments/handler"
+	attachment_repo "hr-system/internal/attachments/repo"
+	attachment_service "hr-system/internal/attachments/service"
+	"hr-system/internal/auth"
+	auth_handler "hr-system/internal/auth/handler"
+	balance_domain "hr-system/internal/balances/domain"
+	balance_handler "hr-system/internal/balances/handler"
+	balance_repo "hr-system/internal/balances/repo"
+	balance_service "hr-system/internal/balances/service"
 	"hr-system/internal/cache"
 	"hr-system/internal/common"
+	"hr-system/internal/common/cursor"
+	"hr-system/internal/common/transaction"
+	coverage_handler "hr-system/internal/coverage/handler"
+	coverage_repo "hr-system/internal/coverage/repo"
+	coverage_service "hr-system/internal/coverage/service"
+	delegation_handler "hr-system/internal/delegations/handler"
+	delegation_repo "hr-system/internal/delegations/repo"
+	delegation_service "hr-system/internal/delegations/service"
 	employee_cache "hr-system/internal/employees/cache"
 	employee_handler "hr-system/internal/employees/handler"
 	employee_repo "hr-system/internal/employees/repo"
 	employee_service "hr-system/internal/employees/service"
+	feed_handler "hr-system/internal/feeds/handler"
+	feed_repo "hr-system/internal/feeds/repo"
+	feed_service "hr-system/internal/feeds/service"
+	holiday_handler "hr-system/internal/holidays/handler"
+	holiday_repo "hr-system/internal/holidays/repo"
+	holiday_service "hr-system/internal/holidays/service"
 	leave_cache "hr-system/internal/leaves/cache"
 	leave_handler "hr-system/internal/leaves/handler"
 	leave_repo "hr-system/internal/leaves/repo"
 	leave_service "hr-system/internal/leaves/service"
+	leavetype_handler "hr-system/internal/leavetypes/handler"
+	leavetype_repo "hr-system/internal/leavetypes/repo"
+	leavetype_service "hr-system/internal/leavetypes/service"
 	"hr-system/internal/middleware"
+	offboarding_handler "hr-system/internal/offboarding/handler"
+	offboarding_repo "hr-system/internal/offboarding/repo"
+	offboarding_service "hr-system/internal/offboarding/service"
+	"hr-system/internal/scheduler"
+	toil_handler "hr-system/internal/toil/handler"
+	toil_repo "hr-system/internal/toil/repo"
+	toil_service "hr-system/internal/toil/service"
 )
 
 var cachePrefixEmployee = "employee"
 var cachePrefixLeave = "leave"
+var cachePrefixScheduler = "scheduler"
 
 func main() {
 	cfg, err := config.LoadConfig()
@@ -46,19 +87,40 @@ func main() {
 		log.Fatalf("Failed to connect to MySQL after %d attempts: %v", maxRetries, err)
 	}
 
+	ctx := context.Background()
+	// the subcommands run against the database and exit instead of serving the API
+	if len(os.Args) > 1 && os.Args[1] == "accrue" {
+		if err := runAccrue(ctx, logger, db, os.Args[2:]); err != nil {
+			logger.Fatalf("Failed to run accrual, cause: %v", err)
+		}
+		return
+	}
+
 	rdb := redis.NewClient(&redis.Options{
 		Addr: fmt.Sprintf("%s:%s", cfg.RedisHost, cfg.RedisPort),
 	})
-	ctx := context.Background()
 	_, err = rdb.Ping(ctx).Result()
 	if err != nil {
 		log.Fatalf("Failed to connect to Redis: %v", err)
 	}
 	commonCache := cache.NewCache(rdb)
 
-	r := gin.Default()
+	// the default logger would write the feed tokens in the paths to the logs
+	r := gin.New()
+	r.Use(middleware.Logger(), gin.Recovery())
 	r.Use(middleware.ContextMiddleware())
 
+	verifier, err := newTokenVerifier(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to New token verifier, cause: %v", err)
+	}
+	api := r.Group("api/v1", middleware.AuthMiddleware(verifier))
+
+	cursors, err := newCursorCodec(logger, cfg)
+	if err != nil {
+		logger.Fatalf("Failed to New cursor codec, cause: %v", err)
+	}
+
 	// API for employees
 	employeeRepo, err := employee_repo.NewEmployeeRepo(db)
 	if err != nil {
@@ -70,10 +132,119 @@ func main() {
 
 	employeeService := employee_service.NewEmployeeService(logger, employeeRepo,
 		employee_cache.NewEmployeeCache(commonCache, cachePrefixEmployee))
-	employeeHandler := employee_handler.NewEmployeeHandler(logger, employeeService)
-	r.POST("api/v1/employees", employeeHandler.CreateEmployee)
-	r.GET("api/v1/employees/:id", employeeHandler.GetEmployeeByID)
-	r.GET("api/v1/employees", employeeHandler.GetEmployees)
+	employeeHandler := employee_handler.NewEmployeeHandler(logger, employeeService, cursors)
+	api.POST("employees", employeeHandler.CreateEmployee)
+	api.GET("employees/:id", employeeHandler.GetEmployeeByID)
+	api.GET("employees", employeeHandler.GetEmployees)
+	api.PUT("employees/:id", employeeHandler.UpdateEmployee)
+	api.PATCH("employees/:id", employeeHandler.PatchEmployee)
+	api.POST("employees/:id/positions", employeeHandler.ChangePosition)
+	api.GET("employees/:id/reports", employeeHandler.GetReports)
+	api.GET("employees/:id/chain", employeeHandler.GetChain)
+
+	// API for development tokens
+	if cfg.DevTokenEnabled {
+		logger.Warnf("Development token endpoint is enabled, do not enable it in production")
+		authHandler := auth_handler.NewAuthHandler(logger, auth.NewIssuer([]byte(cfg.JWTSecret), 24*time.Hour),
+			employeeService)
+		r.POST("api/v1/dev/tokens", authHandler.IssueDevToken)
+	}
+
+	// API for holidays
+	holidayRepo, err := holiday_repo.NewHolidayRepo(db)
+	if err != nil {
+		logger.Fatalf("Failed to New holidayRepo, cause: %v", err)
+	}
+	holidayService := holiday_service.NewHolidayService(logger, holidayRepo)
+	holidayHandler := holiday_handler.NewHolidayHandler(logger, holidayService)
+	api.POST("holidays", holidayHandler.CreateHoliday)
+	api.GET("holidays", holidayHandler.GetHolidays)
+	api.GET("holidays/:id", holidayHandler.GetHolidayByID)
+	api.PUT("holidays/:id", holidayHandler.UpdateHoliday)
+	api.DELETE("holidays/:id", holidayHandler.DeleteHoliday)
+	api.GET("working-weeks/:region", holidayHandler.GetWorkingWeek)
+	api.PUT("working-weeks/:region", holidayHandler.SetWorkingWeek)
+
+	// API for leave balances
+	balanceRepo, err := balance_repo.NewBalanceRepo(db)
+	if err != nil {
+		logger.Fatalf("Failed to New balanceRepo, cause: %v", err)
+	}
+	if err = balanceRepo.SeedData(ctx); err != nil {
+		logger.Fatalf("Failed to seed data, cause: %v", err)
+	}
+	balanceService := balance_service.NewBalanceService(logger, balanceRepo, employeeRepo, holidayService)
+	balanceHandler := balance_handler.NewBalanceHandler(logger, balanceService)
+	api.GET("employees/:id/leave-balances", balanceHandler.GetLeaveBalances)
+
+	// API for leave types
+	leaveTypeRepo, err := leavetype_repo.NewTypeRepo(db)
+	if err != nil {
+		logger.Fatalf("Failed to New leaveTypeRepo, cause: %v", err)
+	}
+	if err = leaveTypeRepo.SeedData(ctx); err != nil {
+		logger.Fatalf("Failed to seed data, cause: %v", err)
+	}
+	leaveTypeService := leavetype_service.NewTypeService(logger, leaveTypeRepo)
+	leaveTypeHandler := leavetype_handler.NewTypeHandler(logger, leaveTypeService)
+	api.POST("leave-types", leaveTypeHandler.CreateType)
+	api.GET("leave-types", leaveTypeHandler.GetTypes)
+	api.GET("leave-types/:code", leaveTypeHandler.GetTypeByCode)
+	api.PUT("leave-types/:code", leaveTypeHandler.UpdateType)
+
+	// API for the accrual rules of leave balances, the accrual engine runs as a background job
+	accrualService := balance_service.NewAccrualService(logger, balanceRepo, employeeRepo, leaveTypeRepo)
+	accrualHandler := balance_handler.NewAccrualHandler(logger, accrualService)
+	api.GET("accrual-rules", accrualHandler.GetRules)
+	api.PUT("accrual-rules", accrualHandler.SetRule)
+	api.DELETE("accrual-rules", accrualHandler.DeleteRule)
+
+	// API for the yearly entitlements of the leave types which do not accrue
+	entitlementService := balance_service.NewEntitlementService(logger, balanceRepo, leaveTypeRepo)
+	entitlementHandler := balance_handler.NewEntitlementHandler(logger, entitlementService)
+	api.GET("entitlements", entitlementHandler.GetEntitlements)
+	api.PUT("entitlements", entitlementHandler.SetEntitlement)
+
+	// API for approval policies
+	policyRepo, err := approval_repo.NewPolicyRepo(db)
+	if err != nil {
+		logger.Fatalf("Failed to New policyRepo, cause: %v", err)
+	}
+	if err = policyRepo.SeedData(ctx); err != nil {
+		logger.Fatalf("Failed to seed data, cause: %v", err)
+	}
+	policyService := approval_service.NewPolicyService(logger, policyRepo, leaveTypeRepo)
+	policyHandler := approval_handler.NewPolicyHandler(logger, policyService)
+	api.POST("approval-policies", policyHandler.CreatePolicy)
+	api.GET("approval-policies", policyHandler.GetPolicies)
+	api.GET("approval-policies/:id", policyHandler.GetPolicyByID)
+	api.PUT("approval-policies/:id", policyHandler.UpdatePolicy)
+	api.DELETE("approval-policies/:id", policyHandler.DeletePolicy)
+
+	// API for delegations
+	delegationRepo, err := delegation_repo.NewDelegationRepo(db)
+	if err != nil {
+		logger.Fatalf("Failed to New delegationRepo, cause: %v", err)
+	}
+	delegationService := delegation_service.NewDelegationService(logger, delegationRepo, employeeRepo)
+	delegationHandler := delegation_handler.NewDelegationHandler(logger, delegationService)
+	api.POST("delegations", delegationHandler.CreateDelegation)
+	api.GET("delegations", delegationHandler.GetDelegations)
+	api.GET("delegations/:id", delegationHandler.GetDelegationByID)
+	api.DELETE("delegations/:id", delegationHandler.DeleteDelegation)
+
+	// API for coverage rules
+	ruleRepo, err := coverage_repo.NewRuleRepo(db)
+	if err != nil {
+		logger.Fatalf("Failed to New ruleRepo, cause: %v", err)
+	}
+	ruleService := coverage_service.NewRuleService(logger, ruleRepo, employeeRepo)
+	ruleHandler := coverage_handler.NewRuleHandler(logger, ruleService)
+	api.POST("coverage-rules", ruleHandler.CreateRule)
+	api.GET("coverage-rules", ruleHandler.GetRules)
+	api.GET("coverage-rules/:id", ruleHandler.GetRuleByID)
+	api.PUT("coverage-rules/:id", ruleHandler.UpdateRule)
+	api.DELETE("coverage-rules/:id", ruleHandler.DeleteRule)
 
 	// API for leaves
 	leaveRepo, err := leave_repo.NewLeaveRepo(db)
@@ -83,18 +254,146 @@ func main() {
 	if err = leaveRepo.SeedData(ctx, employeeRepo); err != nil {
 		logger.Fatalf("Failed to seed data, cause: %v", err)
 	}
+	attachmentRepo, err := attachment_repo.NewAttachmentRepo(db)
+	if err != nil {
+		logger.Fatalf("Failed to New attachmentRepo, cause: %v", err)
+	}
+	blobStore, err := blob.NewLocalStore(cfg.AttachmentDir)
+	if err != nil {
+		logger.Fatalf("Failed to New blob store, cause: %v", err)
+	}
+	attachmentService := attachment_service.NewAttachmentService(logger, attachmentRepo, blobStore, leaveRepo)
+	toilRepo, err := toil_repo.NewTOILRepo(db)
+	if err != nil {
+		logger.Fatalf("Failed to New toilRepo, cause: %v", err)
+	}
+	toilService := toil_service.NewTOILService(logger, toilRepo, employeeRepo, leaveRepo, delegationService,
+		cfg.CompOffValidity)
 	leaveService := leave_service.NewLeaveService(logger, leaveRepo, employeeRepo,
-		leave_cache.NewLeaveCache(commonCache, cachePrefixLeave))
-	leaveHandler := leave_handler.NewLeaveHandler(logger, leaveService)
-	// TODO: add API for revoking leave
-	r.POST("api/v1/leaves", leaveHandler.CreateLeave)
-	r.POST("api/v1/leaves/:id/review", leaveHandler.ReviewLeave)
-	r.GET("api/v1/leaves", leaveHandler.GetLeaves)
-	r.GET("api/v1/leaves/:id", leaveHandler.GetLeaveByID)
+		leave_cache.NewLeaveCache(commonCache, cachePrefixLeave), balanceService, holidayService,
+		policyService, delegationService, ruleService, leaveTypeService, attachmentService, toilService,
+		transaction.NewTransactor(db))
+	// the leaves created before their working days were counted have no days
+	if backfilled, err := leaveService.BackfillDays(auth.AsSystem(ctx)); err != nil {
+		logger.Fatalf("Failed to backfill the days of leaves, cause: %v", err)
+	} else if backfilled > 0 {
+		logger.Infof("backfilled the days of %d leaves", backfilled)
+	}
+	leaveHandler := leave_handler.NewLeaveHandler(logger, leaveService, cursors)
+	api.POST("leaves", leaveHandler.CreateLeave)
+	api.POST("leaves/:id/review", leaveHandler.ReviewLeave)
+	api.POST("leaves/:id/cancel", leaveHandler.CancelLeave)
+	api.POST("leaves/:id/revoke", leaveHandler.RevokeLeave)
+	api.GET("leaves", leaveHandler.GetLeaves)
+	api.GET("leaves/:id", leaveHandler.GetLeaveByID)
+	api.GET("teams/:manager_id/calendar", leaveHandler.GetTeamCalendar)
+	api.POST("approval-policies/dry-run", leaveHandler.DryRunApprovalChain)
+
+	// API for the attachments of leaves
+	attachmentHandler := attachment_handler.NewAttachmentHandler(logger, attachmentService)
+	api.POST("attachments", attachmentHandler.UploadPending)
+	api.POST("leaves/:id/attachments", attachmentHandler.UploadToLeave)
+	api.GET("leaves/:id/attachments", attachmentHandler.GetAttachments)
+	api.GET("leaves/:id/attachments/:attachment_id", attachmentHandler.Download)
+	api.GET("approval-policies/unsatisfiable-chains", leaveHandler.GetUnsatisfiableChains)
+
+	// API for the overtime worked for comp-off
+	toilHandler := toil_handler.NewTOILHandler(logger, toilService)
+	api.POST("toil-requests", toilHandler.CreateRequest)
+	api.GET("toil-requests", toilHandler.GetRequests)
+	api.GET("toil-requests/:id", toilHandler.GetRequestByID)
+	api.POST("toil-requests/:id/review", toilHandler.ReviewRequest)
+	api.POST("toil-requests/:id/cancel", toilHandler.CancelRequest)
+	api.GET("employees/:id/comp-off", toilHandler.GetAllowance)
+
+	// API for calendar feeds, the feeds are read by calendar clients with the token in the path instead of a JWT
+	feedRepo, err := feed_repo.NewFeedRepo(db)
+	if err != nil {
+		logger.Fatalf("Failed to New feedRepo, cause: %v", err)
+	}
+	feedService := feed_service.NewFeedService(logger, feedRepo, leaveRepo, employeeRepo)
+	feedHandler := feed_handler.NewFeedHandler(logger, feedService)
+	api.POST("employees/:id/feed-token", feedHandler.IssueToken)
+	api.DELETE("employees/:id/feed-token", feedHandler.RevokeToken)
+	r.GET("api/v1/feeds/:token/leaves.ics", feedHandler.GetEmployeeFeed)
+	r.GET("api/v1/feeds/:token/teams/:manager_id", feedHandler.GetTeamFeed)
+
+	// API for offboarding
+	offboardingRepo, err := offboarding_repo.NewOffboardingRepo(db)
+	if err != nil {
+		logger.Fatalf("Failed to New offboardingRepo, cause: %v", err)
+	}
+	offboardingService := offboarding_service.NewOffboardingService(logger, offboardingRepo, employeeService,
+		leaveService, toilService)
+	offboardingHandler := offboarding_handler.NewOffboardingHandler(logger, offboardingService)
+	api.POST("employees/:id/offboarding", offboardingHandler.Offboard)
+
+	// background jobs
+	jobScheduler := scheduler.NewScheduler(logger, commonCache, cachePrefixScheduler)
+	jobScheduler.Every("escalate_stale_reviews", cfg.EscalationInterval, func(ctx context.Context) error {
+		escalated, err := leaveService.EscalateStaleReviews(ctx, cfg.EscalationSLA)
+		if err != nil {
+			return err
+		}
+		escalatedTOIL, err := toilService.EscalateStaleReviews(ctx, cfg.EscalationSLA)
+		if err != nil {
+			return err
+		}
+		logger.Infof("escalated %d stale leave reviews and %d stale TOIL reviews", escalated, escalatedTOIL)
+		return nil
+	})
+	jobScheduler.Every("accrue_leaves", cfg.AccrualInterval, func(ctx context.Context) error {
+		_, err := accrualService.RunPeriod(ctx, balance_domain.PeriodOf(time.Now()).Previous())
+		return err
+	})
+	jobScheduler.Every("sweep_pending_attachments", time.Hour, func(ctx context.Context) error {
+		swept, err := attachmentService.SweepPending(ctx, cfg.PendingAttachmentTTL)
+		if err != nil {
+			return err
+		}
+		logger.Infof("swept %d pending attachments", swept)
+		return nil
+	})
+	jobScheduler.Every("hand_over_offboardings", time.Hour, func(ctx context.Context) error {
+		handedOver, err := offboardingService.HandOverDue(ctx)
+		if err != nil {
+			return err
+		}
+		logger.Infof("handed over %d offboarded employees", handedOver)
+		return nil
+	})
+	go jobScheduler.Start(ctx)
 
 	logger.Fatalf(r.Run(fmt.Sprintf(":%s", cfg.RestServerPort)).Error())
 }
 
+func newTokenVerifier(cfg config.Config) (*auth.Verifier, error) {
+	var publicKey *rsa.PublicKey
+	if cfg.JWTPublicKeyFile != "" {
+		pem, err := os.ReadFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key: %w", err)
+		}
+		publicKey, err = auth.ParseRSAPublicKey(pem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+	}
+	return auth.NewVerifier([]byte(cfg.JWTSecret), publicKey)
+}
+
+func newCursorCodec(logger *common.Logger, cfg config.Config) (*cursor.Codec, error) {
+	if cfg.CursorSecret != "" {
+		return cursor.NewCodec([]byte(cfg.CursorSecret)), nil
+	}
+	logger.Warnf("CURSOR_SECRET is not set, the pagination cursors will not work across instances or restarts")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate cursor secret: %w", err)
+	}
+	return cursor.NewCodec(secret), nil
+}
+
 func connectMySqlWithRetry(logger *common.Logger, dsn string, maxRetries int, retryDelay time.Duration) (*gorm.DB, error) {
 	var db *gorm.DB
 	var err error
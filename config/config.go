@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 )
 
 type Config struct {
@@ -16,6 +17,42 @@ type Config struct {
 
 	RedisHost string `env:"REDIS_HOST"`
 	RedisPort string `env:"REDIS_PORT"`
+
+	// EscalationSLA is how long a leave review can be pending before it's escalated to the reviewer's manager
+	EscalationSLA      time.Duration `env:"ESCALATION_SLA"`
+	EscalationInterval time.Duration `env:"ESCALATION_INTERVAL"`
+	// AccrualInterval is how often the accrual engine runs for the previous month, the runs after the first are no-ops
+	AccrualInterval time.Duration `env:"ACCRUAL_INTERVAL"`
+
+	// access tokens are signed with either of them
+	JWTSecret        string `env:"JWT_HS256_SECRET"`
+	JWTPublicKeyFile string `env:"JWT_RS256_PUBLIC_KEY_FILE"`
+	DevTokenEnabled  bool   `env:"AUTH_DEV_TOKEN_ENABLED"` // exposes the endpoint issuing tokens of any employee
+
+	// CursorSecret signs the pagination cursors, a random one is used if it's not set,
+	// the cursors are then only valid on the instance until it restarts
+	CursorSecret string `env:"CURSOR_SECRET"`
+
+	// CompOffValidity is how long the approved overtime can be taken as comp-off leaves
+	CompOffValidity time.Duration `env:"COMP_OFF_VALIDITY"`
+
+	// AttachmentDir is the directory the attachments of the leaves are stored in
+	AttachmentDir string `env:"ATTACHMENT_DIR"`
+	// PendingAttachmentTTL is how long an attachment uploaded for a new leave waits to be submitted before it's deleted
+	PendingAttachmentTTL time.Duration `env:"PENDING_ATTACHMENT_TTL"`
+}
+
+// getDurationEnv parses the duration of the environment variable, it returns the default value if it's not set
+func getDurationEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s environment variable is not set properly", key)
+	}
+	return d, nil
 }
 
 func LoadConfig() (Config, error) {
@@ -42,6 +79,45 @@ func LoadConfig() (Config, error) {
 		return Config{}, fmt.Errorf("redis environment variables are not set properly")
 	}
 
+	jwtSecret := os.Getenv("JWT_HS256_SECRET")
+	jwtPublicKeyFile := os.Getenv("JWT_RS256_PUBLIC_KEY_FILE")
+	devTokenEnabled := os.Getenv("AUTH_DEV_TOKEN_ENABLED") == "true"
+
+	if jwtSecret == "" && jwtPublicKeyFile == "" {
+		return Config{}, fmt.Errorf("JWT environment variables are not set properly")
+	}
+	if devTokenEnabled && jwtSecret == "" {
+		return Config{}, fmt.Errorf("AUTH_DEV_TOKEN_ENABLED requires JWT_HS256_SECRET")
+	}
+
+	escalationSLA, err := getDurationEnv("ESCALATION_SLA", 72*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	escalationInterval, err := getDurationEnv("ESCALATION_INTERVAL", 10*time.Minute)
+	if err != nil {
+		return Config{}, err
+	}
+
+	accrualInterval, err := getDurationEnv("ACCRUAL_INTERVAL", 24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+
+	compOffValidity, err := getDurationEnv("COMP_OFF_VALIDITY", 90*24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+
+	attachmentDir := os.Getenv("ATTACHMENT_DIR")
+	if attachmentDir == "" {
+		attachmentDir = "data/attachments"
+	}
+	pendingAttachmentTTL, err := getDurationEnv("PENDING_ATTACHMENT_TTL", 24*time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+
 	return Config{
 		RestServerPort: restServerPort,
 		MySQLHost:      mysqlHost,
@@ -51,5 +127,20 @@ func LoadConfig() (Config, error) {
 		MySQLDBName:    mysqlDBName,
 		RedisHost:      redisHost,
 		RedisPort:      redisPort,
+
+		EscalationSLA:      escalationSLA,
+		EscalationInterval: escalationInterval,
+		AccrualInterval:    accrualInterval,
+
+		JWTSecret:        jwtSecret,
+		JWTPublicKeyFile: jwtPublicKeyFile,
+		DevTokenEnabled:  devTokenEnabled,
+
+		CursorSecret: os.Getenv("CURSOR_SECRET"),
+
+		CompOffValidity: compOffValidity,
+
+		AttachmentDir:        attachmentDir,
+		PendingAttachmentTTL: pendingAttachmentTTL,
 	}, nil
 }
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+// Policy decides who has to approve the leaves of a leave type
+type Policy struct {
+	ID        int                    `json:"id" gorm:"primaryKey;autoIncrement"`
+	LeaveType leave_domain.LeaveType `json:"leave_type" gorm:"type:varchar(50);not null;uniqueIndex:idx_type_level" validate:"required"`
+	// PositionLevel is the Position.Level of the requester, empty means every level
+	PositionLevel string    `json:"position_level" gorm:"type:varchar(50);not null;default:'';uniqueIndex:idx_type_level"`
+	Rules         []Rule    `json:"rules" gorm:"foreignKey:PolicyID;constraint:OnDelete:CASCADE" validate:"required,gt=0,dive"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Rule applies to the leaves longer than MinDays and not longer than MaxDays
+type Rule struct {
+	ID       int      `json:"id" gorm:"primaryKey;autoIncrement"`
+	PolicyID int      `json:"-" gorm:"index:idx_policy_id"`
+	MinDays  float64  `json:"min_days" gorm:"type:decimal(6,2);not null" validate:"gte=0"`
+	MaxDays  *float64 `json:"max_days" gorm:"type:decimal(6,2)"` // nil means no limit
+	// RequiredManagerLevel is the lowest ManagerLevel of the last approver
+	RequiredManagerLevel int `json:"required_manager_level" gorm:"not null;default:0" validate:"gte=0"`
+	// RequiredApprovers is the least number of approvers
+	RequiredApprovers int `json:"required_approvers" gorm:"not null;default:1" validate:"gte=1"`
+}
+
+// Requirement is what a leave needs to be approved
+type Requirement struct {
+	ManagerLevel int `json:"manager_level"`
+	Approvers    int `json:"approvers"`
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+// DefaultRules apply to the leave types without a policy
+var DefaultRules = []Rule{
+	{MinDays: 0, MaxDays: floatPtr(5), RequiredManagerLevel: 0, RequiredApprovers: 1},
+	{MinDays: 5, MaxDays: floatPtr(10), RequiredManagerLevel: 3, RequiredApprovers: 1},
+	{MinDays: 10, MaxDays: nil, RequiredManagerLevel: 5, RequiredApprovers: 1},
+}
+
+// ValidateRules makes sure the rules cover every leave length exactly once
+func ValidateRules(rules []Rule) error {
+	sorted := make([]Rule, len(rules))
+	copy(sorted, rules)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinDays < sorted[j].MinDays
+	})
+
+	if len(sorted) == 0 || sorted[0].MinDays != 0 {
+		return fmt.Errorf("the first rule must start from 0 days")
+	}
+	for i := range sorted {
+		if sorted[i].MaxDays == nil {
+			if i != len(sorted)-1 {
+				return fmt.Errorf("only the last rule can be unlimited")
+			}
+			continue
+		}
+		if *sorted[i].MaxDays <= sorted[i].MinDays {
+			return fmt.Errorf("max days must be greater than min days")
+		}
+		if i == len(sorted)-1 {
+			return fmt.Errorf("the last rule must be unlimited")
+		}
+		if sorted[i+1].MinDays != *sorted[i].MaxDays {
+			return fmt.Errorf("rules must be continuous, gap or overlap at %.1f days", *sorted[i].MaxDays)
+		}
+	}
+	return nil
+}
+
+// Match returns the requirement of the rule covering the days
+func Match(rules []Rule, days float64) (Requirement, bool) {
+	for _, rule := range rules {
+		if days > rule.MinDays && (rule.MaxDays == nil || days <= *rule.MaxDays) {
+			return Requirement{ManagerLevel: rule.RequiredManagerLevel, Approvers: rule.RequiredApprovers}, true
+		}
+	}
+	return Requirement{}, false
+}
+
+// Requirements returns the requirements of every rule which applies to the leaves of the leave type
+// requested by the position level, from the shortest leaves to the longest
+func Requirements(policies []Policy, leaveType leave_domain.LeaveType, positionLevel string,
+	fallback []Rule) []Requirement {
+	var typePolicy, levelPolicy *Policy
+	for i := range policies {
+		if policies[i].LeaveType != leaveType {
+			continue
+		}
+		if policies[i].PositionLevel == positionLevel {
+			levelPolicy = &policies[i]
+		} else if policies[i].PositionLevel == "" {
+			typePolicy = &policies[i]
+		}
+	}
+	rules := fallbackRules(fallback)
+	if levelPolicy != nil {
+		rules = levelPolicy.Rules
+	} else if typePolicy != nil {
+		rules = typePolicy.Rules
+	}
+
+	sorted := make([]Rule, len(rules))
+	copy(sorted, rules)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinDays < sorted[j].MinDays
+	})
+	requirements := make([]Requirement, 0, len(sorted))
+	for _, rule := range sorted {
+		requirements = append(requirements, Requirement{
+			ManagerLevel: rule.RequiredManagerLevel,
+			Approvers:    rule.RequiredApprovers,
+		})
+	}
+	return requirements
+}
+
+// fallbackRules returns the rules of the leave type, DefaultRules when it has none
+func fallbackRules(fallback []Rule) []Rule {
+	if len(fallback) == 0 {
+		return DefaultRules
+	}
+	return fallback
+}
+
+// Resolve picks the most specific policy of the leave type and the position level,
+// the fallback rules of the leave type (or DefaultRules) are used when none of the policies applies.
+func Resolve(policies []Policy, leaveType leave_domain.LeaveType, positionLevel string, days float64,
+	fallback []Rule) Requirement {
+	var typePolicy, levelPolicy *Policy
+	for i := range policies {
+		if policies[i].LeaveType != leaveType {
+			continue
+		}
+		if policies[i].PositionLevel == positionLevel {
+			levelPolicy = &policies[i]
+		} else if policies[i].PositionLevel == "" {
+			typePolicy = &policies[i]
+		}
+	}
+
+	for _, policy := range []*Policy{levelPolicy, typePolicy} {
+		if policy == nil {
+			continue
+		}
+		if requirement, ok := Match(policy.Rules, days); ok {
+			return requirement
+		}
+	}
+
+	requirement, _ := Match(fallbackRules(fallback), days)
+	return requirement
+}
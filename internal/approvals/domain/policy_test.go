@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+func TestValidateRules(t *testing.T) {
+	assert.NoError(t, ValidateRules(DefaultRules))
+	assert.NoError(t, ValidateRules([]Rule{{MinDays: 0}}))
+
+	assert.Error(t, ValidateRules(nil))
+	assert.Error(t, ValidateRules([]Rule{{MinDays: 1}}))
+	assert.Error(t, ValidateRules([]Rule{{MinDays: 0, MaxDays: floatPtr(3)}}))
+	assert.Error(t, ValidateRules([]Rule{{MinDays: 0, MaxDays: floatPtr(3)}, {MinDays: 4}}))
+	assert.Error(t, ValidateRules([]Rule{{MinDays: 0}, {MinDays: 3}}))
+}
+
+func TestResolve(t *testing.T) {
+	policies := []Policy{
+		{
+			LeaveType: leave_domain.LeaveTypeSick,
+			Rules: []Rule{
+				{MinDays: 0, MaxDays: floatPtr(2), RequiredManagerLevel: 0, RequiredApprovers: 1},
+				{MinDays: 2, RequiredManagerLevel: 0, RequiredApprovers: 2},
+			},
+		},
+		{
+			LeaveType:     leave_domain.LeaveTypeSick,
+			PositionLevel: "Manager",
+			Rules:         []Rule{{MinDays: 0, RequiredManagerLevel: 5, RequiredApprovers: 1}},
+		},
+	}
+
+	// the thresholds of the leave type take the place of the default rules
+	studyRules := []Rule{{MinDays: 0, RequiredManagerLevel: 3, RequiredApprovers: 1}}
+
+	tests := []struct {
+		name      string
+		leaveType leave_domain.LeaveType
+		level     string
+		days      float64
+		fallback  []Rule
+		want      Requirement
+	}{
+		{name: "type policy", leaveType: leave_domain.LeaveTypeSick, level: "Junior", days: 3,
+			want: Requirement{ManagerLevel: 0, Approvers: 2}},
+		{name: "level policy", leaveType: leave_domain.LeaveTypeSick, level: "Manager", days: 3,
+			want: Requirement{ManagerLevel: 5, Approvers: 1}},
+		{name: "default short", leaveType: leave_domain.LeaveTypeAnnual, level: "Junior", days: 5,
+			want: Requirement{ManagerLevel: 0, Approvers: 1}},
+		{name: "default long", leaveType: leave_domain.LeaveTypeAnnual, level: "Junior", days: 10.5,
+			want: Requirement{ManagerLevel: 5, Approvers: 1}},
+		{name: "type thresholds", leaveType: "study", level: "Junior", days: 1, fallback: studyRules,
+			want: Requirement{ManagerLevel: 3, Approvers: 1}},
+		{name: "policy over thresholds", leaveType: leave_domain.LeaveTypeSick, level: "Junior", days: 1,
+			fallback: studyRules, want: Requirement{ManagerLevel: 0, Approvers: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Resolve(policies, tt.leaveType, tt.level, tt.days, tt.fallback))
+		})
+	}
+}
+
+func TestRequirements(t *testing.T) {
+	policies := []Policy{
+		{
+			LeaveType: leave_domain.LeaveTypeSick,
+			Rules: []Rule{
+				{MinDays: 3, RequiredManagerLevel: 2, RequiredApprovers: 1},
+				{MinDays: 0, MaxDays: floatPtr(3), RequiredManagerLevel: 0, RequiredApprovers: 2},
+			},
+		},
+		{
+			LeaveType:     leave_domain.LeaveTypeSick,
+			PositionLevel: "Manager",
+			Rules:         []Rule{{MinDays: 0, RequiredManagerLevel: 5, RequiredApprovers: 1}},
+		},
+	}
+
+	assert.Equal(t, []Requirement{{ManagerLevel: 0, Approvers: 2}, {ManagerLevel: 2, Approvers: 1}},
+		Requirements(policies, leave_domain.LeaveTypeSick, "Junior", nil))
+	assert.Equal(t, []Requirement{{ManagerLevel: 5, Approvers: 1}},
+		Requirements(policies, leave_domain.LeaveTypeSick, "Manager", nil))
+	assert.Len(t, Requirements(policies, leave_domain.LeaveTypeAnnual, "Manager", nil), len(DefaultRules))
+	assert.Equal(t, []Requirement{{ManagerLevel: 3, Approvers: 1}}, Requirements(policies, "study", "Manager",
+		[]Rule{{MinDays: 0, RequiredManagerLevel: 3, RequiredApprovers: 1}}))
+}
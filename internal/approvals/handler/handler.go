@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/approvals/domain"
+	"hr-system/internal/approvals/service"
+	"hr-system/internal/common"
+	leave_domain "hr-system/internal/leaves/domain"
+	"hr-system/internal/middleware"
+)
+
+type PolicyHandler struct {
+	policyService service.PolicyService
+	logger        *common.Logger
+}
+
+func NewPolicyHandler(logger *common.Logger, policyService service.PolicyService) *PolicyHandler {
+	return &PolicyHandler{
+		policyService: policyService,
+		logger:        logger,
+	}
+}
+
+type PolicyRequest struct {
+	LeaveType     string        `json:"leave_type" binding:"required"`
+	PositionLevel string        `json:"position_level"`
+	Rules         []domain.Rule `json:"rules" binding:"required"`
+}
+
+func (r *PolicyRequest) toPolicy(id int) *domain.Policy {
+	return &domain.Policy{
+		ID:            id,
+		LeaveType:     leave_domain.LeaveType(r.LeaveType),
+		PositionLevel: r.PositionLevel,
+		Rules:         r.Rules,
+	}
+}
+
+func (h *PolicyHandler) CreatePolicy(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req PolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	policy, err := h.policyService.CreatePolicy(ctx, req.toPolicy(0))
+	if err != nil {
+		middleware.HandleErr(c, err, "create policy")
+		return
+	}
+
+	c.JSON(http.StatusCreated, policy)
+}
+
+func (h *PolicyHandler) GetPolicies(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var leaveType *leave_domain.LeaveType
+	if t := c.Query("leave_type"); t != "" {
+		leaveType = (*leave_domain.LeaveType)(&t)
+	}
+
+	policies, err := h.policyService.GetPolicies(ctx, leaveType)
+	if err != nil {
+		middleware.HandleErr(c, err, "get policies")
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+func (h *PolicyHandler) GetPolicyByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid policy ID"))
+		return
+	}
+
+	policy, err := h.policyService.GetPolicyByID(ctx, id)
+	if err != nil {
+		middleware.HandleErr(c, err, "get policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *PolicyHandler) UpdatePolicy(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid policy ID"))
+		return
+	}
+
+	var req PolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	policy, err := h.policyService.UpdatePolicy(ctx, req.toPolicy(id))
+	if err != nil {
+		middleware.HandleErr(c, err, "update policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *PolicyHandler) DeletePolicy(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid policy ID"))
+		return
+	}
+
+	if err := h.policyService.DeletePolicy(ctx, id); err != nil {
+		middleware.HandleErr(c, err, "delete policy")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
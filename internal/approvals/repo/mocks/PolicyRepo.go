@@ -0,0 +1,160 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/approvals/domain"
+	leavesdomain "hr-system/internal/leaves/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PolicyRepo is an autogenerated mock type for the PolicyRepo type
+type PolicyRepo struct {
+	mock.Mock
+}
+
+// CreatePolicy provides a mock function with given fields: ctx, policy
+func (_m *PolicyRepo) CreatePolicy(ctx context.Context, policy *domain.Policy) error {
+	ret := _m.Called(ctx, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePolicy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Policy) error); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePolicy provides a mock function with given fields: ctx, id
+func (_m *PolicyRepo) DeletePolicy(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePolicy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPolicies provides a mock function with given fields: ctx, leaveType
+func (_m *PolicyRepo) GetPolicies(ctx context.Context, leaveType *leavesdomain.LeaveType) ([]domain.Policy, error) {
+	ret := _m.Called(ctx, leaveType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPolicies")
+	}
+
+	var r0 []domain.Policy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *leavesdomain.LeaveType) ([]domain.Policy, error)); ok {
+		return rf(ctx, leaveType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *leavesdomain.LeaveType) []domain.Policy); ok {
+		r0 = rf(ctx, leaveType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Policy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *leavesdomain.LeaveType) error); ok {
+		r1 = rf(ctx, leaveType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPolicyByID provides a mock function with given fields: ctx, id
+func (_m *PolicyRepo) GetPolicyByID(ctx context.Context, id int) (domain.Policy, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPolicyByID")
+	}
+
+	var r0 domain.Policy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (domain.Policy, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) domain.Policy); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Policy)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SeedData provides a mock function with given fields: ctx
+func (_m *PolicyRepo) SeedData(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SeedData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePolicy provides a mock function with given fields: ctx, policy
+func (_m *PolicyRepo) UpdatePolicy(ctx context.Context, policy *domain.Policy) error {
+	ret := _m.Called(ctx, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePolicy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Policy) error); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPolicyRepo creates a new instance of PolicyRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPolicyRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PolicyRepo {
+	mock := &PolicyRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"hr-system/internal/approvals/domain"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/common/transaction"
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+type PolicyRepo interface {
+	SeedData(ctx context.Context) error
+	CreatePolicy(ctx context.Context, policy *domain.Policy) error
+	GetPolicyByID(ctx context.Context, id int) (domain.Policy, error)
+	GetPolicies(ctx context.Context, leaveType *leave_domain.LeaveType) ([]domain.Policy, error)
+	// UpdatePolicy replaces the rules of the policy
+	UpdatePolicy(ctx context.Context, policy *domain.Policy) error
+	DeletePolicy(ctx context.Context, id int) error
+}
+
+type policyRepo struct {
+	db *gorm.DB
+}
+
+func NewPolicyRepo(db *gorm.DB) (PolicyRepo, error) {
+	repo := &policyRepo{
+		db: db,
+	}
+
+	if err := repo.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *policyRepo) ensureSchema() error {
+	// AutoMigrate
+	if err := r.db.AutoMigrate(domain.Policy{}); err != nil {
+		return err
+	}
+	if err := r.db.AutoMigrate(domain.Rule{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *policyRepo) SeedData(ctx context.Context) error {
+	return r.SeedPolicies(ctx)
+}
+
+func preloadRules(db *gorm.DB) *gorm.DB {
+	return db.Preload("Rules", func(db *gorm.DB) *gorm.DB {
+		return db.Order("min_days ASC")
+	})
+}
+
+func (r *policyRepo) CreatePolicy(ctx context.Context, policy *domain.Policy) error {
+	if err := r.db.WithContext(ctx).Create(policy).Error; err != nil {
+		return fmt.Errorf("failed to create policy: %w", err)
+	}
+	return nil
+}
+
+func (r *policyRepo) GetPolicyByID(ctx context.Context, id int) (domain.Policy, error) {
+	var policy domain.Policy
+	db := preloadRules(r.db.WithContext(ctx))
+	if err := db.First(&policy, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Policy{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Policy{}, fmt.Errorf("failed to find policy with id %d: %w", id, err)
+	}
+	return policy, nil
+}
+
+func (r *policyRepo) GetPolicies(ctx context.Context, leaveType *leave_domain.LeaveType) ([]domain.Policy, error) {
+	var policies []domain.Policy
+
+	db := preloadRules(r.db.WithContext(ctx))
+	if leaveType != nil {
+		db = db.Where("leave_type = ?", *leaveType)
+	}
+	if err := db.Order("id ASC").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to get policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+func (r *policyRepo) UpdatePolicy(ctx context.Context, policy *domain.Policy) error {
+	err := transaction.Run(ctx, r.db, func(tx *gorm.DB) error {
+		result := tx.Model(policy).Select("LeaveType", "PositionLevel").Updates(policy)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update policy: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return common_errors.ErrResourceNotFound
+		}
+
+		if err := tx.Where("policy_id = ?", policy.ID).Delete(&domain.Rule{}).Error; err != nil {
+			return fmt.Errorf("failed to delete rules: %w", err)
+		}
+		for i := range policy.Rules {
+			policy.Rules[i].ID = 0
+			policy.Rules[i].PolicyID = policy.ID
+		}
+		if err := tx.Create(&policy.Rules).Error; err != nil {
+			return fmt.Errorf("failed to create rules: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update policy and rules: %w", err)
+	}
+
+	return nil
+}
+
+func (r *policyRepo) DeletePolicy(ctx context.Context, id int) error {
+	err := transaction.Run(ctx, r.db, func(tx *gorm.DB) error {
+		if err := tx.Where("policy_id = ?", id).Delete(&domain.Rule{}).Error; err != nil {
+			return fmt.Errorf("failed to delete rules: %w", err)
+		}
+		result := tx.Delete(&domain.Policy{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete policy: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return common_errors.ErrResourceNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return common_errors.ErrResourceNotFound
+		}
+		return fmt.Errorf("failed to delete policy: %w", err)
+	}
+
+	return nil
+}
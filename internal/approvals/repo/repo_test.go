@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"hr-system/internal/approvals/domain"
+	common_errors "hr-system/internal/common/errors"
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+func setupTestRepo(t *testing.T) *policyRepo {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := &policyRepo{db: db}
+	assert.NoError(t, repo.ensureSchema())
+	return repo
+}
+
+func TestSeedPolicies(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	// seeding twice does not duplicate the policies
+	assert.NoError(t, repo.SeedData(ctx))
+	assert.NoError(t, repo.SeedData(ctx))
+
+	policies, err := repo.GetPolicies(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, policies, 2)
+	assert.Len(t, policies[0].Rules, len(domain.DefaultRules))
+}
+
+func TestPolicyCRUD(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	maxDays := 3.0
+	policy := &domain.Policy{
+		LeaveType:     leave_domain.LeaveTypeAnnual,
+		PositionLevel: "junior",
+		Rules: []domain.Rule{
+			{MinDays: 3, RequiredManagerLevel: 3, RequiredApprovers: 2},
+			{MinDays: 0, MaxDays: &maxDays, RequiredApprovers: 1},
+		},
+	}
+	assert.NoError(t, repo.CreatePolicy(ctx, policy))
+	assert.NotZero(t, policy.ID)
+
+	// rules are ordered by min days
+	fetched, err := repo.GetPolicyByID(ctx, policy.ID)
+	assert.NoError(t, err)
+	assert.Len(t, fetched.Rules, 2)
+	assert.Equal(t, 0.0, fetched.Rules[0].MinDays)
+	assert.Equal(t, 3.0, fetched.Rules[1].MinDays)
+
+	policy.Rules = []domain.Rule{{MinDays: 0, RequiredManagerLevel: 1, RequiredApprovers: 1}}
+	assert.NoError(t, repo.UpdatePolicy(ctx, policy))
+	fetched, err = repo.GetPolicyByID(ctx, policy.ID)
+	assert.NoError(t, err)
+	assert.Len(t, fetched.Rules, 1)
+	assert.Equal(t, 1, fetched.Rules[0].RequiredManagerLevel)
+
+	sick := leave_domain.LeaveTypeSick
+	policies, err := repo.GetPolicies(ctx, &sick)
+	assert.NoError(t, err)
+	assert.Len(t, policies, 0)
+
+	assert.NoError(t, repo.DeletePolicy(ctx, policy.ID))
+	_, err = repo.GetPolicyByID(ctx, policy.ID)
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+	assert.ErrorIs(t, repo.DeletePolicy(ctx, policy.ID), common_errors.ErrResourceNotFound)
+}
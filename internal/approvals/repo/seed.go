@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"hr-system/internal/approvals/domain"
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+// SeedPolicies seeds the policies of the built-in leave types with the default rules
+func (r *policyRepo) SeedPolicies(ctx context.Context) error {
+	for _, leaveType := range []leave_domain.LeaveType{leave_domain.LeaveTypeAnnual, leave_domain.LeaveTypeSick} {
+		var count int64
+		err := r.db.WithContext(ctx).Model(&domain.Policy{}).
+			Where("leave_type = ? AND position_level = ?", leaveType, "").
+			Count(&count).Error
+		if err != nil {
+			return fmt.Errorf("failed to count %s policies: %w", leaveType, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		policy := domain.Policy{
+			LeaveType: leaveType,
+			Rules:     append([]domain.Rule(nil), domain.DefaultRules...),
+		}
+		if err := r.db.WithContext(ctx).Create(&policy).Error; err != nil {
+			return fmt.Errorf("failed to seed %s policy: %w", leaveType, err)
+		}
+	}
+
+	return nil
+}
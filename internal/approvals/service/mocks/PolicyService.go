@@ -0,0 +1,190 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/approvals/domain"
+	leavesdomain "hr-system/internal/leaves/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PolicyService is an autogenerated mock type for the PolicyService type
+type PolicyService struct {
+	mock.Mock
+}
+
+// CreatePolicy provides a mock function with given fields: ctx, policy
+func (_m *PolicyService) CreatePolicy(ctx context.Context, policy *domain.Policy) (domain.Policy, error) {
+	ret := _m.Called(ctx, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePolicy")
+	}
+
+	var r0 domain.Policy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Policy) (domain.Policy, error)); ok {
+		return rf(ctx, policy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Policy) domain.Policy); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		r0 = ret.Get(0).(domain.Policy)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Policy) error); ok {
+		r1 = rf(ctx, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePolicy provides a mock function with given fields: ctx, id
+func (_m *PolicyService) DeletePolicy(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePolicy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPolicies provides a mock function with given fields: ctx, leaveType
+func (_m *PolicyService) GetPolicies(ctx context.Context, leaveType *leavesdomain.LeaveType) ([]domain.Policy, error) {
+	ret := _m.Called(ctx, leaveType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPolicies")
+	}
+
+	var r0 []domain.Policy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *leavesdomain.LeaveType) ([]domain.Policy, error)); ok {
+		return rf(ctx, leaveType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *leavesdomain.LeaveType) []domain.Policy); ok {
+		r0 = rf(ctx, leaveType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Policy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *leavesdomain.LeaveType) error); ok {
+		r1 = rf(ctx, leaveType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPolicyByID provides a mock function with given fields: ctx, id
+func (_m *PolicyService) GetPolicyByID(ctx context.Context, id int) (domain.Policy, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPolicyByID")
+	}
+
+	var r0 domain.Policy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (domain.Policy, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) domain.Policy); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Policy)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRequirement provides a mock function with given fields: ctx, leaveType, positionLevel, days
+func (_m *PolicyService) GetRequirement(ctx context.Context, leaveType leavesdomain.LeaveType, positionLevel string, days float64) (domain.Requirement, error) {
+	ret := _m.Called(ctx, leaveType, positionLevel, days)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequirement")
+	}
+
+	var r0 domain.Requirement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, leavesdomain.LeaveType, string, float64) (domain.Requirement, error)); ok {
+		return rf(ctx, leaveType, positionLevel, days)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, leavesdomain.LeaveType, string, float64) domain.Requirement); ok {
+		r0 = rf(ctx, leaveType, positionLevel, days)
+	} else {
+		r0 = ret.Get(0).(domain.Requirement)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, leavesdomain.LeaveType, string, float64) error); ok {
+		r1 = rf(ctx, leaveType, positionLevel, days)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePolicy provides a mock function with given fields: ctx, policy
+func (_m *PolicyService) UpdatePolicy(ctx context.Context, policy *domain.Policy) (domain.Policy, error) {
+	ret := _m.Called(ctx, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePolicy")
+	}
+
+	var r0 domain.Policy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Policy) (domain.Policy, error)); ok {
+		return rf(ctx, policy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Policy) domain.Policy); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		r0 = ret.Get(0).(domain.Policy)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Policy) error); ok {
+		r1 = rf(ctx, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPolicyService creates a new instance of PolicyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPolicyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PolicyService {
+	mock := &PolicyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
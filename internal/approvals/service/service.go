@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+
+	"hr-system/internal/approvals/domain"
+	"hr-system/internal/approvals/repo"
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	leave_domain "hr-system/internal/leaves/domain"
+	leavetype_repo "hr-system/internal/leavetypes/repo"
+)
+
+type PolicyService interface {
+	CreatePolicy(ctx context.Context, policy *domain.Policy) (domain.Policy, error)
+	GetPolicyByID(ctx context.Context, id int) (domain.Policy, error)
+	GetPolicies(ctx context.Context, leaveType *leave_domain.LeaveType) ([]domain.Policy, error)
+	UpdatePolicy(ctx context.Context, policy *domain.Policy) (domain.Policy, error)
+	DeletePolicy(ctx context.Context, id int) error
+	// GetRequirement returns what a leave of the leave type and days requested by the position level needs to be approved
+	GetRequirement(ctx context.Context, leaveType leave_domain.LeaveType, positionLevel string,
+		days float64) (domain.Requirement, error)
+}
+
+type policyService struct {
+	policyRepo repo.PolicyRepo
+	typeRepo   leavetype_repo.TypeRepo
+	logger     *common.Logger
+	validate   *validator.Validate
+}
+
+func NewPolicyService(logger *common.Logger, policyRepo repo.PolicyRepo,
+	typeRepo leavetype_repo.TypeRepo) PolicyService {
+	return &policyService{
+		policyRepo: policyRepo,
+		typeRepo:   typeRepo,
+		logger:     logger,
+		validate:   validator.New(),
+	}
+}
+
+func (s *policyService) validatePolicy(ctx context.Context, policy *domain.Policy) error {
+	if err := s.validate.Struct(policy); err != nil {
+		return fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	if err := domain.ValidateRules(policy.Rules); err != nil {
+		return fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+
+	if _, err := s.typeRepo.GetTypeByCode(ctx, policy.LeaveType); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return fmt.Errorf("%w, unknown leave type %s", common_errors.ErrInvalidInput, policy.LeaveType)
+		}
+		return fmt.Errorf("failed to get leave type: %w", err)
+	}
+
+	// one policy for each leave type and position level
+	policies, err := s.policyRepo.GetPolicies(ctx, &policy.LeaveType)
+	if err != nil {
+		return fmt.Errorf("failed to get policies: %w", err)
+	}
+	for _, p := range policies {
+		if p.ID != policy.ID && p.PositionLevel == policy.PositionLevel {
+			return fmt.Errorf("%w, policy %d has the same leave type and position level",
+				common_errors.ErrStatusConflict, p.ID)
+		}
+	}
+
+	return nil
+}
+
+func (s *policyService) CreatePolicy(ctx context.Context, policy *domain.Policy) (domain.Policy, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.Policy{}, err
+	}
+	if err := s.validatePolicy(ctx, policy); err != nil {
+		return domain.Policy{}, err
+	}
+	if err := s.policyRepo.CreatePolicy(ctx, policy); err != nil {
+		return domain.Policy{}, fmt.Errorf("failed to create policy: %w", err)
+	}
+	return *policy, nil
+}
+
+func (s *policyService) GetPolicyByID(ctx context.Context, id int) (domain.Policy, error) {
+	policy, err := s.policyRepo.GetPolicyByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Policy{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Policy{}, fmt.Errorf("failed to get policy: %w", err)
+	}
+	return policy, nil
+}
+
+func (s *policyService) GetPolicies(ctx context.Context, leaveType *leave_domain.LeaveType) ([]domain.Policy, error) {
+	policies, err := s.policyRepo.GetPolicies(ctx, leaveType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policies: %w", err)
+	}
+	return policies, nil
+}
+
+func (s *policyService) UpdatePolicy(ctx context.Context, policy *domain.Policy) (domain.Policy, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.Policy{}, err
+	}
+	if err := s.validatePolicy(ctx, policy); err != nil {
+		return domain.Policy{}, err
+	}
+	if err := s.policyRepo.UpdatePolicy(ctx, policy); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Policy{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Policy{}, fmt.Errorf("failed to update policy: %w", err)
+	}
+	return s.GetPolicyByID(ctx, policy.ID)
+}
+
+func (s *policyService) DeletePolicy(ctx context.Context, id int) error {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return err
+	}
+	if err := s.policyRepo.DeletePolicy(ctx, id); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return common_errors.ErrResourceNotFound
+		}
+		return fmt.Errorf("failed to delete policy: %w", err)
+	}
+	return nil
+}
+
+func (s *policyService) GetRequirement(ctx context.Context, leaveType leave_domain.LeaveType, positionLevel string,
+	days float64) (domain.Requirement, error) {
+	policies, err := s.policyRepo.GetPolicies(ctx, &leaveType)
+	if err != nil {
+		return domain.Requirement{}, fmt.Errorf("failed to get policies: %w", err)
+	}
+	// the thresholds of the leave type apply when none of the policies does
+	catalogued, err := s.typeRepo.GetTypeByCode(ctx, leaveType)
+	if err != nil && !errors.Is(err, common_errors.ErrResourceNotFound) {
+		return domain.Requirement{}, fmt.Errorf("failed to get leave type: %w", err)
+	}
+	return domain.Resolve(policies, leaveType, positionLevel, days, catalogued.Thresholds), nil
+}
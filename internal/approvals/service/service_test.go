@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"hr-system/internal/approvals/domain"
+	mocks_policy_repo "hr-system/internal/approvals/repo/mocks"
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	leave_domain "hr-system/internal/leaves/domain"
+	leavetype_domain "hr-system/internal/leavetypes/domain"
+	mocks_type_repo "hr-system/internal/leavetypes/repo/mocks"
+)
+
+func TestGetRequirement(t *testing.T) {
+	mockRepo := mocks_policy_repo.NewPolicyRepo(t)
+	mockTypeRepo := mocks_type_repo.NewTypeRepo(t)
+	service := NewPolicyService(common.NewLogger(), mockRepo, mockTypeRepo)
+
+	ctx := context.Background()
+	leaveType := leave_domain.LeaveTypeAnnual
+	policies := []domain.Policy{
+		{ID: 1, LeaveType: leaveType, Rules: domain.DefaultRules},
+		{ID: 2, LeaveType: leaveType, PositionLevel: "intern",
+			Rules: []domain.Rule{{MinDays: 0, RequiredManagerLevel: 1, RequiredApprovers: 2}}},
+	}
+	mockRepo.On("GetPolicies", ctx, &leaveType).Return(policies, nil).Twice()
+	mockTypeRepo.On("GetTypeByCode", ctx, leaveType).Return(leavetype_domain.Type{Code: leaveType}, nil).Twice()
+
+	// the policy of the position level wins
+	requirement, err := service.GetRequirement(ctx, leaveType, "intern", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Requirement{ManagerLevel: 1, Approvers: 2}, requirement)
+
+	requirement, err = service.GetRequirement(ctx, leaveType, "senior", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Requirement{ManagerLevel: 3, Approvers: 1}, requirement)
+
+	// a leave type without a policy falls back to its own thresholds
+	studyType := leave_domain.LeaveType("study")
+	mockRepo.On("GetPolicies", ctx, &studyType).Return([]domain.Policy{}, nil).Once()
+	mockTypeRepo.On("GetTypeByCode", ctx, studyType).Return(leavetype_domain.Type{Code: studyType,
+		Thresholds: []domain.Rule{{MinDays: 0, RequiredManagerLevel: 2, RequiredApprovers: 1}}}, nil).Once()
+	requirement, err = service.GetRequirement(ctx, studyType, "senior", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Requirement{ManagerLevel: 2, Approvers: 1}, requirement)
+}
+
+func TestCreatePolicy_Invalid(t *testing.T) {
+	mockRepo := mocks_policy_repo.NewPolicyRepo(t)
+	mockTypeRepo := mocks_type_repo.NewTypeRepo(t)
+	service := NewPolicyService(common.NewLogger(), mockRepo, mockTypeRepo)
+
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 1, Roles: []auth.Role{auth.RoleHRAdmin}})
+	leaveType := leave_domain.LeaveTypeAnnual
+
+	// rules must cover every leave length
+	maxDays := 5.0
+	_, err := service.CreatePolicy(ctx, &domain.Policy{LeaveType: leaveType,
+		Rules: []domain.Rule{{MinDays: 0, MaxDays: &maxDays, RequiredApprovers: 1}}})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// the leave type must be in the catalogue
+	mockTypeRepo.On("GetTypeByCode", ctx, leave_domain.LeaveType("study")).
+		Return(leavetype_domain.Type{}, common_errors.ErrResourceNotFound).Once()
+	_, err = service.CreatePolicy(ctx, &domain.Policy{LeaveType: "study",
+		Rules: []domain.Rule{{MinDays: 0, RequiredApprovers: 1}}})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// one policy for each leave type and position level
+	mockTypeRepo.On("GetTypeByCode", ctx, leaveType).Return(leavetype_domain.Type{Code: leaveType}, nil).Once()
+	mockRepo.On("GetPolicies", ctx, &leaveType).
+		Return([]domain.Policy{{ID: 1, LeaveType: leaveType}}, nil).Once()
+	_, err = service.CreatePolicy(ctx, &domain.Policy{LeaveType: leaveType,
+		Rules: []domain.Rule{{MinDays: 0, RequiredApprovers: 1}}})
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+}
+
+func TestCreatePolicy_Forbidden(t *testing.T) {
+	mockRepo := mocks_policy_repo.NewPolicyRepo(t)
+	mockTypeRepo := mocks_type_repo.NewTypeRepo(t)
+	service := NewPolicyService(common.NewLogger(), mockRepo, mockTypeRepo)
+
+	// only HR changes the policies
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 2, Roles: []auth.Role{auth.RoleManager}})
+	_, err := service.CreatePolicy(ctx, &domain.Policy{LeaveType: leave_domain.LeaveTypeAnnual,
+		Rules: []domain.Rule{{MinDays: 0, RequiredApprovers: 1}}})
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
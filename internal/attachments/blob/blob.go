@@ -0,0 +1,97 @@
+// Package blob keeps the content of the attachments, the store is pluggable and the local filesystem is the default
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	common_errors "hr-system/internal/common/errors"
+)
+
+// BlobStore keeps contents by their keys, a missing key is common_errors.ErrResourceNotFound
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader) error
+	// Get returns the content of the key, the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// keys are generated by the callers, they never come from a request
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+func checkKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("%w, invalid blob key %q", common_errors.ErrInvalidInput, key)
+	}
+	return nil
+}
+
+// localStore keeps every content in a file named by its key in a directory
+type localStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) (BlobStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory %s: %w", dir, err)
+	}
+	return &localStore{
+		dir: dir,
+	}, nil
+}
+
+func (s *localStore) Put(ctx context.Context, key string, content io.Reader) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	// the content shows up under its key only when it's written completely
+	file, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write blob %s: %w", key, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write blob %s: %w", key, err)
+	}
+	if err := os.Rename(file.Name(), filepath.Join(s.dir, key)); err != nil {
+		return fmt.Errorf("failed to save blob %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *localStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(filepath.Join(s.dir, key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, common_errors.ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to open blob %s: %w", key, err)
+	}
+	return file, nil
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.dir, key)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return common_errors.ErrResourceNotFound
+		}
+		return fmt.Errorf("failed to delete blob %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	common_errors "hr-system/internal/common/errors"
+)
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "note_1", strings.NewReader("doctor's note")))
+	content, err := store.Get(ctx, "note_1")
+	assert.NoError(t, err)
+	data, err := io.ReadAll(content)
+	assert.NoError(t, err)
+	assert.NoError(t, content.Close())
+	assert.Equal(t, "doctor's note", string(data))
+
+	assert.NoError(t, store.Delete(ctx, "note_1"))
+	_, err = store.Get(ctx, "note_1")
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, "note_1"), common_errors.ErrResourceNotFound)
+
+	// a key never leaves the directory
+	assert.ErrorIs(t, store.Put(ctx, "../note", strings.NewReader("")), common_errors.ErrInvalidInput)
+	_, err = store.Get(ctx, "")
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxSize is the largest attachment in bytes
+const MaxSize = 5 << 20
+
+// MaxPending is the most attachments an employee can have waiting to be submitted with a new leave
+const MaxPending = 10
+
+// ContentTypes are the accepted content types, the type is sniffed from the content instead of trusting the upload
+var ContentTypes = []string{"application/pdf", "image/jpeg", "image/png"}
+
+// Attachment is a supporting document of a leave, e.g. a doctor's note, its content is kept in a blob store
+type Attachment struct {
+	ID int `json:"id" gorm:"primaryKey;autoIncrement"`
+	// LeaveID is nil while the attachment waits to be submitted with a new leave
+	LeaveID     *int      `json:"leave_id" gorm:"index:idx_leave_id"`
+	UploaderID  int       `json:"uploader_id" gorm:"not null;index:idx_uploader_id"`
+	FileName    string    `json:"file_name" gorm:"type:varchar(255);not null"`
+	ContentType string    `json:"content_type" gorm:"type:varchar(100);not null"`
+	Size        int64     `json:"size" gorm:"not null"`
+	BlobKey     string    `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:idx_blob_key"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// CleanFileName keeps the base name of an uploaded file without control characters, at most 255 bytes
+func CleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "." || name == "/" || name == "" {
+		return "attachment"
+	}
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/attachments/domain"
+	"hr-system/internal/attachments/service"
+	"hr-system/internal/common"
+	"hr-system/internal/middleware"
+)
+
+// maxBodySize leaves room for the multipart headers around the largest attachment
+const maxBodySize = domain.MaxSize + 64<<10
+
+type AttachmentHandler struct {
+	attachmentService service.AttachmentService
+	logger            *common.Logger
+}
+
+func NewAttachmentHandler(logger *common.Logger, attachmentService service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+		logger:            logger,
+	}
+}
+
+// upload stores the file of the multipart form as an attachment of the leave, or as a pending one without a leave
+func (h *AttachmentHandler) upload(c *gin.Context, leaveID *int) {
+	ctx := c.Request.Context()
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge,
+				middleware.CreateErrResp("attachment is larger than %d bytes", domain.MaxSize))
+			return
+		}
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid file, cause: %v", err))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid file, cause: %v", err))
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.Upload(ctx, leaveID, fileHeader.Filename, file)
+	if err != nil {
+		middleware.HandleErr(c, err, "upload attachment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// UploadPending uploads an attachment to submit with a new leave by its ID
+func (h *AttachmentHandler) UploadPending(c *gin.Context) {
+	h.upload(c, nil)
+}
+
+func (h *AttachmentHandler) UploadToLeave(c *gin.Context) {
+	leaveID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid leave ID"))
+		return
+	}
+	h.upload(c, &leaveID)
+}
+
+func (h *AttachmentHandler) GetAttachments(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	leaveID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid leave ID"))
+		return
+	}
+
+	attachments, err := h.attachmentService.GetAttachments(ctx, leaveID)
+	if err != nil {
+		middleware.HandleErr(c, err, "get attachments")
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	leaveID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid leave ID"))
+		return
+	}
+	id, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid attachment ID"))
+		return
+	}
+
+	attachment, content, err := h.attachmentService.Download(ctx, leaveID, id)
+	if err != nil {
+		middleware.HandleErr(c, err, "download attachment")
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{
+			"filename": attachment.FileName,
+		}),
+		"Cache-Control":          "private, no-store",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
@@ -0,0 +1,241 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/attachments/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// AttachmentRepo is an autogenerated mock type for the AttachmentRepo type
+type AttachmentRepo struct {
+	mock.Mock
+}
+
+// AttachToLeave provides a mock function with given fields: ctx, leaveID, ids
+func (_m *AttachmentRepo) AttachToLeave(ctx context.Context, leaveID int, ids []int) error {
+	ret := _m.Called(ctx, leaveID, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachToLeave")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []int) error); ok {
+		r0 = rf(ctx, leaveID, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountPending provides a mock function with given fields: ctx, uploaderID
+func (_m *AttachmentRepo) CountPending(ctx context.Context, uploaderID int) (int, error) {
+	ret := _m.Called(ctx, uploaderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountPending")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, uploaderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, uploaderID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, uploaderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAttachment provides a mock function with given fields: ctx, attachment
+func (_m *AttachmentRepo) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	ret := _m.Called(ctx, attachment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAttachment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Attachment) error); ok {
+		r0 = rf(ctx, attachment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePending provides a mock function with given fields: ctx, id
+func (_m *AttachmentRepo) DeletePending(ctx context.Context, id int) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePending")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAttachmentByID provides a mock function with given fields: ctx, id
+func (_m *AttachmentRepo) GetAttachmentByID(ctx context.Context, id int) (domain.Attachment, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttachmentByID")
+	}
+
+	var r0 domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (domain.Attachment, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) domain.Attachment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Attachment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAttachmentsByIDs provides a mock function with given fields: ctx, ids
+func (_m *AttachmentRepo) GetAttachmentsByIDs(ctx context.Context, ids []int) ([]domain.Attachment, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttachmentsByIDs")
+	}
+
+	var r0 []domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) ([]domain.Attachment, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) []domain.Attachment); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAttachmentsOfLeave provides a mock function with given fields: ctx, leaveID
+func (_m *AttachmentRepo) GetAttachmentsOfLeave(ctx context.Context, leaveID int) ([]domain.Attachment, error) {
+	ret := _m.Called(ctx, leaveID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttachmentsOfLeave")
+	}
+
+	var r0 []domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.Attachment, error)); ok {
+		return rf(ctx, leaveID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.Attachment); ok {
+		r0 = rf(ctx, leaveID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, leaveID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPendingBefore provides a mock function with given fields: ctx, before
+func (_m *AttachmentRepo) GetPendingBefore(ctx context.Context, before time.Time) ([]domain.Attachment, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingBefore")
+	}
+
+	var r0 []domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]domain.Attachment, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []domain.Attachment); ok {
+		r0 = rf(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAttachmentRepo creates a new instance of AttachmentRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAttachmentRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AttachmentRepo {
+	mock := &AttachmentRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"hr-system/internal/attachments/domain"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/common/transaction"
+)
+
+type AttachmentRepo interface {
+	CreateAttachment(ctx context.Context, attachment *domain.Attachment) error
+	GetAttachmentByID(ctx context.Context, id int) (domain.Attachment, error)
+	GetAttachmentsByIDs(ctx context.Context, ids []int) ([]domain.Attachment, error)
+	// GetAttachmentsOfLeave lists the attachments of the leave, the first uploaded first
+	GetAttachmentsOfLeave(ctx context.Context, leaveID int) ([]domain.Attachment, error)
+	// AttachToLeave links the pending attachments to the leave,
+	// it fails with common_errors.ErrStatusConflict when any of them is not pending anymore
+	AttachToLeave(ctx context.Context, leaveID int, ids []int) error
+	// CountPending counts the attachments of the uploader waiting to be submitted with a new leave
+	CountPending(ctx context.Context, uploaderID int) (int, error)
+	// GetPendingBefore returns the attachments uploaded before the time which are still not submitted with a leave
+	GetPendingBefore(ctx context.Context, before time.Time) ([]domain.Attachment, error)
+	// DeletePending deletes the attachment if it is still pending, it reports whether it was deleted
+	DeletePending(ctx context.Context, id int) (bool, error)
+}
+
+type attachmentRepo struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepo(db *gorm.DB) (AttachmentRepo, error) {
+	repo := &attachmentRepo{
+		db: db,
+	}
+
+	if err := repo.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *attachmentRepo) ensureSchema() error {
+	// AutoMigrate
+	if err := r.db.AutoMigrate(domain.Attachment{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *attachmentRepo) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	if err := transaction.DB(ctx, r.db).Create(attachment).Error; err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *attachmentRepo) GetAttachmentByID(ctx context.Context, id int) (domain.Attachment, error) {
+	var attachment domain.Attachment
+	if err := transaction.DB(ctx, r.db).First(&attachment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Attachment{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Attachment{}, fmt.Errorf("failed to find attachment with id %d: %w", id, err)
+	}
+	return attachment, nil
+}
+
+func (r *attachmentRepo) GetAttachmentsByIDs(ctx context.Context, ids []int) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+	if err := transaction.DB(ctx, r.db).Where("id IN ?", ids).Order("id ASC").Find(&attachments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepo) GetAttachmentsOfLeave(ctx context.Context, leaveID int) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+	err := transaction.DB(ctx, r.db).Where("leave_id = ?", leaveID).Order("id ASC").Find(&attachments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments of leave %d: %w", leaveID, err)
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepo) AttachToLeave(ctx context.Context, leaveID int, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return transaction.Run(ctx, r.db, func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Attachment{}).Where("id IN ? AND leave_id IS NULL", ids).
+			Update("leave_id", leaveID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to attach attachments to leave %d: %w", leaveID, result.Error)
+		}
+		if result.RowsAffected != int64(len(ids)) {
+			// roll back the ones attached
+			return fmt.Errorf("%w, attachments %v are not all pending", common_errors.ErrStatusConflict, ids)
+		}
+		return nil
+	})
+}
+
+func (r *attachmentRepo) CountPending(ctx context.Context, uploaderID int) (int, error) {
+	var count int64
+	err := transaction.DB(ctx, r.db).Model(&domain.Attachment{}).
+		Where("uploader_id = ? AND leave_id IS NULL", uploaderID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending attachments of %d: %w", uploaderID, err)
+	}
+	return int(count), nil
+}
+
+func (r *attachmentRepo) GetPendingBefore(ctx context.Context, before time.Time) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+	err := transaction.DB(ctx, r.db).Where("leave_id IS NULL AND created_at < ?", before).Order("id ASC").
+		Find(&attachments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepo) DeletePending(ctx context.Context, id int) (bool, error) {
+	// the attachment may have been submitted with a leave in the meantime
+	result := transaction.DB(ctx, r.db).Where("id = ? AND leave_id IS NULL", id).Delete(&domain.Attachment{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete pending attachment %d: %w", id, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
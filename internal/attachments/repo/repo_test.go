@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"hr-system/internal/attachments/domain"
+	common_errors "hr-system/internal/common/errors"
+)
+
+func setupTestRepo(t *testing.T) *attachmentRepo {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := &attachmentRepo{db: db}
+	assert.NoError(t, repo.ensureSchema())
+	return repo
+}
+
+func TestAttachToLeave(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	var ids []int
+	for _, key := range []string{"a", "b", "c"} {
+		attachment := domain.Attachment{UploaderID: 3, FileName: key + ".pdf", ContentType: "application/pdf",
+			Size: 10, BlobKey: key}
+		assert.NoError(t, repo.CreateAttachment(ctx, &attachment))
+		ids = append(ids, attachment.ID)
+	}
+
+	assert.NoError(t, repo.AttachToLeave(ctx, 1, ids[:2]))
+	attachments, err := repo.GetAttachmentsOfLeave(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 2)
+
+	// an attachment goes with one leave only, none of them is attached then
+	assert.ErrorIs(t, repo.AttachToLeave(ctx, 2, ids[1:]), common_errors.ErrStatusConflict)
+	attachment, err := repo.GetAttachmentByID(ctx, ids[2])
+	assert.NoError(t, err)
+	assert.Nil(t, attachment.LeaveID)
+
+	attachments, err = repo.GetAttachmentsByIDs(ctx, []int{ids[2], 99})
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 1)
+
+	_, err = repo.GetAttachmentByID(ctx, 99)
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+}
+
+func TestSweepPending(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	var ids []int
+	for _, key := range []string{"a", "b", "c"} {
+		attachment := domain.Attachment{UploaderID: 3, FileName: key + ".pdf", ContentType: "application/pdf",
+			Size: 10, BlobKey: key}
+		assert.NoError(t, repo.CreateAttachment(ctx, &attachment))
+		ids = append(ids, attachment.ID)
+	}
+	assert.NoError(t, repo.AttachToLeave(ctx, 1, ids[:1]))
+
+	count, err := repo.CountPending(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	pending, err := repo.GetPendingBefore(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+	pending, err = repo.GetPendingBefore(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, pending, 0)
+
+	// a submitted attachment is kept
+	deleted, err := repo.DeletePending(ctx, ids[0])
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	deleted, err = repo.DeletePending(ctx, ids[1])
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	count, err = repo.CountPending(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,191 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/attachments/domain"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// AttachmentService is an autogenerated mock type for the AttachmentService type
+type AttachmentService struct {
+	mock.Mock
+}
+
+// AttachToLeave provides a mock function with given fields: ctx, leaveID, ids
+func (_m *AttachmentService) AttachToLeave(ctx context.Context, leaveID int, ids []int) error {
+	ret := _m.Called(ctx, leaveID, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachToLeave")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []int) error); ok {
+		r0 = rf(ctx, leaveID, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckPending provides a mock function with given fields: ctx, ids
+func (_m *AttachmentService) CheckPending(ctx context.Context, ids []int) error {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckPending")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) error); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Download provides a mock function with given fields: ctx, leaveID, id
+func (_m *AttachmentService) Download(ctx context.Context, leaveID int, id int) (domain.Attachment, io.ReadCloser, error) {
+	ret := _m.Called(ctx, leaveID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Download")
+	}
+
+	var r0 domain.Attachment
+	var r1 io.ReadCloser
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (domain.Attachment, io.ReadCloser, error)); ok {
+		return rf(ctx, leaveID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) domain.Attachment); ok {
+		r0 = rf(ctx, leaveID, id)
+	} else {
+		r0 = ret.Get(0).(domain.Attachment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) io.ReadCloser); ok {
+		r1 = rf(ctx, leaveID, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, leaveID, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAttachments provides a mock function with given fields: ctx, leaveID
+func (_m *AttachmentService) GetAttachments(ctx context.Context, leaveID int) ([]domain.Attachment, error) {
+	ret := _m.Called(ctx, leaveID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttachments")
+	}
+
+	var r0 []domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.Attachment, error)); ok {
+		return rf(ctx, leaveID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.Attachment); ok {
+		r0 = rf(ctx, leaveID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, leaveID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SweepPending provides a mock function with given fields: ctx, ttl
+func (_m *AttachmentService) SweepPending(ctx context.Context, ttl time.Duration) (int, error) {
+	ret := _m.Called(ctx, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SweepPending")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (int, error)); ok {
+		return rf(ctx, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) int); ok {
+		r0 = rf(ctx, ttl)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Upload provides a mock function with given fields: ctx, leaveID, fileName, content
+func (_m *AttachmentService) Upload(ctx context.Context, leaveID *int, fileName string, content io.Reader) (domain.Attachment, error) {
+	ret := _m.Called(ctx, leaveID, fileName, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upload")
+	}
+
+	var r0 domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *int, string, io.Reader) (domain.Attachment, error)); ok {
+		return rf(ctx, leaveID, fileName, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *int, string, io.Reader) domain.Attachment); ok {
+		r0 = rf(ctx, leaveID, fileName, content)
+	} else {
+		r0 = ret.Get(0).(domain.Attachment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *int, string, io.Reader) error); ok {
+		r1 = rf(ctx, leaveID, fileName, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAttachmentService creates a new instance of AttachmentService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAttachmentService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AttachmentService {
+	mock := &AttachmentService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"time"
+
+	"hr-system/internal/attachments/blob"
+	"hr-system/internal/attachments/domain"
+	"hr-system/internal/attachments/repo"
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	leave_domain "hr-system/internal/leaves/domain"
+	leave_repo "hr-system/internal/leaves/repo"
+)
+
+type AttachmentService interface {
+	// Upload stores an attachment of the leave, or one waiting to be submitted with a new leave when leaveID is nil
+	Upload(ctx context.Context, leaveID *int, fileName string, content io.Reader) (domain.Attachment, error)
+	GetAttachments(ctx context.Context, leaveID int) ([]domain.Attachment, error)
+	// Download returns the attachment of the leave with its content, the caller closes the content
+	Download(ctx context.Context, leaveID, id int) (domain.Attachment, io.ReadCloser, error)
+	// CheckPending makes sure the caller uploaded the attachments and none of them is submitted with a leave yet
+	CheckPending(ctx context.Context, ids []int) error
+	// AttachToLeave submits the pending attachments with the new leave
+	AttachToLeave(ctx context.Context, leaveID int, ids []int) error
+	// SweepPending deletes the attachments which have not been submitted with a leave within the TTL,
+	// it returns the number of deleted attachments
+	SweepPending(ctx context.Context, ttl time.Duration) (int, error)
+}
+
+type attachmentService struct {
+	attachmentRepo repo.AttachmentRepo
+	blobStore      blob.BlobStore
+	leaveRepo      leave_repo.LeaveRepo
+	logger         *common.Logger
+}
+
+func NewAttachmentService(logger *common.Logger, attachmentRepo repo.AttachmentRepo, blobStore blob.BlobStore,
+	leaveRepo leave_repo.LeaveRepo) AttachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		blobStore:      blobStore,
+		leaveRepo:      leaveRepo,
+		logger:         logger,
+	}
+}
+
+func (s *attachmentService) getLeave(ctx context.Context, leaveID int) (leave_domain.Leave, error) {
+	leave, err := s.leaveRepo.GetLeaveByID(ctx, leaveID)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return leave_domain.Leave{}, common_errors.ErrResourceNotFound
+		}
+		return leave_domain.Leave{}, fmt.Errorf("failed to get leave: %w", err)
+	}
+	return leave, nil
+}
+
+// authorizeLeave allows the requester of the leave, its reviewers and HR to read its attachments
+func authorizeLeave(ctx context.Context, leave *leave_domain.Leave) error {
+	actor, err := auth.Authorize(ctx)
+	if err != nil {
+		return err
+	}
+	for _, review := range leave.Reviews {
+		if review.ReviewerID == actor.EmployeeID ||
+			review.OnBehalfOfID != nil && *review.OnBehalfOfID == actor.EmployeeID {
+			return nil
+		}
+	}
+	_, err = auth.AuthorizeSelf(ctx, leave.EmployeeID, auth.RoleHRAdmin)
+	return err
+}
+
+// readContent reads the content up to the size limit and returns it with its sniffed content type
+func readContent(content io.Reader) ([]byte, string, error) {
+	data, err := io.ReadAll(io.LimitReader(content, domain.MaxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, "", fmt.Errorf("%w, attachment is empty", common_errors.ErrInvalidInput)
+	}
+	if len(data) > domain.MaxSize {
+		return nil, "", fmt.Errorf("%w, attachment is larger than %d bytes", common_errors.ErrInvalidInput,
+			domain.MaxSize)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if !slices.Contains(domain.ContentTypes, contentType) {
+		return nil, "", fmt.Errorf("%w, content type %s is not one of %v", common_errors.ErrInvalidInput,
+			contentType, domain.ContentTypes)
+	}
+	return data, contentType, nil
+}
+
+func newBlobKey() (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate blob key: %w", err)
+	}
+	return hex.EncodeToString(random), nil
+}
+
+func (s *attachmentService) Upload(ctx context.Context, leaveID *int, fileName string,
+	content io.Reader) (domain.Attachment, error) {
+	actor, err := auth.Authorize(ctx)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if leaveID != nil {
+		// the requester adds the documents of their leave, HR may add them for them
+		leave, err := s.getLeave(ctx, *leaveID)
+		if err != nil {
+			return domain.Attachment{}, err
+		}
+		if _, err := auth.AuthorizeSelf(ctx, leave.EmployeeID, auth.RoleHRAdmin); err != nil {
+			return domain.Attachment{}, err
+		}
+	} else {
+		pending, err := s.attachmentRepo.CountPending(ctx, actor.EmployeeID)
+		if err != nil {
+			return domain.Attachment{}, fmt.Errorf("failed to count pending attachments: %w", err)
+		}
+		if pending >= domain.MaxPending {
+			return domain.Attachment{}, fmt.Errorf("%w, there are already %d attachments waiting for a leave",
+				common_errors.ErrInvalidInput, pending)
+		}
+	}
+
+	data, contentType, err := readContent(content)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	key, err := newBlobKey()
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if err := s.blobStore.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return domain.Attachment{}, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	attachment := domain.Attachment{
+		LeaveID:     leaveID,
+		UploaderID:  actor.EmployeeID,
+		FileName:    domain.CleanFileName(fileName),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		BlobKey:     key,
+	}
+	if err := s.attachmentRepo.CreateAttachment(ctx, &attachment); err != nil {
+		if err := s.blobStore.Delete(ctx, key); err != nil {
+			s.logger.Errorf("failed to delete blob %s of a failed upload, cause: %s", key, err)
+		}
+		return domain.Attachment{}, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+func (s *attachmentService) GetAttachments(ctx context.Context, leaveID int) ([]domain.Attachment, error) {
+	leave, err := s.getLeave(ctx, leaveID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeLeave(ctx, &leave); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.GetAttachmentsOfLeave(ctx, leaveID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+func (s *attachmentService) Download(ctx context.Context, leaveID, id int) (domain.Attachment, io.ReadCloser,
+	error) {
+	leave, err := s.getLeave(ctx, leaveID)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+	if err := authorizeLeave(ctx, &leave); err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	attachment, err := s.attachmentRepo.GetAttachmentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Attachment{}, nil, common_errors.ErrResourceNotFound
+		}
+		return domain.Attachment{}, nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	if attachment.LeaveID == nil || *attachment.LeaveID != leaveID {
+		return domain.Attachment{}, nil, common_errors.ErrResourceNotFound
+	}
+
+	content, err := s.blobStore.Get(ctx, attachment.BlobKey)
+	if err != nil {
+		return domain.Attachment{}, nil, fmt.Errorf("failed to get content of attachment %d: %w", id, err)
+	}
+	return attachment, content, nil
+}
+
+func (s *attachmentService) CheckPending(ctx context.Context, ids []int) error {
+	actor, err := auth.Authorize(ctx)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := s.attachmentRepo.GetAttachmentsByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get attachments: %w", err)
+	}
+	pending := make(map[int]bool, len(attachments))
+	for _, attachment := range attachments {
+		pending[attachment.ID] = attachment.LeaveID == nil && attachment.UploaderID == actor.EmployeeID
+	}
+	for _, id := range ids {
+		if !pending[id] {
+			return fmt.Errorf("%w, attachment %d is not a pending upload of the caller", common_errors.ErrInvalidInput,
+				id)
+		}
+	}
+	return nil
+}
+
+func (s *attachmentService) AttachToLeave(ctx context.Context, leaveID int, ids []int) error {
+	if err := s.attachmentRepo.AttachToLeave(ctx, leaveID, ids); err != nil {
+		if errors.Is(err, common_errors.ErrStatusConflict) {
+			return err
+		}
+		return fmt.Errorf("failed to attach attachments: %w", err)
+	}
+	return nil
+}
+
+func (s *attachmentService) SweepPending(ctx context.Context, ttl time.Duration) (int, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return 0, err
+	}
+
+	attachments, err := s.attachmentRepo.GetPendingBefore(ctx, time.Now().Add(-ttl))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending attachments: %w", err)
+	}
+
+	swept := 0
+	for _, attachment := range attachments {
+		deleted, err := s.attachmentRepo.DeletePending(ctx, attachment.ID)
+		if err != nil {
+			// the others can still be swept
+			s.logger.Errorf("failed to delete pending attachment %d, cause: %s", attachment.ID, err)
+			continue
+		}
+		if !deleted {
+			continue
+		}
+		if err := s.blobStore.Delete(ctx, attachment.BlobKey); err != nil {
+			s.logger.Errorf("failed to delete blob %s of attachment %d, cause: %s", attachment.BlobKey, attachment.ID,
+				err)
+		}
+		swept++
+	}
+
+	return swept, nil
+}
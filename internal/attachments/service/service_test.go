@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mocks_blob "hr-system/internal/attachments/blob/mocks"
+	"hr-system/internal/attachments/domain"
+	mocks_attachment_repo "hr-system/internal/attachments/repo/mocks"
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	leave_domain "hr-system/internal/leaves/domain"
+	mocks_leave_repo "hr-system/internal/leaves/repo/mocks"
+	review_domain "hr-system/internal/reviews/domain"
+)
+
+const pdf = "%PDF-1.4\n%doctor's note\n"
+
+func actorCtx(employeeID int, roles ...auth.Role) context.Context {
+	return auth.WithActor(context.Background(), auth.Actor{EmployeeID: employeeID, Roles: roles})
+}
+
+// the leave of employee 3 reviewed by 2 on behalf of 4
+var leave = leave_domain.Leave{ID: 1, EmployeeID: 3,
+	Reviews: []review_domain.Review{{ReviewerID: 2, OnBehalfOfID: common.GetPtr(4)}}}
+
+func TestUpload(t *testing.T) {
+	mockAttachmentRepo := mocks_attachment_repo.NewAttachmentRepo(t)
+	mockBlobStore := mocks_blob.NewBlobStore(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	logger := common.NewLogger()
+
+	service := NewAttachmentService(logger, mockAttachmentRepo, mockBlobStore, mockLeaveRepo)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+
+	mockAttachmentRepo.On("CountPending", ctx, 3).Return(domain.MaxPending-1, nil).Times(3)
+	mockBlobStore.On("Put", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+	mockAttachmentRepo.On("CreateAttachment", ctx, mock.Anything).Return(nil).Once()
+	attachment, err := service.Upload(ctx, nil, "../../note.pdf", strings.NewReader(pdf))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Attachment{UploaderID: 3, FileName: "note.pdf", ContentType: "application/pdf",
+		Size: int64(len(pdf)), BlobKey: attachment.BlobKey}, attachment)
+
+	// the content type is sniffed, the name does not matter
+	_, err = service.Upload(ctx, nil, "note.pdf", strings.NewReader("just text"))
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	tooLarge := io.MultiReader(strings.NewReader(pdf), bytes.NewReader(make([]byte, domain.MaxSize)))
+	_, err = service.Upload(ctx, nil, "note.pdf", tooLarge)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// too many attachments waiting for a leave
+	mockAttachmentRepo.On("CountPending", ctx, 3).Return(domain.MaxPending, nil).Once()
+	_, err = service.Upload(ctx, nil, "note.pdf", strings.NewReader(pdf))
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// only the requester and HR add to a leave
+	mockLeaveRepo.On("GetLeaveByID", mock.Anything, leave.ID).Return(leave, nil).Once()
+	_, err = service.Upload(actorCtx(2, auth.RoleManager), &leave.ID, "note.pdf", strings.NewReader(pdf))
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestDownload(t *testing.T) {
+	attachment := domain.Attachment{ID: 5, LeaveID: &leave.ID, BlobKey: "key"}
+
+	for _, tt := range []struct {
+		name    string
+		ctx     context.Context
+		allowed bool
+	}{
+		{"requester", actorCtx(3, auth.RoleEmployee), true},
+		{"reviewer", actorCtx(2, auth.RoleManager), true},
+		{"delegating manager", actorCtx(4, auth.RoleManager), true},
+		{"hr", actorCtx(9, auth.RoleHRAdmin), true},
+		{"payroll", actorCtx(8, auth.RolePayroll), false},
+		{"teammate", actorCtx(5, auth.RoleEmployee), false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAttachmentRepo := mocks_attachment_repo.NewAttachmentRepo(t)
+			mockBlobStore := mocks_blob.NewBlobStore(t)
+			mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+			logger := common.NewLogger()
+
+			service := NewAttachmentService(logger, mockAttachmentRepo, mockBlobStore, mockLeaveRepo)
+
+			mockLeaveRepo.On("GetLeaveByID", tt.ctx, leave.ID).Return(leave, nil).Once()
+			if tt.allowed {
+				mockAttachmentRepo.On("GetAttachmentByID", tt.ctx, attachment.ID).Return(attachment, nil).Once()
+				mockBlobStore.On("Get", tt.ctx, "key").Return(io.NopCloser(strings.NewReader(pdf)), nil).Once()
+			}
+
+			_, content, err := service.Download(tt.ctx, leave.ID, attachment.ID)
+			if tt.allowed {
+				assert.NoError(t, err)
+				assert.NoError(t, content.Close())
+			} else {
+				assert.ErrorIs(t, err, common_errors.ErrForbidden)
+			}
+		})
+	}
+
+	t.Run("other leave", func(t *testing.T) {
+		mockAttachmentRepo := mocks_attachment_repo.NewAttachmentRepo(t)
+		mockBlobStore := mocks_blob.NewBlobStore(t)
+		mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+		logger := common.NewLogger()
+
+		service := NewAttachmentService(logger, mockAttachmentRepo, mockBlobStore, mockLeaveRepo)
+
+		ctx := actorCtx(3, auth.RoleEmployee)
+		mockLeaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
+		mockAttachmentRepo.On("GetAttachmentByID", ctx, 6).
+			Return(domain.Attachment{ID: 6, LeaveID: common.GetPtr(2)}, nil).Once()
+
+		_, _, err := service.Download(ctx, leave.ID, 6)
+		assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+	})
+}
+
+func TestCheckPending(t *testing.T) {
+	mockAttachmentRepo := mocks_attachment_repo.NewAttachmentRepo(t)
+	mockBlobStore := mocks_blob.NewBlobStore(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	logger := common.NewLogger()
+
+	service := NewAttachmentService(logger, mockAttachmentRepo, mockBlobStore, mockLeaveRepo)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+
+	mockAttachmentRepo.On("GetAttachmentsByIDs", ctx, []int{1, 2}).
+		Return([]domain.Attachment{{ID: 1, UploaderID: 3}, {ID: 2, UploaderID: 3}}, nil).Once()
+	assert.NoError(t, service.CheckPending(ctx, []int{1, 2}))
+
+	// submitted with another leave, uploaded by someone else or missing
+	mockAttachmentRepo.On("GetAttachmentsByIDs", ctx, []int{1, 2, 3}).
+		Return([]domain.Attachment{{ID: 1, UploaderID: 3, LeaveID: common.GetPtr(7)}, {ID: 2, UploaderID: 4}},
+			nil).Once()
+	assert.ErrorIs(t, service.CheckPending(ctx, []int{1, 2, 3}), common_errors.ErrInvalidInput)
+}
+
+func TestSweepPending(t *testing.T) {
+	mockAttachmentRepo := mocks_attachment_repo.NewAttachmentRepo(t)
+	mockBlobStore := mocks_blob.NewBlobStore(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	logger := common.NewLogger()
+
+	service := NewAttachmentService(logger, mockAttachmentRepo, mockBlobStore, mockLeaveRepo)
+
+	ctx := auth.AsSystem(context.Background())
+	mockAttachmentRepo.On("GetPendingBefore", ctx, mock.Anything).
+		Return([]domain.Attachment{{ID: 1, BlobKey: "a"}, {ID: 2, BlobKey: "b"}}, nil).Once()
+	mockAttachmentRepo.On("DeletePending", ctx, 1).Return(true, nil).Once()
+	mockBlobStore.On("Delete", ctx, "a").Return(nil).Once()
+	// submitted with a leave in the meantime
+	mockAttachmentRepo.On("DeletePending", ctx, 2).Return(false, nil).Once()
+
+	swept, err := service.SweepPending(ctx, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, swept)
+
+	_, err = service.SweepPending(actorCtx(3, auth.RoleEmployee), time.Hour)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
@@ -0,0 +1,38 @@
+package auth
+
+import "context"
+
+type Role string
+
+// Actor is the authenticated caller of a request
+type Actor struct {
+	EmployeeID int    `json:"employee_id"`
+	Roles      []Role `json:"roles"`
+	system     bool   // internal calls, e.g. background jobs, have every role
+}
+
+func (a Actor) HasRole(role Role) bool {
+	if a.system {
+		return true
+	}
+	for _, r := range a.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey string
+
+const actorKey contextKey = "Actor"
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromContext returns the actor of the request, false if the request is not authenticated
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey).(Actor)
+	return actor, ok
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	employee_service "hr-system/internal/employees/service"
+	"hr-system/internal/middleware"
+)
+
+type AuthHandler struct {
+	issuer          *auth.Issuer
+	employeeService employee_service.EmployeeService
+	logger          *common.Logger
+}
+
+func NewAuthHandler(logger *common.Logger, issuer *auth.Issuer,
+	employeeService employee_service.EmployeeService) *AuthHandler {
+	return &AuthHandler{
+		issuer:          issuer,
+		employeeService: employeeService,
+		logger:          logger,
+	}
+}
+
+type IssueDevTokenRequest struct {
+	EmployeeID int         `json:"employee_id" binding:"required"`
+	Roles      []auth.Role `json:"roles"`
+}
+
+type IssueDevTokenResponse struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// IssueDevToken signs a token for any employee, it must only be enabled in development
+func (h *AuthHandler) IssueDevToken(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req IssueDevTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	// nobody is authenticated yet
+	if _, err := h.employeeService.GetEmployeeByID(auth.AsSystem(ctx), req.EmployeeID); err != nil {
+		middleware.HandleErr(c, err, "get employee")
+		return
+	}
+
+	token, expiresAt, err := h.issuer.Issue(auth.Actor{EmployeeID: req.EmployeeID, Roles: req.Roles})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.CreateErrResp("failed to issue token, cause: %v", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, IssueDevTokenResponse{AccessToken: token, ExpiresAt: expiresAt})
+}
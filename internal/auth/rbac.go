@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	common_errors "hr-system/internal/common/errors"
+)
+
+const (
+	RoleEmployee Role = "employee"
+	// RoleManager sees the employees of their reporting subtree and their leaves
+	RoleManager Role = "manager"
+	// RoleHRAdmin sees and manages everything
+	RoleHRAdmin Role = "hr_admin"
+	// RolePayroll sees every employee with their salary
+	RolePayroll Role = "payroll"
+)
+
+func (a Actor) HasAnyRole(roles ...Role) bool {
+	for _, role := range roles {
+		if a.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanSeeSalary reports whether the actor can see the salary of the employees they can access
+func (a Actor) CanSeeSalary() bool {
+	return a.HasAnyRole(RoleHRAdmin, RolePayroll)
+}
+
+// AsSystem marks the context as an internal call which passes every access check
+func AsSystem(ctx context.Context) context.Context {
+	return WithActor(ctx, Actor{system: true})
+}
+
+// ManagerChainGetter returns the managers above the employee, the nearest first
+type ManagerChainGetter interface {
+	GetManagerChain(ctx context.Context, employeeID int) ([]int, error)
+}
+
+// Authorize returns the actor of the context if they have any of the roles, or just if they are authenticated
+// when no role is given
+func Authorize(ctx context.Context, roles ...Role) (Actor, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return Actor{}, fmt.Errorf("%w, unauthenticated", common_errors.ErrForbidden)
+	}
+	if len(roles) > 0 && !actor.HasAnyRole(roles...) {
+		return Actor{}, fmt.Errorf("%w, one of roles %v is required", common_errors.ErrForbidden, roles)
+	}
+	return actor, nil
+}
+
+// AuthorizeSelf returns the actor of the context if they are the employee or have any of the roles
+func AuthorizeSelf(ctx context.Context, employeeID int, roles ...Role) (Actor, error) {
+	actor, err := Authorize(ctx)
+	if err != nil {
+		return Actor{}, err
+	}
+	if actor.EmployeeID != employeeID && !actor.system && !actor.HasAnyRole(roles...) {
+		return Actor{}, fmt.Errorf("%w, employee %d can not act for employee %d",
+			common_errors.ErrForbidden, actor.EmployeeID, employeeID)
+	}
+	return actor, nil
+}
+
+// AuthorizeEmployee returns the actor of the context if they can access the data of the employee:
+// the employee themselves, HR and payroll, and the managers above the employee
+func AuthorizeEmployee(ctx context.Context, chains ManagerChainGetter, employeeID int) (Actor, error) {
+	actor, err := Authorize(ctx)
+	if err != nil {
+		return Actor{}, err
+	}
+	if actor.EmployeeID == employeeID || actor.HasAnyRole(RoleHRAdmin, RolePayroll) {
+		return actor, nil
+	}
+
+	if actor.HasRole(RoleManager) {
+		managerIDs, err := chains.GetManagerChain(ctx, employeeID)
+		if err != nil {
+			return Actor{}, fmt.Errorf("failed to get managers of employee %d: %w", employeeID, err)
+		}
+		for _, managerID := range managerIDs {
+			if managerID == actor.EmployeeID {
+				return actor, nil
+			}
+		}
+	}
+
+	return Actor{}, fmt.Errorf("%w, employee %d can not access employee %d",
+		common_errors.ErrForbidden, actor.EmployeeID, employeeID)
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	common_errors "hr-system/internal/common/errors"
+)
+
+type fakeChains map[int][]int
+
+func (f fakeChains) GetManagerChain(_ context.Context, employeeID int) ([]int, error) {
+	return f[employeeID], nil
+}
+
+func TestAuthorize(t *testing.T) {
+	_, err := Authorize(context.Background())
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+
+	ctx := WithActor(context.Background(), Actor{EmployeeID: 1, Roles: []Role{RoleEmployee}})
+	_, err = Authorize(ctx)
+	assert.NoError(t, err)
+	_, err = Authorize(ctx, RoleHRAdmin)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+
+	_, err = AuthorizeSelf(ctx, 1)
+	assert.NoError(t, err)
+	_, err = AuthorizeSelf(ctx, 2)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+
+	// internal calls pass every check
+	_, err = AuthorizeSelf(AsSystem(context.Background()), 2)
+	assert.NoError(t, err)
+}
+
+func TestAuthorizeEmployee(t *testing.T) {
+	chains := fakeChains{3: {2, 1}}
+	cases := []struct {
+		name  string
+		actor Actor
+		ok    bool
+	}{
+		{"self", Actor{EmployeeID: 3, Roles: []Role{RoleEmployee}}, true},
+		{"manager above", Actor{EmployeeID: 1, Roles: []Role{RoleManager}}, true},
+		{"manager elsewhere", Actor{EmployeeID: 4, Roles: []Role{RoleManager}}, false},
+		{"above without the manager role", Actor{EmployeeID: 2, Roles: []Role{RoleEmployee}}, false},
+		{"hr", Actor{EmployeeID: 5, Roles: []Role{RoleHRAdmin}}, true},
+		{"payroll", Actor{EmployeeID: 6, Roles: []Role{RolePayroll}}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := AuthorizeEmployee(WithActor(context.Background(), c.actor), chains, 3)
+			if c.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, common_errors.ErrForbidden)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the claims of the access tokens, the subject is the employee ID
+type Claims struct {
+	Roles []Role `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+// Verifier validates HS256 tokens with the shared secret and RS256 tokens with the public key,
+// the tokens of an algorithm without a configured key are rejected
+type Verifier struct {
+	secret    []byte
+	publicKey *rsa.PublicKey
+	methods   []string
+}
+
+func NewVerifier(secret []byte, publicKey *rsa.PublicKey) (*Verifier, error) {
+	v := &Verifier{
+		secret:    secret,
+		publicKey: publicKey,
+	}
+	if len(secret) > 0 {
+		v.methods = append(v.methods, jwt.SigningMethodHS256.Alg())
+	}
+	if publicKey != nil {
+		v.methods = append(v.methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(v.methods) == 0 {
+		return nil, fmt.Errorf("either HS256 secret or RS256 public key is required")
+	}
+	return v, nil
+}
+
+// ParseRSAPublicKey parses a PEM encoded RSA public key
+func ParseRSAPublicKey(pem []byte) (*rsa.PublicKey, error) {
+	return jwt.ParseRSAPublicKeyFromPEM(pem)
+}
+
+func (v *Verifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return v.secret, nil
+	case *jwt.SigningMethodRSA:
+		return v.publicKey, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+// Verify validates the token and returns its actor
+func (v *Verifier) Verify(tokenString string) (Actor, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, v.keyFunc,
+		jwt.WithValidMethods(v.methods), jwt.WithExpirationRequired())
+	if err != nil {
+		return Actor{}, fmt.Errorf("%w, cause: %s", ErrInvalidToken, err)
+	}
+
+	employeeID, err := strconv.Atoi(claims.Subject)
+	if err != nil || employeeID <= 0 {
+		return Actor{}, fmt.Errorf("%w, subject must be an employee ID", ErrInvalidToken)
+	}
+
+	return Actor{EmployeeID: employeeID, Roles: claims.Roles}, nil
+}
+
+// Issuer signs HS256 access tokens, it's meant for development since there is no identity provider
+type Issuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewIssuer(secret []byte, ttl time.Duration) *Issuer {
+	return &Issuer{
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+func (i *Issuer) Issue(actor Actor) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(i.ttl)
+	claims := Claims{
+		Roles: actor.Roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(actor.EmployeeID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+	return token, expiresAt, nil
+}
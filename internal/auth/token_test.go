@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify_HS256(t *testing.T) {
+	secret := []byte("secret")
+	verifier, err := NewVerifier(secret, nil)
+	assert.NoError(t, err)
+
+	token, _, err := NewIssuer(secret, time.Hour).Issue(Actor{EmployeeID: 3, Roles: []Role{"manager"}})
+	assert.NoError(t, err)
+
+	actor, err := verifier.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, Actor{EmployeeID: 3, Roles: []Role{"manager"}}, actor)
+	assert.True(t, actor.HasRole("manager"))
+
+	// signed by another secret
+	token, _, err = NewIssuer([]byte("other"), time.Hour).Issue(Actor{EmployeeID: 3})
+	assert.NoError(t, err)
+	_, err = verifier.Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// expired
+	token, _, err = NewIssuer(secret, -time.Minute).Issue(Actor{EmployeeID: 3})
+	assert.NoError(t, err)
+	_, err = verifier.Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestVerify_RS256(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "5",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(privateKey)
+	assert.NoError(t, err)
+
+	verifier, err := NewVerifier(nil, &privateKey.PublicKey)
+	assert.NoError(t, err)
+	actor, err := verifier.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, actor.EmployeeID)
+
+	// HS256 is rejected without a secret
+	hsToken, _, err := NewIssuer([]byte("secret"), time.Hour).Issue(Actor{EmployeeID: 5})
+	assert.NoError(t, err)
+	_, err = verifier.Verify(hsToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewVerifier_NoKey(t *testing.T) {
+	_, err := NewVerifier(nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	employee_domain "hr-system/internal/employees/domain"
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+// CarryOverExpiryMonth is the last month the days carried over from the previous year can be taken in,
+// the days left at its end expire
+const CarryOverExpiryMonth = time.March
+
+// AccrualRule is how many days of the leave type the employees of the position level accrue a year, month by month,
+// instead of the yearly entitlement. The rule of the empty level applies to the levels without their own rule.
+type AccrualRule struct {
+	LeaveType     leave_domain.LeaveType `json:"leave_type" gorm:"primaryKey;type:varchar(50)" validate:"required,max=50"`
+	PositionLevel string                 `json:"position_level" gorm:"primaryKey;type:varchar(50)" validate:"max=50"`
+	DaysPerYear   float64                `json:"days_per_year" gorm:"type:decimal(6,2);not null" validate:"gt=0,lte=366"`
+	// MaxCarryOverDays is how many of the days left at the end of a year go into the next one
+	MaxCarryOverDays float64   `json:"max_carry_over_days" gorm:"type:decimal(6,2);not null" validate:"gte=0,lte=366"`
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// FindAccrualRule returns the rule of the leave type for the position level, falling back to the rule of the empty
+// level, and nil if the leave type is not accrued at the level
+func FindAccrualRule(rules []AccrualRule, leaveType leave_domain.LeaveType, level string) *AccrualRule {
+	var fallback *AccrualRule
+	for i := range rules {
+		if rules[i].LeaveType != leaveType {
+			continue
+		}
+		if rules[i].PositionLevel == level {
+			return &rules[i]
+		}
+		if rules[i].PositionLevel == "" {
+			fallback = &rules[i]
+		}
+	}
+	return fallback
+}
+
+// Period is a month the accrual engine runs for
+type Period struct {
+	Year  int
+	Month time.Month
+}
+
+// ParsePeriod parses a period in the format of 2006-01
+func ParsePeriod(s string) (Period, error) {
+	t, err := time.Parse("2006-01", s)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid period %q, expected YYYY-MM", s)
+	}
+	return Period{Year: t.Year(), Month: t.Month()}, nil
+}
+
+// PeriodOf returns the period the time is in
+func PeriodOf(t time.Time) Period {
+	return Period{Year: t.Year(), Month: t.Month()}
+}
+
+func (p Period) String() string {
+	return fmt.Sprintf("%04d-%02d", p.Year, p.Month)
+}
+
+// Start returns the first day of the period
+func (p Period) Start() time.Time {
+	return time.Date(p.Year, p.Month, 1, 0, 0, 0, 0, time.Local)
+}
+
+// End returns the first day after the period
+func (p Period) End() time.Time {
+	return p.Start().AddDate(0, 1, 0)
+}
+
+// Previous returns the period before
+func (p Period) Previous() Period {
+	return PeriodOf(p.Start().AddDate(0, -1, 0))
+}
+
+func (p Period) Validate() error {
+	if p.Year < 1 || p.Month < time.January || p.Month > time.December {
+		return fmt.Errorf("invalid period %s", p)
+	}
+	return nil
+}
+
+// AccrualRun is the result of running the accrual engine for a period
+type AccrualRun struct {
+	Period    string `json:"period"`
+	Employees int    `json:"employees"` // the employees with any entry in the period
+	// Entries is the number of entries written by the run, running a period again writes only the missing ones
+	Entries int `json:"entries"`
+}
+
+// accruedDays returns the days the employee accrues from the start of the year of the period to its end,
+// each day the employee is in a position accrues its share of the month by the rule of the level of the position
+func accruedDays(employee *employee_domain.Employee, rules []AccrualRule, leaveType leave_domain.LeaveType,
+	period Period) float64 {
+	var days float64
+	for month := (Period{Year: period.Year, Month: time.January}); month.Month <= period.Month; month.Month++ {
+		start, end := month.Start(), month.End()
+		daysInMonth := float64(end.AddDate(0, 0, -1).Day())
+		for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+			position := employee.PositionAt(d)
+			if position.StartDate.IsZero() || employee.TerminatedAt(d) {
+				continue
+			}
+			if rule := FindAccrualRule(rules, leaveType, position.Level); rule != nil {
+				days += rule.DaysPerYear / 12 / daysInMonth
+			}
+		}
+	}
+	return days
+}
+
+// MonthlyAccrual returns the days of the leave type the employee accrues in the period, pro-rated from the start date
+// of the employee. The accruals are rounded on the running total of the year, so the months of a full year add up to
+// the days of the rule.
+func MonthlyAccrual(employee *employee_domain.Employee, rules []AccrualRule, leaveType leave_domain.LeaveType,
+	period Period) float64 {
+	total := roundDays(accruedDays(employee, rules, leaveType, period))
+	if period.Month == time.January {
+		return total
+	}
+	return roundDays(total - roundDays(accruedDays(employee, rules, leaveType, period.Previous())))
+}
+
+// CarryOver returns how many of the days left at the end of a year go into the next one
+func CarryOver(rule *AccrualRule, remaining float64) float64 {
+	if rule == nil || remaining <= 0 {
+		return 0
+	}
+	return roundDays(math.Min(remaining, rule.MaxCarryOverDays))
+}
+
+// ExpiredCarryOver returns the days carried over into the year which were not taken by the end of
+// CarryOverExpiryMonth, the carried days are taken before the other days of the year.
+// The entries are the ones of the leave type in the year.
+func ExpiredCarryOver(entries []LedgerEntry, year int) float64 {
+	deadline := Period{Year: year, Month: CarryOverExpiryMonth}.End()
+	var carried, taken float64
+	for _, entry := range entries {
+		switch entry.Type {
+		case EntryTypeCarryOver:
+			carried += entry.Days
+		case EntryTypeDebit, EntryTypeCredit, EntryTypeShortening:
+			// the entries written before the leaves were dated count as taken in time
+			if entry.Date == nil || entry.Date.Before(deadline) {
+				taken -= entry.Days
+			}
+		}
+	}
+	if carried <= taken {
+		return 0
+	}
+	return roundDays(carried - taken)
+}
+
+func roundDays(days float64) float64 {
+	return math.Round(days*100) / 100
+}
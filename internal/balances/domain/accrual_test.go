@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"hr-system/internal/common"
+	employee_domain "hr-system/internal/employees/domain"
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+func TestMonthlyAccrual(t *testing.T) {
+	rules := []AccrualRule{
+		{LeaveType: leave_domain.LeaveTypeAnnual, DaysPerYear: 14, MaxCarryOverDays: 5},
+		{LeaveType: leave_domain.LeaveTypeAnnual, PositionLevel: "L5", DaysPerYear: 20, MaxCarryOverDays: 5},
+	}
+	employee := employee_domain.Employee{ID: 1, Positions: []employee_domain.Position{
+		{Title: "Engineer", Level: "L3", StartDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)},
+	}}
+
+	// the months of a full year add up to the days of the rule
+	var total float64
+	for month := time.January; month <= time.December; month++ {
+		total += MonthlyAccrual(&employee, rules, leave_domain.LeaveTypeAnnual, Period{Year: 2024, Month: month})
+	}
+	assert.InDelta(t, 14, total, 0.001)
+	assert.Equal(t, 1.17, MonthlyAccrual(&employee, rules, leave_domain.LeaveTypeAnnual, Period{2024, time.January}))
+	assert.Equal(t, 1.16, MonthlyAccrual(&employee, rules, leave_domain.LeaveTypeAnnual, Period{2024, time.February}))
+
+	// pro-rated from the start date
+	employee.Positions[0].StartDate = time.Date(2024, 6, 16, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, 0.0, MonthlyAccrual(&employee, rules, leave_domain.LeaveTypeAnnual, Period{2024, time.May}))
+	assert.Equal(t, 0.58, MonthlyAccrual(&employee, rules, leave_domain.LeaveTypeAnnual, Period{2024, time.June}))
+
+	// until the last day
+	employee.TerminationDate = common.GetPtr(time.Date(2024, 7, 15, 0, 0, 0, 0, time.Local))
+	assert.Equal(t, 0.57, MonthlyAccrual(&employee, rules, leave_domain.LeaveTypeAnnual, Period{2024, time.July}))
+	assert.Equal(t, 0.0, MonthlyAccrual(&employee, rules, leave_domain.LeaveTypeAnnual, Period{2024, time.August}))
+
+	// by the rule of the level
+	employee.TerminationDate = nil
+	employee.Positions[0].Level = "L5"
+	assert.Equal(t, 1.67, MonthlyAccrual(&employee, rules, leave_domain.LeaveTypeAnnual, Period{2024, time.July}))
+
+	// not accrued
+	assert.Equal(t, 0.0, MonthlyAccrual(&employee, rules, leave_domain.LeaveTypeSick, Period{2024, time.July}))
+}
+
+func TestCarryOver(t *testing.T) {
+	rule := &AccrualRule{LeaveType: leave_domain.LeaveTypeAnnual, DaysPerYear: 14, MaxCarryOverDays: 5}
+
+	assert.Equal(t, 5.0, CarryOver(rule, 8.5))
+	assert.Equal(t, 2.5, CarryOver(rule, 2.5))
+	assert.Equal(t, 0.0, CarryOver(rule, -1))
+	assert.Equal(t, 0.0, CarryOver(nil, 3))
+}
+
+func TestExpiredCarryOver(t *testing.T) {
+	inQ1 := time.Date(2025, 3, 31, 0, 0, 0, 0, time.Local)
+	afterQ1 := time.Date(2025, 4, 1, 0, 0, 0, 0, time.Local)
+	entries := []LedgerEntry{
+		{Type: EntryTypeCarryOver, Days: 5},
+		{Type: EntryTypeAccrual, Days: 1.17},
+		{Type: EntryTypeDebit, Days: -2, Date: &inQ1},
+		{Type: EntryTypeDebit, Days: -1, Date: &afterQ1},
+	}
+	assert.Equal(t, 3.0, ExpiredCarryOver(entries, 2025))
+
+	// a leave taken back gives the days back
+	entries = append(entries, LedgerEntry{Type: EntryTypeCredit, Days: 2, Date: &inQ1})
+	assert.Equal(t, 5.0, ExpiredCarryOver(entries, 2025))
+
+	// every carried day taken
+	entries = append(entries, LedgerEntry{Type: EntryTypeDebit, Days: -6, Date: &inQ1})
+	assert.Equal(t, 0.0, ExpiredCarryOver(entries, 2025))
+}
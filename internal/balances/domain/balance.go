@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+type EntryType string
+
+var (
+	EntryTypeEntitlement EntryType = "entitlement"
+	EntryTypeDebit       EntryType = "debit"
+	EntryTypeCredit      EntryType = "credit"
+	// EntryTypeAccrual is the days accrued in a month, in place of the yearly entitlement of an accrued leave type
+	EntryTypeAccrual EntryType = "accrual"
+	// EntryTypeCarryOver is the days left at the end of the previous year
+	EntryTypeCarryOver EntryType = "carry_over"
+	// EntryTypeExpiry takes back the carried over days not taken in time
+	EntryTypeExpiry EntryType = "expiry"
+	// EntryTypeShortening gives back the debited days of a leave cut short by the last day of its employee
+	EntryTypeShortening EntryType = "shortening"
+)
+
+// Entitlement is the number of days every employee gets for a leave type each year
+type Entitlement struct {
+	LeaveType leave_domain.LeaveType `json:"leave_type" gorm:"primaryKey;type:varchar(50)" validate:"required,max=50"`
+	Days      float64                `json:"days" gorm:"type:decimal(6,2);not null" validate:"gte=0"`
+}
+
+// LedgerEntry is a change of the balance of an employee, the balance is the sum of Days of all entries.
+// Entitlements, accruals, carry-overs, credits and shortenings are positive, debits and expiries are negative.
+type LedgerEntry struct {
+	ID         int                    `json:"id" gorm:"primaryKey;autoIncrement"`
+	EmployeeID int                    `json:"employee_id" gorm:"index:idx_employee_year;uniqueIndex:idx_period_entry;not null"`
+	Year       int                    `json:"year" gorm:"index:idx_employee_year;uniqueIndex:idx_leave_year_entry;not null"`
+	LeaveType  leave_domain.LeaveType `json:"leave_type" gorm:"type:varchar(50);not null;uniqueIndex:idx_period_entry"`
+	Type       EntryType              `json:"type" gorm:"type:varchar(50);not null;uniqueIndex:idx_leave_year_entry;uniqueIndex:idx_period_entry"`
+	Days       float64                `json:"days" gorm:"type:decimal(6,2);not null"`
+	// nil for entries not caused by a leave, a leave spanning the turn of a year has an entry in each year
+	LeaveID *int `json:"leave_id,omitempty" gorm:"uniqueIndex:idx_leave_year_entry"`
+	// Period is the month of an accrual or the year of an entitlement, a carry-over or an expiry,
+	// an entry is written once per period
+	Period *string `json:"period,omitempty" gorm:"type:varchar(7);uniqueIndex:idx_period_entry"`
+	// Date is the day the entry takes effect, the start date of the leave in the year of a debit or a credit
+	Date      *time.Time `json:"date,omitempty" gorm:"type:date"`
+	Note      string     `json:"note" gorm:"type:varchar(255)"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+type Balance struct {
+	LeaveType leave_domain.LeaveType `json:"leave_type"`
+	Year      int                    `json:"year"`
+	Entitled  float64                `json:"entitled"` // the entitlement or the days accrued so far
+	// CarriedOver is the days left from the previous year, Expired is the ones of them not taken in time
+	CarriedOver float64 `json:"carried_over"`
+	Expired     float64 `json:"expired"`
+	Used        float64 `json:"used"`
+	Remaining   float64 `json:"remaining"`
+}
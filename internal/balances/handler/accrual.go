@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/balances/domain"
+	"hr-system/internal/balances/service"
+	"hr-system/internal/common"
+	leave_domain "hr-system/internal/leaves/domain"
+	"hr-system/internal/middleware"
+)
+
+type AccrualHandler struct {
+	accrualService service.AccrualService
+	logger         *common.Logger
+}
+
+func NewAccrualHandler(logger *common.Logger, accrualService service.AccrualService) *AccrualHandler {
+	return &AccrualHandler{
+		accrualService: accrualService,
+		logger:         logger,
+	}
+}
+
+type AccrualRuleRequest struct {
+	LeaveType        string  `json:"leave_type" binding:"required"`
+	PositionLevel    string  `json:"position_level"` // empty for the levels without their own rule
+	DaysPerYear      float64 `json:"days_per_year" binding:"required"`
+	MaxCarryOverDays float64 `json:"max_carry_over_days"`
+}
+
+func (h *AccrualHandler) GetRules(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	rules, err := h.accrualService.GetRules(ctx)
+	if err != nil {
+		middleware.HandleErr(c, err, "get accrual rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *AccrualHandler) SetRule(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req AccrualRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	rule := &domain.AccrualRule{
+		LeaveType:        leave_domain.LeaveType(req.LeaveType),
+		PositionLevel:    req.PositionLevel,
+		DaysPerYear:      req.DaysPerYear,
+		MaxCarryOverDays: req.MaxCarryOverDays,
+	}
+	if err := h.accrualService.SetRule(ctx, rule); err != nil {
+		middleware.HandleErr(c, err, "set accrual rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *AccrualHandler) DeleteRule(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	leaveType := c.Query("leave_type")
+	if leaveType == "" {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("leave_type is required"))
+		return
+	}
+
+	err := h.accrualService.DeleteRule(ctx, leave_domain.LeaveType(leaveType), c.Query("position_level"))
+	if err != nil {
+		middleware.HandleErr(c, err, "delete accrual rule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/balances/domain"
+	"hr-system/internal/balances/service"
+	"hr-system/internal/common"
+	leave_domain "hr-system/internal/leaves/domain"
+	"hr-system/internal/middleware"
+)
+
+type EntitlementHandler struct {
+	entitlementService service.EntitlementService
+	logger             *common.Logger
+}
+
+func NewEntitlementHandler(logger *common.Logger, entitlementService service.EntitlementService) *EntitlementHandler {
+	return &EntitlementHandler{
+		entitlementService: entitlementService,
+		logger:             logger,
+	}
+}
+
+type EntitlementRequest struct {
+	LeaveType string   `json:"leave_type" binding:"required"`
+	Days      *float64 `json:"days" binding:"required"`
+}
+
+func (h *EntitlementHandler) GetEntitlements(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	entitlements, err := h.entitlementService.GetEntitlements(ctx)
+	if err != nil {
+		middleware.HandleErr(c, err, "get entitlements")
+		return
+	}
+
+	c.JSON(http.StatusOK, entitlements)
+}
+
+func (h *EntitlementHandler) SetEntitlement(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req EntitlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	entitlement := &domain.Entitlement{
+		LeaveType: leave_domain.LeaveType(req.LeaveType),
+		Days:      *req.Days,
+	}
+	if err := h.entitlementService.SetEntitlement(ctx, entitlement); err != nil {
+		middleware.HandleErr(c, err, "set entitlement")
+		return
+	}
+
+	c.JSON(http.StatusOK, entitlement)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/balances/service"
+	"hr-system/internal/common"
+	"hr-system/internal/middleware"
+)
+
+type BalanceHandler struct {
+	balanceService service.BalanceService
+	logger         *common.Logger
+}
+
+func NewBalanceHandler(logger *common.Logger, balanceService service.BalanceService) *BalanceHandler {
+	return &BalanceHandler{
+		balanceService: balanceService,
+		logger:         logger,
+	}
+}
+
+func (h *BalanceHandler) GetLeaveBalances(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid employee ID"))
+		return
+	}
+
+	year := time.Now().Year()
+	if y := c.Query("year"); y != "" {
+		year, err = strconv.Atoi(y)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid year"))
+			return
+		}
+	}
+
+	balances, err := h.balanceService.GetBalances(ctx, employeeID, year)
+	if err != nil {
+		middleware.HandleErr(c, err, "get leave balances")
+		return
+	}
+
+	c.JSON(http.StatusOK, balances)
+}
@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm/clause"
+
+	"hr-system/internal/balances/domain"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/common/transaction"
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+func (r *balanceRepo) GetAccrualRules(ctx context.Context) ([]domain.AccrualRule, error) {
+	var rules []domain.AccrualRule
+	if err := transaction.DB(ctx, r.db).Order("leave_type, position_level").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to get accrual rules: %w", err)
+	}
+	return rules, nil
+}
+
+// SaveAccrualRule creates the rule of the leave type and position level or replaces it
+func (r *balanceRepo) SaveAccrualRule(ctx context.Context, rule *domain.AccrualRule) error {
+	err := transaction.DB(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "leave_type"}, {Name: "position_level"}},
+		DoUpdates: clause.AssignmentColumns([]string{"days_per_year", "max_carry_over_days", "updated_at"}),
+	}).Create(rule).Error
+	if err != nil {
+		return fmt.Errorf("failed to save accrual rule: %w", err)
+	}
+	return nil
+}
+
+func (r *balanceRepo) DeleteAccrualRule(ctx context.Context, leaveType leave_domain.LeaveType, level string) error {
+	result := transaction.DB(ctx, r.db).
+		Where("leave_type = ? AND position_level = ?", leaveType, level).
+		Delete(&domain.AccrualRule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete accrual rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return common_errors.ErrResourceNotFound
+	}
+	return nil
+}
+
+// CreatePeriodEntries creates the entries of a period which have not been written yet,
+// it returns the number of entries created
+func (r *balanceRepo) CreatePeriodEntries(ctx context.Context, entries []domain.LedgerEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	result := transaction.DB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&entries)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to create period entries: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
@@ -0,0 +1,268 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/balances/domain"
+	leavesdomain "hr-system/internal/leaves/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BalanceRepo is an autogenerated mock type for the BalanceRepo type
+type BalanceRepo struct {
+	mock.Mock
+}
+
+// CreateEntries provides a mock function with given fields: ctx, entries
+func (_m *BalanceRepo) CreateEntries(ctx context.Context, entries []domain.LedgerEntry) error {
+	ret := _m.Called(ctx, entries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEntries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.LedgerEntry) error); ok {
+		r0 = rf(ctx, entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreatePeriodEntries provides a mock function with given fields: ctx, entries
+func (_m *BalanceRepo) CreatePeriodEntries(ctx context.Context, entries []domain.LedgerEntry) (int, error) {
+	ret := _m.Called(ctx, entries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePeriodEntries")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.LedgerEntry) (int, error)); ok {
+		return rf(ctx, entries)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.LedgerEntry) int); ok {
+		r0 = rf(ctx, entries)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.LedgerEntry) error); ok {
+		r1 = rf(ctx, entries)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAccrualRule provides a mock function with given fields: ctx, leaveType, level
+func (_m *BalanceRepo) DeleteAccrualRule(ctx context.Context, leaveType leavesdomain.LeaveType, level string) error {
+	ret := _m.Called(ctx, leaveType, level)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccrualRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, leavesdomain.LeaveType, string) error); ok {
+		r0 = rf(ctx, leaveType, level)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAccrualRules provides a mock function with given fields: ctx
+func (_m *BalanceRepo) GetAccrualRules(ctx context.Context) ([]domain.AccrualRule, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccrualRules")
+	}
+
+	var r0 []domain.AccrualRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.AccrualRule, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.AccrualRule); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AccrualRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEntitlements provides a mock function with given fields: ctx
+func (_m *BalanceRepo) GetEntitlements(ctx context.Context) ([]domain.Entitlement, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEntitlements")
+	}
+
+	var r0 []domain.Entitlement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Entitlement, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Entitlement); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Entitlement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEntries provides a mock function with given fields: ctx, employeeID, year
+func (_m *BalanceRepo) GetEntries(ctx context.Context, employeeID int, year int) ([]domain.LedgerEntry, error) {
+	ret := _m.Called(ctx, employeeID, year)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEntries")
+	}
+
+	var r0 []domain.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.LedgerEntry, error)); ok {
+		return rf(ctx, employeeID, year)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.LedgerEntry); ok {
+		r0 = rf(ctx, employeeID, year)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, employeeID, year)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEntriesByLeaveID provides a mock function with given fields: ctx, leaveID
+func (_m *BalanceRepo) GetEntriesByLeaveID(ctx context.Context, leaveID int) ([]domain.LedgerEntry, error) {
+	ret := _m.Called(ctx, leaveID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEntriesByLeaveID")
+	}
+
+	var r0 []domain.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.LedgerEntry, error)); ok {
+		return rf(ctx, leaveID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.LedgerEntry); ok {
+		r0 = rf(ctx, leaveID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, leaveID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveAccrualRule provides a mock function with given fields: ctx, rule
+func (_m *BalanceRepo) SaveAccrualRule(ctx context.Context, rule *domain.AccrualRule) error {
+	ret := _m.Called(ctx, rule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAccrualRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AccrualRule) error); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveEntitlement provides a mock function with given fields: ctx, entitlement
+func (_m *BalanceRepo) SaveEntitlement(ctx context.Context, entitlement *domain.Entitlement) error {
+	ret := _m.Called(ctx, entitlement)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveEntitlement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Entitlement) error); ok {
+		r0 = rf(ctx, entitlement)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SeedData provides a mock function with given fields: ctx
+func (_m *BalanceRepo) SeedData(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SeedData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBalanceRepo creates a new instance of BalanceRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBalanceRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BalanceRepo {
+	mock := &BalanceRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
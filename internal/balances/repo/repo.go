@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"hr-system/internal/balances/domain"
+	"hr-system/internal/common/transaction"
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+type BalanceRepo interface {
+	SeedData(ctx context.Context) error
+	GetEntitlements(ctx context.Context) ([]domain.Entitlement, error)
+	// SaveEntitlement creates the entitlement of the leave type or replaces it
+	SaveEntitlement(ctx context.Context, entitlement *domain.Entitlement) error
+	GetEntries(ctx context.Context, employeeID, year int) ([]domain.LedgerEntry, error)
+	GetEntriesByLeaveID(ctx context.Context, leaveID int) ([]domain.LedgerEntry, error)
+	CreateEntries(ctx context.Context, entries []domain.LedgerEntry) error
+	GetAccrualRules(ctx context.Context) ([]domain.AccrualRule, error)
+	SaveAccrualRule(ctx context.Context, rule *domain.AccrualRule) error
+	DeleteAccrualRule(ctx context.Context, leaveType leave_domain.LeaveType, level string) error
+	CreatePeriodEntries(ctx context.Context, entries []domain.LedgerEntry) (int, error)
+}
+
+type balanceRepo struct {
+	db *gorm.DB
+}
+
+func NewBalanceRepo(db *gorm.DB) (BalanceRepo, error) {
+	repo := &balanceRepo{
+		db: db,
+	}
+
+	if err := repo.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *balanceRepo) ensureSchema() error {
+	// AutoMigrate
+	if err := r.db.AutoMigrate(domain.Entitlement{}); err != nil {
+		return err
+	}
+	if err := r.db.AutoMigrate(domain.LedgerEntry{}); err != nil {
+		return err
+	}
+	// a leave had a single debit before its days were split by year
+	if r.db.Migrator().HasIndex(&domain.LedgerEntry{}, "idx_leave_entry") {
+		if err := r.db.Migrator().DropIndex(&domain.LedgerEntry{}, "idx_leave_entry"); err != nil {
+			return err
+		}
+	}
+	if err := r.keyEntitlements(); err != nil {
+		return err
+	}
+	if err := r.db.AutoMigrate(domain.AccrualRule{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// keyEntitlements sets the year as the period of the entitlements granted before they had one,
+// a second grant of the same year is a duplicate of a concurrent first access and is removed
+func (r *balanceRepo) keyEntitlements() error {
+	var entries []domain.LedgerEntry
+	err := r.db.Where("type = ? AND period IS NULL", domain.EntryTypeEntitlement).Order("id ASC").Find(&entries).Error
+	if err != nil {
+		return fmt.Errorf("failed to get entitlements without period: %w", err)
+	}
+
+	return transaction.Run(context.Background(), r.db, func(tx *gorm.DB) error {
+		granted := make(map[string]bool)
+		for _, entry := range entries {
+			period := strconv.Itoa(entry.Year)
+			key := fmt.Sprintf("%d/%s/%s", entry.EmployeeID, entry.LeaveType, period)
+			if granted[key] {
+				if err := tx.Delete(&domain.LedgerEntry{}, entry.ID).Error; err != nil {
+					return fmt.Errorf("failed to delete duplicate entitlement %d: %w", entry.ID, err)
+				}
+				continue
+			}
+			granted[key] = true
+			if err := tx.Model(&entry).Update("period", period).Error; err != nil {
+				return fmt.Errorf("failed to set period of entitlement %d: %w", entry.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *balanceRepo) GetEntitlements(ctx context.Context) ([]domain.Entitlement, error) {
+	var entitlements []domain.Entitlement
+	if err := transaction.DB(ctx, r.db).Order("leave_type").Find(&entitlements).Error; err != nil {
+		return nil, fmt.Errorf("failed to get entitlements: %w", err)
+	}
+	return entitlements, nil
+}
+
+func (r *balanceRepo) SaveEntitlement(ctx context.Context, entitlement *domain.Entitlement) error {
+	err := transaction.DB(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "leave_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"days"}),
+	}).Create(entitlement).Error
+	if err != nil {
+		return fmt.Errorf("failed to save entitlement: %w", err)
+	}
+	return nil
+}
+
+func (r *balanceRepo) GetEntries(ctx context.Context, employeeID, year int) ([]domain.LedgerEntry, error) {
+	var entries []domain.LedgerEntry
+	err := transaction.DB(ctx, r.db).
+		Where("employee_id = ? AND year = ?", employeeID, year).
+		Order("id ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *balanceRepo) GetEntriesByLeaveID(ctx context.Context, leaveID int) ([]domain.LedgerEntry, error) {
+	var entries []domain.LedgerEntry
+	if err := transaction.DB(ctx, r.db).Where("leave_id = ?", leaveID).Order("id ASC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get ledger entries of leave %d: %w", leaveID, err)
+	}
+	return entries, nil
+}
+
+func (r *balanceRepo) CreateEntries(ctx context.Context, entries []domain.LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := transaction.DB(ctx, r.db).Create(&entries).Error; err != nil {
+		return fmt.Errorf("failed to create ledger entries: %w", err)
+	}
+	return nil
+}
+
+func (r *balanceRepo) SeedData(ctx context.Context) error {
+	if err := r.SeedEntitlements(ctx); err != nil {
+		return err
+	}
+	return r.SeedAccrualRules(ctx)
+}
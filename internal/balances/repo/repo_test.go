@@ -0,0 +1,173 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"hr-system/internal/balances/domain"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+func setupTestRepo(t *testing.T) *balanceRepo {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := &balanceRepo{db: db}
+	assert.NoError(t, repo.ensureSchema())
+	return repo
+}
+
+func TestSeedEntitlements(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	// seeding twice keeps a single entitlement per leave type
+	assert.NoError(t, repo.SeedData(ctx))
+	assert.NoError(t, repo.SeedData(ctx))
+
+	entitlements, err := repo.GetEntitlements(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, entitlements, 2)
+}
+
+func TestSaveEntitlement(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	assert.NoError(t, repo.SeedData(ctx))
+
+	// a new leave type gets an entitlement, an existing one is replaced
+	assert.NoError(t, repo.SaveEntitlement(ctx, &domain.Entitlement{LeaveType: "bereavement", Days: 3}))
+	assert.NoError(t, repo.SaveEntitlement(ctx, &domain.Entitlement{LeaveType: leave_domain.LeaveTypeAnnual, Days: 15}))
+
+	entitlements, err := repo.GetEntitlements(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, entitlements, 3)
+	days := make(map[leave_domain.LeaveType]float64)
+	for _, entitlement := range entitlements {
+		days[entitlement.LeaveType] = entitlement.Days
+	}
+	assert.Equal(t, 3.0, days["bereavement"])
+	assert.Equal(t, 15.0, days[leave_domain.LeaveTypeAnnual])
+}
+
+func TestCreateAndGetEntries(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	entries := []domain.LedgerEntry{
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeEntitlement, Days: 14},
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeDebit, Days: -2,
+			LeaveID: common.GetPtr(7)},
+		{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeEntitlement, Days: 14},
+		{EmployeeID: 2, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeEntitlement, Days: 14},
+	}
+	assert.NoError(t, repo.CreateEntries(ctx, entries))
+
+	fetched, err := repo.GetEntries(ctx, 1, 2024)
+	assert.NoError(t, err)
+	assert.Len(t, fetched, 2)
+
+	fetched, err = repo.GetEntriesByLeaveID(ctx, 7)
+	assert.NoError(t, err)
+	assert.Len(t, fetched, 1)
+	assert.Equal(t, domain.EntryTypeDebit, fetched[0].Type)
+
+	// a leave can only be debited once
+	err = repo.CreateEntries(ctx, []domain.LedgerEntry{
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeDebit, Days: -2,
+			LeaveID: common.GetPtr(7)},
+	})
+	assert.Error(t, err)
+	// in each year
+	err = repo.CreateEntries(ctx, []domain.LedgerEntry{
+		{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeDebit, Days: -1,
+			LeaveID: common.GetPtr(7)},
+	})
+	assert.NoError(t, err)
+}
+
+func TestCreatePeriodEntries(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	entries := []domain.LedgerEntry{
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeAccrual,
+			Days: 1.17, Period: common.GetPtr("2024-01")},
+		{EmployeeID: 2, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeAccrual,
+			Days: 1.17, Period: common.GetPtr("2024-01")},
+	}
+	created, err := repo.CreatePeriodEntries(ctx, entries)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+
+	// running the period again writes only the missing entries
+	entries = append(entries, domain.LedgerEntry{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual,
+		Type: domain.EntryTypeAccrual, Days: 1.16, Period: common.GetPtr("2024-02")})
+	for i := range entries {
+		entries[i].ID = 0
+	}
+	created, err = repo.CreatePeriodEntries(ctx, entries)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created)
+
+	fetched, err := repo.GetEntries(ctx, 1, 2024)
+	assert.NoError(t, err)
+	assert.Len(t, fetched, 2)
+}
+
+func TestKeyEntitlements(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	// granted twice by concurrent first accesses before the entitlements had a period
+	entries := []domain.LedgerEntry{
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeSick, Type: domain.EntryTypeEntitlement, Days: 30},
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeSick, Type: domain.EntryTypeEntitlement, Days: 30},
+		{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeSick, Type: domain.EntryTypeEntitlement, Days: 30},
+	}
+	assert.NoError(t, repo.CreateEntries(ctx, entries))
+	assert.NoError(t, repo.keyEntitlements())
+
+	fetched, err := repo.GetEntries(ctx, 1, 2024)
+	assert.NoError(t, err)
+	assert.Len(t, fetched, 1)
+	assert.Equal(t, entries[0].ID, fetched[0].ID)
+	assert.Equal(t, "2024", *fetched[0].Period)
+
+	// a later grant of the year is not written twice
+	created, err := repo.CreatePeriodEntries(ctx, []domain.LedgerEntry{
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeSick, Type: domain.EntryTypeEntitlement, Days: 30,
+			Period: common.GetPtr("2024")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, created)
+}
+
+func TestSaveAndDeleteAccrualRule(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.SeedData(ctx))
+	assert.NoError(t, repo.SaveAccrualRule(ctx, &domain.AccrualRule{LeaveType: leave_domain.LeaveTypeAnnual,
+		PositionLevel: "L5", DaysPerYear: 20, MaxCarryOverDays: 5}))
+	// the rule of the level is replaced
+	assert.NoError(t, repo.SaveAccrualRule(ctx, &domain.AccrualRule{LeaveType: leave_domain.LeaveTypeAnnual,
+		PositionLevel: "L5", DaysPerYear: 18, MaxCarryOverDays: 3}))
+
+	rules, err := repo.GetAccrualRules(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "L5", rules[1].PositionLevel)
+	assert.Equal(t, 18.0, rules[1].DaysPerYear)
+	assert.Equal(t, 3.0, rules[1].MaxCarryOverDays)
+
+	assert.NoError(t, repo.DeleteAccrualRule(ctx, leave_domain.LeaveTypeAnnual, "L5"))
+	err = repo.DeleteAccrualRule(ctx, leave_domain.LeaveTypeAnnual, "L5")
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+}
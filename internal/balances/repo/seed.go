@@ -0,0 +1,52 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm/clause"
+
+	"hr-system/internal/balances/domain"
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+// SeedEntitlements seeds the default yearly entitlements
+func (r *balanceRepo) SeedEntitlements(ctx context.Context) error {
+	entitlements := []domain.Entitlement{
+		{
+			LeaveType: leave_domain.LeaveTypeAnnual,
+			Days:      14,
+		},
+		{
+			LeaveType: leave_domain.LeaveTypeSick,
+			Days:      30,
+		},
+	}
+
+	// keep the entitlements which have been changed
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&entitlements).Error
+	if err != nil {
+		return fmt.Errorf("failed to seed entitlements: %w", err)
+	}
+
+	return nil
+}
+
+// SeedAccrualRules seeds the default accrual of the annual leave, which replaces its yearly entitlement
+func (r *balanceRepo) SeedAccrualRules(ctx context.Context) error {
+	rules := []domain.AccrualRule{
+		{
+			LeaveType:        leave_domain.LeaveTypeAnnual,
+			DaysPerYear:      14,
+			MaxCarryOverDays: 5,
+		},
+	}
+
+	// keep the rules which have been changed
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rules).Error
+	if err != nil {
+		return fmt.Errorf("failed to seed accrual rules: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/balances/domain"
+	"hr-system/internal/balances/repo"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	employee_domain "hr-system/internal/employees/domain"
+	employee_repo "hr-system/internal/employees/repo"
+	leave_domain "hr-system/internal/leaves/domain"
+	leavetype_repo "hr-system/internal/leavetypes/repo"
+)
+
+type AccrualService interface {
+	GetRules(ctx context.Context) ([]domain.AccrualRule, error)
+	// SetRule creates the rule of the leave type and position level or replaces it
+	SetRule(ctx context.Context, rule *domain.AccrualRule) error
+	DeleteRule(ctx context.Context, leaveType leave_domain.LeaveType, level string) error
+	// RunPeriod writes the accruals of the month of every employee. Closing December also carries the days left over
+	// into the next year, and closing CarryOverExpiryMonth expires the carried days not taken. Running a period again
+	// only writes the entries missing.
+	RunPeriod(ctx context.Context, period domain.Period) (domain.AccrualRun, error)
+}
+
+type accrualService struct {
+	balanceRepo  repo.BalanceRepo
+	employeeRepo employee_repo.EmployeeRepo
+	typeRepo     leavetype_repo.TypeRepo
+	logger       *common.Logger
+	validate     *validator.Validate
+}
+
+func NewAccrualService(logger *common.Logger, balanceRepo repo.BalanceRepo, employeeRepo employee_repo.EmployeeRepo,
+	typeRepo leavetype_repo.TypeRepo) AccrualService {
+	return &accrualService{
+		balanceRepo:  balanceRepo,
+		employeeRepo: employeeRepo,
+		typeRepo:     typeRepo,
+		logger:       logger,
+		validate:     validator.New(),
+	}
+}
+
+func (s *accrualService) GetRules(ctx context.Context) ([]domain.AccrualRule, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.balanceRepo.GetAccrualRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accrual rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *accrualService) SetRule(ctx context.Context, rule *domain.AccrualRule) error {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return err
+	}
+
+	if err := s.validate.Struct(rule); err != nil {
+		return fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	if _, err := s.typeRepo.GetTypeByCode(ctx, rule.LeaveType); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return fmt.Errorf("%w, unknown leave type %s", common_errors.ErrInvalidInput, rule.LeaveType)
+		}
+		return fmt.Errorf("failed to get leave type: %w", err)
+	}
+
+	if err := s.balanceRepo.SaveAccrualRule(ctx, rule); err != nil {
+		return fmt.Errorf("failed to save accrual rule: %w", err)
+	}
+	return nil
+}
+
+func (s *accrualService) DeleteRule(ctx context.Context, leaveType leave_domain.LeaveType, level string) error {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return err
+	}
+
+	if err := s.balanceRepo.DeleteAccrualRule(ctx, leaveType, level); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return common_errors.ErrResourceNotFound
+		}
+		return fmt.Errorf("failed to delete accrual rule: %w", err)
+	}
+	return nil
+}
+
+func (s *accrualService) RunPeriod(ctx context.Context, period domain.Period) (domain.AccrualRun, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.AccrualRun{}, err
+	}
+
+	if err := period.Validate(); err != nil {
+		return domain.AccrualRun{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	// the employments and the leaves of the month are only settled once it's over
+	if period.End().After(time.Now()) {
+		return domain.AccrualRun{}, fmt.Errorf("%w, period %s has not ended yet", common_errors.ErrInvalidInput, period)
+	}
+
+	run := domain.AccrualRun{Period: period.String()}
+	rules, err := s.balanceRepo.GetAccrualRules(ctx)
+	if err != nil {
+		return run, fmt.Errorf("failed to get accrual rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return run, nil
+	}
+
+	// the ones who have left accrue until their last day
+	employees, err := employee_repo.GetAllEmployees(ctx, s.employeeRepo)
+	if err != nil {
+		return run, err
+	}
+	for i := range employees {
+		employee := &employees[i]
+		// neither employed in the period nor left with days to carry over
+		if employee.StartDate().IsZero() || !employee.StartDate().Before(period.End()) ||
+			employee.TerminatedAt(time.Date(period.Year, time.January, 1, 0, 0, 0, 0, time.Local)) {
+			continue
+		}
+
+		entries, err := s.periodEntries(ctx, employee, rules, period)
+		if err != nil {
+			return run, err
+		}
+		// the entries of each employee are written on their own, so a failed run keeps what it has done so far
+		created, err := s.balanceRepo.CreatePeriodEntries(ctx, entries)
+		if err != nil {
+			return run, fmt.Errorf("failed to write entries of employee %d: %w", employee.ID, err)
+		}
+		if len(entries) > 0 {
+			run.Employees++
+		}
+		run.Entries += created
+	}
+
+	s.logger.Infof("accrual of %s wrote %d entries of %d employees", run.Period, run.Entries, run.Employees)
+	return run, nil
+}
+
+// periodEntries returns the accrual, carry-over and expiry entries of the employee in the period
+func (s *accrualService) periodEntries(ctx context.Context, employee *employee_domain.Employee,
+	rules []domain.AccrualRule, period domain.Period) ([]domain.LedgerEntry, error) {
+	entries, err := s.balanceRepo.GetEntries(ctx, employee.ID, period.Year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger entries of employee %d: %w", employee.ID, err)
+	}
+
+	var periodEntries []domain.LedgerEntry
+	for _, leaveType := range accruedTypes(rules) {
+		var typeEntries []domain.LedgerEntry
+		var remaining float64
+		entitled, accrued := false, false
+		for _, entry := range entries {
+			if entry.LeaveType != leaveType {
+				continue
+			}
+			typeEntries = append(typeEntries, entry)
+			remaining += entry.Days
+			switch {
+			case entry.Type == domain.EntryTypeEntitlement:
+				entitled = true
+			case entry.Type == domain.EntryTypeAccrual && entry.Period != nil && *entry.Period == period.String():
+				accrued = true
+			}
+		}
+
+		// the year granted as a whole before the leave type was accrued has nothing to accrue
+		if !entitled && !accrued {
+			if days := domain.MonthlyAccrual(employee, rules, leaveType, period); days > 0 {
+				start := period.Start()
+				periodEntries = append(periodEntries, domain.LedgerEntry{
+					EmployeeID: employee.ID,
+					Year:       period.Year,
+					LeaveType:  leaveType,
+					Type:       domain.EntryTypeAccrual,
+					Days:       days,
+					Period:     common.GetPtr(period.String()),
+					Date:       &start,
+					Note:       fmt.Sprintf("accrual of %s", period),
+				})
+				remaining += days
+			}
+		}
+
+		if period.Month == time.December {
+			lastDay := period.End().AddDate(0, 0, -1)
+			if !employee.TerminatedAt(lastDay) {
+				rule := domain.FindAccrualRule(rules, leaveType, employee.PositionAt(lastDay).Level)
+				if days := domain.CarryOver(rule, remaining); days > 0 {
+					nextYear := period.End()
+					periodEntries = append(periodEntries, domain.LedgerEntry{
+						EmployeeID: employee.ID,
+						Year:       period.Year + 1,
+						LeaveType:  leaveType,
+						Type:       domain.EntryTypeCarryOver,
+						Days:       days,
+						Period:     common.GetPtr(strconv.Itoa(period.Year + 1)),
+						Date:       &nextYear,
+						Note:       fmt.Sprintf("carried over from %d", period.Year),
+					})
+				}
+			}
+		}
+
+		if period.Month == domain.CarryOverExpiryMonth {
+			if days := domain.ExpiredCarryOver(typeEntries, period.Year); days > 0 {
+				lastDay := period.End().AddDate(0, 0, -1)
+				periodEntries = append(periodEntries, domain.LedgerEntry{
+					EmployeeID: employee.ID,
+					Year:       period.Year,
+					LeaveType:  leaveType,
+					Type:       domain.EntryTypeExpiry,
+					Days:       -days,
+					Period:     common.GetPtr(strconv.Itoa(period.Year)),
+					Date:       &lastDay,
+					Note:       fmt.Sprintf("days carried over into %d expired", period.Year),
+				})
+			}
+		}
+	}
+	return periodEntries, nil
+}
+
+// accruedTypes returns the leave types of the rules in their order
+func accruedTypes(rules []domain.AccrualRule) []leave_domain.LeaveType {
+	var leaveTypes []leave_domain.LeaveType
+	seen := make(map[leave_domain.LeaveType]bool)
+	for _, rule := range rules {
+		if !seen[rule.LeaveType] {
+			seen[rule.LeaveType] = true
+			leaveTypes = append(leaveTypes, rule.LeaveType)
+		}
+	}
+	return leaveTypes
+}
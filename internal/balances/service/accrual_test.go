@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/balances/domain"
+	mocks_balance_repo "hr-system/internal/balances/repo/mocks"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	employee_domain "hr-system/internal/employees/domain"
+	mocks_employee_repo "hr-system/internal/employees/repo/mocks"
+	leave_domain "hr-system/internal/leaves/domain"
+	leavetype_domain "hr-system/internal/leavetypes/domain"
+	mocks_leavetype_repo "hr-system/internal/leavetypes/repo/mocks"
+)
+
+func genFakeAccrualRules() []domain.AccrualRule {
+	return []domain.AccrualRule{
+		{LeaveType: leave_domain.LeaveTypeAnnual, DaysPerYear: 12, MaxCarryOverDays: 5},
+	}
+}
+
+func genFakeAccrualEmployees() []employee_domain.Employee {
+	return []employee_domain.Employee{
+		{ID: 1, Positions: []employee_domain.Position{
+			{Title: "Engineer", StartDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)},
+		}},
+		// left before the year
+		{ID: 2, TerminationDate: common.GetPtr(time.Date(2023, 6, 30, 0, 0, 0, 0, time.Local)),
+			Positions: []employee_domain.Position{
+				{Title: "Engineer", StartDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)},
+			}},
+	}
+}
+
+func TestRunPeriod(t *testing.T) {
+	mockBalanceRepo := mocks_balance_repo.NewBalanceRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockTypeRepo := mocks_leavetype_repo.NewTypeRepo(t)
+	logger := common.NewLogger()
+
+	service := NewAccrualService(logger, mockBalanceRepo, mockEmployeeRepo, mockTypeRepo)
+	ctx := auth.AsSystem(context.Background())
+
+	mockBalanceRepo.On("GetAccrualRules", ctx).Return(genFakeAccrualRules(), nil)
+	mockEmployeeRepo.On("GetEmployees", ctx, mock.Anything).
+		Return(employee_domain.EmployeesPage{Employees: genFakeAccrualEmployees()}, nil)
+
+	t.Run("accrual", func(t *testing.T) {
+		mockBalanceRepo.On("GetEntries", ctx, 1, 2024).Return([]domain.LedgerEntry{}, nil).Once()
+		mockBalanceRepo.On("CreatePeriodEntries", ctx, mock.MatchedBy(func(entries []domain.LedgerEntry) bool {
+			return len(entries) == 1 && entries[0].Type == domain.EntryTypeAccrual && entries[0].Days == 1 &&
+				*entries[0].Period == "2024-05" && entries[0].Year == 2024
+		})).Return(1, nil).Once()
+
+		run, err := service.RunPeriod(ctx, domain.Period{Year: 2024, Month: time.May})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AccrualRun{Period: "2024-05", Employees: 1, Entries: 1}, run)
+	})
+
+	t.Run("already accrued", func(t *testing.T) {
+		mockBalanceRepo.On("GetEntries", ctx, 1, 2024).Return([]domain.LedgerEntry{
+			{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeAccrual, Days: 1,
+				Period: common.GetPtr("2024-05")},
+		}, nil).Once()
+		mockBalanceRepo.On("CreatePeriodEntries", ctx, []domain.LedgerEntry(nil)).Return(0, nil).Once()
+
+		run, err := service.RunPeriod(ctx, domain.Period{Year: 2024, Month: time.May})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AccrualRun{Period: "2024-05"}, run)
+	})
+
+	t.Run("entitled as a whole", func(t *testing.T) {
+		mockBalanceRepo.On("GetEntries", ctx, 1, 2024).Return([]domain.LedgerEntry{
+			{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeEntitlement,
+				Days: 14},
+		}, nil).Once()
+		mockBalanceRepo.On("CreatePeriodEntries", ctx, []domain.LedgerEntry(nil)).Return(0, nil).Once()
+
+		_, err := service.RunPeriod(ctx, domain.Period{Year: 2024, Month: time.May})
+		assert.NoError(t, err)
+	})
+
+	t.Run("carry over", func(t *testing.T) {
+		mockBalanceRepo.On("GetEntries", ctx, 1, 2024).Return([]domain.LedgerEntry{
+			{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeAccrual,
+				Days: 11},
+			{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeDebit, Days: -4},
+		}, nil).Once()
+		// 11 + 1 - 4 days are left, 5 of them are carried over
+		mockBalanceRepo.On("CreatePeriodEntries", ctx, mock.MatchedBy(func(entries []domain.LedgerEntry) bool {
+			return len(entries) == 2 && entries[0].Type == domain.EntryTypeAccrual &&
+				entries[1].Type == domain.EntryTypeCarryOver && entries[1].Days == 5 && entries[1].Year == 2025 &&
+				*entries[1].Period == "2025"
+		})).Return(2, nil).Once()
+
+		run, err := service.RunPeriod(ctx, domain.Period{Year: 2024, Month: time.December})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, run.Entries)
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		inQ1 := time.Date(2025, 2, 3, 0, 0, 0, 0, time.Local)
+		mockBalanceRepo.On("GetEntries", ctx, 1, 2025).Return([]domain.LedgerEntry{
+			{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeCarryOver,
+				Days: 5},
+			{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeDebit, Days: -2,
+				Date: &inQ1},
+		}, nil).Once()
+		mockBalanceRepo.On("CreatePeriodEntries", ctx, mock.MatchedBy(func(entries []domain.LedgerEntry) bool {
+			return len(entries) == 2 && entries[1].Type == domain.EntryTypeExpiry && entries[1].Days == -3 &&
+				entries[1].Year == 2025
+		})).Return(2, nil).Once()
+
+		_, err := service.RunPeriod(ctx, domain.Period{Year: 2025, Month: time.March})
+		assert.NoError(t, err)
+	})
+}
+
+func TestRunPeriod_Invalid(t *testing.T) {
+	mockBalanceRepo := mocks_balance_repo.NewBalanceRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockTypeRepo := mocks_leavetype_repo.NewTypeRepo(t)
+	logger := common.NewLogger()
+
+	service := NewAccrualService(logger, mockBalanceRepo, mockEmployeeRepo, mockTypeRepo)
+
+	// only HR runs the accrual outside of the job
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 1, Roles: []auth.Role{auth.RoleManager}})
+	_, err := service.RunPeriod(ctx, domain.Period{Year: 2024, Month: time.May})
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+
+	// the month is not over yet
+	ctx = auth.AsSystem(context.Background())
+	_, err = service.RunPeriod(ctx, domain.PeriodOf(time.Now()))
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	_, err = service.RunPeriod(ctx, domain.Period{Year: 2024, Month: 13})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
+
+func TestSetRule(t *testing.T) {
+	mockBalanceRepo := mocks_balance_repo.NewBalanceRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockTypeRepo := mocks_leavetype_repo.NewTypeRepo(t)
+	logger := common.NewLogger()
+
+	service := NewAccrualService(logger, mockBalanceRepo, mockEmployeeRepo, mockTypeRepo)
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 1, Roles: []auth.Role{auth.RoleHRAdmin}})
+
+	rule := &domain.AccrualRule{LeaveType: leave_domain.LeaveTypeAnnual, PositionLevel: "L5", DaysPerYear: 20,
+		MaxCarryOverDays: 5}
+	mockTypeRepo.On("GetTypeByCode", ctx, leave_domain.LeaveTypeAnnual).
+		Return(leavetype_domain.Type{Code: leave_domain.LeaveTypeAnnual}, nil).Once()
+	mockBalanceRepo.On("SaveAccrualRule", ctx, rule).Return(nil).Once()
+	assert.NoError(t, service.SetRule(ctx, rule))
+
+	// unknown leave type
+	mockTypeRepo.On("GetTypeByCode", ctx, leave_domain.LeaveType("sabbatical")).
+		Return(leavetype_domain.Type{}, common_errors.ErrResourceNotFound).Once()
+	err := service.SetRule(ctx, &domain.AccrualRule{LeaveType: "sabbatical", DaysPerYear: 5})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// nothing accrued
+	err = service.SetRule(ctx, &domain.AccrualRule{LeaveType: leave_domain.LeaveTypeAnnual})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
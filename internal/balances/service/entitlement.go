@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/balances/domain"
+	"hr-system/internal/balances/repo"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	leavetype_repo "hr-system/internal/leavetypes/repo"
+)
+
+type EntitlementService interface {
+	GetEntitlements(ctx context.Context) ([]domain.Entitlement, error)
+	// SetEntitlement creates the yearly entitlement of a paid leave type or replaces it,
+	// the years already entitled keep the days they were granted
+	SetEntitlement(ctx context.Context, entitlement *domain.Entitlement) error
+}
+
+type entitlementService struct {
+	balanceRepo repo.BalanceRepo
+	typeRepo    leavetype_repo.TypeRepo
+	logger      *common.Logger
+	validate    *validator.Validate
+}
+
+func NewEntitlementService(logger *common.Logger, balanceRepo repo.BalanceRepo,
+	typeRepo leavetype_repo.TypeRepo) EntitlementService {
+	return &entitlementService{
+		balanceRepo: balanceRepo,
+		typeRepo:    typeRepo,
+		logger:      logger,
+		validate:    validator.New(),
+	}
+}
+
+func (s *entitlementService) GetEntitlements(ctx context.Context) ([]domain.Entitlement, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return nil, err
+	}
+
+	entitlements, err := s.balanceRepo.GetEntitlements(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entitlements: %w", err)
+	}
+	return entitlements, nil
+}
+
+func (s *entitlementService) SetEntitlement(ctx context.Context, entitlement *domain.Entitlement) error {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return err
+	}
+
+	if err := s.validate.Struct(entitlement); err != nil {
+		return fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	leaveType, err := s.typeRepo.GetTypeByCode(ctx, entitlement.LeaveType)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return fmt.Errorf("%w, unknown leave type %s", common_errors.ErrInvalidInput, entitlement.LeaveType)
+		}
+		return fmt.Errorf("failed to get leave type: %w", err)
+	}
+	// the unpaid leaves are not drawn from a balance
+	if !leaveType.Paid {
+		return fmt.Errorf("%w, leave type %s is unpaid", common_errors.ErrInvalidInput, entitlement.LeaveType)
+	}
+
+	if err := s.balanceRepo.SaveEntitlement(ctx, entitlement); err != nil {
+		return fmt.Errorf("failed to save entitlement: %w", err)
+	}
+	return nil
+}
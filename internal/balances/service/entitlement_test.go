@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/balances/domain"
+	mocks_balance_repo "hr-system/internal/balances/repo/mocks"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	leave_domain "hr-system/internal/leaves/domain"
+	leavetype_domain "hr-system/internal/leavetypes/domain"
+	mocks_leavetype_repo "hr-system/internal/leavetypes/repo/mocks"
+)
+
+func TestSetEntitlement(t *testing.T) {
+	mockBalanceRepo := mocks_balance_repo.NewBalanceRepo(t)
+	mockTypeRepo := mocks_leavetype_repo.NewTypeRepo(t)
+	logger := common.NewLogger()
+
+	service := NewEntitlementService(logger, mockBalanceRepo, mockTypeRepo)
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 1, Roles: []auth.Role{auth.RoleHRAdmin}})
+
+	entitlement := &domain.Entitlement{LeaveType: "bereavement", Days: 3}
+	mockTypeRepo.On("GetTypeByCode", ctx, entitlement.LeaveType).
+		Return(leavetype_domain.Type{Code: entitlement.LeaveType, Paid: true}, nil).Once()
+	mockBalanceRepo.On("SaveEntitlement", ctx, entitlement).Return(nil).Once()
+	assert.NoError(t, service.SetEntitlement(ctx, entitlement))
+
+	// unknown leave type
+	mockTypeRepo.On("GetTypeByCode", ctx, leave_domain.LeaveType("sabbatical")).
+		Return(leavetype_domain.Type{}, common_errors.ErrResourceNotFound).Once()
+	err := service.SetEntitlement(ctx, &domain.Entitlement{LeaveType: "sabbatical", Days: 5})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// the unpaid leaves have no balance
+	mockTypeRepo.On("GetTypeByCode", ctx, leave_domain.LeaveType("unpaid")).
+		Return(leavetype_domain.Type{Code: "unpaid"}, nil).Once()
+	err = service.SetEntitlement(ctx, &domain.Entitlement{LeaveType: "unpaid", Days: 5})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// negative days
+	err = service.SetEntitlement(ctx, &domain.Entitlement{LeaveType: leave_domain.LeaveTypeAnnual, Days: -1})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// not HR
+	ctx = auth.WithActor(context.Background(), auth.Actor{EmployeeID: 2})
+	err = service.SetEntitlement(ctx, entitlement)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
@@ -0,0 +1,125 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	balancesdomain "hr-system/internal/balances/domain"
+
+	domain "hr-system/internal/leaves/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccrualService is an autogenerated mock type for the AccrualService type
+type AccrualService struct {
+	mock.Mock
+}
+
+// DeleteRule provides a mock function with given fields: ctx, leaveType, level
+func (_m *AccrualService) DeleteRule(ctx context.Context, leaveType domain.LeaveType, level string) error {
+	ret := _m.Called(ctx, leaveType, level)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LeaveType, string) error); ok {
+		r0 = rf(ctx, leaveType, level)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRules provides a mock function with given fields: ctx
+func (_m *AccrualService) GetRules(ctx context.Context) ([]balancesdomain.AccrualRule, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRules")
+	}
+
+	var r0 []balancesdomain.AccrualRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]balancesdomain.AccrualRule, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []balancesdomain.AccrualRule); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]balancesdomain.AccrualRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunPeriod provides a mock function with given fields: ctx, period
+func (_m *AccrualService) RunPeriod(ctx context.Context, period balancesdomain.Period) (balancesdomain.AccrualRun, error) {
+	ret := _m.Called(ctx, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunPeriod")
+	}
+
+	var r0 balancesdomain.AccrualRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, balancesdomain.Period) (balancesdomain.AccrualRun, error)); ok {
+		return rf(ctx, period)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, balancesdomain.Period) balancesdomain.AccrualRun); ok {
+		r0 = rf(ctx, period)
+	} else {
+		r0 = ret.Get(0).(balancesdomain.AccrualRun)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, balancesdomain.Period) error); ok {
+		r1 = rf(ctx, period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetRule provides a mock function with given fields: ctx, rule
+func (_m *AccrualService) SetRule(ctx context.Context, rule *balancesdomain.AccrualRule) error {
+	ret := _m.Called(ctx, rule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *balancesdomain.AccrualRule) error); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAccrualService creates a new instance of AccrualService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccrualService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccrualService {
+	mock := &AccrualService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,173 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	balancesdomain "hr-system/internal/balances/domain"
+
+	domain "hr-system/internal/leaves/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BalanceService is an autogenerated mock type for the BalanceService type
+type BalanceService struct {
+	mock.Mock
+}
+
+// CreditLeave provides a mock function with given fields: ctx, leave
+func (_m *BalanceService) CreditLeave(ctx context.Context, leave *domain.Leave) error {
+	ret := _m.Called(ctx, leave)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreditLeave")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Leave) error); ok {
+		r0 = rf(ctx, leave)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DaysByYear provides a mock function with given fields: ctx, leave
+func (_m *BalanceService) DaysByYear(ctx context.Context, leave *domain.Leave) (map[int]float64, error) {
+	ret := _m.Called(ctx, leave)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DaysByYear")
+	}
+
+	var r0 map[int]float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Leave) (map[int]float64, error)); ok {
+		return rf(ctx, leave)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Leave) map[int]float64); ok {
+		r0 = rf(ctx, leave)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]float64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Leave) error); ok {
+		r1 = rf(ctx, leave)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DebitLeave provides a mock function with given fields: ctx, leave
+func (_m *BalanceService) DebitLeave(ctx context.Context, leave *domain.Leave) error {
+	ret := _m.Called(ctx, leave)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DebitLeave")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Leave) error); ok {
+		r0 = rf(ctx, leave)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetBalance provides a mock function with given fields: ctx, employeeID, leaveType, year
+func (_m *BalanceService) GetBalance(ctx context.Context, employeeID int, leaveType domain.LeaveType, year int) (balancesdomain.Balance, error) {
+	ret := _m.Called(ctx, employeeID, leaveType, year)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalance")
+	}
+
+	var r0 balancesdomain.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, domain.LeaveType, int) (balancesdomain.Balance, error)); ok {
+		return rf(ctx, employeeID, leaveType, year)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, domain.LeaveType, int) balancesdomain.Balance); ok {
+		r0 = rf(ctx, employeeID, leaveType, year)
+	} else {
+		r0 = ret.Get(0).(balancesdomain.Balance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, domain.LeaveType, int) error); ok {
+		r1 = rf(ctx, employeeID, leaveType, year)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalances provides a mock function with given fields: ctx, employeeID, year
+func (_m *BalanceService) GetBalances(ctx context.Context, employeeID int, year int) ([]balancesdomain.Balance, error) {
+	ret := _m.Called(ctx, employeeID, year)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalances")
+	}
+
+	var r0 []balancesdomain.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]balancesdomain.Balance, error)); ok {
+		return rf(ctx, employeeID, year)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []balancesdomain.Balance); ok {
+		r0 = rf(ctx, employeeID, year)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]balancesdomain.Balance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, employeeID, year)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ShortenLeave provides a mock function with given fields: ctx, leave
+func (_m *BalanceService) ShortenLeave(ctx context.Context, leave *domain.Leave) error {
+	ret := _m.Called(ctx, leave)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ShortenLeave")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Leave) error); ok {
+		r0 = rf(ctx, leave)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBalanceService creates a new instance of BalanceService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBalanceService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BalanceService {
+	mock := &BalanceService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
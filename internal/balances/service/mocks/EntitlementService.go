@@ -0,0 +1,77 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/balances/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EntitlementService is an autogenerated mock type for the EntitlementService type
+type EntitlementService struct {
+	mock.Mock
+}
+
+// GetEntitlements provides a mock function with given fields: ctx
+func (_m *EntitlementService) GetEntitlements(ctx context.Context) ([]domain.Entitlement, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEntitlements")
+	}
+
+	var r0 []domain.Entitlement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Entitlement, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Entitlement); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Entitlement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetEntitlement provides a mock function with given fields: ctx, entitlement
+func (_m *EntitlementService) SetEntitlement(ctx context.Context, entitlement *domain.Entitlement) error {
+	ret := _m.Called(ctx, entitlement)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetEntitlement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Entitlement) error); ok {
+		r0 = rf(ctx, entitlement)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEntitlementService creates a new instance of EntitlementService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEntitlementService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EntitlementService {
+	mock := &EntitlementService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/balances/domain"
+	"hr-system/internal/balances/repo"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	employee_repo "hr-system/internal/employees/repo"
+	holiday_service "hr-system/internal/holidays/service"
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+type BalanceService interface {
+	GetBalances(ctx context.Context, employeeID, year int) ([]domain.Balance, error)
+	GetBalance(ctx context.Context, employeeID int, leaveType leave_domain.LeaveType, year int) (domain.Balance, error)
+	// DebitLeave takes the days of an approved leave from the balance of its employee
+	DebitLeave(ctx context.Context, leave *leave_domain.Leave) error
+	// CreditLeave gives back the days debited by a leave which is no longer taken
+	CreditLeave(ctx context.Context, leave *leave_domain.Leave) error
+	// ShortenLeave gives back the debited days a leave no longer takes once its end date is brought forward
+	ShortenLeave(ctx context.Context, leave *leave_domain.Leave) error
+	// DaysByYear splits the days of the leave by the year of its working dates, the days of a leave spanning the turn
+	// of a year are taken from the balance of each year
+	DaysByYear(ctx context.Context, leave *leave_domain.Leave) (map[int]float64, error)
+}
+
+type balanceService struct {
+	balanceRepo    repo.BalanceRepo
+	employeeRepo   employee_repo.EmployeeRepo
+	holidayService holiday_service.HolidayService
+	logger         *common.Logger
+}
+
+func NewBalanceService(logger *common.Logger, balanceRepo repo.BalanceRepo,
+	employeeRepo employee_repo.EmployeeRepo, holidayService holiday_service.HolidayService) BalanceService {
+	return &balanceService{
+		balanceRepo:    balanceRepo,
+		employeeRepo:   employeeRepo,
+		holidayService: holidayService,
+		logger:         logger,
+	}
+}
+
+// getEntries returns the ledger entries of the employee in the year,
+// the yearly entitlements are granted on the first access of the year, except the ones of the accrued leave types.
+func (s *balanceService) getEntries(ctx context.Context, employeeID, year int) ([]domain.LedgerEntry, error) {
+	entries, err := s.balanceRepo.GetEntries(ctx, employeeID, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger entries: %w", err)
+	}
+
+	entitlements, err := s.balanceRepo.GetEntitlements(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entitlements: %w", err)
+	}
+
+	rules, err := s.balanceRepo.GetAccrualRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accrual rules: %w", err)
+	}
+
+	// the accrual engine grants the accrued leave types month by month
+	granted := make(map[leave_domain.LeaveType]bool)
+	for _, rule := range rules {
+		granted[rule.LeaveType] = true
+	}
+	for _, entry := range entries {
+		if entry.Type == domain.EntryTypeEntitlement {
+			granted[entry.LeaveType] = true
+		}
+	}
+
+	var grants []domain.LedgerEntry
+	for _, entitlement := range entitlements {
+		if granted[entitlement.LeaveType] {
+			continue
+		}
+		grants = append(grants, domain.LedgerEntry{
+			EmployeeID: employeeID,
+			Year:       year,
+			LeaveType:  entitlement.LeaveType,
+			Type:       domain.EntryTypeEntitlement,
+			Days:       entitlement.Days,
+			Period:     common.GetPtr(strconv.Itoa(year)),
+			Note:       fmt.Sprintf("yearly entitlement of %d", year),
+		})
+	}
+	if len(grants) == 0 {
+		return entries, nil
+	}
+	// the entitlement of a year is written once, a concurrent first access may have granted it already
+	if _, err := s.balanceRepo.CreatePeriodEntries(ctx, grants); err != nil {
+		return nil, fmt.Errorf("failed to grant entitlements: %w", err)
+	}
+
+	entries, err = s.balanceRepo.GetEntries(ctx, employeeID, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *balanceService) GetBalances(ctx context.Context, employeeID, year int) ([]domain.Balance, error) {
+	if year < 1 {
+		return nil, fmt.Errorf("%w, invalid year(%d)", common_errors.ErrInvalidInput, year)
+	}
+
+	if _, err := s.employeeRepo.GetEmployeeByID(ctx, employeeID); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return nil, common_errors.ErrResourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get employee: %w", err)
+	}
+	if _, err := auth.AuthorizeEmployee(ctx, s.employeeRepo, employeeID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.getEntries(ctx, employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	return sumBalances(year, entries), nil
+}
+
+func (s *balanceService) GetBalance(ctx context.Context, employeeID int, leaveType leave_domain.LeaveType,
+	year int) (domain.Balance, error) {
+	entries, err := s.getEntries(ctx, employeeID, year)
+	if err != nil {
+		return domain.Balance{}, err
+	}
+
+	for _, balance := range sumBalances(year, entries) {
+		if balance.LeaveType == leaveType {
+			return balance, nil
+		}
+	}
+
+	// no entitlement for this leave type
+	return domain.Balance{LeaveType: leaveType, Year: year}, nil
+}
+
+// sumBalances sums up the entries by leave type, the balances keep the order of the first entry of each type
+func sumBalances(year int, entries []domain.LedgerEntry) []domain.Balance {
+	var balances []domain.Balance
+	indexes := make(map[leave_domain.LeaveType]int)
+	for _, entry := range entries {
+		i, ok := indexes[entry.LeaveType]
+		if !ok {
+			i = len(balances)
+			indexes[entry.LeaveType] = i
+			balances = append(balances, domain.Balance{LeaveType: entry.LeaveType, Year: year})
+		}
+
+		switch entry.Type {
+		case domain.EntryTypeEntitlement, domain.EntryTypeAccrual:
+			balances[i].Entitled += entry.Days
+		case domain.EntryTypeCarryOver:
+			balances[i].CarriedOver += entry.Days
+		case domain.EntryTypeExpiry:
+			balances[i].Expired -= entry.Days
+		default:
+			balances[i].Used -= entry.Days
+		}
+		balances[i].Remaining += entry.Days
+	}
+	return balances
+}
+
+func (s *balanceService) DebitLeave(ctx context.Context, leave *leave_domain.Leave) error {
+	// the comp-off leaves are taken from the allowance of the approved overtime, which keeps track of them itself
+	if leave.Type == leave_domain.LeaveTypeCompOff {
+		return nil
+	}
+
+	entries, err := s.balanceRepo.GetEntriesByLeaveID(ctx, leave.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get ledger entries of leave: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Type == domain.EntryTypeDebit {
+			// already debited
+			return nil
+		}
+	}
+
+	days, err := s.DaysByYear(ctx, leave)
+	if err != nil {
+		return err
+	}
+
+	var debits []domain.LedgerEntry
+	for year := leave.StartDate.Year(); year <= leave.EndDate.Year(); year++ {
+		if days[year] == 0 {
+			continue
+		}
+		// make sure the entitlements of the year are granted before the first debit
+		if _, err := s.getEntries(ctx, leave.EmployeeID, year); err != nil {
+			return err
+		}
+
+		date := leave.StartDate
+		if year != date.Year() {
+			date = time.Date(year, time.January, 1, 0, 0, 0, 0, date.Location())
+		}
+		debits = append(debits, domain.LedgerEntry{
+			EmployeeID: leave.EmployeeID,
+			Year:       year,
+			LeaveType:  leave.Type,
+			Type:       domain.EntryTypeDebit,
+			Days:       -days[year],
+			LeaveID:    &leave.ID,
+			Date:       &date,
+			Note:       fmt.Sprintf("leave %d approved", leave.ID),
+		})
+	}
+	if err := s.balanceRepo.CreateEntries(ctx, debits); err != nil {
+		return fmt.Errorf("failed to debit leave %d: %w", leave.ID, err)
+	}
+
+	return nil
+}
+
+func (s *balanceService) DaysByYear(ctx context.Context, leave *leave_domain.Leave) (map[int]float64, error) {
+	if leave.StartDate.Year() == leave.EndDate.Year() {
+		return map[int]float64{leave.StartDate.Year(): leave.Days}, nil
+	}
+
+	employee, err := s.employeeRepo.GetEmployeeByID(ctx, leave.EmployeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employee: %w", err)
+	}
+	dates, err := s.holidayService.WorkingDates(ctx, employee.Region, leave.StartDate, leave.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working dates: %w", err)
+	}
+	if len(dates) == 0 {
+		return map[int]float64{leave.StartDate.Year(): leave.Days}, nil
+	}
+
+	// the days of the leave were counted when it was requested, they are shared out in proportion
+	days := make(map[int]float64)
+	for _, date := range dates {
+		days[date.Year()] += leave.Days / float64(len(dates))
+	}
+	return days, nil
+}
+
+func (s *balanceService) CreditLeave(ctx context.Context, leave *leave_domain.Leave) error {
+	entries, err := s.balanceRepo.GetEntriesByLeaveID(ctx, leave.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get ledger entries of leave: %w", err)
+	}
+
+	// the days of a shortened leave have partly been given back already
+	shortened := make(map[int]float64)
+	for _, entry := range entries {
+		if entry.Type == domain.EntryTypeShortening {
+			shortened[entry.Year] += entry.Days
+		}
+	}
+
+	// a leave spanning the turn of a year has a debit in each year
+	var credits []domain.LedgerEntry
+	for _, entry := range entries {
+		switch entry.Type {
+		case domain.EntryTypeDebit:
+			if entry.Days+shortened[entry.Year] == 0 {
+				continue
+			}
+			credits = append(credits, domain.LedgerEntry{
+				EmployeeID: entry.EmployeeID,
+				Year:       entry.Year,
+				LeaveType:  entry.LeaveType,
+				Type:       domain.EntryTypeCredit,
+				Days:       -(entry.Days + shortened[entry.Year]),
+				LeaveID:    &leave.ID,
+				Date:       entry.Date,
+				Note:       fmt.Sprintf("leave %d %s", leave.ID, leave.Status),
+			})
+		case domain.EntryTypeCredit:
+			// already credited
+			return nil
+		}
+	}
+
+	// nothing to give back when there is no debit
+	if err := s.balanceRepo.CreateEntries(ctx, credits); err != nil {
+		return fmt.Errorf("failed to credit leave %d: %w", leave.ID, err)
+	}
+
+	return nil
+}
+
+func (s *balanceService) ShortenLeave(ctx context.Context, leave *leave_domain.Leave) error {
+	entries, err := s.balanceRepo.GetEntriesByLeaveID(ctx, leave.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get ledger entries of leave: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Type == domain.EntryTypeShortening || entry.Type == domain.EntryTypeCredit {
+			// already shortened, or nothing left to shorten
+			return nil
+		}
+	}
+
+	days, err := s.DaysByYear(ctx, leave)
+	if err != nil {
+		return err
+	}
+
+	var shortenings []domain.LedgerEntry
+	for _, entry := range entries {
+		if entry.Type != domain.EntryTypeDebit || -entry.Days <= days[entry.Year] {
+			continue
+		}
+		shortenings = append(shortenings, domain.LedgerEntry{
+			EmployeeID: entry.EmployeeID,
+			Year:       entry.Year,
+			LeaveType:  entry.LeaveType,
+			Type:       domain.EntryTypeShortening,
+			Days:       -entry.Days - days[entry.Year],
+			LeaveID:    &leave.ID,
+			Date:       entry.Date,
+			Note:       fmt.Sprintf("leave %d shortened to %s", leave.ID, leave.EndDate.Format(time.DateOnly)),
+		})
+	}
+	if err := s.balanceRepo.CreateEntries(ctx, shortenings); err != nil {
+		return fmt.Errorf("failed to shorten leave %d: %w", leave.ID, err)
+	}
+
+	return nil
+}
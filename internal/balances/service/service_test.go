@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/balances/domain"
+	mocks_balance_repo "hr-system/internal/balances/repo/mocks"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	employee_domain "hr-system/internal/employees/domain"
+	mocks_employee_repo "hr-system/internal/employees/repo/mocks"
+	mocks_holiday_service "hr-system/internal/holidays/service/mocks"
+	leave_domain "hr-system/internal/leaves/domain"
+	review_domain "hr-system/internal/reviews/domain"
+)
+
+func genFakeEntitlements() []domain.Entitlement {
+	return []domain.Entitlement{
+		{LeaveType: leave_domain.LeaveTypeAnnual, Days: 14},
+		{LeaveType: leave_domain.LeaveTypeSick, Days: 30},
+	}
+}
+
+func TestGetBalances(t *testing.T) {
+	mockBalanceRepo := mocks_balance_repo.NewBalanceRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockHolidayService := mocks_holiday_service.NewHolidayService(t)
+	logger := common.NewLogger()
+
+	service := NewBalanceService(logger, mockBalanceRepo, mockEmployeeRepo, mockHolidayService)
+
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 1, Roles: []auth.Role{auth.RoleEmployee}})
+	entries := []domain.LedgerEntry{
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeEntitlement, Days: 14},
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeDebit, Days: -5},
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeCredit, Days: 2},
+	}
+
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(employee_domain.Employee{ID: 1}, nil).Once()
+	mockBalanceRepo.On("GetEntries", ctx, 1, 2024).Return(entries, nil).Once()
+	mockBalanceRepo.On("GetEntitlements", ctx).Return(genFakeEntitlements(), nil).Once()
+	mockBalanceRepo.On("GetAccrualRules", ctx).Return([]domain.AccrualRule{}, nil).Once()
+	// sick leave entitlement is granted on the first access, once in the year
+	grant := domain.LedgerEntry{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeSick,
+		Type: domain.EntryTypeEntitlement, Days: 30, Period: common.GetPtr("2024")}
+	mockBalanceRepo.On("CreatePeriodEntries", ctx, mock.MatchedBy(func(grants []domain.LedgerEntry) bool {
+		return len(grants) == 1 && grants[0].LeaveType == leave_domain.LeaveTypeSick && grants[0].Days == 30 &&
+			*grants[0].Period == "2024"
+	})).Return(1, nil).Once()
+	mockBalanceRepo.On("GetEntries", ctx, 1, 2024).Return(append(entries, grant), nil).Once()
+
+	balances, err := service.GetBalances(ctx, 1, 2024)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Balance{
+		{LeaveType: leave_domain.LeaveTypeAnnual, Year: 2024, Entitled: 14, Used: 3, Remaining: 11},
+		{LeaveType: leave_domain.LeaveTypeSick, Year: 2024, Entitled: 30, Used: 0, Remaining: 30},
+	}, balances)
+}
+
+func TestDebitAndCreditLeave(t *testing.T) {
+	mockBalanceRepo := mocks_balance_repo.NewBalanceRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockHolidayService := mocks_holiday_service.NewHolidayService(t)
+	logger := common.NewLogger()
+
+	service := NewBalanceService(logger, mockBalanceRepo, mockEmployeeRepo, mockHolidayService)
+
+	ctx := context.Background()
+	leave := leave_domain.Leave{
+		ID:         3,
+		EmployeeID: 1,
+		Type:       leave_domain.LeaveTypeAnnual,
+		StartDate:  time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+		Days:       2,
+		Status:     review_domain.ReviewStatusApproved,
+	}
+	entitlement := domain.LedgerEntry{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual,
+		Type: domain.EntryTypeEntitlement, Days: 14}
+	debit := domain.LedgerEntry{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeAnnual,
+		Type: domain.EntryTypeDebit, Days: -2, LeaveID: &leave.ID}
+
+	mockBalanceRepo.On("GetEntries", ctx, 1, 2024).Return([]domain.LedgerEntry{entitlement}, nil).Once()
+	mockBalanceRepo.On("GetEntitlements", ctx).Return(genFakeEntitlements()[:1], nil).Once()
+	mockBalanceRepo.On("GetAccrualRules", ctx).Return([]domain.AccrualRule{}, nil).Once()
+	mockBalanceRepo.On("GetEntriesByLeaveID", ctx, leave.ID).Return([]domain.LedgerEntry{}, nil).Once()
+	mockBalanceRepo.On("CreateEntries", ctx, mock.MatchedBy(func(entries []domain.LedgerEntry) bool {
+		return len(entries) == 1 && entries[0].Type == domain.EntryTypeDebit && entries[0].Days == -2
+	})).Return(nil).Once()
+
+	err := service.DebitLeave(ctx, &leave)
+	assert.NoError(t, err)
+
+	leave.Status = review_domain.ReviewStatusRevoked
+	mockBalanceRepo.On("GetEntriesByLeaveID", ctx, leave.ID).Return([]domain.LedgerEntry{debit}, nil).Once()
+	mockBalanceRepo.On("CreateEntries", ctx, mock.MatchedBy(func(entries []domain.LedgerEntry) bool {
+		return len(entries) == 1 && entries[0].Type == domain.EntryTypeCredit && entries[0].Days == 2
+	})).Return(nil).Once()
+
+	err = service.CreditLeave(ctx, &leave)
+	assert.NoError(t, err)
+
+	// credit only once
+	credit := debit
+	credit.Type = domain.EntryTypeCredit
+	credit.Days = 2
+	mockBalanceRepo.On("GetEntriesByLeaveID", ctx, leave.ID).Return([]domain.LedgerEntry{debit, credit}, nil).Once()
+
+	err = service.CreditLeave(ctx, &leave)
+	assert.NoError(t, err)
+}
+
+func TestDebitLeave_AcrossYears(t *testing.T) {
+	mockBalanceRepo := mocks_balance_repo.NewBalanceRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockHolidayService := mocks_holiday_service.NewHolidayService(t)
+	logger := common.NewLogger()
+
+	service := NewBalanceService(logger, mockBalanceRepo, mockEmployeeRepo, mockHolidayService)
+
+	ctx := context.Background()
+	leave := leave_domain.Leave{
+		ID:         3,
+		EmployeeID: 1,
+		Type:       leave_domain.LeaveTypeSick,
+		StartDate:  time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+		Days:       4,
+		Status:     review_domain.ReviewStatusApproved,
+	}
+	// the 1st of January is a holiday
+	dates := []time.Time{
+		time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+	}
+	entitlement := func(year int) domain.LedgerEntry {
+		return domain.LedgerEntry{EmployeeID: 1, Year: year, LeaveType: leave_domain.LeaveTypeSick,
+			Type: domain.EntryTypeEntitlement, Days: 30}
+	}
+
+	mockBalanceRepo.On("GetEntriesByLeaveID", ctx, leave.ID).Return([]domain.LedgerEntry{}, nil).Once()
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(employee_domain.Employee{ID: 1, Region: "TW"}, nil).Once()
+	mockHolidayService.On("WorkingDates", ctx, "TW", leave.StartDate, leave.EndDate).Return(dates, nil).Once()
+	mockBalanceRepo.On("GetEntries", ctx, 1, 2024).Return([]domain.LedgerEntry{entitlement(2024)}, nil).Once()
+	mockBalanceRepo.On("GetEntries", ctx, 1, 2025).Return([]domain.LedgerEntry{entitlement(2025)}, nil).Once()
+	mockBalanceRepo.On("GetEntitlements", ctx).Return(genFakeEntitlements()[1:], nil).Times(2)
+	mockBalanceRepo.On("GetAccrualRules", ctx).Return([]domain.AccrualRule{}, nil).Times(2)
+	mockBalanceRepo.On("CreateEntries", ctx, mock.MatchedBy(func(entries []domain.LedgerEntry) bool {
+		return len(entries) == 2 &&
+			entries[0].Year == 2024 && entries[0].Days == -2 && entries[0].Date.Equal(leave.StartDate) &&
+			entries[1].Year == 2025 && entries[1].Days == -2 && entries[1].Date.Year() == 2025
+	})).Return(nil).Once()
+
+	err := service.DebitLeave(ctx, &leave)
+	assert.NoError(t, err)
+
+	// each year is given back
+	leave.Status = review_domain.ReviewStatusRevoked
+	debits := []domain.LedgerEntry{
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeSick, Type: domain.EntryTypeDebit, Days: -2},
+		{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeSick, Type: domain.EntryTypeDebit, Days: -2},
+	}
+	mockBalanceRepo.On("GetEntriesByLeaveID", ctx, leave.ID).Return(debits, nil).Once()
+	mockBalanceRepo.On("CreateEntries", ctx, mock.MatchedBy(func(entries []domain.LedgerEntry) bool {
+		return len(entries) == 2 && entries[0].Type == domain.EntryTypeCredit &&
+			entries[0].Year == 2024 && entries[0].Days == 2 && entries[1].Year == 2025 && entries[1].Days == 2
+	})).Return(nil).Once()
+
+	err = service.CreditLeave(ctx, &leave)
+	assert.NoError(t, err)
+}
+
+func TestShortenLeave(t *testing.T) {
+	mockBalanceRepo := mocks_balance_repo.NewBalanceRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockHolidayService := mocks_holiday_service.NewHolidayService(t)
+	logger := common.NewLogger()
+
+	service := NewBalanceService(logger, mockBalanceRepo, mockEmployeeRepo, mockHolidayService)
+
+	ctx := context.Background()
+	// debited across the turn of the year, then cut short on the last day of the employee in 2024
+	leave := leave_domain.Leave{
+		ID:         3,
+		EmployeeID: 1,
+		Type:       leave_domain.LeaveTypeSick,
+		StartDate:  time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		Days:       2,
+		Status:     review_domain.ReviewStatusApproved,
+	}
+	debits := []domain.LedgerEntry{
+		{EmployeeID: 1, Year: 2024, LeaveType: leave_domain.LeaveTypeSick, Type: domain.EntryTypeDebit, Days: -2},
+		{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeSick, Type: domain.EntryTypeDebit, Days: -2},
+	}
+
+	mockBalanceRepo.On("GetEntriesByLeaveID", ctx, leave.ID).Return(debits, nil).Once()
+	mockBalanceRepo.On("CreateEntries", ctx, mock.MatchedBy(func(entries []domain.LedgerEntry) bool {
+		return len(entries) == 1 && entries[0].Type == domain.EntryTypeShortening &&
+			entries[0].Year == 2025 && entries[0].Days == 2
+	})).Return(nil).Once()
+
+	err := service.ShortenLeave(ctx, &leave)
+	assert.NoError(t, err)
+
+	// shortened only once
+	shortening := domain.LedgerEntry{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeSick,
+		Type: domain.EntryTypeShortening, Days: 2}
+	entries := append(debits, shortening)
+	mockBalanceRepo.On("GetEntriesByLeaveID", ctx, leave.ID).Return(entries, nil).Once()
+
+	err = service.ShortenLeave(ctx, &leave)
+	assert.NoError(t, err)
+
+	// only the days still taken are given back
+	leave.Status = review_domain.ReviewStatusRevoked
+	mockBalanceRepo.On("GetEntriesByLeaveID", ctx, leave.ID).Return(entries, nil).Once()
+	mockBalanceRepo.On("CreateEntries", ctx, mock.MatchedBy(func(entries []domain.LedgerEntry) bool {
+		return len(entries) == 1 && entries[0].Type == domain.EntryTypeCredit &&
+			entries[0].Year == 2024 && entries[0].Days == 2
+	})).Return(nil).Once()
+
+	err = service.CreditLeave(ctx, &leave)
+	assert.NoError(t, err)
+}
+
+func TestGetBalances_Forbidden(t *testing.T) {
+	mockBalanceRepo := mocks_balance_repo.NewBalanceRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockHolidayService := mocks_holiday_service.NewHolidayService(t)
+	logger := common.NewLogger()
+
+	service := NewBalanceService(logger, mockBalanceRepo, mockEmployeeRepo, mockHolidayService)
+
+	// a manager out of the chain of the employee
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 3, Roles: []auth.Role{auth.RoleManager}})
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(employee_domain.Employee{ID: 1}, nil).Once()
+	mockEmployeeRepo.On("GetManagerChain", ctx, 1).Return([]int{2}, nil).Once()
+
+	_, err := service.GetBalances(ctx, 1, 2024)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestGetBalances_Accrued(t *testing.T) {
+	mockBalanceRepo := mocks_balance_repo.NewBalanceRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockHolidayService := mocks_holiday_service.NewHolidayService(t)
+	logger := common.NewLogger()
+
+	service := NewBalanceService(logger, mockBalanceRepo, mockEmployeeRepo, mockHolidayService)
+
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 1, Roles: []auth.Role{auth.RoleEmployee}})
+	entries := []domain.LedgerEntry{
+		{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeSick, Type: domain.EntryTypeEntitlement, Days: 30},
+		{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeCarryOver, Days: 5},
+		{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeAccrual, Days: 1.17},
+		{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeAccrual, Days: 1.16},
+		{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeDebit, Days: -2},
+		{EmployeeID: 1, Year: 2025, LeaveType: leave_domain.LeaveTypeAnnual, Type: domain.EntryTypeExpiry, Days: -3},
+	}
+
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(employee_domain.Employee{ID: 1}, nil).Once()
+	mockBalanceRepo.On("GetEntries", ctx, 1, 2025).Return(entries, nil).Once()
+	mockBalanceRepo.On("GetEntitlements", ctx).Return(genFakeEntitlements(), nil).Once()
+	// the accrued annual leave is not granted for the whole year
+	mockBalanceRepo.On("GetAccrualRules", ctx).Return(genFakeAccrualRules(), nil).Once()
+
+	balances, err := service.GetBalances(ctx, 1, 2025)
+	assert.NoError(t, err)
+	assert.Len(t, balances, 2)
+	assert.Equal(t, leave_domain.LeaveTypeAnnual, balances[1].LeaveType)
+	assert.InDelta(t, 2.33, balances[1].Entitled, 0.001)
+	assert.Equal(t, 5.0, balances[1].CarriedOver)
+	assert.Equal(t, 3.0, balances[1].Expired)
+	assert.Equal(t, 2.0, balances[1].Used)
+	assert.InDelta(t, 2.33, balances[1].Remaining, 0.001)
+}
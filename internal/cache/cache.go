@@ -38,6 +38,11 @@ func (c *Cache) Del(ctx context.Context, key string) error {
 	return err
 }
 
+// Incr increments the integer of the key by one and returns it, a missing key is incremented from 0
+func (c *Cache) Incr(ctx context.Context, key string) (int64, error) {
+	return c.rdb.Incr(ctx, key).Result()
+}
+
 func (c *Cache) DelByPrefix(ctx context.Context, prefix string) error {
 	var cursor uint64
 	var keys []string
@@ -67,3 +72,45 @@ func (c *Cache) DelByPrefix(ctx context.Context, prefix string) error {
 
 	return errors.Combine(errs...)
 }
+
+// TryLock sets the key only if it does not exist, it reports whether the lock is acquired.
+// The lock is released when it expires or by Unlock with the same value.
+func (c *Cache) TryLock(ctx context.Context, key string, value string, expiration time.Duration) (bool, error) {
+	ok, err := c.rdb.SetNX(ctx, key, value, expiration).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to set lock %s: %w", key, err)
+	}
+	return ok, nil
+}
+
+// unlockScript deletes the lock only if it's still held by the same value
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// extendScript resets the expiration of the lock only if it's still held by the same value
+var extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// ExtendLock resets the expiration of the lock held with the value, it reports whether the lock is still held
+func (c *Cache) ExtendLock(ctx context.Context, key string, value string, expiration time.Duration) (bool, error) {
+	n, err := extendScript.Run(ctx, c.rdb, []string{key}, value, expiration.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to extend lock %s: %w", key, err)
+	}
+	return n == 1, nil
+}
+
+func (c *Cache) Unlock(ctx context.Context, key string, value string) error {
+	if err := unlockScript.Run(ctx, c.rdb, []string{key}, value).Err(); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Package cursor makes the opaque tokens of the keyset pagination, they are signed so a client can not forge
+// a position in a list it's not allowed to see
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Codec encodes a position as "<payload>.<signature>", both base64url encoded
+type Codec struct {
+	secret []byte
+}
+
+func NewCodec(secret []byte) *Codec {
+	return &Codec{
+		secret: secret,
+	}
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// Encode returns the token of the position v
+func (c *Codec) Encode(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode verifies the token and unmarshals its position into v
+func (c *Codec) Decode(token string, v interface{}) error {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("%w, cause: %s", ErrInvalidCursor, err)
+	}
+	return nil
+}
+
+// Key is a column a list is ordered by with its value in the cursor
+type Key struct {
+	Column string
+	Desc   bool
+	Value  interface{}
+}
+
+// Condition returns the SQL condition keeping the rows after the cursor in the order of the keys with its arguments,
+// e.g. "((a > ?) OR (a = ? AND b < ?))" for a ascending and b descending, the last key must be unique
+func Condition(keys []Key) (string, []interface{}) {
+	var conditions []string
+	var args, equalArgs []interface{}
+	equals := ""
+	for _, key := range keys {
+		operator := ">"
+		if key.Desc {
+			operator = "<"
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s%s %s ?)", equals, key.Column, operator))
+		args = append(append(args, equalArgs...), key.Value)
+		equals += key.Column + " = ? AND "
+		equalArgs = append(equalArgs, key.Value)
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// Page is the response of a list paged by cursors
+type Page[T any] struct {
+	Data []T `json:"data"`
+	// NextCursor continues the list, it's empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+	// TotalCount is only given when it's asked for
+	TotalCount *int `json:"total_count,omitempty"`
+}
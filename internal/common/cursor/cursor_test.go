@@ -0,0 +1,49 @@
+package cursor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type position struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestCodec(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+
+	token, err := codec.Encode(position{ID: 42, Name: "Jane"})
+	assert.NoError(t, err)
+
+	var decoded position
+	assert.NoError(t, codec.Decode(token, &decoded))
+	assert.Equal(t, position{ID: 42, Name: "Jane"}, decoded)
+
+	t.Run("tampered", func(t *testing.T) {
+		forged, err := codec.Encode(position{ID: 1})
+		assert.NoError(t, err)
+		payload, _, _ := strings.Cut(forged, ".")
+		_, signature, _ := strings.Cut(token, ".")
+
+		assert.ErrorIs(t, codec.Decode(payload+"."+signature, &decoded), ErrInvalidCursor)
+	})
+
+	t.Run("other secret", func(t *testing.T) {
+		assert.ErrorIs(t, NewCodec([]byte("other")).Decode(token, &decoded), ErrInvalidCursor)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, token := range []string{"", "abc", "a.b", "!!.!!"} {
+			assert.ErrorIs(t, codec.Decode(token, &decoded), ErrInvalidCursor)
+		}
+	})
+}
+
+func TestCondition(t *testing.T) {
+	condition, args := Condition([]Key{{Column: "a", Value: 1}, {Column: "b", Desc: true, Value: "x"}, {Column: "id", Value: 3}})
+	assert.Equal(t, "((a > ?) OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id > ?))", condition)
+	assert.Equal(t, []interface{}{1, 1, "x", 1, "x", 3}, args)
+}
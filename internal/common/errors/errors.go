@@ -9,6 +9,9 @@ import (
 var ErrResourceNotFound = errors.New("resource not found")
 var ErrInvalidInput = errors.New("invalid input")
 var ErrStatusConflict = errors.New("status conflict")
+var ErrForbidden = errors.New("forbidden")
+var ErrPreconditionFailed = errors.New("precondition failed")
+var ErrOverrideRequired = errors.New("override required")
 
 func Combine(errs ...error) error {
 	if len(errs) == 0 {
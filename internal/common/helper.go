@@ -3,3 +3,20 @@ package common
 func GetPtr[T any](v T) *T {
 	return &v
 }
+
+// PtrEqual reports whether both pointers are nil or point to equal values
+func PtrEqual[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// ElemPtrs returns the pointers to the elements of the slice, the changes made through them are kept in the slice
+func ElemPtrs[T any](s []T) []*T {
+	ptrs := make([]*T, len(s))
+	for i := range s {
+		ptrs[i] = &s[i]
+	}
+	return ptrs
+}
@@ -0,0 +1,66 @@
+package transaction
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transactor runs the operations of several repos in one transaction,
+// the repos find the transaction in the context passed to op with DB
+type Transactor interface {
+	Do(ctx context.Context, op func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{
+		db: db,
+	}
+}
+
+func (t *transactor) Do(ctx context.Context, op func(ctx context.Context) error) error {
+	return Run(ctx, t.db, func(tx *gorm.DB) error {
+		return op(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// DB returns the transaction carried by the context, or db with the context when there is none
+func DB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db.WithContext(ctx)
+}
+
+// Run runs op in a transaction of db, the transaction is rolled back when op fails or panics.
+// op joins the transaction carried by the context if there is one, which is then committed by its owner.
+func Run(ctx context.Context, db *gorm.DB, op func(tx *gorm.DB) error) (err error) {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return op(tx)
+	}
+
+	tx := db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := op(tx); err != nil {
+		return err
+	}
+	return tx.Commit().Error
+}
@@ -0,0 +1,50 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type record struct {
+	ID int `gorm:"primaryKey"`
+}
+
+func TestDo(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&record{}))
+
+	transactor := NewTransactor(db)
+	ctx := context.Background()
+	count := func() int64 {
+		var count int64
+		assert.NoError(t, db.Model(&record{}).Count(&count).Error)
+		return count
+	}
+
+	// a failure rolls back the writes of every repo, including the ones which run their own transaction
+	failure := errors.New("failure")
+	err = transactor.Do(ctx, func(ctx context.Context) error {
+		assert.NoError(t, DB(ctx, db).Create(&record{ID: 1}).Error)
+		assert.NoError(t, Run(ctx, db, func(tx *gorm.DB) error {
+			return tx.Create(&record{ID: 2}).Error
+		}))
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, int64(0), count())
+
+	err = transactor.Do(ctx, func(ctx context.Context) error {
+		assert.NoError(t, DB(ctx, db).Create(&record{ID: 1}).Error)
+		return Run(ctx, db, func(tx *gorm.DB) error {
+			return tx.Create(&record{ID: 2}).Error
+		})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count())
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// Rule asks for at least MinWorkingPercent of the direct reports of the manager to be working on every working day,
+// a leave is not approved when it leaves fewer of them working
+type Rule struct {
+	ID                int `json:"id" gorm:"primaryKey;autoIncrement"`
+	ManagerID         int `json:"manager_id" gorm:"not null;uniqueIndex:idx_manager_id" validate:"required"`
+	MinWorkingPercent int `json:"min_working_percent" gorm:"not null" validate:"gte=1,lte=100"`
+	// AllowOverride lets the reviewer approve a leave breaking the rule by saying so explicitly,
+	// otherwise such a leave can not be approved
+	AllowOverride bool      `json:"allow_override" gorm:"not null;default:false"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// MetBy reports whether working of the total direct reports are enough, a team without anyone meets every rule.
+// The reports on a half-day or hourly leave count as working for the rest of the day.
+func (r *Rule) MetBy(working float64, total int) bool {
+	// the day fractions have 2 decimals, rounding keeps the float error from breaking a rule met exactly
+	return total == 0 || math.Round(working*100) >= float64(r.MinWorkingPercent*total)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/common"
+	"hr-system/internal/coverage/domain"
+	"hr-system/internal/coverage/service"
+	"hr-system/internal/middleware"
+)
+
+type RuleHandler struct {
+	ruleService service.RuleService
+	logger      *common.Logger
+}
+
+func NewRuleHandler(logger *common.Logger, ruleService service.RuleService) *RuleHandler {
+	return &RuleHandler{
+		ruleService: ruleService,
+		logger:      logger,
+	}
+}
+
+type RuleRequest struct {
+	ManagerID         int  `json:"manager_id" binding:"required"`
+	MinWorkingPercent int  `json:"min_working_percent" binding:"required"`
+	AllowOverride     bool `json:"allow_override"`
+}
+
+func (r *RuleRequest) toRule(id int) *domain.Rule {
+	return &domain.Rule{
+		ID:                id,
+		ManagerID:         r.ManagerID,
+		MinWorkingPercent: r.MinWorkingPercent,
+		AllowOverride:     r.AllowOverride,
+	}
+}
+
+func (h *RuleHandler) CreateRule(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req RuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	rule, err := h.ruleService.CreateRule(ctx, req.toRule(0))
+	if err != nil {
+		middleware.HandleErr(c, err, "create coverage rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+func (h *RuleHandler) GetRules(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var managerID *int
+	if param := c.Query("manager_id"); param != "" {
+		id, err := strconv.Atoi(param)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid manager_id"))
+			return
+		}
+		managerID = &id
+	}
+
+	rules, err := h.ruleService.GetRules(ctx, managerID)
+	if err != nil {
+		middleware.HandleErr(c, err, "get coverage rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *RuleHandler) GetRuleByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid coverage rule ID"))
+		return
+	}
+
+	rule, err := h.ruleService.GetRuleByID(ctx, id)
+	if err != nil {
+		middleware.HandleErr(c, err, "get coverage rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *RuleHandler) UpdateRule(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid coverage rule ID"))
+		return
+	}
+
+	var req RuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	rule, err := h.ruleService.UpdateRule(ctx, req.toRule(id))
+	if err != nil {
+		middleware.HandleErr(c, err, "update coverage rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *RuleHandler) DeleteRule(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid coverage rule ID"))
+		return
+	}
+
+	if err := h.ruleService.DeleteRule(ctx, id); err != nil {
+		middleware.HandleErr(c, err, "delete coverage rule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,141 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/coverage/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RuleRepo is an autogenerated mock type for the RuleRepo type
+type RuleRepo struct {
+	mock.Mock
+}
+
+// CreateRule provides a mock function with given fields: ctx, rule
+func (_m *RuleRepo) CreateRule(ctx context.Context, rule *domain.Rule) error {
+	ret := _m.Called(ctx, rule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Rule) error); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRule provides a mock function with given fields: ctx, id
+func (_m *RuleRepo) DeleteRule(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRuleByID provides a mock function with given fields: ctx, id
+func (_m *RuleRepo) GetRuleByID(ctx context.Context, id int) (domain.Rule, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRuleByID")
+	}
+
+	var r0 domain.Rule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (domain.Rule, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) domain.Rule); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Rule)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRules provides a mock function with given fields: ctx, managerID
+func (_m *RuleRepo) GetRules(ctx context.Context, managerID *int) ([]domain.Rule, error) {
+	ret := _m.Called(ctx, managerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRules")
+	}
+
+	var r0 []domain.Rule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *int) ([]domain.Rule, error)); ok {
+		return rf(ctx, managerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *int) []domain.Rule); ok {
+		r0 = rf(ctx, managerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Rule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *int) error); ok {
+		r1 = rf(ctx, managerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRule provides a mock function with given fields: ctx, rule
+func (_m *RuleRepo) UpdateRule(ctx context.Context, rule *domain.Rule) error {
+	ret := _m.Called(ctx, rule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Rule) error); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRuleRepo creates a new instance of RuleRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRuleRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RuleRepo {
+	mock := &RuleRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/coverage/domain"
+)
+
+type RuleRepo interface {
+	CreateRule(ctx context.Context, rule *domain.Rule) error
+	GetRuleByID(ctx context.Context, id int) (domain.Rule, error)
+	// GetRules lists the rules, of the manager only when managerID is given
+	GetRules(ctx context.Context, managerID *int) ([]domain.Rule, error)
+	UpdateRule(ctx context.Context, rule *domain.Rule) error
+	DeleteRule(ctx context.Context, id int) error
+}
+
+type ruleRepo struct {
+	db *gorm.DB
+}
+
+func NewRuleRepo(db *gorm.DB) (RuleRepo, error) {
+	repo := &ruleRepo{
+		db: db,
+	}
+
+	if err := repo.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *ruleRepo) ensureSchema() error {
+	// AutoMigrate
+	if err := r.db.AutoMigrate(domain.Rule{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ruleRepo) CreateRule(ctx context.Context, rule *domain.Rule) error {
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return fmt.Errorf("failed to create coverage rule: %w", err)
+	}
+	return nil
+}
+
+func (r *ruleRepo) GetRuleByID(ctx context.Context, id int) (domain.Rule, error) {
+	var rule domain.Rule
+	if err := r.db.WithContext(ctx).First(&rule, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Rule{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Rule{}, fmt.Errorf("failed to find coverage rule with id %d: %w", id, err)
+	}
+	return rule, nil
+}
+
+func (r *ruleRepo) GetRules(ctx context.Context, managerID *int) ([]domain.Rule, error) {
+	var rules []domain.Rule
+
+	db := r.db.WithContext(ctx)
+	if managerID != nil {
+		db = db.Where("manager_id = ?", *managerID)
+	}
+	if err := db.Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to get coverage rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *ruleRepo) UpdateRule(ctx context.Context, rule *domain.Rule) error {
+	result := r.db.WithContext(ctx).Model(rule).
+		Select("ManagerID", "MinWorkingPercent", "AllowOverride").Updates(rule)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update coverage rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return common_errors.ErrResourceNotFound
+	}
+	return nil
+}
+
+func (r *ruleRepo) DeleteRule(ctx context.Context, id int) error {
+	result := r.db.WithContext(ctx).Delete(&domain.Rule{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete coverage rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return common_errors.ErrResourceNotFound
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/coverage/domain"
+)
+
+func setupTestRepo(t *testing.T) *ruleRepo {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := &ruleRepo{db: db}
+	assert.NoError(t, repo.ensureSchema())
+	return repo
+}
+
+func TestRules(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	rule := domain.Rule{ManagerID: 2, MinWorkingPercent: 60}
+	assert.NoError(t, repo.CreateRule(ctx, &rule))
+	assert.NoError(t, repo.CreateRule(ctx, &domain.Rule{ManagerID: 3, MinWorkingPercent: 50}))
+
+	rules, err := repo.GetRules(ctx, common.GetPtr(2))
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+	assert.Equal(t, rule.ID, rules[0].ID)
+
+	// the override can be turned off again
+	rule.AllowOverride = true
+	assert.NoError(t, repo.UpdateRule(ctx, &rule))
+	rule.AllowOverride = false
+	assert.NoError(t, repo.UpdateRule(ctx, &rule))
+	got, err := repo.GetRuleByID(ctx, rule.ID)
+	assert.NoError(t, err)
+	assert.False(t, got.AllowOverride)
+
+	assert.NoError(t, repo.DeleteRule(ctx, rule.ID))
+	_, err = repo.GetRuleByID(ctx, rule.ID)
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+	assert.ErrorIs(t, repo.DeleteRule(ctx, rule.ID), common_errors.ErrResourceNotFound)
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/coverage/domain"
+	"hr-system/internal/coverage/repo"
+	employee_repo "hr-system/internal/employees/repo"
+)
+
+type RuleService interface {
+	CreateRule(ctx context.Context, rule *domain.Rule) (domain.Rule, error)
+	// GetRuleByID returns the rule to HR and to the manager it's of
+	GetRuleByID(ctx context.Context, id int) (domain.Rule, error)
+	// GetRules returns the rules of the manager to HR and to the manager, the rules of everyone only to HR
+	GetRules(ctx context.Context, managerID *int) ([]domain.Rule, error)
+	UpdateRule(ctx context.Context, rule *domain.Rule) (domain.Rule, error)
+	DeleteRule(ctx context.Context, id int) error
+	// GetRuleOfManager returns the rule of the manager's direct reports, nil if they have none
+	GetRuleOfManager(ctx context.Context, managerID int) (*domain.Rule, error)
+}
+
+type ruleService struct {
+	ruleRepo     repo.RuleRepo
+	employeeRepo employee_repo.EmployeeRepo
+	logger       *common.Logger
+	validate     *validator.Validate
+}
+
+func NewRuleService(logger *common.Logger, ruleRepo repo.RuleRepo, employeeRepo employee_repo.EmployeeRepo) RuleService {
+	return &ruleService{
+		ruleRepo:     ruleRepo,
+		employeeRepo: employeeRepo,
+		logger:       logger,
+		validate:     validator.New(),
+	}
+}
+
+func (s *ruleService) validateRule(ctx context.Context, rule *domain.Rule) error {
+	if err := s.validate.Struct(rule); err != nil {
+		return fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	if _, err := s.employeeRepo.GetEmployeeByID(ctx, rule.ManagerID); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return fmt.Errorf("%w, manager %d not found", common_errors.ErrResourceNotFound, rule.ManagerID)
+		}
+		return fmt.Errorf("failed to get manager %d: %w", rule.ManagerID, err)
+	}
+
+	// one rule for the reports of a manager
+	rules, err := s.ruleRepo.GetRules(ctx, &rule.ManagerID)
+	if err != nil {
+		return fmt.Errorf("failed to get coverage rules: %w", err)
+	}
+	for _, r := range rules {
+		if r.ID != rule.ID {
+			return fmt.Errorf("%w, coverage rule %d has the same manager", common_errors.ErrStatusConflict, r.ID)
+		}
+	}
+
+	return nil
+}
+
+func (s *ruleService) CreateRule(ctx context.Context, rule *domain.Rule) (domain.Rule, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.Rule{}, err
+	}
+	if err := s.validateRule(ctx, rule); err != nil {
+		return domain.Rule{}, err
+	}
+	if err := s.ruleRepo.CreateRule(ctx, rule); err != nil {
+		return domain.Rule{}, fmt.Errorf("failed to create coverage rule: %w", err)
+	}
+	return *rule, nil
+}
+
+func (s *ruleService) GetRuleByID(ctx context.Context, id int) (domain.Rule, error) {
+	rule, err := s.ruleRepo.GetRuleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Rule{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Rule{}, fmt.Errorf("failed to get coverage rule: %w", err)
+	}
+	if _, err := auth.AuthorizeSelf(ctx, rule.ManagerID, auth.RoleHRAdmin); err != nil {
+		return domain.Rule{}, err
+	}
+	return rule, nil
+}
+
+func (s *ruleService) GetRules(ctx context.Context, managerID *int) ([]domain.Rule, error) {
+	var err error
+	if managerID == nil {
+		_, err = auth.Authorize(ctx, auth.RoleHRAdmin)
+	} else {
+		_, err = auth.AuthorizeSelf(ctx, *managerID, auth.RoleHRAdmin)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.ruleRepo.GetRules(ctx, managerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coverage rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *ruleService) UpdateRule(ctx context.Context, rule *domain.Rule) (domain.Rule, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.Rule{}, err
+	}
+	if err := s.validateRule(ctx, rule); err != nil {
+		return domain.Rule{}, err
+	}
+	if err := s.ruleRepo.UpdateRule(ctx, rule); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Rule{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Rule{}, fmt.Errorf("failed to update coverage rule: %w", err)
+	}
+	return s.GetRuleByID(ctx, rule.ID)
+}
+
+func (s *ruleService) DeleteRule(ctx context.Context, id int) error {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.DeleteRule(ctx, id); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return common_errors.ErrResourceNotFound
+		}
+		return fmt.Errorf("failed to delete coverage rule: %w", err)
+	}
+	return nil
+}
+
+func (s *ruleService) GetRuleOfManager(ctx context.Context, managerID int) (*domain.Rule, error) {
+	rules, err := s.ruleRepo.GetRules(ctx, &managerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coverage rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return &rules[0], nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/coverage/domain"
+	mocks_rule_repo "hr-system/internal/coverage/repo/mocks"
+	employee_domain "hr-system/internal/employees/domain"
+	mocks_employee_repo "hr-system/internal/employees/repo/mocks"
+)
+
+func TestCreateRule(t *testing.T) {
+	mockRepo := mocks_rule_repo.NewRuleRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	service := NewRuleService(common.NewLogger(), mockRepo, mockEmployeeRepo)
+
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 9, Roles: []auth.Role{auth.RoleHRAdmin}})
+	rule := domain.Rule{ManagerID: 2, MinWorkingPercent: 60}
+
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 2).Return(employee_domain.Employee{ID: 2}, nil).Twice()
+	mockRepo.On("GetRules", ctx, &rule.ManagerID).Return([]domain.Rule{}, nil).Once()
+	mockRepo.On("CreateRule", ctx, &rule).Return(nil).Once()
+	_, err := service.CreateRule(ctx, &rule)
+	assert.NoError(t, err)
+
+	// one rule for the reports of a manager
+	mockRepo.On("GetRules", ctx, &rule.ManagerID).Return([]domain.Rule{{ID: 1, ManagerID: 2}}, nil).Once()
+	_, err = service.CreateRule(ctx, &domain.Rule{ManagerID: 2, MinWorkingPercent: 50})
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+
+	_, err = service.CreateRule(ctx, &domain.Rule{ManagerID: 2, MinWorkingPercent: 120})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// only HR sets the rules
+	managerCtx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 2, Roles: []auth.Role{auth.RoleManager}})
+	_, err = service.CreateRule(managerCtx, &domain.Rule{ManagerID: 2, MinWorkingPercent: 10})
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestRuleMetBy(t *testing.T) {
+	rule := domain.Rule{MinWorkingPercent: 60}
+	assert.True(t, rule.MetBy(3, 5))
+	assert.False(t, rule.MetBy(2, 5))
+	assert.True(t, rule.MetBy(0, 0))
+	// the reports on a part-day leave count for the part they work
+	assert.True(t, rule.MetBy(2.7+0.3, 5))
+	assert.False(t, rule.MetBy(2.5, 5))
+}
+
+func TestGetRules(t *testing.T) {
+	mockRepo := mocks_rule_repo.NewRuleRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	service := NewRuleService(common.NewLogger(), mockRepo, mockEmployeeRepo)
+
+	hrCtx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 9, Roles: []auth.Role{auth.RoleHRAdmin}})
+	managerCtx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 2, Roles: []auth.Role{auth.RoleManager}})
+	otherCtx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 3, Roles: []auth.Role{auth.RoleManager}})
+	rule := domain.Rule{ID: 1, ManagerID: 2, MinWorkingPercent: 60}
+
+	// the rules of everyone are for HR
+	mockRepo.On("GetRules", hrCtx, (*int)(nil)).Return([]domain.Rule{rule}, nil).Once()
+	rules, err := service.GetRules(hrCtx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Rule{rule}, rules)
+	_, err = service.GetRules(managerCtx, nil)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+
+	// a manager sees their own rule
+	mockRepo.On("GetRules", managerCtx, &rule.ManagerID).Return([]domain.Rule{rule}, nil).Once()
+	_, err = service.GetRules(managerCtx, &rule.ManagerID)
+	assert.NoError(t, err)
+	_, err = service.GetRules(otherCtx, &rule.ManagerID)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+
+	mockRepo.On("GetRuleByID", managerCtx, rule.ID).Return(rule, nil).Once()
+	_, err = service.GetRuleByID(managerCtx, rule.ID)
+	assert.NoError(t, err)
+	mockRepo.On("GetRuleByID", otherCtx, rule.ID).Return(rule, nil).Once()
+	_, err = service.GetRuleByID(otherCtx, rule.ID)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
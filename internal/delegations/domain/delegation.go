@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// Delegation lets the delegate review leaves on behalf of the manager from StartDate to EndDate
+type Delegation struct {
+	ID         int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	ManagerID  int       `json:"manager_id" gorm:"not null;index:idx_manager_id" validate:"required"`
+	DelegateID int       `json:"delegate_id" gorm:"not null;index:idx_delegate_id" validate:"required,nefield=ManagerID"`
+	StartDate  time.Time `json:"start_date" gorm:"type:date;not null" validate:"required"`
+	EndDate    time.Time `json:"end_date" gorm:"type:date;not null" validate:"required,gtefield=StartDate"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+type DelegationsQuery struct {
+	ManagerID  *int
+	DelegateID *int
+	// From and To select the delegations active on any day between them
+	From *time.Time
+	To   *time.Time
+}
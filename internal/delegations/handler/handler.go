@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/common"
+	"hr-system/internal/delegations/domain"
+	"hr-system/internal/delegations/service"
+	"hr-system/internal/middleware"
+)
+
+type DelegationHandler struct {
+	delegationService service.DelegationService
+	logger            *common.Logger
+}
+
+func NewDelegationHandler(logger *common.Logger, delegationService service.DelegationService) *DelegationHandler {
+	return &DelegationHandler{
+		delegationService: delegationService,
+		logger:            logger,
+	}
+}
+
+type CreateDelegationRequest struct {
+	ManagerID  int       `json:"manager_id" binding:"required"`
+	DelegateID int       `json:"delegate_id" binding:"required"`
+	StartDate  time.Time `json:"start_date" binding:"required"`
+	EndDate    time.Time `json:"end_date" binding:"required"`
+}
+
+func (h *DelegationHandler) CreateDelegation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	delegation, err := h.delegationService.CreateDelegation(ctx, &domain.Delegation{
+		ManagerID:  req.ManagerID,
+		DelegateID: req.DelegateID,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+	})
+	if err != nil {
+		middleware.HandleErr(c, err, "create delegation")
+		return
+	}
+
+	c.JSON(http.StatusCreated, delegation)
+}
+
+func parseIntQuery(c *gin.Context, key string) (*int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (h *DelegationHandler) GetDelegations(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	managerID, err := parseIntQuery(c, "manager_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid manager_id"))
+		return
+	}
+	delegateID, err := parseIntQuery(c, "delegate_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid delegate_id"))
+		return
+	}
+
+	delegations, err := h.delegationService.GetDelegations(ctx, domain.DelegationsQuery{
+		ManagerID:  managerID,
+		DelegateID: delegateID,
+	})
+	if err != nil {
+		middleware.HandleErr(c, err, "get delegations")
+		return
+	}
+
+	c.JSON(http.StatusOK, delegations)
+}
+
+func (h *DelegationHandler) GetDelegationByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid delegation ID"))
+		return
+	}
+
+	delegation, err := h.delegationService.GetDelegationByID(ctx, id)
+	if err != nil {
+		middleware.HandleErr(c, err, "get delegation")
+		return
+	}
+
+	c.JSON(http.StatusOK, delegation)
+}
+
+func (h *DelegationHandler) DeleteDelegation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid delegation ID"))
+		return
+	}
+
+	if err := h.delegationService.DeleteDelegation(ctx, id); err != nil {
+		middleware.HandleErr(c, err, "delete delegation")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
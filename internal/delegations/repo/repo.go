@@ -0,0 +1,95 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/delegations/domain"
+)
+
+type DelegationRepo interface {
+	CreateDelegation(ctx context.Context, delegation *domain.Delegation) error
+	GetDelegationByID(ctx context.Context, id int) (domain.Delegation, error)
+	GetDelegations(ctx context.Context, query domain.DelegationsQuery) ([]domain.Delegation, error)
+	DeleteDelegation(ctx context.Context, id int) error
+}
+
+type delegationRepo struct {
+	db *gorm.DB
+}
+
+func NewDelegationRepo(db *gorm.DB) (DelegationRepo, error) {
+	repo := &delegationRepo{
+		db: db,
+	}
+
+	if err := repo.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *delegationRepo) ensureSchema() error {
+	// AutoMigrate
+	if err := r.db.AutoMigrate(domain.Delegation{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *delegationRepo) CreateDelegation(ctx context.Context, delegation *domain.Delegation) error {
+	if err := r.db.WithContext(ctx).Create(delegation).Error; err != nil {
+		return fmt.Errorf("failed to create delegation: %w", err)
+	}
+	return nil
+}
+
+func (r *delegationRepo) GetDelegationByID(ctx context.Context, id int) (domain.Delegation, error) {
+	var delegation domain.Delegation
+	if err := r.db.WithContext(ctx).First(&delegation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Delegation{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Delegation{}, fmt.Errorf("failed to find delegation with id %d: %w", id, err)
+	}
+	return delegation, nil
+}
+
+func (r *delegationRepo) GetDelegations(ctx context.Context, query domain.DelegationsQuery) ([]domain.Delegation, error) {
+	var delegations []domain.Delegation
+
+	db := r.db.WithContext(ctx)
+	if query.ManagerID != nil {
+		db = db.Where("manager_id = ?", *query.ManagerID)
+	}
+	if query.DelegateID != nil {
+		db = db.Where("delegate_id = ?", *query.DelegateID)
+	}
+	if query.From != nil {
+		db = db.Where("end_date >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("start_date <= ?", *query.To)
+	}
+	if err := db.Order("start_date ASC, id ASC").Find(&delegations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get delegations: %w", err)
+	}
+
+	return delegations, nil
+}
+
+func (r *delegationRepo) DeleteDelegation(ctx context.Context, id int) error {
+	result := r.db.WithContext(ctx).Delete(&domain.Delegation{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete delegation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return common_errors.ErrResourceNotFound
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/delegations/domain"
+)
+
+func setupTestRepo(t *testing.T) *delegationRepo {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := &delegationRepo{db: db}
+	assert.NoError(t, repo.ensureSchema())
+	return repo
+}
+
+func TestGetDelegations(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	day := func(d int) time.Time {
+		return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC)
+	}
+	delegations := []*domain.Delegation{
+		{ManagerID: 1, DelegateID: 2, StartDate: day(1), EndDate: day(3)},
+		{ManagerID: 1, DelegateID: 3, StartDate: day(10), EndDate: day(12)},
+		{ManagerID: 4, DelegateID: 2, StartDate: day(2), EndDate: day(2)},
+	}
+	for _, delegation := range delegations {
+		assert.NoError(t, repo.CreateDelegation(ctx, delegation))
+	}
+
+	// active on the day
+	from, to := day(2), day(2)
+	fetched, err := repo.GetDelegations(ctx, domain.DelegationsQuery{DelegateID: common.GetPtr(2), From: &from, To: &to})
+	assert.NoError(t, err)
+	assert.Len(t, fetched, 2)
+
+	from, to = day(4), day(9)
+	fetched, err = repo.GetDelegations(ctx, domain.DelegationsQuery{ManagerID: common.GetPtr(1), From: &from, To: &to})
+	assert.NoError(t, err)
+	assert.Len(t, fetched, 0)
+
+	assert.NoError(t, repo.DeleteDelegation(ctx, delegations[0].ID))
+	_, err = repo.GetDelegationByID(ctx, delegations[0].ID)
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+}
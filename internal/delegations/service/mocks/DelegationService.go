@@ -0,0 +1,195 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/delegations/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// DelegationService is an autogenerated mock type for the DelegationService type
+type DelegationService struct {
+	mock.Mock
+}
+
+// CreateDelegation provides a mock function with given fields: ctx, delegation
+func (_m *DelegationService) CreateDelegation(ctx context.Context, delegation *domain.Delegation) (domain.Delegation, error) {
+	ret := _m.Called(ctx, delegation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDelegation")
+	}
+
+	var r0 domain.Delegation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Delegation) (domain.Delegation, error)); ok {
+		return rf(ctx, delegation)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Delegation) domain.Delegation); ok {
+		r0 = rf(ctx, delegation)
+	} else {
+		r0 = ret.Get(0).(domain.Delegation)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Delegation) error); ok {
+		r1 = rf(ctx, delegation)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteDelegation provides a mock function with given fields: ctx, id
+func (_m *DelegationService) DeleteDelegation(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDelegation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDelegate provides a mock function with given fields: ctx, managerID, at
+func (_m *DelegationService) GetDelegate(ctx context.Context, managerID int, at time.Time) (*int, error) {
+	ret := _m.Called(ctx, managerID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDelegate")
+	}
+
+	var r0 *int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) (*int, error)); ok {
+		return rf(ctx, managerID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) *int); ok {
+		r0 = rf(ctx, managerID, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = rf(ctx, managerID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDelegationByID provides a mock function with given fields: ctx, id
+func (_m *DelegationService) GetDelegationByID(ctx context.Context, id int) (domain.Delegation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDelegationByID")
+	}
+
+	var r0 domain.Delegation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (domain.Delegation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) domain.Delegation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Delegation)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDelegations provides a mock function with given fields: ctx, query
+func (_m *DelegationService) GetDelegations(ctx context.Context, query domain.DelegationsQuery) ([]domain.Delegation, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDelegations")
+	}
+
+	var r0 []domain.Delegation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.DelegationsQuery) ([]domain.Delegation, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.DelegationsQuery) []domain.Delegation); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Delegation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.DelegationsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDelegators provides a mock function with given fields: ctx, delegateID, at
+func (_m *DelegationService) GetDelegators(ctx context.Context, delegateID int, at time.Time) ([]int, error) {
+	ret := _m.Called(ctx, delegateID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDelegators")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) ([]int, error)); ok {
+		return rf(ctx, delegateID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) []int); ok {
+		r0 = rf(ctx, delegateID, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = rf(ctx, delegateID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDelegationService creates a new instance of DelegationService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDelegationService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DelegationService {
+	mock := &DelegationService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
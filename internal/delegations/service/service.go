@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/delegations/domain"
+	"hr-system/internal/delegations/repo"
+	employee_repo "hr-system/internal/employees/repo"
+)
+
+type DelegationService interface {
+	CreateDelegation(ctx context.Context, delegation *domain.Delegation) (domain.Delegation, error)
+	GetDelegationByID(ctx context.Context, id int) (domain.Delegation, error)
+	GetDelegations(ctx context.Context, query domain.DelegationsQuery) ([]domain.Delegation, error)
+	DeleteDelegation(ctx context.Context, id int) error
+	// GetDelegate returns the employee reviewing on behalf of the manager at the time, nil if there is none
+	GetDelegate(ctx context.Context, managerID int, at time.Time) (*int, error)
+	// GetDelegators returns the managers the delegate reviews on behalf of at the time
+	GetDelegators(ctx context.Context, delegateID int, at time.Time) ([]int, error)
+}
+
+type delegationService struct {
+	delegationRepo repo.DelegationRepo
+	employeeRepo   employee_repo.EmployeeRepo
+	logger         *common.Logger
+	validate       *validator.Validate
+}
+
+func NewDelegationService(logger *common.Logger, delegationRepo repo.DelegationRepo,
+	employeeRepo employee_repo.EmployeeRepo) DelegationService {
+	return &delegationService{
+		delegationRepo: delegationRepo,
+		employeeRepo:   employeeRepo,
+		logger:         logger,
+		validate:       validator.New(),
+	}
+}
+
+// dayOf returns the start of the day of t, dates in the database are compared in the same location
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (s *delegationService) CreateDelegation(ctx context.Context,
+	delegation *domain.Delegation) (domain.Delegation, error) {
+	if _, err := auth.AuthorizeSelf(ctx, delegation.ManagerID, auth.RoleHRAdmin); err != nil {
+		return domain.Delegation{}, err
+	}
+	if err := s.validate.Struct(delegation); err != nil {
+		return domain.Delegation{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+
+	for _, id := range []int{delegation.ManagerID, delegation.DelegateID} {
+		if _, err := s.employeeRepo.GetEmployeeByID(ctx, id); err != nil {
+			if errors.Is(err, common_errors.ErrResourceNotFound) {
+				return domain.Delegation{}, fmt.Errorf("%w, employee %d not found", common_errors.ErrResourceNotFound, id)
+			}
+			return domain.Delegation{}, fmt.Errorf("failed to get employee %d: %w", id, err)
+		}
+	}
+
+	// a manager has at most one delegate on a day
+	delegations, err := s.delegationRepo.GetDelegations(ctx, domain.DelegationsQuery{
+		ManagerID: &delegation.ManagerID,
+		From:      &delegation.StartDate,
+		To:        &delegation.EndDate,
+	})
+	if err != nil {
+		return domain.Delegation{}, fmt.Errorf("failed to get delegations: %w", err)
+	}
+	if len(delegations) > 0 {
+		return domain.Delegation{}, fmt.Errorf("%w, delegation %d overlaps the date range",
+			common_errors.ErrStatusConflict, delegations[0].ID)
+	}
+
+	if err := s.delegationRepo.CreateDelegation(ctx, delegation); err != nil {
+		return domain.Delegation{}, fmt.Errorf("failed to create delegation: %w", err)
+	}
+	return *delegation, nil
+}
+
+// authorizeDelegation lets the manager and the delegate of the delegation access it on top of HR
+func authorizeDelegation(ctx context.Context, delegation *domain.Delegation) error {
+	if _, err := auth.AuthorizeSelf(ctx, delegation.DelegateID); err == nil {
+		return nil
+	}
+	_, err := auth.AuthorizeSelf(ctx, delegation.ManagerID, auth.RoleHRAdmin)
+	return err
+}
+
+func (s *delegationService) GetDelegationByID(ctx context.Context, id int) (domain.Delegation, error) {
+	delegation, err := s.delegationRepo.GetDelegationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Delegation{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Delegation{}, fmt.Errorf("failed to get delegation: %w", err)
+	}
+	if err := authorizeDelegation(ctx, &delegation); err != nil {
+		return domain.Delegation{}, err
+	}
+	return delegation, nil
+}
+
+func (s *delegationService) GetDelegations(ctx context.Context,
+	query domain.DelegationsQuery) ([]domain.Delegation, error) {
+	if query.From != nil && query.To != nil && query.From.After(*query.To) {
+		return nil, fmt.Errorf("%w, from must not be after to", common_errors.ErrInvalidInput)
+	}
+	// besides HR, one only lists the delegations they give or receive
+	actor, err := auth.Authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.HasRole(auth.RoleHRAdmin) {
+		ownManager := query.ManagerID != nil && *query.ManagerID == actor.EmployeeID
+		ownDelegate := query.DelegateID != nil && *query.DelegateID == actor.EmployeeID
+		if !ownManager && !ownDelegate {
+			return nil, fmt.Errorf("%w, manager_id or delegate_id must be the caller", common_errors.ErrForbidden)
+		}
+	}
+
+	delegations, err := s.delegationRepo.GetDelegations(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegations: %w", err)
+	}
+	return delegations, nil
+}
+
+func (s *delegationService) DeleteDelegation(ctx context.Context, id int) error {
+	delegation, err := s.GetDelegationByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := auth.AuthorizeSelf(ctx, delegation.ManagerID, auth.RoleHRAdmin); err != nil {
+		return err
+	}
+
+	if err := s.delegationRepo.DeleteDelegation(ctx, id); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return common_errors.ErrResourceNotFound
+		}
+		return fmt.Errorf("failed to delete delegation: %w", err)
+	}
+	return nil
+}
+
+func (s *delegationService) GetDelegate(ctx context.Context, managerID int, at time.Time) (*int, error) {
+	day := dayOf(at)
+	delegations, err := s.delegationRepo.GetDelegations(ctx, domain.DelegationsQuery{
+		ManagerID: &managerID,
+		From:      &day,
+		To:        &day,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegations: %w", err)
+	}
+	if len(delegations) == 0 {
+		return nil, nil
+	}
+	return &delegations[0].DelegateID, nil
+}
+
+func (s *delegationService) GetDelegators(ctx context.Context, delegateID int, at time.Time) ([]int, error) {
+	day := dayOf(at)
+	delegations, err := s.delegationRepo.GetDelegations(ctx, domain.DelegationsQuery{
+		DelegateID: &delegateID,
+		From:       &day,
+		To:         &day,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegations: %w", err)
+	}
+
+	managerIDs := make([]int, 0, len(delegations))
+	for _, delegation := range delegations {
+		managerIDs = append(managerIDs, delegation.ManagerID)
+	}
+	return managerIDs, nil
+}
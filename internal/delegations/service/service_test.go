@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/delegations/domain"
+	mocks_delegation_repo "hr-system/internal/delegations/repo/mocks"
+	employee_domain "hr-system/internal/employees/domain"
+	mocks_employee_repo "hr-system/internal/employees/repo/mocks"
+)
+
+func TestCreateDelegation(t *testing.T) {
+	mockRepo := mocks_delegation_repo.NewDelegationRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	service := NewDelegationService(common.NewLogger(), mockRepo, mockEmployeeRepo)
+
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 1, Roles: []auth.Role{auth.RoleManager}})
+	delegation := domain.Delegation{
+		ManagerID:  1,
+		DelegateID: 2,
+		StartDate:  time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:    time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(employee_domain.Employee{ID: 1}, nil).Twice()
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 2).Return(employee_domain.Employee{ID: 2}, nil).Twice()
+	mockRepo.On("GetDelegations", ctx, mock.Anything).Return([]domain.Delegation{}, nil).Once()
+	mockRepo.On("CreateDelegation", ctx, &delegation).Return(nil).Once()
+
+	_, err := service.CreateDelegation(ctx, &delegation)
+	assert.NoError(t, err)
+
+	// one delegate for a manager on a day
+	mockRepo.On("GetDelegations", ctx, mock.Anything).Return([]domain.Delegation{{ID: 1}}, nil).Once()
+	_, err = service.CreateDelegation(ctx, &delegation)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+
+	// a manager can not delegate to themselves
+	_, err = service.CreateDelegation(ctx, &domain.Delegation{ManagerID: 1, DelegateID: 1,
+		StartDate: delegation.StartDate, EndDate: delegation.EndDate})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// the range must not end before it starts
+	_, err = service.CreateDelegation(ctx, &domain.Delegation{ManagerID: 1, DelegateID: 2,
+		StartDate: delegation.EndDate, EndDate: delegation.StartDate})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// nobody delegates the reviews of another manager
+	_, err = service.CreateDelegation(ctx, &domain.Delegation{ManagerID: 3, DelegateID: 2,
+		StartDate: delegation.StartDate, EndDate: delegation.EndDate})
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestGetDelegate(t *testing.T) {
+	mockRepo := mocks_delegation_repo.NewDelegationRepo(t)
+	service := NewDelegationService(common.NewLogger(), mockRepo, mocks_employee_repo.NewEmployeeRepo(t))
+
+	ctx := context.Background()
+	at := time.Date(2024, 5, 2, 15, 30, 0, 0, time.UTC)
+	day := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetDelegations", ctx, domain.DelegationsQuery{ManagerID: common.GetPtr(1), From: &day, To: &day}).
+		Return([]domain.Delegation{{ManagerID: 1, DelegateID: 2}}, nil).Once()
+	mockRepo.On("GetDelegations", ctx, domain.DelegationsQuery{ManagerID: common.GetPtr(3), From: &day, To: &day}).
+		Return([]domain.Delegation{}, nil).Once()
+
+	delegateID, err := service.GetDelegate(ctx, 1, at)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *delegateID)
+
+	delegateID, err = service.GetDelegate(ctx, 3, at)
+	assert.NoError(t, err)
+	assert.Nil(t, delegateID)
+}
@@ -2,6 +2,8 @@ package cache
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,9 +20,15 @@ type EmployeeCache interface {
 	GetEmployeeByID(ctx context.Context, id int) (domain.Employee, error)
 	SetEmployeeToCache(ctx context.Context, employee *domain.Employee, expiration time.Duration) error
 	DeleteEmployeeCache(ctx context.Context, id int) error
-	GetEmployees(ctx context.Context, page, pageSize int) (employees []domain.Employee, totalCount int, er error)
-	SetEmployeesToCache(ctx context.Context, page, pageSize int, employees []domain.Employee, totalCount int, expiration time.Duration) error
+	GetEmployees(ctx context.Context, query domain.EmployeesQuery) (domain.EmployeesPage, error)
+	SetEmployeesToCache(ctx context.Context, query domain.EmployeesQuery, page *domain.EmployeesPage, expiration time.Duration) error
 	DeleteEmployeesListCache(ctx context.Context) error
+	GetReports(ctx context.Context, id, depth int) (domain.OrgNode, error)
+	SetReportsToCache(ctx context.Context, id, depth int, reports *domain.OrgNode, expiration time.Duration) error
+	GetChain(ctx context.Context, id int) ([]domain.OrgNode, error)
+	SetChainToCache(ctx context.Context, id int, chain []domain.OrgNode, expiration time.Duration) error
+	// DeleteOrgChartCache deletes the cached reports and chains of every employee
+	DeleteOrgChartCache(ctx context.Context) error
 }
 
 func NewEmployeeCache(c *cache.Cache, prefix string) EmployeeCache {
@@ -82,56 +90,105 @@ func (e *employeeCache) genEmployeesListCachePrefix() string {
 	return fmt.Sprintf("%s_list", e.prefix)
 }
 
-func (e *employeeCache) genEmployeesListCacheKey(page, pageSize int) string {
+func (e *employeeCache) genEmployeesListCacheKey(query domain.EmployeesQuery) string {
 	prefix := e.genEmployeesListCachePrefix()
-	return fmt.Sprintf("%s_page_%d_page_size_%d", prefix, page, pageSize)
+	return fmt.Sprintf("%s_page_%d_page_size_%d_filter_%s", prefix, query.Page, query.PageSize, hashFilter(query))
 }
 
-type EmployeesCacheData struct {
-	Employees  []domain.Employee `json:"employees"`
-	TotalCount int               `json:"total_count"`
+// hashFilter returns a short hash of everything in the normalized query except the page
+func hashFilter(query domain.EmployeesQuery) string {
+	query.Normalize()
+	query.Page, query.PageSize = 0, 0
+	data, _ := json.Marshal(query)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
 }
 
-func (e *employeeCache) GetEmployees(ctx context.Context, page, pageSize int) ([]domain.Employee, int, error) {
-	cacheKey := e.genEmployeesListCacheKey(page, pageSize)
-	data, err := e.cache.Get(ctx, cacheKey)
+func (e *employeeCache) GetEmployees(ctx context.Context, query domain.EmployeesQuery) (domain.EmployeesPage, error) {
+	var page domain.EmployeesPage
+	if err := e.get(ctx, e.genEmployeesListCacheKey(query), &page); err != nil {
+		return domain.EmployeesPage{}, err
+	}
+	return page, nil
+}
+
+func (e *employeeCache) SetEmployeesToCache(ctx context.Context, query domain.EmployeesQuery,
+	page *domain.EmployeesPage, expiration time.Duration) error {
+	if page == nil {
+		return errors.New("page is nil")
+	}
+	return e.set(ctx, e.genEmployeesListCacheKey(query), page, expiration)
+}
+
+func (e *employeeCache) DeleteEmployeesListCache(ctx context.Context) error {
+	return e.cache.DelByPrefix(ctx, e.genEmployeesListCachePrefix())
+}
+
+func (e *employeeCache) genOrgChartCachePrefix() string {
+	return fmt.Sprintf("%s_org", e.prefix)
+}
+
+func (e *employeeCache) genReportsCacheKey(id, depth int) string {
+	return fmt.Sprintf("%s_reports_%d_depth_%d", e.genOrgChartCachePrefix(), id, depth)
+}
+
+func (e *employeeCache) genChainCacheKey(id int) string {
+	return fmt.Sprintf("%s_chain_%d", e.genOrgChartCachePrefix(), id)
+}
+
+// get unmarshals the value of the key into v, it returns ErrResourceNotFound on a cache miss
+func (e *employeeCache) get(ctx context.Context, key string, v interface{}) error {
+	data, err := e.cache.Get(ctx, key)
 	if errors.Is(err, redis.Nil) {
-		return nil, 0, common_errors.ErrResourceNotFound
+		return common_errors.ErrResourceNotFound
 	} else if err != nil {
-		return nil, 0, err
+		return err
 	}
 
 	if data == "" {
-		return nil, 0, common_errors.ErrResourceNotFound
+		return common_errors.ErrResourceNotFound
 	}
 
-	var employees EmployeesCacheData
-	err = json.Unmarshal([]byte(data), &employees)
+	return json.Unmarshal([]byte(data), v)
+}
+
+func (e *employeeCache) set(ctx context.Context, key string, v interface{}, expiration time.Duration) error {
+	jsonData, err := json.Marshal(v)
 	if err != nil {
-		return nil, 0, err
+		return err
 	}
-
-	return employees.Employees, employees.TotalCount, nil
+	return e.cache.Set(ctx, key, string(jsonData), expiration)
 }
 
-func (e *employeeCache) SetEmployeesToCache(ctx context.Context, page, pageSize int, employees []domain.Employee,
-	totalCount int, expiration time.Duration) error {
-
-	cacheData := EmployeesCacheData{
-		Employees:  employees,
-		TotalCount: totalCount,
+func (e *employeeCache) GetReports(ctx context.Context, id, depth int) (domain.OrgNode, error) {
+	var reports domain.OrgNode
+	if err := e.get(ctx, e.genReportsCacheKey(id, depth), &reports); err != nil {
+		return domain.OrgNode{}, err
 	}
+	return reports, nil
+}
 
-	cacheKey := e.genEmployeesListCacheKey(page, pageSize)
+func (e *employeeCache) SetReportsToCache(ctx context.Context, id, depth int, reports *domain.OrgNode,
+	expiration time.Duration) error {
+	if reports == nil {
+		return errors.New("reports is nil")
+	}
+	return e.set(ctx, e.genReportsCacheKey(id, depth), reports, expiration)
+}
 
-	jsonData, err := json.Marshal(cacheData)
-	if err != nil {
-		return err
+func (e *employeeCache) GetChain(ctx context.Context, id int) ([]domain.OrgNode, error) {
+	var chain []domain.OrgNode
+	if err := e.get(ctx, e.genChainCacheKey(id), &chain); err != nil {
+		return nil, err
 	}
+	return chain, nil
+}
 
-	return e.cache.Set(ctx, cacheKey, string(jsonData), expiration)
+func (e *employeeCache) SetChainToCache(ctx context.Context, id int, chain []domain.OrgNode,
+	expiration time.Duration) error {
+	return e.set(ctx, e.genChainCacheKey(id), chain, expiration)
 }
 
-func (e *employeeCache) DeleteEmployeesListCache(ctx context.Context) error {
-	return e.cache.DelByPrefix(ctx, e.genEmployeesListCachePrefix())
+func (e *employeeCache) DeleteOrgChartCache(ctx context.Context) error {
+	return e.cache.DelByPrefix(ctx, e.genOrgChartCachePrefix())
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"hr-system/internal/cache"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
 	"hr-system/internal/employees/domain"
 )
 
@@ -83,6 +85,11 @@ func TestEmployeeCache_DeleteEmployeeCache(t *testing.T) {
 	assert.Equal(t, redis.Nil, err)
 }
 
+func activeListKey() string {
+	return "test_list_page_1_page_size_2_filter_" +
+		hashFilter(domain.EmployeesQuery{Status: domain.EmployeeStatusActive})
+}
+
 func TestEmployeeCache_GetEmployees(t *testing.T) {
 	mr, c := setupTestRedis()
 	defer mr.Close()
@@ -93,19 +100,20 @@ func TestEmployeeCache_GetEmployees(t *testing.T) {
 		{ID: 1, Name: "John Doe"},
 		{ID: 2, Name: "Jane Doe"},
 	}
-	cacheData := EmployeesCacheData{
+	cacheData := domain.EmployeesPage{
 		Employees:  employees,
-		TotalCount: 2,
+		TotalCount: common.GetPtr(2),
 	}
 	jsonData, _ := json.Marshal(cacheData)
-	mr.Set("test_list_page_1_page_size_2", string(jsonData))
+	mr.Set(activeListKey(), string(jsonData))
 
 	ctx := context.Background()
-	result, totalCount, err := employeeCache.GetEmployees(ctx, 1, 2)
+	page, err := employeeCache.GetEmployees(ctx,
+		domain.EmployeesQuery{Page: 1, PageSize: 2, Status: domain.EmployeeStatusActive})
 
 	assert.NoError(t, err)
-	assert.Equal(t, employees, result)
-	assert.Equal(t, 2, totalCount)
+	assert.Equal(t, employees, page.Employees)
+	assert.Equal(t, common.GetPtr(2), page.TotalCount)
 }
 
 func TestEmployeeCache_SetEmployeesToCache(t *testing.T) {
@@ -118,17 +126,21 @@ func TestEmployeeCache_SetEmployeesToCache(t *testing.T) {
 		{ID: 2, Name: "Jane Doe"},
 	}
 	ctx := context.Background()
-	err := employeeCache.SetEmployeesToCache(ctx, 1, 2, employees, 2, time.Minute)
+	next := &domain.EmployeeCursor{ID: 2}
+	err := employeeCache.SetEmployeesToCache(ctx,
+		domain.EmployeesQuery{Page: 1, PageSize: 2, Status: domain.EmployeeStatusActive},
+		&domain.EmployeesPage{Employees: employees, TotalCount: common.GetPtr(2), Next: next}, time.Minute)
 	assert.NoError(t, err)
 
-	data, err := c.Get(ctx, "test_list_page_1_page_size_2")
+	data, err := c.Get(ctx, activeListKey())
 	assert.NoError(t, err)
 
-	var cachedData EmployeesCacheData
+	var cachedData domain.EmployeesPage
 	err = json.Unmarshal([]byte(data), &cachedData)
 	assert.NoError(t, err)
 	assert.Equal(t, employees, cachedData.Employees)
-	assert.Equal(t, 2, cachedData.TotalCount)
+	assert.Equal(t, common.GetPtr(2), cachedData.TotalCount)
+	assert.Equal(t, next, cachedData.Next)
 }
 
 func TestEmployeeCache_DeleteEmployeesListCache(t *testing.T) {
@@ -141,17 +153,77 @@ func TestEmployeeCache_DeleteEmployeesListCache(t *testing.T) {
 		{ID: 1, Name: "John Doe"},
 		{ID: 2, Name: "Jane Doe"},
 	}
-	cacheData := EmployeesCacheData{
+	cacheData := domain.EmployeesPage{
 		Employees:  employees,
-		TotalCount: 2,
+		TotalCount: common.GetPtr(2),
 	}
 	jsonData, _ := json.Marshal(cacheData)
-	mr.Set("test_list_page_1_page_size_2", string(jsonData))
+	mr.Set(activeListKey(), string(jsonData))
 
 	ctx := context.Background()
 	err := employeeCache.DeleteEmployeesListCache(ctx)
 	assert.NoError(t, err)
 
-	_, err = c.Get(ctx, "test_list_page_1_page_size_2")
+	_, err = c.Get(ctx, activeListKey())
 	assert.Equal(t, redis.Nil, err)
 }
+
+func TestEmployeeCache_ListCacheKey(t *testing.T) {
+	employeeCache := &employeeCache{prefix: "test"}
+
+	query := domain.EmployeesQuery{Page: 1, PageSize: 2, Search: "Doe", Sort: []domain.SortField{{Field: "name"}}}
+	key := employeeCache.genEmployeesListCacheKey(query)
+
+	// the same filters after normalization
+	same := query
+	same.Search = " doe "
+	same.Status = domain.EmployeeStatusActive
+	assert.Equal(t, key, employeeCache.genEmployeesListCacheKey(same))
+
+	// but not another page, filter or order
+	other := query
+	other.Page = 2
+	assert.NotEqual(t, key, employeeCache.genEmployeesListCacheKey(other))
+	other = query
+	other.ManagerID = common.GetPtr(1)
+	assert.NotEqual(t, key, employeeCache.genEmployeesListCacheKey(other))
+	other = query
+	other.Sort = []domain.SortField{{Field: "name", Desc: true}}
+	assert.NotEqual(t, key, employeeCache.genEmployeesListCacheKey(other))
+	other = query
+	other.After = &domain.EmployeeCursor{ID: 1}
+	assert.NotEqual(t, key, employeeCache.genEmployeesListCacheKey(other))
+	other = query
+	other.SkipCount = true
+	assert.NotEqual(t, key, employeeCache.genEmployeesListCacheKey(other))
+}
+
+func TestEmployeeCache_OrgChart(t *testing.T) {
+	mr, c := setupTestRedis()
+	defer mr.Close()
+
+	employeeCache := NewEmployeeCache(c, "test")
+	ctx := context.Background()
+
+	reports := &domain.OrgNode{ID: 1, Name: "Boss", Reports: []domain.OrgNode{{ID: 2, Name: "John Doe"}}}
+	assert.NoError(t, employeeCache.SetReportsToCache(ctx, 1, 2, reports, time.Minute))
+	chain := []domain.OrgNode{{ID: 1, Name: "Boss"}}
+	assert.NoError(t, employeeCache.SetChainToCache(ctx, 2, chain, time.Minute))
+
+	cachedReports, err := employeeCache.GetReports(ctx, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, *reports, cachedReports)
+	cachedChain, err := employeeCache.GetChain(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, chain, cachedChain)
+
+	// another depth is another tree
+	_, err = employeeCache.GetReports(ctx, 1, 0)
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+
+	assert.NoError(t, employeeCache.DeleteOrgChartCache(ctx))
+	_, err = employeeCache.GetReports(ctx, 1, 2)
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+	_, err = employeeCache.GetChain(ctx, 2)
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+}
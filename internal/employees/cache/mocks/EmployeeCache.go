@@ -52,6 +52,54 @@ func (_m *EmployeeCache) DeleteEmployeesListCache(ctx context.Context) error {
 	return r0
 }
 
+// DeleteOrgChartCache provides a mock function with given fields: ctx
+func (_m *EmployeeCache) DeleteOrgChartCache(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOrgChartCache")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetChain provides a mock function with given fields: ctx, id
+func (_m *EmployeeCache) GetChain(ctx context.Context, id int) ([]domain.OrgNode, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChain")
+	}
+
+	var r0 []domain.OrgNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.OrgNode, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.OrgNode); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.OrgNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEmployeeByID provides a mock function with given fields: ctx, id
 func (_m *EmployeeCache) GetEmployeeByID(ctx context.Context, id int) (domain.Employee, error) {
 	ret := _m.Called(ctx, id)
@@ -80,41 +128,78 @@ func (_m *EmployeeCache) GetEmployeeByID(ctx context.Context, id int) (domain.Em
 	return r0, r1
 }
 
-// GetEmployees provides a mock function with given fields: ctx, page, pageSize
-func (_m *EmployeeCache) GetEmployees(ctx context.Context, page int, pageSize int) ([]domain.Employee, int, error) {
-	ret := _m.Called(ctx, page, pageSize)
+// GetEmployees provides a mock function with given fields: ctx, query
+func (_m *EmployeeCache) GetEmployees(ctx context.Context, query domain.EmployeesQuery) (domain.EmployeesPage, error) {
+	ret := _m.Called(ctx, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetEmployees")
 	}
 
-	var r0 []domain.Employee
-	var r1 int
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.Employee, int, error)); ok {
-		return rf(ctx, page, pageSize)
+	var r0 domain.EmployeesPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.EmployeesQuery) (domain.EmployeesPage, error)); ok {
+		return rf(ctx, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.Employee); ok {
-		r0 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.EmployeesQuery) domain.EmployeesPage); ok {
+		r0 = rf(ctx, query)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Employee)
-		}
+		r0 = ret.Get(0).(domain.EmployeesPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.EmployeesQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReports provides a mock function with given fields: ctx, id, depth
+func (_m *EmployeeCache) GetReports(ctx context.Context, id int, depth int) (domain.OrgNode, error) {
+	ret := _m.Called(ctx, id, depth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReports")
+	}
+
+	var r0 domain.OrgNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (domain.OrgNode, error)); ok {
+		return rf(ctx, id, depth)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) domain.OrgNode); ok {
+		r0 = rf(ctx, id, depth)
+	} else {
+		r0 = ret.Get(0).(domain.OrgNode)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
-		r1 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, id, depth)
 	} else {
-		r1 = ret.Get(1).(int)
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetChainToCache provides a mock function with given fields: ctx, id, chain, expiration
+func (_m *EmployeeCache) SetChainToCache(ctx context.Context, id int, chain []domain.OrgNode, expiration time.Duration) error {
+	ret := _m.Called(ctx, id, chain, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetChainToCache")
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
-		r2 = rf(ctx, page, pageSize)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []domain.OrgNode, time.Duration) error); ok {
+		r0 = rf(ctx, id, chain, expiration)
 	} else {
-		r2 = ret.Error(2)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1, r2
+	return r0
 }
 
 // SetEmployeeToCache provides a mock function with given fields: ctx, employee, expiration
@@ -135,17 +220,35 @@ func (_m *EmployeeCache) SetEmployeeToCache(ctx context.Context, employee *domai
 	return r0
 }
 
-// SetEmployeesToCache provides a mock function with given fields: ctx, page, pageSize, employees, totalCount, expiration
-func (_m *EmployeeCache) SetEmployeesToCache(ctx context.Context, page int, pageSize int, employees []domain.Employee, totalCount int, expiration time.Duration) error {
-	ret := _m.Called(ctx, page, pageSize, employees, totalCount, expiration)
+// SetEmployeesToCache provides a mock function with given fields: ctx, query, page, expiration
+func (_m *EmployeeCache) SetEmployeesToCache(ctx context.Context, query domain.EmployeesQuery, page *domain.EmployeesPage, expiration time.Duration) error {
+	ret := _m.Called(ctx, query, page, expiration)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetEmployeesToCache")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int, []domain.Employee, int, time.Duration) error); ok {
-		r0 = rf(ctx, page, pageSize, employees, totalCount, expiration)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.EmployeesQuery, *domain.EmployeesPage, time.Duration) error); ok {
+		r0 = rf(ctx, query, page, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetReportsToCache provides a mock function with given fields: ctx, id, depth, reports, expiration
+func (_m *EmployeeCache) SetReportsToCache(ctx context.Context, id int, depth int, reports *domain.OrgNode, expiration time.Duration) error {
+	ret := _m.Called(ctx, id, depth, reports, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetReportsToCache")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *domain.OrgNode, time.Duration) error); ok {
+		r0 = rf(ctx, id, depth, reports, expiration)
 	} else {
 		r0 = ret.Error(0)
 	}
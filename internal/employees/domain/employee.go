@@ -1,24 +1,249 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Employee struct {
 	ID          int        `json:"id"`
-	Name        string     `json:"name" validate:"required"`
-	Email       string     `json:"email" validate:"required"`
-	Address     string     `json:"address"`
-	PhoneNumber string     `json:"phone_number"`
+	Name        string     `json:"name" validate:"required,max=255"`
+	Email       string     `json:"email" validate:"required,email,max=255"`
+	Address     string     `json:"address" validate:"max=255"`
+	PhoneNumber string     `json:"phone_number" validate:"max=20"`
+	Region      string     `json:"region" validate:"max=50"`
 	Positions   []Position `json:"positions" validate:"required,gt=0,dive"`
 	ManagerID   *int       `json:"manager_id,omitempty"`
 	Manager     *Employee  `json:"manager,omitempty"`
+	Version     int        `json:"version"` // increased by every update, an update must be based on the latest one
+	// TerminationDate is the last day of the employee in the company
+	TerminationDate *time.Time `json:"termination_date,omitempty"`
+}
+
+// TerminatedAt reports whether the employee has left the company at the time
+func (e *Employee) TerminatedAt(t time.Time) bool {
+	return e.TerminationDate != nil && !t.Before(e.TerminationDate.AddDate(0, 0, 1))
+}
+
+type EmployeeStatus string
+
+const (
+	// EmployeeStatusActive is the employees who have not left yet, including the ones leaving in the future
+	EmployeeStatusActive     EmployeeStatus = "active"
+	EmployeeStatusTerminated EmployeeStatus = "terminated"
+	EmployeeStatusAll        EmployeeStatus = "all"
+)
+
+// Matches reports whether the employee is in the status at the time
+func (s EmployeeStatus) Matches(e *Employee, t time.Time) bool {
+	switch s {
+	case EmployeeStatusActive:
+		return !e.TerminatedAt(t)
+	case EmployeeStatusTerminated:
+		return e.TerminatedAt(t)
+	default:
+		return true
+	}
+}
+
+type EmployeesQuery struct {
+	Page     int            `validate:"gte=1"`
+	PageSize int            `validate:"gte=1,lte=100"`
+	Status   EmployeeStatus `validate:"omitempty,oneof=active terminated all"` // active if empty
+	Search   string         `validate:"max=255"`                               // a part of the name or the email
+	// ManagerID keeps the direct reports of the manager
+	ManagerID *int
+	Title     string      `validate:"max=255"` // a part of the title of the current position
+	Level     string      `validate:"max=50"`  // the level of the current position
+	Sort      []SortField `validate:"dive"`    // by ID when empty, ties are ordered by ID
+	// After continues the list after the cursor, the page is ignored then
+	After *EmployeeCursor
+	// SkipCount leaves out the total count, which costs a scan of every employee matching the filters
+	SkipCount bool
+	// IDs restricts the list to the employees, nil for no restriction
+	IDs []int
+}
+
+// EmployeesPage is a page of the employees list
+type EmployeesPage struct {
+	Employees []Employee `json:"employees"`
+	// TotalCount is nil when the query skips the count
+	TotalCount *int `json:"total_count,omitempty"`
+	// Next is where the next page starts, nil on the last page
+	Next *EmployeeCursor `json:"next,omitempty"`
+}
+
+// EmployeeCursor holds the sort values of the last employee of a page, the next page starts after them
+type EmployeeCursor struct {
+	Sort      string     `json:"sort,omitempty"` // the sort the values are of, formatted by FormatSort
+	ID        int        `json:"id"`
+	Name      string     `json:"name,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	Region    string     `json:"region,omitempty"`
+	StartDate *time.Time `json:"start_date,omitempty"`
+}
+
+// NewEmployeeCursor returns the cursor after the employee in the sort, it only keeps the values the sort uses
+func NewEmployeeCursor(e *Employee, sort []SortField) *EmployeeCursor {
+	cursor := &EmployeeCursor{Sort: FormatSort(sort), ID: e.ID}
+	for _, field := range sort {
+		switch field.Field {
+		case "name":
+			cursor.Name = e.Name
+		case "email":
+			cursor.Email = e.Email
+		case "region":
+			cursor.Region = e.Region
+		case "start_date":
+			startDate := e.StartDate()
+			cursor.StartDate = &startDate
+		}
+	}
+	return cursor
+}
+
+// Normalize fills the defaults of the query and trims the filters, so equal queries look the same
+func (q *EmployeesQuery) Normalize() {
+	if q.Status == "" {
+		q.Status = EmployeeStatusActive
+	}
+	q.Search = strings.ToLower(strings.TrimSpace(q.Search))
+	q.Title = strings.ToLower(strings.TrimSpace(q.Title))
+	q.Level = strings.TrimSpace(q.Level)
+	if len(q.Sort) == 0 {
+		q.Sort = nil
+	}
+}
+
+// SortField orders the employees list by a field, the sortable fields are id, name, email, region and start_date,
+// the date the employee started their first position
+type SortField struct {
+	Field string `validate:"oneof=id name email region start_date"`
+	Desc  bool
+}
+
+// ParseSort parses a comma separated list of fields, each descending if it starts with "-", e.g. "name,-start_date"
+func ParseSort(s string) []SortField {
+	var fields []SortField
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// FormatSort is the inverse of ParseSort
+func FormatSort(sort []SortField) string {
+	parts := make([]string, 0, len(sort))
+	for _, field := range sort {
+		if field.Desc {
+			parts = append(parts, "-"+field.Field)
+		} else {
+			parts = append(parts, field.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// EmployeePatch holds the fields to change, nil fields are kept
+type EmployeePatch struct {
+	Name        *string
+	Email       *string
+	Address     *string
+	PhoneNumber *string
+	Region      *string
+	ManagerID   *int
+	// RemoveManager makes the employee report to nobody, ManagerID is ignored
+	RemoveManager bool
+}
+
+// Apply changes the employee by the patch
+func (p *EmployeePatch) Apply(e *Employee) {
+	for _, field := range []struct {
+		value  *string
+		target *string
+	}{
+		{p.Name, &e.Name},
+		{p.Email, &e.Email},
+		{p.Address, &e.Address},
+		{p.PhoneNumber, &e.PhoneNumber},
+		{p.Region, &e.Region},
+	} {
+		if field.value != nil {
+			*field.target = *field.value
+		}
+	}
+
+	if p.RemoveManager {
+		e.ManagerID = nil
+	} else if p.ManagerID != nil {
+		e.ManagerID = p.ManagerID
+	}
+	// the preloaded manager is stale once the manager changes
+	if e.Manager != nil && (e.ManagerID == nil || *e.ManagerID != e.Manager.ID) {
+		e.Manager = nil
+	}
 }
 
 type Position struct {
 	Title        string    `json:"title" validate:"required"`
 	Level        string    `json:"level"`
 	ManagerLevel int       `json:"manager_level"`
-	MonthSalary  float64   `json:"month_salary" validate:"gte=0"`
+	MonthSalary  float64   `json:"month_salary,omitempty" validate:"gte=0"` // hidden from callers other than HR and payroll
 	StartDate    time.Time `json:"start_date" validate:"required"`
-	// nil means no end date yet
+	// the start date of the next position, nil means no end date yet
 	EndDate *time.Time `json:"end_time"`
 }
+
+// ActiveAt reports whether the employee is in the position at the time
+func (p *Position) ActiveAt(t time.Time) bool {
+	return !p.StartDate.After(t) && (p.EndDate == nil || t.Before(*p.EndDate))
+}
+
+// PositionAt returns the position the employee is in at the time, the latest started one if there are several,
+// and an empty position if there is none
+func (e *Employee) PositionAt(t time.Time) Position {
+	var active *Position
+	for i := range e.Positions {
+		p := &e.Positions[i]
+		if p.ActiveAt(t) && (active == nil || p.StartDate.After(active.StartDate)) {
+			active = p
+		}
+	}
+	if active == nil {
+		return Position{}
+	}
+	return *active
+}
+
+// StartDate returns the date the employee started their first position
+func (e *Employee) StartDate() time.Time {
+	var startDate time.Time
+	for i := range e.Positions {
+		if startDate.IsZero() || e.Positions[i].StartDate.Before(startDate) {
+			startDate = e.Positions[i].StartDate
+		}
+	}
+	return startDate
+}
+
+// WithoutSalary returns a copy of the employee without the salary of any position
+func (e Employee) WithoutSalary() Employee {
+	if e.Positions != nil {
+		positions := make([]Position, len(e.Positions))
+		for i := range e.Positions {
+			positions[i] = e.Positions[i]
+			positions[i].MonthSalary = 0
+		}
+		e.Positions = positions
+	}
+	if e.Manager != nil {
+		manager := e.Manager.WithoutSalary()
+		e.Manager = &manager
+	}
+	return e
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionAt(t *testing.T) {
+	day := func(m time.Month, d int) time.Time {
+		return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	promotion := day(6, 1)
+	// ordered by start date desc as loaded, the promotion is in the future
+	employee := Employee{Positions: []Position{
+		{Title: "Lead", ManagerLevel: 2, StartDate: promotion},
+		{Title: "Engineer", ManagerLevel: 1, StartDate: day(1, 1), EndDate: &promotion},
+	}}
+
+	assert.Equal(t, "Engineer", employee.PositionAt(day(5, 31).Add(23*time.Hour)).Title)
+	assert.Equal(t, "Lead", employee.PositionAt(promotion).Title)
+	assert.Equal(t, "Lead", employee.PositionAt(day(12, 31)).Title)
+	// before the first position
+	assert.Equal(t, Position{}, employee.PositionAt(day(1, 1).Add(-time.Hour)))
+}
+
+func TestEmployeePatch_Apply(t *testing.T) {
+	managerID := 1
+	employee := Employee{Name: "John", Address: "123 Elm St", ManagerID: &managerID,
+		Manager: &Employee{ID: managerID}}
+
+	address := "456 Oak St"
+	newManagerID := 2
+	patch := EmployeePatch{Address: &address, ManagerID: &newManagerID}
+	patch.Apply(&employee)
+	assert.Equal(t, "John", employee.Name)
+	assert.Equal(t, address, employee.Address)
+	assert.Equal(t, 2, *employee.ManagerID)
+	assert.Nil(t, employee.Manager)
+
+	(&EmployeePatch{RemoveManager: true}).Apply(&employee)
+	assert.Nil(t, employee.ManagerID)
+}
+
+func TestParseSort(t *testing.T) {
+	assert.Equal(t, []SortField{{Field: "name"}, {Field: "start_date", Desc: true}}, ParseSort("name, -start_date,"))
+	assert.Nil(t, ParseSort(""))
+}
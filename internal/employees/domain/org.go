@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// OrgNode is an employee in the org chart with the employees reporting to them
+type OrgNode struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Title     string    `json:"title"` // of the current position
+	ManagerID *int      `json:"manager_id,omitempty"`
+	Reports   []OrgNode `json:"reports,omitempty"`
+}
+
+// NewOrgNode returns the node of the employee with the position they have at the time, without reports
+func NewOrgNode(e *Employee, at time.Time) OrgNode {
+	return OrgNode{
+		ID:        e.ID,
+		Name:      e.Name,
+		Title:     e.PositionAt(at).Title,
+		ManagerID: e.ManagerID,
+	}
+}
+
+// BuildOrgChart links the employees below the root to their managers, the ones who have left at the time
+// are left out together with whoever still reports to them, it reports false if the root is not in the employees
+func BuildOrgChart(rootID int, employees []Employee, at time.Time) (OrgNode, bool) {
+	var root *Employee
+	reports := make(map[int][]*Employee)
+	for i := range employees {
+		e := &employees[i]
+		if e.ID == rootID {
+			root = e
+			continue
+		}
+		if e.ManagerID != nil && !e.TerminatedAt(at) {
+			reports[*e.ManagerID] = append(reports[*e.ManagerID], e)
+		}
+	}
+	if root == nil {
+		return OrgNode{}, false
+	}
+
+	visited := map[int]bool{}
+	var build func(e *Employee) OrgNode
+	build = func(e *Employee) OrgNode {
+		visited[e.ID] = true
+		node := NewOrgNode(e, at)
+		for _, report := range reports[e.ID] {
+			if !visited[report.ID] {
+				node.Reports = append(node.Reports, build(report))
+			}
+		}
+		return node
+	}
+	return build(root), true
+}
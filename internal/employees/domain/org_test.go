@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"hr-system/internal/common"
+)
+
+func TestBuildOrgChart(t *testing.T) {
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	employees := []Employee{
+		{ID: 1, Name: "Boss", Positions: []Position{{Title: "CEO", StartDate: yesterday}}},
+		{ID: 2, Name: "Manager", ManagerID: common.GetPtr(1)},
+		{ID: 3, Name: "Engineer", ManagerID: common.GetPtr(2)},
+		{ID: 4, Name: "Designer", ManagerID: common.GetPtr(1)},
+		// has left the company
+		{ID: 5, Name: "Former", ManagerID: common.GetPtr(1), TerminationDate: common.GetPtr(yesterday.AddDate(0, 0, -1))},
+	}
+
+	chart, ok := BuildOrgChart(1, employees, now)
+	assert.True(t, ok)
+	assert.Equal(t, OrgNode{ID: 1, Name: "Boss", Title: "CEO", Reports: []OrgNode{
+		{ID: 2, Name: "Manager", ManagerID: common.GetPtr(1), Reports: []OrgNode{
+			{ID: 3, Name: "Engineer", ManagerID: common.GetPtr(2)},
+		}},
+		{ID: 4, Name: "Designer", ManagerID: common.GetPtr(1)},
+	}}, chart)
+
+	_, ok = BuildOrgChart(6, employees, now)
+	assert.False(t, ok)
+}
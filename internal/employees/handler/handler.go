@@ -1,16 +1,18 @@
 package handler
 
 import (
-	"errors"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 
 	"hr-system/internal/common"
-	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/common/cursor"
 	"hr-system/internal/employees/domain"
 	"hr-system/internal/employees/service"
 	"hr-system/internal/middleware"
@@ -19,13 +21,15 @@ import (
 type EmployeeHandler struct {
 	service  service.EmployeeService
 	validate *validator.Validate
+	cursors  *cursor.Codec
 	logger   *common.Logger
 }
 
-func NewEmployeeHandler(logger *common.Logger, service service.EmployeeService) *EmployeeHandler {
+func NewEmployeeHandler(logger *common.Logger, service service.EmployeeService, cursors *cursor.Codec) *EmployeeHandler {
 	return &EmployeeHandler{
 		service:  service,
 		validate: validator.New(),
+		cursors:  cursors,
 		logger:   logger,
 	}
 }
@@ -35,6 +39,7 @@ type CreateEmployeeRequest struct {
 	Email       string   `json:"email"`
 	Address     string   `json:"address"`
 	PhoneNumber string   `json:"phone_number"`
+	Region      string   `json:"region"`
 	Position    Position `json:"position_level"`
 	ManagerID   *int     `json:"manager_id"`
 }
@@ -75,6 +80,7 @@ func (h *EmployeeHandler) CreateEmployee(c *gin.Context) {
 		Email:       req.Email,
 		Address:     req.Address,
 		PhoneNumber: req.PhoneNumber,
+		Region:      req.Region,
 		Positions: []domain.Position{
 			{
 				Title:        req.Position.Title,
@@ -87,11 +93,7 @@ func (h *EmployeeHandler) CreateEmployee(c *gin.Context) {
 		ManagerID: req.ManagerID,
 	})
 	if err != nil {
-		if errors.Is(err, common_errors.ErrInvalidInput) {
-			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid input, detail: %s", err))
-		} else {
-			c.JSON(http.StatusInternalServerError, middleware.CreateErrResp("failed to create employee, cause: %s", err))
-		}
+		middleware.HandleErr(c, err, "create employee")
 		return
 	}
 
@@ -109,19 +111,15 @@ func (h *EmployeeHandler) GetEmployeeByID(c *gin.Context) {
 
 	employee, err := h.service.GetEmployeeByID(ctx, id)
 	if err != nil {
-		if errors.Is(err, common_errors.ErrResourceNotFound) {
-			c.JSON(http.StatusNotFound, middleware.CreateErrResp(err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, middleware.CreateErrResp(err.Error()))
-		}
+		middleware.HandleErr(c, err, "get employee")
 		return
 	}
 
+	c.Header("ETag", etag(employee.Version))
 	c.JSON(http.StatusOK, employee)
 }
 
 func (h *EmployeeHandler) GetEmployees(c *gin.Context) {
-	// TODO: order by query param
 	ctx := c.Request.Context()
 
 	var page, pageSize int
@@ -133,19 +131,283 @@ func (h *EmployeeHandler) GetEmployees(c *gin.Context) {
 		pageSize, _ = strconv.Atoi(ps)
 	}
 
-	employees, totalCount, err := h.service.GetEmployees(ctx, page, pageSize)
+	var managerID *int
+	if value := c.Query("manager_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid manager_id"))
+			return
+		}
+		managerID = &id
+	}
+
+	params, ok := middleware.GetCursorParams(c, pageSize)
+	if !ok {
+		return
+	}
+	// the offset paging counts by default as it always did
+	count, ok := middleware.GetBoolQuery(c, "count", !params.Enabled)
+	if !ok {
+		return
+	}
+
+	query := domain.EmployeesQuery{
+		Page:      page,
+		PageSize:  pageSize,
+		Status:    domain.EmployeeStatus(c.Query("status")),
+		Search:    c.Query("search"),
+		ManagerID: managerID,
+		Title:     c.Query("title"),
+		Level:     c.Query("level"),
+		Sort:      domain.ParseSort(c.Query("sort")),
+		SkipCount: !count,
+	}
+	if params.Enabled {
+		query.Page, query.PageSize = 1, params.Limit
+		if params.Cursor != "" {
+			query.After = &domain.EmployeeCursor{}
+			if err := h.cursors.Decode(params.Cursor, query.After); err != nil {
+				c.JSON(http.StatusBadRequest, middleware.CreateErrResp(err.Error()))
+				return
+			}
+		}
+	}
+
+	result, err := h.service.GetEmployees(ctx, query)
 	if err != nil {
-		if errors.Is(err, common_errors.ErrInvalidInput) {
-			c.JSON(http.StatusBadRequest, middleware.CreateErrResp(err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, middleware.CreateErrResp(err.Error()))
+		middleware.HandleErr(c, err, "get employees")
+		return
+	}
+
+	if params.Enabled {
+		response := cursor.Page[domain.Employee]{Data: result.Employees, TotalCount: result.TotalCount}
+		if result.Next != nil {
+			if response.NextCursor, err = h.cursors.Encode(result.Next); err != nil {
+				c.JSON(http.StatusInternalServerError, middleware.CreateErrResp(err.Error()))
+				return
+			}
 		}
+		c.JSON(http.StatusOK, response)
 		return
 	}
 
-	c.Header("X-Total-Count", strconv.Itoa(totalCount))
+	if result.TotalCount != nil {
+		c.Header("X-Total-Count", strconv.Itoa(*result.TotalCount))
+	}
 	c.Header("X-Page", strconv.Itoa(page))
 	c.Header("X-Page-Size", strconv.Itoa(pageSize))
 
-	c.JSON(http.StatusOK, employees)
+	c.JSON(http.StatusOK, result.Employees)
+}
+
+func etag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// expectedVersion returns the version an update is based on, taken from If-Match or the version in the body
+func expectedVersion(c *gin.Context, bodyVersion *int) (int, bool) {
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid If-Match: %s", ifMatch))
+			return 0, false
+		}
+		return version, true
+	}
+	if bodyVersion != nil {
+		return *bodyVersion, true
+	}
+	c.JSON(http.StatusPreconditionRequired, middleware.CreateErrResp("If-Match or version is required"))
+	return 0, false
+}
+
+type UpdateEmployeeRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Email       string `json:"email" binding:"required"`
+	Address     string `json:"address"`
+	PhoneNumber string `json:"phone_number"`
+	Region      string `json:"region"`
+	ManagerID   *int   `json:"manager_id"`
+	Version     *int   `json:"version"` // the version the update is based on when If-Match is not given
+}
+
+// UpdateEmployee replaces the employee, the positions are changed by their own API
+func (h *EmployeeHandler) UpdateEmployee(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid employee ID"))
+		return
+	}
+
+	var req UpdateEmployeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %s", err))
+		return
+	}
+	version, ok := expectedVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	employee, err := h.service.UpdateEmployee(ctx, &domain.Employee{
+		ID:          id,
+		Name:        req.Name,
+		Email:       req.Email,
+		Address:     req.Address,
+		PhoneNumber: req.PhoneNumber,
+		Region:      req.Region,
+		ManagerID:   req.ManagerID,
+		Version:     version,
+	})
+	if err != nil {
+		middleware.HandleErr(c, err, "update employee")
+		return
+	}
+
+	c.Header("ETag", etag(employee.Version))
+	c.JSON(http.StatusOK, employee)
+}
+
+type PatchEmployeeRequest struct {
+	Name        *string         `json:"name"`
+	Email       *string         `json:"email"`
+	Address     *string         `json:"address"`
+	PhoneNumber *string         `json:"phone_number"`
+	Region      *string         `json:"region"`
+	ManagerID   json.RawMessage `json:"manager_id"` // null removes the manager
+	Version     *int            `json:"version"`    // the version the update is based on when If-Match is not given
+}
+
+// PatchEmployee changes the fields given in the body
+func (h *EmployeeHandler) PatchEmployee(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid employee ID"))
+		return
+	}
+
+	var req PatchEmployeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %s", err))
+		return
+	}
+	patch := domain.EmployeePatch{
+		Name:        req.Name,
+		Email:       req.Email,
+		Address:     req.Address,
+		PhoneNumber: req.PhoneNumber,
+		Region:      req.Region,
+	}
+	if len(req.ManagerID) > 0 {
+		if string(req.ManagerID) == "null" {
+			patch.RemoveManager = true
+		} else if err := json.Unmarshal(req.ManagerID, &patch.ManagerID); err != nil {
+			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid manager_id, cause: %s", err))
+			return
+		}
+	}
+	version, ok := expectedVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	employee, err := h.service.PatchEmployee(ctx, id, version, &patch)
+	if err != nil {
+		middleware.HandleErr(c, err, "update employee")
+		return
+	}
+
+	c.Header("ETag", etag(employee.Version))
+	c.JSON(http.StatusOK, employee)
+}
+
+type ChangePositionRequest struct {
+	Title         string     `json:"title" binding:"required"`
+	Level         string     `json:"level"`
+	ManagerLevel  int        `json:"manager_level"`
+	MonthSalary   float64    `json:"month_salary"`
+	EffectiveDate *time.Time `json:"effective_date"` // today if not given
+}
+
+// ChangePosition promotes or moves the employee to a new position
+func (h *EmployeeHandler) ChangePosition(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid employee ID"))
+		return
+	}
+
+	var req ChangePositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %s", err))
+		return
+	}
+	startDate := time.Now()
+	if req.EffectiveDate != nil {
+		startDate = *req.EffectiveDate
+	}
+
+	employee, err := h.service.ChangePosition(ctx, id, &domain.Position{
+		Title:        req.Title,
+		Level:        req.Level,
+		ManagerLevel: req.ManagerLevel,
+		MonthSalary:  req.MonthSalary,
+		StartDate:    time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location()),
+	})
+	if err != nil {
+		middleware.HandleErr(c, err, "change position")
+		return
+	}
+
+	c.Header("ETag", etag(employee.Version))
+	c.JSON(http.StatusCreated, employee)
+}
+
+// GetReports returns the org chart below the employee, down to the depth query or to the bottom when it's not given
+func (h *EmployeeHandler) GetReports(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid employee ID"))
+		return
+	}
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid depth"))
+		return
+	}
+
+	reports, err := h.service.GetReports(ctx, id, depth)
+	if err != nil {
+		middleware.HandleErr(c, err, "get org chart")
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// GetChain returns the managers above the employee up to the top, the nearest first
+func (h *EmployeeHandler) GetChain(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid employee ID"))
+		return
+	}
+
+	chain, err := h.service.GetChain(ctx, id)
+	if err != nil {
+		middleware.HandleErr(c, err, "get org chart")
+		return
+	}
+
+	c.JSON(http.StatusOK, chain)
 }
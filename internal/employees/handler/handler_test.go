@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"hr-system/internal/common"
+	"hr-system/internal/common/cursor"
 	common_errors "hr-system/internal/common/errors"
 	"hr-system/internal/employees/domain"
 	mock_service "hr-system/internal/employees/service/mocks"
@@ -43,7 +44,7 @@ func TestCreateEmployee(t *testing.T) {
 
 	mockService := mock_service.NewEmployeeService(t)
 	logger := common.NewLogger()
-	handler := NewEmployeeHandler(logger, mockService)
+	handler := NewEmployeeHandler(logger, mockService, cursor.NewCodec([]byte("secret")))
 
 	router := gin.Default()
 	router.POST("/employees", handler.CreateEmployee)
@@ -92,7 +93,7 @@ func TestGetEmployeeByID(t *testing.T) {
 
 	mockService := mock_service.NewEmployeeService(t)
 	logger := common.NewLogger()
-	handler := NewEmployeeHandler(logger, mockService)
+	handler := NewEmployeeHandler(logger, mockService, cursor.NewCodec([]byte("secret")))
 
 	router := gin.Default()
 	router.GET("/employees/:id", handler.GetEmployeeByID)
@@ -134,7 +135,7 @@ func TestGetEmployees(t *testing.T) {
 
 	mockService := mock_service.NewEmployeeService(t)
 	logger := common.NewLogger()
-	handler := NewEmployeeHandler(logger, mockService)
+	handler := NewEmployeeHandler(logger, mockService, cursor.NewCodec([]byte("secret")))
 
 	router := gin.Default()
 	router.GET("/employees", handler.GetEmployees)
@@ -144,7 +145,8 @@ func TestGetEmployees(t *testing.T) {
 			genFakeEmployee(),
 		}
 
-		mockService.On("GetEmployees", mock.Anything, 1, 10).Return(employees, 1, nil).Once()
+		mockService.On("GetEmployees", mock.Anything, domain.EmployeesQuery{Page: 1, PageSize: 10}).
+			Return(domain.EmployeesPage{Employees: employees, TotalCount: common.GetPtr(1)}, nil).Once()
 
 		req, _ := http.NewRequest(http.MethodGet, "/employees?page=1&page_size=10", nil)
 		w := httptest.NewRecorder()
@@ -157,8 +159,8 @@ func TestGetEmployees(t *testing.T) {
 	})
 
 	t.Run("invalid input", func(t *testing.T) {
-		mockService.On("GetEmployees", mock.Anything, 1, 10).
-			Return(nil, 0, common_errors.ErrInvalidInput).
+		mockService.On("GetEmployees", mock.Anything, domain.EmployeesQuery{Page: 1, PageSize: 10}).
+			Return(domain.EmployeesPage{}, common_errors.ErrInvalidInput).
 			Once()
 
 		req, _ := http.NewRequest(http.MethodGet, "/employees?page=1&page_size=10", nil)
@@ -167,4 +169,108 @@ func TestGetEmployees(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
+
+	t.Run("filters and sort", func(t *testing.T) {
+		mockService.On("GetEmployees", mock.Anything, domain.EmployeesQuery{Page: 2, PageSize: 5, Search: "doe",
+			ManagerID: common.GetPtr(1), Title: "engineer", Level: "Junior",
+			Sort: []domain.SortField{{Field: "name"}, {Field: "start_date", Desc: true}}}).
+			Return(domain.EmployeesPage{Employees: []domain.Employee{}, TotalCount: common.GetPtr(0)}, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/employees?page=2&page_size=5&search=doe&manager_id=1"+
+			"&title=engineer&level=Junior&sort=name,-start_date", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		employee := genFakeEmployee()
+		next := &domain.EmployeeCursor{Sort: "-name", ID: employee.ID, Name: employee.Name}
+		mockService.On("GetEmployees", mock.Anything, domain.EmployeesQuery{Page: 1, PageSize: 1, SkipCount: true,
+			Sort: []domain.SortField{{Field: "name", Desc: true}}}).
+			Return(domain.EmployeesPage{Employees: []domain.Employee{employee}, Next: next}, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/employees?limit=1&sort=-name", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-Total-Count"))
+		var page cursor.Page[domain.Employee]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Len(t, page.Data, 1)
+		assert.Nil(t, page.TotalCount)
+		assert.NotEmpty(t, page.NextCursor)
+
+		// the next page continues after the cursor and counts when asked to
+		mockService.On("GetEmployees", mock.Anything, domain.EmployeesQuery{Page: 1, PageSize: 1, After: next,
+			Sort: []domain.SortField{{Field: "name", Desc: true}}}).
+			Return(domain.EmployeesPage{Employees: []domain.Employee{}, TotalCount: common.GetPtr(1)}, nil).Once()
+
+		req, _ = http.NewRequest(http.MethodGet, "/employees?limit=1&sort=-name&count=true&cursor="+page.NextCursor, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"data":[],"total_count":1}`, w.Body.String())
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		for _, url := range []string{"/employees?cursor=forged", "/employees?limit=0", "/employees?limit=101",
+			"/employees?count=maybe"} {
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, url)
+		}
+	})
+}
+
+func TestPatchEmployee(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := mock_service.NewEmployeeService(t)
+	logger := common.NewLogger()
+	handler := NewEmployeeHandler(logger, mockService, cursor.NewCodec([]byte("secret")))
+
+	router := gin.Default()
+	router.PATCH("/employees/:id", handler.PatchEmployee)
+
+	t.Run("success", func(t *testing.T) {
+		employee := genFakeEmployee()
+		employee.Version = 4
+		address := "456 Oak St"
+		patch := &domain.EmployeePatch{Address: &address, RemoveManager: true}
+		mockService.On("PatchEmployee", mock.Anything, 1, 3, patch).Return(employee, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPatch, "/employees/1",
+			bytes.NewBufferString(`{"address": "456 Oak St", "manager_id": null}`))
+		req.Header.Set("If-Match", `"3"`)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("version required", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPatch, "/employees/1", bytes.NewBufferString(`{"address": "x"}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		mockService.On("PatchEmployee", mock.Anything, 1, 2, mock.Anything).
+			Return(domain.Employee{}, common_errors.ErrPreconditionFailed).Once()
+
+		req, _ := http.NewRequest(http.MethodPatch, "/employees/1", bytes.NewBufferString(`{"version": 2}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
 }
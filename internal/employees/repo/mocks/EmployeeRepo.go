@@ -7,6 +7,8 @@ import (
 	domain "hr-system/internal/employees/domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // EmployeeRepo is an autogenerated mock type for the EmployeeRepo type
@@ -14,6 +16,24 @@ type EmployeeRepo struct {
 	mock.Mock
 }
 
+// ChangePosition provides a mock function with given fields: ctx, employeeID, position
+func (_m *EmployeeRepo) ChangePosition(ctx context.Context, employeeID int, position *domain.Position) error {
+	ret := _m.Called(ctx, employeeID, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePosition")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *domain.Position) error); ok {
+		r0 = rf(ctx, employeeID, position)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, employee
 func (_m *EmployeeRepo) Create(ctx context.Context, employee *domain.Employee) error {
 	ret := _m.Called(ctx, employee)
@@ -60,41 +80,182 @@ func (_m *EmployeeRepo) GetEmployeeByID(ctx context.Context, id int) (domain.Emp
 	return r0, r1
 }
 
-// GetEmployees provides a mock function with given fields: ctx, page, pageSize
-func (_m *EmployeeRepo) GetEmployees(ctx context.Context, page int, pageSize int) ([]domain.Employee, int, error) {
-	ret := _m.Called(ctx, page, pageSize)
+// GetEmployees provides a mock function with given fields: ctx, query
+func (_m *EmployeeRepo) GetEmployees(ctx context.Context, query domain.EmployeesQuery) (domain.EmployeesPage, error) {
+	ret := _m.Called(ctx, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetEmployees")
 	}
 
+	var r0 domain.EmployeesPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.EmployeesQuery) (domain.EmployeesPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.EmployeesQuery) domain.EmployeesPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Get(0).(domain.EmployeesPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.EmployeesQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEmployeesByIDs provides a mock function with given fields: ctx, ids
+func (_m *EmployeeRepo) GetEmployeesByIDs(ctx context.Context, ids []int) ([]domain.Employee, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEmployeesByIDs")
+	}
+
 	var r0 []domain.Employee
-	var r1 int
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.Employee, int, error)); ok {
-		return rf(ctx, page, pageSize)
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) ([]domain.Employee, error)); ok {
+		return rf(ctx, ids)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.Employee); ok {
-		r0 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(0).(func(context.Context, []int) []domain.Employee); ok {
+		r0 = rf(ctx, ids)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Employee)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
-		r1 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetManagerChain provides a mock function with given fields: ctx, employeeID
+func (_m *EmployeeRepo) GetManagerChain(ctx context.Context, employeeID int) ([]int, error) {
+	ret := _m.Called(ctx, employeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetManagerChain")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]int, error)); ok {
+		return rf(ctx, employeeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []int); ok {
+		r0 = rf(ctx, employeeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, employeeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReportIDs provides a mock function with given fields: ctx, managerID, depth
+func (_m *EmployeeRepo) GetReportIDs(ctx context.Context, managerID int, depth int) ([]int, error) {
+	ret := _m.Called(ctx, managerID, depth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReportIDs")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]int, error)); ok {
+		return rf(ctx, managerID, depth)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []int); ok {
+		r0 = rf(ctx, managerID, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, managerID, depth)
 	} else {
-		r1 = ret.Get(1).(int)
+		r1 = ret.Error(1)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
-		r2 = rf(ctx, page, pageSize)
+	return r0, r1
+}
+
+// GetSubordinateIDs provides a mock function with given fields: ctx, managerID
+func (_m *EmployeeRepo) GetSubordinateIDs(ctx context.Context, managerID int) ([]int, error) {
+	ret := _m.Called(ctx, managerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubordinateIDs")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]int, error)); ok {
+		return rf(ctx, managerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []int); ok {
+		r0 = rf(ctx, managerID)
 	} else {
-		r2 = ret.Error(2)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
 	}
 
-	return r0, r1, r2
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, managerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HandOverReports provides a mock function with given fields: ctx, employeeID, successorID
+func (_m *EmployeeRepo) HandOverReports(ctx context.Context, employeeID int, successorID *int) ([]int, error) {
+	ret := _m.Called(ctx, employeeID, successorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HandOverReports")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *int) ([]int, error)); ok {
+		return rf(ctx, employeeID, successorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *int) []int); ok {
+		r0 = rf(ctx, employeeID, successorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *int) error); ok {
+		r1 = rf(ctx, employeeID, successorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // SeedData provides a mock function with given fields: ctx
@@ -115,6 +276,42 @@ func (_m *EmployeeRepo) SeedData(ctx context.Context) error {
 	return r0
 }
 
+// Terminate provides a mock function with given fields: ctx, employeeID, terminationDate
+func (_m *EmployeeRepo) Terminate(ctx context.Context, employeeID int, terminationDate time.Time) error {
+	ret := _m.Called(ctx, employeeID, terminationDate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Terminate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) error); ok {
+		r0 = rf(ctx, employeeID, terminationDate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, employee
+func (_m *EmployeeRepo) Update(ctx context.Context, employee *domain.Employee) error {
+	ret := _m.Called(ctx, employee)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Employee) error); ok {
+		r0 = rf(ctx, employee)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewEmployeeRepo creates a new instance of EmployeeRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewEmployeeRepo(t interface {
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"hr-system/internal/common"
+	"hr-system/internal/common/cursor"
 	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/common/transaction"
 	"hr-system/internal/employees/domain"
 )
 
@@ -16,8 +21,28 @@ type EmployeeRepo interface {
 	// TODO: find a better place to put this
 	SeedData(ctx context.Context) error
 	Create(ctx context.Context, employee *domain.Employee) error
+	// Update saves the fields of the employee except the positions if it is still at employee.Version,
+	// the version is increased on success
+	Update(ctx context.Context, employee *domain.Employee) error
 	GetEmployeeByID(ctx context.Context, id int) (domain.Employee, error)
-	GetEmployees(ctx context.Context, page, pageSize int) (employees []domain.Employee, totalCount int, err error)
+	// GetEmployees returns a page of the employees matching the query, after the cursor of the query if there is one
+	GetEmployees(ctx context.Context, query domain.EmployeesQuery) (domain.EmployeesPage, error)
+	GetEmployeesByIDs(ctx context.Context, ids []int) ([]domain.Employee, error)
+	// ChangePosition ends the current position of the employee when the new one starts and adds the new one,
+	// the new position must start after every existing one
+	ChangePosition(ctx context.Context, employeeID int, position *domain.Position) error
+	// GetManagerChain returns the IDs of the managers above the employee, the nearest first
+	GetManagerChain(ctx context.Context, employeeID int) ([]int, error)
+	// GetSubordinateIDs returns the IDs of the employees reporting to the manager directly or indirectly
+	GetSubordinateIDs(ctx context.Context, managerID int) ([]int, error)
+	// GetReportIDs returns the IDs of the employees at most depth levels below the manager, the nearest levels first,
+	// a depth of 0 returns all of them
+	GetReportIDs(ctx context.Context, managerID, depth int) ([]int, error)
+	// Terminate sets the termination date of the employee and ends their positions on it
+	Terminate(ctx context.Context, employeeID int, terminationDate time.Time) error
+	// HandOverReports moves the direct reports of the employee to the successor, who takes the place of the employee
+	// if they were one of them, it returns the IDs of the moved reports
+	HandOverReports(ctx context.Context, employeeID int, successorID *int) ([]int, error)
 }
 
 type Employee struct {
@@ -26,9 +51,13 @@ type Employee struct {
 	Email       string     `gorm:"type:varchar(255);unique;not null"`
 	Address     string     `gorm:"type:varchar(255)"`
 	PhoneNumber string     `gorm:"type:varchar(20)"`
+	Region      string     `gorm:"type:varchar(50)"`
 	ManagerID   *int       `gorm:"index:idx_manager_id"`
 	Manager     *Employee  `gorm:"foreignKey:ManagerID;constraint:OnDelete:SET NULL"`
 	Positions   []Position `gorm:"foreignKey:EmployeeID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Version     int        `gorm:"not null;default:1"`
+	// TerminationDate is the last day of the employee in the company
+	TerminationDate *time.Time `gorm:"type:date;index"`
 }
 
 type Position struct {
@@ -84,6 +113,7 @@ func toRepoPosition(employeeID int, p *domain.Position) Position {
 		ManagerLevel: p.ManagerLevel,
 		MonthSalary:  p.MonthSalary,
 		StartDate:    p.StartDate,
+		EndDate:      p.EndDate,
 	}
 }
 
@@ -97,17 +127,89 @@ func (r *employeeRepo) Create(ctx context.Context, e *domain.Employee) error {
 		Email:       e.Email,
 		Address:     e.Address,
 		PhoneNumber: e.PhoneNumber,
+		Region:      e.Region,
 		ManagerID:   e.ManagerID,
 		Positions:   positions,
+		Version:     1,
 	}
 	if err := r.db.WithContext(ctx).Create(employee).Error; err != nil {
 		return fmt.Errorf("failed to create employee: %w", err)
 	}
 	e.ID = employee.ID
+	e.Version = employee.Version
 
 	return nil
 }
 
+func (r *employeeRepo) Update(ctx context.Context, e *domain.Employee) error {
+	result := r.db.WithContext(ctx).Model(&Employee{}).
+		Where("id = ? AND version = ?", e.ID, e.Version).
+		Updates(map[string]interface{}{
+			"name":         e.Name,
+			"email":        e.Email,
+			"address":      e.Address,
+			"phone_number": e.PhoneNumber,
+			"region":       e.Region,
+			"manager_id":   e.ManagerID,
+			"version":      gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update employee: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := r.db.WithContext(ctx).Model(&Employee{}).Where("id = ?", e.ID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to count employee: %w", err)
+		}
+		if count == 0 {
+			return common_errors.ErrResourceNotFound
+		}
+		return fmt.Errorf("%w, employee %d is no longer at version %d",
+			common_errors.ErrPreconditionFailed, e.ID, e.Version)
+	}
+	e.Version++
+
+	return nil
+}
+
+func (r *employeeRepo) ChangePosition(ctx context.Context, employeeID int, position *domain.Position) error {
+	return transaction.Run(ctx, r.db, func(tx *gorm.DB) error {
+		// the positions are part of the employee, and updating the employee first serializes the changes of them
+		result := tx.Model(&Employee{}).Where("id = ?", employeeID).Update("version", gorm.Expr("version + 1"))
+		if result.Error != nil {
+			return fmt.Errorf("failed to update employee: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return common_errors.ErrResourceNotFound
+		}
+
+		var later int64
+		err := tx.Model(&Position{}).
+			Where("employee_id = ? AND start_date >= ?", employeeID, position.StartDate).
+			Count(&later).Error
+		if err != nil {
+			return fmt.Errorf("failed to count positions: %w", err)
+		}
+		if later > 0 {
+			return fmt.Errorf("%w, employee %d already has a position starting on or after %s",
+				common_errors.ErrStatusConflict, employeeID, position.StartDate.Format(time.DateOnly))
+		}
+
+		err = tx.Model(&Position{}).
+			Where("employee_id = ? AND (end_date IS NULL OR end_date > ?)", employeeID, position.StartDate).
+			Update("end_date", position.StartDate).Error
+		if err != nil {
+			return fmt.Errorf("failed to end current position: %w", err)
+		}
+
+		newPosition := toRepoPosition(employeeID, position)
+		if err := tx.Create(&newPosition).Error; err != nil {
+			return fmt.Errorf("failed to create position: %w", err)
+		}
+		return nil
+	})
+}
+
 func toDomainEmployee(e *Employee) domain.Employee {
 	var domainPositions []domain.Position
 	for i := range e.Positions {
@@ -128,14 +230,17 @@ func toDomainEmployee(e *Employee) domain.Employee {
 		manager = &managerPtr
 	}
 	return domain.Employee{
-		ID:          e.ID,
-		Name:        e.Name,
-		Email:       e.Email,
-		Address:     e.Address,
-		PhoneNumber: e.PhoneNumber,
-		Positions:   domainPositions,
-		ManagerID:   e.ManagerID,
-		Manager:     manager,
+		ID:              e.ID,
+		Name:            e.Name,
+		Email:           e.Email,
+		Address:         e.Address,
+		PhoneNumber:     e.PhoneNumber,
+		Region:          e.Region,
+		Positions:       domainPositions,
+		ManagerID:       e.ManagerID,
+		Manager:         manager,
+		Version:         e.Version,
+		TerminationDate: e.TerminationDate,
 	}
 }
 
@@ -165,22 +270,189 @@ func (r *employeeRepo) GetEmployeeByID(ctx context.Context, id int) (domain.Empl
 	return toDomainEmployee(&employee), nil
 }
 
-func (r *employeeRepo) GetEmployees(ctx context.Context, page, pageSize int) ([]domain.Employee, int, error) {
+// filterStatus keeps the employees in the status today
+func filterStatus(db *gorm.DB, status domain.EmployeeStatus, today time.Time) *gorm.DB {
+	switch status {
+	case domain.EmployeeStatusAll:
+		return db
+	case domain.EmployeeStatusTerminated:
+		return db.Where("termination_date < ?", today)
+	default:
+		return db.Where("termination_date IS NULL OR termination_date >= ?", today)
+	}
+}
+
+// likePattern matches the strings containing s, "!" escapes the wildcards in s
+func likePattern(s string) string {
+	return "%" + strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s) + "%"
+}
+
+// filterEmployees keeps the employees matching the filters of the query
+func filterEmployees(db *gorm.DB, query domain.EmployeesQuery) *gorm.DB {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	db = filterStatus(db, query.Status, today)
+	if query.Search != "" {
+		pattern := likePattern(strings.ToLower(query.Search))
+		db = db.Where("LOWER(name) LIKE ? ESCAPE '!' OR LOWER(email) LIKE ? ESCAPE '!'", pattern, pattern)
+	}
+	if query.ManagerID != nil {
+		db = db.Where("manager_id = ?", *query.ManagerID)
+	}
+	if query.Title != "" || query.Level != "" {
+		positions := db.Session(&gorm.Session{NewDB: true}).Model(&Position{}).
+			Select("1").
+			Where("positions.employee_id = employees.id").
+			Where("positions.start_date <= ? AND (positions.end_date IS NULL OR positions.end_date > ?)", today, today)
+		if query.Title != "" {
+			positions = positions.Where("LOWER(positions.title) LIKE ? ESCAPE '!'", likePattern(strings.ToLower(query.Title)))
+		}
+		if query.Level != "" {
+			positions = positions.Where("positions.level = ?", query.Level)
+		}
+		db = db.Where("EXISTS (?)", positions)
+	}
+	if query.IDs != nil {
+		db = db.Where("id IN ?", query.IDs)
+	}
+	return db
+}
+
+// noStartDate is the start date of an employee without a position, the zero date as Employee.StartDate has it
+const noStartDate = "0001-01-01"
+
+// employeeSortColumns maps the sortable fields of the employees list to their columns
+var employeeSortColumns = map[string]string{
+	"id":     "id",
+	"name":   "name",
+	"email":  "email",
+	"region": "region",
+	"start_date": "COALESCE((SELECT MIN(start_date) FROM positions WHERE positions.employee_id = employees.id), '" +
+		noStartDate + "')",
+}
+
+// sortEmployees orders the employees by the sort fields and then by ID
+func sortEmployees(db *gorm.DB, sort []domain.SortField) (*gorm.DB, error) {
+	var columns []clause.OrderByColumn
+	for _, field := range sort {
+		column, ok := employeeSortColumns[field.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w, can not sort by %s", common_errors.ErrInvalidInput, field.Field)
+		}
+		columns = append(columns, clause.OrderByColumn{Column: clause.Column{Name: column, Raw: true}, Desc: field.Desc})
+	}
+	columns = append(columns, clause.OrderByColumn{Column: clause.Column{Name: "id"}})
+	return db.Order(clause.OrderBy{Columns: columns}), nil
+}
+
+// afterCursor keeps the employees coming after the cursor in the order of sortEmployees
+func afterCursor(db *gorm.DB, sort []domain.SortField, after *domain.EmployeeCursor) (*gorm.DB, error) {
+	var startDate interface{} = noStartDate
+	if after.StartDate != nil && !after.StartDate.IsZero() {
+		startDate = *after.StartDate
+	}
+	values := map[string]interface{}{
+		"id":         after.ID,
+		"name":       after.Name,
+		"email":      after.Email,
+		"region":     after.Region,
+		"start_date": startDate,
+	}
+
+	var keys []cursor.Key
+	for _, field := range append(sort[:len(sort):len(sort)], domain.SortField{Field: "id"}) {
+		column, ok := employeeSortColumns[field.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w, can not sort by %s", common_errors.ErrInvalidInput, field.Field)
+		}
+		keys = append(keys, cursor.Key{Column: column, Desc: field.Desc, Value: values[field.Field]})
+		if field.Field == "id" {
+			// the IDs are unique, the fields after it never break a tie
+			break
+		}
+	}
+	condition, args := cursor.Condition(keys)
+	return db.Where(condition, args...), nil
+}
+
+func (r *employeeRepo) GetEmployees(ctx context.Context, query domain.EmployeesQuery) (domain.EmployeesPage, error) {
 	var employeeModels []Employee
+	var page domain.EmployeesPage
+
+	if !query.SkipCount {
+		var totalCount int64
+		db := filterEmployees(r.db.WithContext(ctx).Model(&Employee{}), query)
+		if err := db.Count(&totalCount).Error; err != nil {
+			return domain.EmployeesPage{}, fmt.Errorf("failed to count employees: %w", err)
+		}
+		page.TotalCount = common.GetPtr(int(totalCount))
+	}
+
+	db, err := sortEmployees(filterEmployees(r.db.WithContext(ctx), query), query.Sort)
+	if err != nil {
+		return domain.EmployeesPage{}, err
+	}
+	if query.After != nil {
+		db, err = afterCursor(db, query.Sort, query.After)
+		if err != nil {
+			return domain.EmployeesPage{}, err
+		}
+	} else {
+		db = db.Offset((query.Page - 1) * query.PageSize)
+	}
+	db = preloadPositions(db)
+	// one more to know whether there is a next page
+	err = db.Limit(query.PageSize + 1).Find(&employeeModels).Error
+	if err != nil {
+		return domain.EmployeesPage{}, fmt.Errorf("failed to get employees: %w", err)
+	}
+
+	hasNext := len(employeeModels) > query.PageSize
+	if hasNext {
+		employeeModels = employeeModels[:query.PageSize]
+	}
+	page.Employees = make([]domain.Employee, 0, len(employeeModels))
+	for i := range employeeModels {
+		page.Employees = append(page.Employees, toDomainEmployee(&employeeModels[i]))
+	}
+	if hasNext && len(page.Employees) > 0 {
+		page.Next = domain.NewEmployeeCursor(&page.Employees[len(page.Employees)-1], query.Sort)
+	}
 
-	offset := (page - 1) * pageSize
+	return page, nil
+}
 
-	var totalCountInt64 int64
-	if err := r.db.WithContext(ctx).Model(&Employee{}).Count(&totalCountInt64).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to count employees: %w", err)
+// GetAllEmployees pages through every employee of the repo including the ones who have left, for the jobs and reports
+// which go through the whole company
+func GetAllEmployees(ctx context.Context, repo EmployeeRepo) ([]domain.Employee, error) {
+	query := domain.EmployeesQuery{
+		Page:      1,
+		PageSize:  500,
+		Status:    domain.EmployeeStatusAll,
+		SkipCount: true,
+	}
+	var employees []domain.Employee
+	for {
+		page, err := repo.GetEmployees(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get employees: %w", err)
+		}
+		employees = append(employees, page.Employees...)
+		if page.Next == nil {
+			return employees, nil
+		}
+		query.After = page.Next
 	}
-	totalCount := int(totalCountInt64)
+}
+
+func (r *employeeRepo) GetEmployeesByIDs(ctx context.Context, ids []int) ([]domain.Employee, error) {
+	var employeeModels []Employee
 
 	db := r.db.WithContext(ctx)
 	db = preloadPositions(db)
-	err := db.Limit(pageSize).Offset(offset).Find(&employeeModels).Error
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get employees: %w", err)
+	if err := db.Where("id IN ?", ids).Order("id ASC").Find(&employeeModels).Error; err != nil {
+		return nil, fmt.Errorf("failed to get employees: %w", err)
 	}
 
 	employees := make([]domain.Employee, 0, len(employeeModels))
@@ -188,5 +460,161 @@ func (r *employeeRepo) GetEmployees(ctx context.Context, page, pageSize int) ([]
 		employees = append(employees, toDomainEmployee(&employeeModels[i]))
 	}
 
-	return employees, totalCount, nil
+	return employees, nil
+}
+
+// maxOrgDepth bounds the walks of the hierarchy, so a reporting cycle can not make them endless
+const maxOrgDepth = 100
+
+type orgRow struct {
+	ID    int
+	Depth int
+}
+
+func (r *employeeRepo) GetManagerChain(ctx context.Context, employeeID int) ([]int, error) {
+	var rows []orgRow
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE chain (id, manager_id, depth) AS (
+			SELECT id, manager_id, 0 FROM employees WHERE id = ?
+			UNION ALL
+			SELECT e.id, e.manager_id, c.depth + 1 FROM employees e JOIN chain c ON e.id = c.manager_id
+			WHERE c.depth < ?
+		)
+		SELECT id, depth FROM chain ORDER BY depth`, employeeID, maxOrgDepth).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get managers of employee %d: %w", employeeID, err)
+	}
+	if len(rows) == 0 {
+		return nil, common_errors.ErrResourceNotFound
+	}
+
+	var managerIDs []int
+	visited := map[int]bool{employeeID: true}
+	for _, row := range rows[1:] {
+		// stop at a cycle
+		if visited[row.ID] {
+			break
+		}
+		visited[row.ID] = true
+		managerIDs = append(managerIDs, row.ID)
+	}
+	return managerIDs, nil
+}
+
+func (r *employeeRepo) GetSubordinateIDs(ctx context.Context, managerID int) ([]int, error) {
+	return r.GetReportIDs(ctx, managerID, 0)
+}
+
+func (r *employeeRepo) GetReportIDs(ctx context.Context, managerID, depth int) ([]int, error) {
+	if depth <= 0 || depth > maxOrgDepth {
+		depth = maxOrgDepth
+	}
+
+	var rows []orgRow
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE reports (id, depth) AS (
+			SELECT id, 1 FROM employees WHERE manager_id = ?
+			UNION ALL
+			SELECT e.id, r.depth + 1 FROM employees e JOIN reports r ON e.manager_id = r.id
+			WHERE r.depth < ?
+		)
+		SELECT id, depth FROM reports ORDER BY depth, id`, managerID, depth).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subordinates: %w", err)
+	}
+
+	// a cycle would list the same employees again at deeper levels
+	var reportIDs []int
+	visited := map[int]bool{managerID: true}
+	for _, row := range rows {
+		if !visited[row.ID] {
+			visited[row.ID] = true
+			reportIDs = append(reportIDs, row.ID)
+		}
+	}
+	return reportIDs, nil
+}
+
+func (r *employeeRepo) Terminate(ctx context.Context, employeeID int, terminationDate time.Time) error {
+	return transaction.Run(ctx, r.db, func(tx *gorm.DB) error {
+		var employee Employee
+		if err := tx.Select("id", "termination_date").First(&employee, employeeID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return common_errors.ErrResourceNotFound
+			}
+			return fmt.Errorf("failed to find employee with id %d: %w", employeeID, err)
+		}
+
+		result := tx.Model(&Employee{}).Where("id = ? AND termination_date IS NULL", employeeID).
+			Updates(map[string]interface{}{
+				"termination_date": terminationDate,
+				"version":          gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to terminate employee: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w, employee %d is already terminated", common_errors.ErrStatusConflict, employeeID)
+		}
+
+		// positions end at the start of the day after the termination date,
+		// the ones which would have started later never will
+		end := terminationDate.AddDate(0, 0, 1)
+		err := tx.Model(&Position{}).
+			Where("employee_id = ? AND (end_date IS NULL OR end_date > ?)", employeeID, end).
+			Update("end_date", end).Error
+		if err != nil {
+			return fmt.Errorf("failed to end positions: %w", err)
+		}
+		if err := tx.Where("employee_id = ? AND start_date >= ?", employeeID, end).Delete(&Position{}).Error; err != nil {
+			return fmt.Errorf("failed to delete future positions: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *employeeRepo) HandOverReports(ctx context.Context, employeeID int, successorID *int) ([]int, error) {
+	var reportIDs []int
+	err := transaction.Run(ctx, r.db, func(tx *gorm.DB) error {
+		var employee Employee
+		if err := tx.Select("id", "manager_id").First(&employee, employeeID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return common_errors.ErrResourceNotFound
+			}
+			return fmt.Errorf("failed to find employee with id %d: %w", employeeID, err)
+		}
+
+		err := tx.Model(&Employee{}).Where("manager_id = ?", employeeID).Order("id ASC").Pluck("id", &reportIDs).Error
+		if err != nil {
+			return fmt.Errorf("failed to get direct reports: %w", err)
+		}
+		if successorID == nil || len(reportIDs) == 0 {
+			return nil
+		}
+		// the successor takes the place of the employee if they were one of the reports
+		err = tx.Model(&Employee{}).Where("manager_id = ? AND id = ?", employeeID, *successorID).
+			Updates(map[string]interface{}{
+				"manager_id": employee.ManagerID,
+				"version":    gorm.Expr("version + 1"),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to move successor: %w", err)
+		}
+		err = tx.Model(&Employee{}).Where("manager_id = ?", employeeID).
+			Updates(map[string]interface{}{
+				"manager_id": *successorID,
+				"version":    gorm.Expr("version + 1"),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to move direct reports: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reportIDs, nil
 }
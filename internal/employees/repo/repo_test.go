@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -9,6 +10,8 @@ import (
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
 	"hr-system/internal/employees/domain"
 )
 
@@ -121,8 +124,350 @@ func TestEmployeeRepo_GetEmployees(t *testing.T) {
 	err = repo.Create(context.Background(), employee2)
 	assert.NoError(t, err)
 
-	employees, totalCount, err := repo.GetEmployees(context.Background(), 1, 10)
+	page, err := repo.GetEmployees(context.Background(), domain.EmployeesQuery{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, common.GetPtr(2), page.TotalCount)
+	assert.Len(t, page.Employees, 2)
+	assert.Nil(t, page.Next)
+
+	page, err = repo.GetEmployees(context.Background(), domain.EmployeesQuery{Page: 1, PageSize: 1, SkipCount: true})
+	assert.NoError(t, err)
+	assert.Nil(t, page.TotalCount)
+	assert.Equal(t, []string{"John Doe"}, []string{page.Employees[0].Name})
+	assert.Equal(t, &domain.EmployeeCursor{ID: employee1.ID}, page.Next)
+}
+
+func TestEmployeeRepo_GetEmployees_Cursor(t *testing.T) {
+	db := setupTestDB(t)
+	repo, err := NewEmployeeRepo(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	startDate := time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)
+	// the same names and start dates make the ties broken by ID
+	for i, name := range []string{"Bob", "Alice", "Bob", "Carol", "Alice"} {
+		err := repo.Create(ctx, &domain.Employee{Name: name, Email: fmt.Sprintf("%d@example.com", i), Positions: []domain.Position{
+			{Title: "Engineer", StartDate: startDate.AddDate(0, i%2, 0)},
+		}})
+		assert.NoError(t, err)
+	}
+
+	for _, test := range []struct {
+		sort string
+		want []int
+	}{
+		{"", []int{1, 2, 3, 4, 5}},
+		{"-id", []int{5, 4, 3, 2, 1}},
+		{"name", []int{2, 5, 1, 3, 4}},
+		{"-name", []int{4, 1, 3, 2, 5}},
+		{"-start_date,name", []int{2, 4, 5, 1, 3}},
+		{"start_date,-name", []int{1, 3, 5, 4, 2}},
+	} {
+		t.Run(test.sort, func(t *testing.T) {
+			query := domain.EmployeesQuery{Page: 1, PageSize: 2, Sort: domain.ParseSort(test.sort), SkipCount: true}
+			query.Normalize()
+			var ids []int
+			for pages := 0; pages < 10; pages++ {
+				page, err := repo.GetEmployees(ctx, query)
+				assert.NoError(t, err)
+				for _, e := range page.Employees {
+					ids = append(ids, e.ID)
+				}
+				if page.Next == nil {
+					break
+				}
+				assert.Equal(t, test.sort, page.Next.Sort)
+				query.After = page.Next
+			}
+			assert.Equal(t, test.want, ids)
+		})
+	}
+}
+
+func TestEmployeeRepo_GetEmployees_NoPosition(t *testing.T) {
+	db := setupTestDB(t)
+	repo, err := NewEmployeeRepo(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	startDate := time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)
+	for i := 0; i < 3; i++ {
+		var positions []domain.Position
+		if i == 1 {
+			positions = []domain.Position{{Title: "Engineer", StartDate: startDate}}
+		}
+		err := repo.Create(ctx, &domain.Employee{Name: "Bob", Email: fmt.Sprintf("%d@example.com", i),
+			Positions: positions})
+		assert.NoError(t, err)
+	}
+
+	// the employees without a position start before everyone and are paged past like the others
+	for _, test := range []struct {
+		sort string
+		want []int
+	}{
+		{"start_date", []int{1, 3, 2}},
+		{"-start_date", []int{2, 1, 3}},
+	} {
+		t.Run(test.sort, func(t *testing.T) {
+			query := domain.EmployeesQuery{Page: 1, PageSize: 1, Sort: domain.ParseSort(test.sort), SkipCount: true}
+			query.Normalize()
+			var ids []int
+			for pages := 0; pages < 10; pages++ {
+				page, err := repo.GetEmployees(ctx, query)
+				assert.NoError(t, err)
+				for _, e := range page.Employees {
+					ids = append(ids, e.ID)
+				}
+				if page.Next == nil {
+					break
+				}
+				query.After = page.Next
+			}
+			assert.Equal(t, test.want, ids)
+		})
+	}
+}
+
+func TestEmployeeRepo_GetEmployees_Filters(t *testing.T) {
+	db := setupTestDB(t)
+	repo, err := NewEmployeeRepo(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now()
+	employees := []*domain.Employee{
+		{Name: "Alice Boss", Email: "alice@example.com", Positions: []domain.Position{
+			{Title: "Engineering Manager", Level: "Senior", StartDate: now.AddDate(-3, 0, 0)},
+		}},
+		{Name: "Bob 100% Builder", Email: "bob@example.com", Positions: []domain.Position{
+			{Title: "Software Engineer", Level: "Junior", StartDate: now.AddDate(-2, 0, 0), EndDate: common.GetPtr(now.AddDate(0, -1, 0))},
+			{Title: "Software Engineer", Level: "Senior", StartDate: now.AddDate(0, -1, 0)},
+		}},
+		{Name: "Carol Doe", Email: "carol@example.com", Positions: []domain.Position{
+			{Title: "Software Engineer", Level: "Junior", StartDate: now.AddDate(-1, 0, 0)},
+		}},
+	}
+	for _, employee := range employees {
+		assert.NoError(t, repo.Create(ctx, employee))
+	}
+	for _, employee := range employees[1:] {
+		employee.ManagerID = &employees[0].ID
+		assert.NoError(t, repo.Update(ctx, employee))
+	}
+
+	names := func(query domain.EmployeesQuery) []string {
+		query.Page, query.PageSize = 1, 10
+		query.Normalize()
+		page, err := repo.GetEmployees(ctx, query)
+		assert.NoError(t, err)
+		assert.Len(t, page.Employees, *page.TotalCount)
+		var names []string
+		for _, e := range page.Employees {
+			names = append(names, e.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"Carol Doe"}, names(domain.EmployeesQuery{Search: "DOE"}))
+	assert.Equal(t, []string{"Bob 100% Builder"}, names(domain.EmployeesQuery{Search: "100%"}))
+	assert.Equal(t, []string{"Alice Boss"}, names(domain.EmployeesQuery{Search: "alice@"}))
+	assert.Equal(t, []string{"Bob 100% Builder", "Carol Doe"}, names(domain.EmployeesQuery{ManagerID: &employees[0].ID}))
+	assert.Equal(t, []string{"Bob 100% Builder", "Carol Doe"}, names(domain.EmployeesQuery{Title: "software"}))
+	assert.Equal(t, []string{"Carol Doe"}, names(domain.EmployeesQuery{Level: "Junior"}))
+	assert.Equal(t, []string{"Alice Boss", "Bob 100% Builder"}, names(domain.EmployeesQuery{Level: "Senior"}))
+	assert.Equal(t, []string{"Carol Doe", "Bob 100% Builder", "Alice Boss"},
+		names(domain.EmployeesQuery{Sort: []domain.SortField{{Field: "name", Desc: true}}}))
+	assert.Equal(t, []string{"Carol Doe", "Bob 100% Builder", "Alice Boss"},
+		names(domain.EmployeesQuery{Sort: []domain.SortField{{Field: "start_date", Desc: true}}}))
+	assert.Equal(t, []string{"Alice Boss", "Carol Doe"},
+		names(domain.EmployeesQuery{IDs: []int{employees[0].ID, employees[2].ID}}))
+}
+
+func TestEmployeeRepo_GetManagerChainAndSubordinates(t *testing.T) {
+	db := setupTestDB(t)
+	repo, err := NewEmployeeRepo(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	// 1 <- 2 <- 3, 1 <- 4
+	var ids []int
+	for i, managerIdx := range []int{-1, 0, 1, 0} {
+		employee := &domain.Employee{Name: "employee", Email: fmt.Sprintf("employee%d@example.com", i)}
+		if managerIdx >= 0 {
+			employee.ManagerID = common.GetPtr(ids[managerIdx])
+		}
+		assert.NoError(t, repo.Create(ctx, employee))
+		ids = append(ids, employee.ID)
+	}
+
+	chain, err := repo.GetManagerChain(ctx, ids[2])
+	assert.NoError(t, err)
+	assert.Equal(t, []int{ids[1], ids[0]}, chain)
+
+	subordinates, err := repo.GetSubordinateIDs(ctx, ids[0])
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []int{ids[1], ids[2], ids[3]}, subordinates)
+
+	// the nearest level first
+	reports, err := repo.GetReportIDs(ctx, ids[0], 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{ids[1], ids[3]}, reports)
+	reports, err = repo.GetReportIDs(ctx, ids[0], 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{ids[1], ids[3], ids[2]}, reports)
+
+	_, err = repo.GetManagerChain(ctx, 100)
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+
+	// a reporting cycle does not make the walks endless
+	assert.NoError(t, db.Model(&Employee{}).Where("id = ?", ids[0]).Update("manager_id", ids[2]).Error)
+	chain, err = repo.GetManagerChain(ctx, ids[2])
+	assert.NoError(t, err)
+	assert.Equal(t, []int{ids[1], ids[0]}, chain)
+	subordinates, err = repo.GetSubordinateIDs(ctx, ids[0])
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []int{ids[1], ids[2], ids[3]}, subordinates)
+
+	employees, err := repo.GetEmployeesByIDs(ctx, []int{ids[3], ids[1]})
 	assert.NoError(t, err)
-	assert.Equal(t, 2, totalCount)
 	assert.Len(t, employees, 2)
 }
+
+func TestEmployeeRepo_Update(t *testing.T) {
+	db := setupTestDB(t)
+	repo, err := NewEmployeeRepo(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	employee := &domain.Employee{Name: "John Doe", Email: "john.doe@example.com"}
+	assert.NoError(t, repo.Create(ctx, employee))
+	assert.Equal(t, 1, employee.Version)
+
+	employee.Address = "456 Oak St"
+	assert.NoError(t, repo.Update(ctx, employee))
+	assert.Equal(t, 2, employee.Version)
+
+	fetched, err := repo.GetEmployeeByID(ctx, employee.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "456 Oak St", fetched.Address)
+	assert.Equal(t, 2, fetched.Version)
+
+	// an update based on the old version loses
+	stale := fetched
+	stale.Version = 1
+	assert.ErrorIs(t, repo.Update(ctx, &stale), common_errors.ErrPreconditionFailed)
+
+	missing := domain.Employee{ID: 100, Version: 1}
+	assert.ErrorIs(t, repo.Update(ctx, &missing), common_errors.ErrResourceNotFound)
+}
+
+func TestEmployeeRepo_ChangePosition(t *testing.T) {
+	db := setupTestDB(t)
+	repo, err := NewEmployeeRepo(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	employee := &domain.Employee{Name: "John Doe", Email: "john.doe@example.com",
+		Positions: []domain.Position{{Title: "Engineer", StartDate: start}}}
+	assert.NoError(t, repo.Create(ctx, employee))
+
+	promotion := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, repo.ChangePosition(ctx, employee.ID, &domain.Position{Title: "Lead", StartDate: promotion}))
+
+	fetched, err := repo.GetEmployeeByID(ctx, employee.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fetched.Version)
+	assert.Len(t, fetched.Positions, 2)
+	assert.Equal(t, "Lead", fetched.Positions[0].Title)
+	assert.Nil(t, fetched.Positions[0].EndDate)
+	assert.Equal(t, "Engineer", fetched.Positions[1].Title)
+	assert.True(t, promotion.Equal(*fetched.Positions[1].EndDate))
+
+	// a position can not be inserted before the latest one
+	err = repo.ChangePosition(ctx, employee.ID, &domain.Position{Title: "Manager", StartDate: promotion})
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+
+	err = repo.ChangePosition(ctx, 100, &domain.Position{Title: "Manager", StartDate: promotion})
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+}
+
+func TestEmployeeRepo_Terminate(t *testing.T) {
+	db := setupTestDB(t)
+	repo, err := NewEmployeeRepo(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	boss := &domain.Employee{Name: "Boss", Email: "boss@example.com"}
+	assert.NoError(t, repo.Create(ctx, boss))
+	manager := &domain.Employee{Name: "Manager", Email: "manager@example.com", ManagerID: &boss.ID,
+		Positions: []domain.Position{{Title: "Manager", StartDate: start}}}
+	assert.NoError(t, repo.Create(ctx, manager))
+	var reports []*domain.Employee
+	for _, name := range []string{"alice", "bob"} {
+		report := &domain.Employee{Name: name, Email: name + "@example.com", ManagerID: &manager.ID}
+		assert.NoError(t, repo.Create(ctx, report))
+		reports = append(reports, report)
+	}
+
+	terminationDate := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, repo.ChangePosition(ctx, manager.ID,
+		&domain.Position{Title: "Director", StartDate: terminationDate.AddDate(0, 0, 1)}))
+
+	assert.NoError(t, repo.Terminate(ctx, manager.ID, terminationDate))
+
+	fetched, err := repo.GetEmployeeByID(ctx, manager.ID)
+	assert.NoError(t, err)
+	assert.True(t, terminationDate.Equal(*fetched.TerminationDate))
+	assert.Len(t, fetched.Positions, 1)
+	assert.True(t, terminationDate.AddDate(0, 0, 1).Equal(*fetched.Positions[0].EndDate))
+
+	// the reports stay until they are handed over
+	report, err := repo.GetEmployeeByID(ctx, reports[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, manager.ID, *report.ManagerID)
+
+	assert.ErrorIs(t, repo.Terminate(ctx, manager.ID, terminationDate), common_errors.ErrStatusConflict)
+	assert.ErrorIs(t, repo.Terminate(ctx, 100, terminationDate), common_errors.ErrResourceNotFound)
+}
+
+func TestEmployeeRepo_HandOverReports(t *testing.T) {
+	db := setupTestDB(t)
+	repo, err := NewEmployeeRepo(db)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	boss := &domain.Employee{Name: "Boss", Email: "boss@example.com"}
+	assert.NoError(t, repo.Create(ctx, boss))
+	manager := &domain.Employee{Name: "Manager", Email: "manager@example.com", ManagerID: &boss.ID}
+	assert.NoError(t, repo.Create(ctx, manager))
+	var reports []*domain.Employee
+	for _, name := range []string{"alice", "bob"} {
+		report := &domain.Employee{Name: name, Email: name + "@example.com", ManagerID: &manager.ID}
+		assert.NoError(t, repo.Create(ctx, report))
+		reports = append(reports, report)
+	}
+
+	// the first report succeeds the manager
+	reportIDs, err := repo.HandOverReports(ctx, manager.ID, &reports[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{reports[0].ID, reports[1].ID}, reportIDs)
+
+	successor, err := repo.GetEmployeeByID(ctx, reports[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, boss.ID, *successor.ManagerID)
+	assert.Equal(t, 2, successor.Version)
+	report, err := repo.GetEmployeeByID(ctx, reports[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, reports[0].ID, *report.ManagerID)
+
+	// handed over already
+	reportIDs, err = repo.HandOverReports(ctx, manager.ID, &reports[0].ID)
+	assert.NoError(t, err)
+	assert.Empty(t, reportIDs)
+
+	_, err = repo.HandOverReports(ctx, 100, nil)
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+}
@@ -7,6 +7,8 @@ import (
 	domain "hr-system/internal/employees/domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // EmployeeService is an autogenerated mock type for the EmployeeService type
@@ -14,6 +16,34 @@ type EmployeeService struct {
 	mock.Mock
 }
 
+// ChangePosition provides a mock function with given fields: ctx, employeeID, position
+func (_m *EmployeeService) ChangePosition(ctx context.Context, employeeID int, position *domain.Position) (domain.Employee, error) {
+	ret := _m.Called(ctx, employeeID, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePosition")
+	}
+
+	var r0 domain.Employee
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *domain.Position) (domain.Employee, error)); ok {
+		return rf(ctx, employeeID, position)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *domain.Position) domain.Employee); ok {
+		r0 = rf(ctx, employeeID, position)
+	} else {
+		r0 = ret.Get(0).(domain.Employee)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *domain.Position) error); ok {
+		r1 = rf(ctx, employeeID, position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateEmployee provides a mock function with given fields: ctx, employee
 func (_m *EmployeeService) CreateEmployee(ctx context.Context, employee *domain.Employee) (domain.Employee, error) {
 	ret := _m.Called(ctx, employee)
@@ -42,6 +72,36 @@ func (_m *EmployeeService) CreateEmployee(ctx context.Context, employee *domain.
 	return r0, r1
 }
 
+// GetChain provides a mock function with given fields: ctx, id
+func (_m *EmployeeService) GetChain(ctx context.Context, id int) ([]domain.OrgNode, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChain")
+	}
+
+	var r0 []domain.OrgNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.OrgNode, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.OrgNode); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.OrgNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEmployeeByID provides a mock function with given fields: ctx, id
 func (_m *EmployeeService) GetEmployeeByID(ctx context.Context, id int) (domain.Employee, error) {
 	ret := _m.Called(ctx, id)
@@ -70,41 +130,172 @@ func (_m *EmployeeService) GetEmployeeByID(ctx context.Context, id int) (domain.
 	return r0, r1
 }
 
-// GetEmployees provides a mock function with given fields: ctx, page, pageSize
-func (_m *EmployeeService) GetEmployees(ctx context.Context, page int, pageSize int) ([]domain.Employee, int, error) {
-	ret := _m.Called(ctx, page, pageSize)
+// GetEmployees provides a mock function with given fields: ctx, query
+func (_m *EmployeeService) GetEmployees(ctx context.Context, query domain.EmployeesQuery) (domain.EmployeesPage, error) {
+	ret := _m.Called(ctx, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetEmployees")
 	}
 
-	var r0 []domain.Employee
-	var r1 int
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]domain.Employee, int, error)); ok {
-		return rf(ctx, page, pageSize)
+	var r0 domain.EmployeesPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.EmployeesQuery) (domain.EmployeesPage, error)); ok {
+		return rf(ctx, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) []domain.Employee); ok {
-		r0 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.EmployeesQuery) domain.EmployeesPage); ok {
+		r0 = rf(ctx, query)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Employee)
-		}
+		r0 = ret.Get(0).(domain.EmployeesPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.EmployeesQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReports provides a mock function with given fields: ctx, id, depth
+func (_m *EmployeeService) GetReports(ctx context.Context, id int, depth int) (domain.OrgNode, error) {
+	ret := _m.Called(ctx, id, depth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReports")
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
-		r1 = rf(ctx, page, pageSize)
+	var r0 domain.OrgNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (domain.OrgNode, error)); ok {
+		return rf(ctx, id, depth)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) domain.OrgNode); ok {
+		r0 = rf(ctx, id, depth)
 	} else {
-		r1 = ret.Get(1).(int)
+		r0 = ret.Get(0).(domain.OrgNode)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
-		r2 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, id, depth)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
+}
+
+// HandOverReports provides a mock function with given fields: ctx, employeeID, successorID
+func (_m *EmployeeService) HandOverReports(ctx context.Context, employeeID int, successorID *int) (int, error) {
+	ret := _m.Called(ctx, employeeID, successorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HandOverReports")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *int) (int, error)); ok {
+		return rf(ctx, employeeID, successorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *int) int); ok {
+		r0 = rf(ctx, employeeID, successorID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *int) error); ok {
+		r1 = rf(ctx, employeeID, successorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchEmployee provides a mock function with given fields: ctx, id, version, patch
+func (_m *EmployeeService) PatchEmployee(ctx context.Context, id int, version int, patch *domain.EmployeePatch) (domain.Employee, error) {
+	ret := _m.Called(ctx, id, version, patch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchEmployee")
+	}
+
+	var r0 domain.Employee
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *domain.EmployeePatch) (domain.Employee, error)); ok {
+		return rf(ctx, id, version, patch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *domain.EmployeePatch) domain.Employee); ok {
+		r0 = rf(ctx, id, version, patch)
+	} else {
+		r0 = ret.Get(0).(domain.Employee)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *domain.EmployeePatch) error); ok {
+		r1 = rf(ctx, id, version, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TerminateEmployee provides a mock function with given fields: ctx, employeeID, terminationDate, successorID
+func (_m *EmployeeService) TerminateEmployee(ctx context.Context, employeeID int, terminationDate time.Time, successorID *int) (domain.Employee, error) {
+	ret := _m.Called(ctx, employeeID, terminationDate, successorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TerminateEmployee")
+	}
+
+	var r0 domain.Employee
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, *int) (domain.Employee, error)); ok {
+		return rf(ctx, employeeID, terminationDate, successorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, *int) domain.Employee); ok {
+		r0 = rf(ctx, employeeID, terminationDate, successorID)
+	} else {
+		r0 = ret.Get(0).(domain.Employee)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, *int) error); ok {
+		r1 = rf(ctx, employeeID, terminationDate, successorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateEmployee provides a mock function with given fields: ctx, employee
+func (_m *EmployeeService) UpdateEmployee(ctx context.Context, employee *domain.Employee) (domain.Employee, error) {
+	ret := _m.Called(ctx, employee)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEmployee")
+	}
+
+	var r0 domain.Employee
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Employee) (domain.Employee, error)); ok {
+		return rf(ctx, employee)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Employee) domain.Employee); ok {
+		r0 = rf(ctx, employee)
+	} else {
+		r0 = ret.Get(0).(domain.Employee)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Employee) error); ok {
+		r1 = rf(ctx, employee)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewEmployeeService creates a new instance of EmployeeService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 
+	"hr-system/internal/auth"
 	"hr-system/internal/common"
 	common_errors "hr-system/internal/common/errors"
 	"hr-system/internal/employees/cache"
@@ -18,7 +19,25 @@ import (
 type EmployeeService interface {
 	CreateEmployee(ctx context.Context, employee *domain.Employee) (domain.Employee, error)
 	GetEmployeeByID(ctx context.Context, id int) (domain.Employee, error)
-	GetEmployees(ctx context.Context, page, pageSize int) (employees []domain.Employee, totalCount int, err error)
+	// GetEmployees lists the employees in the status of the query, the active ones by default
+	GetEmployees(ctx context.Context, query domain.EmployeesQuery) (domain.EmployeesPage, error)
+	// UpdateEmployee replaces the fields of the employee except the positions,
+	// employee.Version must be the version the change is based on
+	UpdateEmployee(ctx context.Context, employee *domain.Employee) (domain.Employee, error)
+	// PatchEmployee changes the fields set in the patch of the employee at the version
+	PatchEmployee(ctx context.Context, id, version int, patch *domain.EmployeePatch) (domain.Employee, error)
+	// ChangePosition moves the employee to the position from its start date on, today or later
+	ChangePosition(ctx context.Context, employeeID int, position *domain.Position) (domain.Employee, error)
+	// TerminateEmployee records the last day of the employee, the successor has to be able to take their direct
+	// reports, which are moved by HandOverReports once the employee has left
+	TerminateEmployee(ctx context.Context, employeeID int, terminationDate time.Time,
+		successorID *int) (domain.Employee, error)
+	// HandOverReports moves the direct reports of the employee to the successor, it returns the number of moved reports
+	HandOverReports(ctx context.Context, employeeID int, successorID *int) (int, error)
+	// GetReports returns the org chart below the employee down to depth levels, all of them when depth is 0
+	GetReports(ctx context.Context, id, depth int) (domain.OrgNode, error)
+	// GetChain returns the managers above the employee up to the top, the nearest first
+	GetChain(ctx context.Context, id int) ([]domain.OrgNode, error)
 }
 
 type employeeService struct {
@@ -49,12 +68,28 @@ func (s *employeeService) validateCreateEmployee(e *domain.Employee) error {
 	return nil
 }
 
+// redact hides what the actor is not allowed to see
+func redact(actor auth.Actor, employee domain.Employee) domain.Employee {
+	if actor.CanSeeSalary() {
+		return employee
+	}
+	return employee.WithoutSalary()
+}
+
 func (s *employeeService) CreateEmployee(ctx context.Context, employee *domain.Employee) (domain.Employee, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.Employee{}, err
+	}
 	if err := s.validateCreateEmployee(employee); err != nil {
 		return domain.Employee{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
 	}
 
-	// TODO: check manager level vs manager
+	if employee.ManagerID != nil {
+		if err := s.checkManager(ctx, employee, *employee.ManagerID); err != nil {
+			return domain.Employee{}, err
+		}
+	}
+
 	err := s.repo.Create(ctx, employee)
 	if err != nil {
 		return domain.Employee{}, err
@@ -64,6 +99,10 @@ func (s *employeeService) CreateEmployee(ctx context.Context, employee *domain.E
 	if err != nil {
 		s.logger.Errorf("failed to update cache, cause: %s", err)
 	}
+	err = s.cache.DeleteOrgChartCache(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to delete org chart cache, cause: %s", err)
+	}
 
 	err = s.cache.SetEmployeeToCache(ctx, employee, 1*time.Hour)
 	if err != nil {
@@ -74,6 +113,22 @@ func (s *employeeService) CreateEmployee(ctx context.Context, employee *domain.E
 }
 
 func (s *employeeService) GetEmployeeByID(ctx context.Context, id int) (domain.Employee, error) {
+	actor, err := auth.AuthorizeEmployee(ctx, s.repo, id)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Employee{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Employee{}, err
+	}
+
+	employee, err := s.getEmployeeByID(ctx, id)
+	if err != nil {
+		return domain.Employee{}, err
+	}
+	return redact(actor, employee), nil
+}
+
+func (s *employeeService) getEmployeeByID(ctx context.Context, id int) (domain.Employee, error) {
 	employee, err := s.cache.GetEmployeeByID(ctx, id)
 	if err == nil {
 		s.logger.Infof("[Cache Hit] employee id: %d", id)
@@ -98,27 +153,487 @@ func (s *employeeService) GetEmployeeByID(ctx context.Context, id int) (domain.E
 	return employee, nil
 }
 
-func (s *employeeService) GetEmployees(ctx context.Context, page, pageSize int) ([]domain.Employee, int, error) {
-	if page < 1 || pageSize < 1 {
-		return nil, 0, fmt.Errorf("%w, invalid page(%d) or page size(%d)", common_errors.ErrInvalidInput, page, pageSize)
+func (s *employeeService) UpdateEmployee(ctx context.Context, employee *domain.Employee) (domain.Employee, error) {
+	actor, err := auth.AuthorizeSelf(ctx, employee.ID, auth.RoleHRAdmin)
+	if err != nil {
+		return domain.Employee{}, err
+	}
+
+	current, err := s.repo.GetEmployeeByID(ctx, employee.ID)
+	if err != nil {
+		return domain.Employee{}, err
+	}
+
+	return s.update(ctx, actor, &current, employee)
+}
+
+func (s *employeeService) PatchEmployee(ctx context.Context, id, version int,
+	patch *domain.EmployeePatch) (domain.Employee, error) {
+	actor, err := auth.AuthorizeSelf(ctx, id, auth.RoleHRAdmin)
+	if err != nil {
+		return domain.Employee{}, err
+	}
+
+	current, err := s.repo.GetEmployeeByID(ctx, id)
+	if err != nil {
+		return domain.Employee{}, err
+	}
+	updated := current
+	patch.Apply(&updated)
+	updated.Version = version
+
+	return s.update(ctx, actor, &current, &updated)
+}
+
+// update saves the change from current to updated
+func (s *employeeService) update(ctx context.Context, actor auth.Actor, current,
+	updated *domain.Employee) (domain.Employee, error) {
+	// the employee themselves only keeps their contact up to date
+	if !actor.HasRole(auth.RoleHRAdmin) && (updated.Name != current.Name || updated.Email != current.Email ||
+		updated.Region != current.Region || !common.PtrEqual(updated.ManagerID, current.ManagerID)) {
+		return domain.Employee{}, fmt.Errorf("%w, only HR can change the fields other than address and phone number",
+			common_errors.ErrForbidden)
+	}
+
+	if err := s.validate.StructExcept(updated, "Positions", "Manager"); err != nil {
+		return domain.Employee{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	if updated.ManagerID != nil && !common.PtrEqual(updated.ManagerID, current.ManagerID) {
+		if err := s.checkManager(ctx, current, *updated.ManagerID); err != nil {
+			return domain.Employee{}, err
+		}
+	}
+
+	if err := s.repo.Update(ctx, updated); err != nil {
+		return domain.Employee{}, err
+	}
+
+	if err := s.cache.DeleteEmployeeCache(ctx, updated.ID); err != nil {
+		s.logger.Errorf("failed to delete employee cache, cause: %s", err)
+	}
+	if err := s.cache.DeleteEmployeesListCache(ctx); err != nil {
+		s.logger.Errorf("failed to update cache, cause: %s", err)
+	}
+	if err := s.cache.DeleteOrgChartCache(ctx); err != nil {
+		s.logger.Errorf("failed to delete org chart cache, cause: %s", err)
+	}
+
+	employee, err := s.getEmployeeByID(ctx, updated.ID)
+	if err != nil {
+		return domain.Employee{}, err
+	}
+	return redact(actor, employee), nil
+}
+
+func (s *employeeService) ChangePosition(ctx context.Context, employeeID int,
+	position *domain.Position) (domain.Employee, error) {
+	actor, err := auth.Authorize(ctx, auth.RoleHRAdmin)
+	if err != nil {
+		return domain.Employee{}, err
+	}
+	position.EndDate = nil
+	if err := s.validate.Struct(position); err != nil {
+		return domain.Employee{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	// the history is not rewritten
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if position.StartDate.Before(today) {
+		return domain.Employee{}, fmt.Errorf("%w, the position can not start before today",
+			common_errors.ErrInvalidInput)
+	}
+
+	if err := s.checkPositionLevel(ctx, employeeID, position); err != nil {
+		return domain.Employee{}, err
+	}
+
+	if err := s.repo.ChangePosition(ctx, employeeID, position); err != nil {
+		return domain.Employee{}, err
+	}
+
+	if err := s.cache.DeleteEmployeeCache(ctx, employeeID); err != nil {
+		s.logger.Errorf("failed to delete employee cache, cause: %s", err)
+	}
+	if err := s.cache.DeleteEmployeesListCache(ctx); err != nil {
+		s.logger.Errorf("failed to update cache, cause: %s", err)
+	}
+	if err := s.cache.DeleteOrgChartCache(ctx); err != nil {
+		s.logger.Errorf("failed to delete org chart cache, cause: %s", err)
+	}
+
+	employee, err := s.getEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return domain.Employee{}, err
+	}
+	return redact(actor, employee), nil
+}
+
+func (s *employeeService) TerminateEmployee(ctx context.Context, employeeID int, terminationDate time.Time,
+	successorID *int) (domain.Employee, error) {
+	actor, err := auth.Authorize(ctx, auth.RoleHRAdmin)
+	if err != nil {
+		return domain.Employee{}, err
+	}
+	if terminationDate.IsZero() {
+		return domain.Employee{}, fmt.Errorf("%w, termination date is required", common_errors.ErrInvalidInput)
+	}
+
+	if err := s.checkHandOver(ctx, employeeID, successorID); err != nil {
+		return domain.Employee{}, err
+	}
+
+	if err := s.repo.Terminate(ctx, employeeID, terminationDate); err != nil {
+		return domain.Employee{}, err
+	}
+
+	s.deleteCaches(ctx, []int{employeeID})
+
+	employee, err := s.getEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return domain.Employee{}, err
+	}
+	return redact(actor, employee), nil
+}
+
+func (s *employeeService) HandOverReports(ctx context.Context, employeeID int, successorID *int) (int, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return 0, err
+	}
+
+	// the successor or the reports may have changed since the employee was terminated
+	if err := s.checkHandOver(ctx, employeeID, successorID); err != nil {
+		return 0, err
+	}
+
+	reportIDs, err := s.repo.HandOverReports(ctx, employeeID, successorID)
+	if err != nil {
+		return 0, err
+	}
+	if len(reportIDs) > 0 {
+		s.deleteCaches(ctx, reportIDs)
+	}
+
+	return len(reportIDs), nil
+}
+
+// checkHandOver makes sure the direct reports of the employee can move to the successor,
+// who is required if there are any
+func (s *employeeService) checkHandOver(ctx context.Context, employeeID int, successorID *int) error {
+	if successorID != nil {
+		return s.checkSuccessor(ctx, employeeID, *successorID)
+	}
+
+	subordinateIDs, err := s.repo.GetSubordinateIDs(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to get subordinates: %w", err)
+	}
+	if len(subordinateIDs) > 0 {
+		return fmt.Errorf("%w, a successor is required for the direct reports", common_errors.ErrInvalidInput)
+	}
+	return nil
+}
+
+// deleteCaches deletes the caches of the changed employees and the caches listing them
+func (s *employeeService) deleteCaches(ctx context.Context, ids []int) {
+	for _, id := range ids {
+		if err := s.cache.DeleteEmployeeCache(ctx, id); err != nil {
+			s.logger.Errorf("failed to delete employee %d cache, cause: %s", id, err)
+		}
+	}
+	if err := s.cache.DeleteEmployeesListCache(ctx); err != nil {
+		s.logger.Errorf("failed to update cache, cause: %s", err)
+	}
+	if err := s.cache.DeleteOrgChartCache(ctx); err != nil {
+		s.logger.Errorf("failed to delete org chart cache, cause: %s", err)
+	}
+}
+
+// checkSuccessor makes sure the successor can take the direct reports of the employee without a reporting cycle,
+// which is only possible if they are not below the employee or one of the direct reports,
+// and without a report above the successor's manager level
+func (s *employeeService) checkSuccessor(ctx context.Context, employeeID, successorID int) error {
+	if successorID == employeeID {
+		return fmt.Errorf("%w, employee %d can not be their own successor", common_errors.ErrInvalidInput, employeeID)
+	}
+	successor, err := s.repo.GetEmployeeByID(ctx, successorID)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return fmt.Errorf("%w, successor %d not found", common_errors.ErrInvalidInput, successorID)
+		}
+		return fmt.Errorf("failed to get successor %d: %w", successorID, err)
+	}
+	if successor.TerminationDate != nil {
+		return fmt.Errorf("%w, successor %d is leaving the company", common_errors.ErrInvalidInput, successorID)
+	}
+
+	managerIDs, err := s.repo.GetManagerChain(ctx, successorID)
+	if err != nil {
+		return fmt.Errorf("failed to get managers of employee %d: %w", successorID, err)
+	}
+	for i, id := range managerIDs {
+		if id == employeeID && i > 0 {
+			return fmt.Errorf("%w, successor %d reports to a direct report of employee %d",
+				common_errors.ErrInvalidInput, successorID, employeeID)
+		}
+	}
+
+	now := time.Now()
+	reports, err := s.getDirectReports(ctx, employeeID, now)
+	if err != nil {
+		return err
+	}
+	for i := range reports {
+		if reports[i].ID == successorID {
+			continue
+		}
+		err := checkLevel(&successor, successor.PositionAt(now).ManagerLevel, &reports[i],
+			reports[i].PositionAt(now).ManagerLevel)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkManager makes sure the manager exists and is staying, the employee is not above them,
+// which would be a reporting cycle, and the manager is not at a lower manager level than the employee
+func (s *employeeService) checkManager(ctx context.Context, employee *domain.Employee, managerID int) error {
+	if managerID == employee.ID {
+		return fmt.Errorf("%w, employee %d can not be their own manager", common_errors.ErrInvalidInput, employee.ID)
+	}
+
+	managerIDs, err := s.repo.GetManagerChain(ctx, managerID)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return fmt.Errorf("%w, manager %d not found", common_errors.ErrInvalidInput, managerID)
+		}
+		return fmt.Errorf("failed to get managers of employee %d: %w", managerID, err)
+	}
+	for _, id := range managerIDs {
+		if id == employee.ID {
+			return fmt.Errorf("%w, manager %d reports to employee %d", common_errors.ErrInvalidInput, managerID, employee.ID)
+		}
+	}
+
+	manager, err := s.repo.GetEmployeeByID(ctx, managerID)
+	if err != nil {
+		return fmt.Errorf("failed to get manager %d: %w", managerID, err)
+	}
+	if manager.TerminationDate != nil {
+		return fmt.Errorf("%w, manager %d is leaving the company", common_errors.ErrInvalidInput, managerID)
+	}
+	now := time.Now()
+	return checkLevel(&manager, manager.PositionAt(now).ManagerLevel, employee, employee.PositionAt(now).ManagerLevel)
+}
+
+// checkPositionLevel makes sure the new position of the employee keeps them at a manager level
+// between the one of their manager and the ones of their direct reports when it starts
+func (s *employeeService) checkPositionLevel(ctx context.Context, employeeID int, position *domain.Position) error {
+	employee, err := s.repo.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		return err
+	}
+	at := position.StartDate
+
+	if employee.ManagerID != nil {
+		manager, err := s.repo.GetEmployeeByID(ctx, *employee.ManagerID)
+		if err != nil {
+			return fmt.Errorf("failed to get manager %d: %w", *employee.ManagerID, err)
+		}
+		err = checkLevel(&manager, manager.PositionAt(at).ManagerLevel, &employee, position.ManagerLevel)
+		if err != nil {
+			return err
+		}
+	}
+
+	reports, err := s.getDirectReports(ctx, employeeID, at)
+	if err != nil {
+		return err
+	}
+	for i := range reports {
+		if err := checkLevel(&employee, position.ManagerLevel, &reports[i], reports[i].PositionAt(at).ManagerLevel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDirectReports returns the employees reporting to the manager who have not left at the time
+func (s *employeeService) getDirectReports(ctx context.Context, managerID int, at time.Time) ([]domain.Employee,
+	error) {
+	ids, err := s.repo.GetReportIDs(ctx, managerID, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	employees, err := s.repo.GetEmployeesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]domain.Employee, 0, len(employees))
+	for i := range employees {
+		if !employees[i].TerminatedAt(at) {
+			reports = append(reports, employees[i])
+		}
 	}
-	employees, totalCount, err := s.cache.GetEmployees(ctx, page, pageSize)
+	return reports, nil
+}
+
+// checkLevel makes sure nobody reports to a manager at a lower manager level than theirs
+func checkLevel(manager *domain.Employee, managerLevel int, report *domain.Employee, reportLevel int) error {
+	if managerLevel < reportLevel {
+		return fmt.Errorf("%w, manager %d at manager level %d is below employee %d at manager level %d",
+			common_errors.ErrInvalidInput, manager.ID, managerLevel, report.ID, reportLevel)
+	}
+	return nil
+}
+
+// GetEmployees lists every employee for HR and payroll, and the reporting subtree for managers
+func (s *employeeService) GetEmployees(ctx context.Context, query domain.EmployeesQuery) (domain.EmployeesPage,
+	error) {
+	query.Normalize()
+	if err := s.validate.Struct(query); err != nil {
+		return domain.EmployeesPage{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	if query.After != nil && query.After.Sort != domain.FormatSort(query.Sort) {
+		return domain.EmployeesPage{}, fmt.Errorf("%w, the cursor is of another sort", common_errors.ErrInvalidInput)
+	}
+	actor, err := auth.Authorize(ctx, auth.RoleHRAdmin, auth.RolePayroll, auth.RoleManager)
+	if err != nil {
+		return domain.EmployeesPage{}, err
+	}
+	if !actor.HasAnyRole(auth.RoleHRAdmin, auth.RolePayroll) {
+		return s.getSubordinates(ctx, actor, query)
+	}
+
+	page, err := s.cache.GetEmployees(ctx, query)
 	if err == nil {
-		s.logger.Infof("[Cache Hit] emplyees page: %d, pageSize: %d", page, pageSize)
-		return employees, totalCount, nil
+		s.logger.Infof("[Cache Hit] emplyees query: %+v", query)
+		return page, nil
 	}
 	if err != nil && !errors.Is(err, common_errors.ErrResourceNotFound) {
 		s.logger.Warnf("failed to get employees from cache, cause: %s", err)
 	}
 
-	employees, totalCount, err = s.repo.GetEmployees(ctx, page, pageSize)
+	page, err = s.repo.GetEmployees(ctx, query)
 	if err != nil {
-		return nil, 0, err
+		return domain.EmployeesPage{}, err
+	}
+
+	if err := s.cache.SetEmployeesToCache(ctx, query, &page, 1*time.Hour); err != nil {
+		s.logger.Warnf("failed to update cache, cause: %s", err)
+	}
+
+	return page, nil
+}
+
+// getSubordinates lists the reporting subtree of the manager
+func (s *employeeService) getSubordinates(ctx context.Context, actor auth.Actor,
+	query domain.EmployeesQuery) (domain.EmployeesPage, error) {
+	ids, err := s.repo.GetSubordinateIDs(ctx, actor.EmployeeID)
+	if err != nil {
+		return domain.EmployeesPage{}, fmt.Errorf("failed to get subordinates: %w", err)
+	}
+	if len(ids) == 0 {
+		page := domain.EmployeesPage{Employees: []domain.Employee{}}
+		if !query.SkipCount {
+			page.TotalCount = common.GetPtr(0)
+		}
+		return page, nil
+	}
+
+	query.IDs = ids
+	page, err := s.repo.GetEmployees(ctx, query)
+	if err != nil {
+		return domain.EmployeesPage{}, err
+	}
+	for i := range page.Employees {
+		page.Employees[i] = redact(actor, page.Employees[i])
+	}
+
+	return page, nil
+}
+
+func (s *employeeService) GetReports(ctx context.Context, id, depth int) (domain.OrgNode, error) {
+	// the org chart is open to everyone in the company
+	if _, err := auth.Authorize(ctx); err != nil {
+		return domain.OrgNode{}, err
+	}
+	if depth < 0 {
+		return domain.OrgNode{}, fmt.Errorf("%w, invalid depth(%d)", common_errors.ErrInvalidInput, depth)
+	}
+
+	reports, err := s.cache.GetReports(ctx, id, depth)
+	if err == nil {
+		s.logger.Infof("[Cache Hit] reports of employee id: %d", id)
+		return reports, nil
+	}
+	if !errors.Is(err, common_errors.ErrResourceNotFound) {
+		s.logger.Warnf("failed to get reports from cache, cause: %s", err)
+	}
+
+	ids, err := s.repo.GetReportIDs(ctx, id, depth)
+	if err != nil {
+		return domain.OrgNode{}, err
+	}
+	employees, err := s.repo.GetEmployeesByIDs(ctx, append(ids, id))
+	if err != nil {
+		return domain.OrgNode{}, err
+	}
+	reports, ok := domain.BuildOrgChart(id, employees, time.Now())
+	if !ok {
+		return domain.OrgNode{}, common_errors.ErrResourceNotFound
+	}
+
+	if err := s.cache.SetReportsToCache(ctx, id, depth, &reports, 1*time.Hour); err != nil {
+		s.logger.Warnf("failed to update cache, cause: %s", err)
+	}
+
+	return reports, nil
+}
+
+func (s *employeeService) GetChain(ctx context.Context, id int) ([]domain.OrgNode, error) {
+	if _, err := auth.Authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	chain, err := s.cache.GetChain(ctx, id)
+	if err == nil {
+		s.logger.Infof("[Cache Hit] chain of employee id: %d", id)
+		return chain, nil
+	}
+	if !errors.Is(err, common_errors.ErrResourceNotFound) {
+		s.logger.Warnf("failed to get chain from cache, cause: %s", err)
+	}
+
+	managerIDs, err := s.repo.GetManagerChain(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	chain = make([]domain.OrgNode, 0, len(managerIDs))
+	if len(managerIDs) > 0 {
+		managers, err := s.repo.GetEmployeesByIDs(ctx, managerIDs)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[int]*domain.Employee, len(managers))
+		for i := range managers {
+			byID[managers[i].ID] = &managers[i]
+		}
+		now := time.Now()
+		for _, managerID := range managerIDs {
+			if manager, ok := byID[managerID]; ok {
+				chain = append(chain, domain.NewOrgNode(manager, now))
+			}
+		}
 	}
 
-	if err := s.cache.SetEmployeesToCache(ctx, page, pageSize, employees, totalCount, 1*time.Hour); err != nil {
+	if err := s.cache.SetChainToCache(ctx, id, chain, 1*time.Hour); err != nil {
 		s.logger.Warnf("failed to update cache, cause: %s", err)
 	}
 
-	return employees, totalCount, nil
+	return chain, nil
 }
@@ -8,7 +8,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"hr-system/internal/auth"
 	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
 	cache_mocks "hr-system/internal/employees/cache/mocks"
 	"hr-system/internal/employees/domain"
 	repo_mocks "hr-system/internal/employees/repo/mocks"
@@ -21,6 +23,10 @@ func newMockRepoAndCache(t *testing.T) (*repo_mocks.EmployeeRepo, *cache_mocks.E
 	return mockRepo, mockCache
 }
 
+func actorCtx(employeeID int, roles ...auth.Role) context.Context {
+	return auth.WithActor(context.Background(), auth.Actor{EmployeeID: employeeID, Roles: roles})
+}
+
 func genFakeEmployee() domain.Employee {
 	startDate := time.Now()
 	managerID := 1
@@ -49,18 +55,57 @@ func TestCreateEmployee(t *testing.T) {
 	service := NewEmployeeService(logger, mockRepo, mockCache)
 
 	employee := genFakeEmployee()
+	manager := genFakeEmployee()
+	manager.ID = 1
+	manager.Positions[0].ManagerLevel = 5
+	mockRepo.On("GetManagerChain", mock.Anything, 1).Return([]int{}, nil)
+	mockRepo.On("GetEmployeeByID", mock.Anything, 1).Return(manager, nil)
 	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Employee")).Run(func(args mock.Arguments) {
 		emp := args.Get(1).(*domain.Employee)
 		emp.ID = 1 // Set the ID to match the expected employee
 	}).Return(nil)
 	mockCache.On("DeleteEmployeesListCache", mock.Anything).Return(nil)
+	mockCache.On("DeleteOrgChartCache", mock.Anything).Return(nil)
 	mockCache.On("SetEmployeeToCache", mock.Anything, &employee, 1*time.Hour).Return(nil)
 
-	result, err := service.CreateEmployee(context.Background(), &employee)
+	result, err := service.CreateEmployee(actorCtx(1, auth.RoleHRAdmin), &employee)
 	assert.NoError(t, err)
 	assert.Equal(t, employee, result)
 }
 
+func TestCreateEmployee_Hierarchy(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	manager := genFakeEmployee()
+	manager.ID = 1
+	manager.Positions[0].ManagerLevel = 1
+
+	// the manager is at a lower manager level than the employee
+	employee := genFakeEmployee()
+	mockRepo.On("GetManagerChain", ctx, 1).Return([]int{}, nil).Once()
+	mockRepo.On("GetEmployeeByID", ctx, 1).Return(manager, nil).Once()
+	_, err := service.CreateEmployee(ctx, &employee)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// the manager must exist
+	employee.ManagerID = common.GetPtr(7)
+	mockRepo.On("GetManagerChain", ctx, 7).Return(nil, common_errors.ErrResourceNotFound).Once()
+	_, err = service.CreateEmployee(ctx, &employee)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// and stay
+	manager.Positions[0].ManagerLevel = 5
+	manager.TerminationDate = common.GetPtr(time.Now())
+	employee.ManagerID = common.GetPtr(1)
+	mockRepo.On("GetManagerChain", ctx, 1).Return([]int{}, nil).Once()
+	mockRepo.On("GetEmployeeByID", ctx, 1).Return(manager, nil).Once()
+	_, err = service.CreateEmployee(ctx, &employee)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
+
 func TestGetEmployeeByID(t *testing.T) {
 	mockRepo, mockCache := newMockRepoAndCache(t)
 	logger := common.NewLogger()
@@ -70,7 +115,7 @@ func TestGetEmployeeByID(t *testing.T) {
 	employee.ID = 1
 	mockCache.On("GetEmployeeByID", mock.Anything, employee.ID).Return(employee, nil)
 
-	result, err := service.GetEmployeeByID(context.Background(), employee.ID)
+	result, err := service.GetEmployeeByID(actorCtx(2, auth.RolePayroll), employee.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, employee, result)
 }
@@ -84,12 +129,352 @@ func TestGetEmployees(t *testing.T) {
 		genFakeEmployee(),
 	}
 	employees[0].ID = 1
-	totalCount := 1
+	page := domain.EmployeesPage{Employees: employees, TotalCount: common.GetPtr(1)}
+
+	query := domain.EmployeesQuery{Page: 1, PageSize: 10}
+	mockCache.On("GetEmployees", mock.Anything,
+		domain.EmployeesQuery{Page: 1, PageSize: 10, Status: domain.EmployeeStatusActive}).
+		Return(page, nil)
+
+	result, err := service.GetEmployees(actorCtx(2, auth.RoleHRAdmin), query)
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+
+	// the pages are at most 100 employees
+	_, err = service.GetEmployees(actorCtx(2, auth.RoleHRAdmin), domain.EmployeesQuery{Page: 1, PageSize: 101})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
+
+func TestGetEmployees_Cursor(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	ctx := actorCtx(2, auth.RoleHRAdmin)
+	query := domain.EmployeesQuery{Page: 1, PageSize: 10, Status: domain.EmployeeStatusActive, SkipCount: true,
+		Sort:  []domain.SortField{{Field: "name", Desc: true}},
+		After: &domain.EmployeeCursor{Sort: "-name", ID: 3, Name: "John Doe"}}
+	page := domain.EmployeesPage{Employees: []domain.Employee{genFakeEmployee()}}
+	mockCache.On("GetEmployees", ctx, query).Return(domain.EmployeesPage{}, common_errors.ErrResourceNotFound).Once()
+	mockRepo.On("GetEmployees", ctx, query).Return(page, nil).Once()
+	mockCache.On("SetEmployeesToCache", ctx, query, &page, time.Hour).Return(nil).Once()
+
+	result, err := service.GetEmployees(ctx, query)
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+
+	// the cursor only continues the sort it was made for
+	query.Sort = nil
+	_, err = service.GetEmployees(ctx, query)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
+
+func TestCreateEmployee_Forbidden(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	employee := genFakeEmployee()
+	_, err := service.CreateEmployee(actorCtx(1, auth.RoleManager), &employee)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestGetEmployeeByID_Scoped(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	employee := genFakeEmployee()
+	employee.ID = 3
+	mockCache.On("GetEmployeeByID", mock.Anything, employee.ID).Return(employee, nil)
+	mockRepo.On("GetManagerChain", mock.Anything, employee.ID).Return([]int{1}, nil)
+
+	// the manager above sees the employee without the salary
+	result, err := service.GetEmployeeByID(actorCtx(1, auth.RoleManager), employee.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, employee.Name, result.Name)
+	assert.Zero(t, result.Positions[0].MonthSalary)
+	assert.NotZero(t, employee.Positions[0].MonthSalary)
+
+	// so do the employee themselves
+	result, err = service.GetEmployeeByID(actorCtx(3, auth.RoleEmployee), employee.ID)
+	assert.NoError(t, err)
+	assert.Zero(t, result.Positions[0].MonthSalary)
+
+	// the others can not see the employee
+	_, err = service.GetEmployeeByID(actorCtx(2, auth.RoleManager), employee.ID)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+	_, err = service.GetEmployeeByID(actorCtx(4, auth.RoleEmployee), employee.ID)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestGetEmployees_Subordinates(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	employee := genFakeEmployee()
+	employee.ID = 3
+	mockRepo.On("GetSubordinateIDs", mock.Anything, 1).Return([]int{3, 4, 5}, nil)
+
+	// the list is restricted to the subtree
+	query := domain.EmployeesQuery{Page: 1, PageSize: 1, Search: " Doe"}
+	mockRepo.On("GetEmployees", mock.Anything, domain.EmployeesQuery{Page: 1, PageSize: 1, Search: "doe",
+		Status: domain.EmployeeStatusActive, IDs: []int{3, 4, 5}}).
+		Return(domain.EmployeesPage{Employees: []domain.Employee{employee}, TotalCount: common.GetPtr(2)}, nil)
+	result, err := service.GetEmployees(actorCtx(1, auth.RoleManager), query)
+	assert.NoError(t, err)
+	assert.Equal(t, common.GetPtr(2), result.TotalCount)
+	assert.Len(t, result.Employees, 1)
+	assert.Equal(t, 3, result.Employees[0].ID)
+	assert.Zero(t, result.Employees[0].Positions[0].MonthSalary)
+
+	// only the allowed fields are sortable
+	query.Sort = []domain.SortField{{Field: "month_salary"}}
+	_, err = service.GetEmployees(actorCtx(1, auth.RoleManager), query)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// an employee does not list the others
+	query.Sort = nil
+	_, err = service.GetEmployees(actorCtx(3, auth.RoleEmployee), query)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestPatchEmployee(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	employee := genFakeEmployee()
+	employee.ID = 3
+	employee.Version = 2
+	mockRepo.On("GetEmployeeByID", mock.Anything, employee.ID).Return(employee, nil)
+
+	// the employee changes their own phone number
+	ctx := actorCtx(3, auth.RoleEmployee)
+	phoneNumber := "098-765-4321"
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(e *domain.Employee) bool {
+		return e.PhoneNumber == phoneNumber && e.Version == 2
+	})).Return(nil).Once()
+	mockCache.On("DeleteEmployeeCache", ctx, employee.ID).Return(nil).Once()
+	mockCache.On("DeleteEmployeesListCache", ctx).Return(nil).Once()
+	mockCache.On("DeleteOrgChartCache", ctx).Return(nil).Once()
+	mockCache.On("GetEmployeeByID", ctx, employee.ID).Return(employee, nil).Once()
+	_, err := service.PatchEmployee(ctx, employee.ID, 2, &domain.EmployeePatch{PhoneNumber: &phoneNumber})
+	assert.NoError(t, err)
+
+	// but not their manager
+	_, err = service.PatchEmployee(ctx, employee.ID, 2, &domain.EmployeePatch{RemoveManager: true})
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+
+	// nor the email in a wrong format
+	ctx = actorCtx(9, auth.RoleHRAdmin)
+	email := "not an email"
+	_, err = service.PatchEmployee(ctx, employee.ID, 2, &domain.EmployeePatch{Email: &email})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
+
+func TestUpdateEmployee_ManagerCycle(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	employee := genFakeEmployee()
+	employee.ID = 1
+	employee.ManagerID = nil
+	mockRepo.On("GetEmployeeByID", ctx, employee.ID).Return(employee, nil)
+
+	// 3 reports to 2 who reports to 1
+	update := employee
+	update.ManagerID = common.GetPtr(3)
+	mockRepo.On("GetManagerChain", ctx, 3).Return([]int{2, 1}, nil).Once()
+	_, err := service.UpdateEmployee(ctx, &update)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	update.ManagerID = common.GetPtr(1)
+	_, err = service.UpdateEmployee(ctx, &update)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// the manager must exist
+	update.ManagerID = common.GetPtr(7)
+	mockRepo.On("GetManagerChain", ctx, 7).Return(nil, common_errors.ErrResourceNotFound).Once()
+	_, err = service.UpdateEmployee(ctx, &update)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
+
+func TestChangePosition(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	employee := genFakeEmployee()
+	employee.ID = 3
+	position := domain.Position{Title: "Lead", ManagerLevel: 3, StartDate: time.Now().AddDate(0, 1, 0)}
+	manager := genFakeEmployee()
+	manager.ID = 1
+	manager.Positions[0].ManagerLevel = 5
+	report := genFakeEmployee()
+	report.ID = 4
+
+	mockRepo.On("GetEmployeeByID", ctx, employee.ID).Return(employee, nil).Twice()
+	mockRepo.On("GetEmployeeByID", ctx, manager.ID).Return(manager, nil).Twice()
+	mockRepo.On("GetReportIDs", ctx, employee.ID, 1).Return([]int{report.ID}, nil).Twice()
+	mockRepo.On("GetEmployeesByIDs", ctx, []int{report.ID}).Return([]domain.Employee{report}, nil).Twice()
+	mockRepo.On("ChangePosition", ctx, employee.ID, &position).Return(nil).Once()
+	mockCache.On("DeleteEmployeeCache", ctx, employee.ID).Return(nil).Once()
+	mockCache.On("DeleteEmployeesListCache", ctx).Return(nil).Once()
+	mockCache.On("DeleteOrgChartCache", ctx).Return(nil).Once()
+	mockCache.On("GetEmployeeByID", ctx, employee.ID).Return(employee, nil).Once()
+	_, err := service.ChangePosition(ctx, employee.ID, &position)
+	assert.NoError(t, err)
 
-	mockCache.On("GetEmployees", mock.Anything, 1, 10).Return(employees, totalCount, nil)
+	// nobody reports to a manager below them
+	_, err = service.ChangePosition(ctx, employee.ID,
+		&domain.Position{Title: "Engineer", ManagerLevel: 1, StartDate: time.Now().AddDate(0, 1, 0)})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
 
-	result, count, err := service.GetEmployees(context.Background(), 1, 10)
+	// the history is kept as it was
+	_, err = service.ChangePosition(ctx, employee.ID,
+		&domain.Position{Title: "Lead", StartDate: time.Now().AddDate(0, 0, -1)})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	_, err = service.ChangePosition(actorCtx(3, auth.RoleManager), employee.ID, &position)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestTerminateEmployee(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	employee := genFakeEmployee()
+	employee.ID = 3
+	successor := genFakeEmployee()
+	successor.ID = 5
+	terminationDate := time.Now().AddDate(0, 1, 0)
+
+	mockRepo.On("GetEmployeeByID", ctx, successor.ID).Return(successor, nil).Once()
+	mockRepo.On("GetManagerChain", ctx, successor.ID).Return([]int{successor.ID, 1}, nil).Once()
+	mockRepo.On("GetReportIDs", ctx, employee.ID, 1).Return([]int{4}, nil).Once()
+	mockRepo.On("GetEmployeesByIDs", ctx, []int{4}).Return([]domain.Employee{{ID: 4, ManagerID: &employee.ID}}, nil).
+		Once()
+	mockRepo.On("Terminate", ctx, employee.ID, terminationDate).Return(nil).Once()
+	mockCache.On("DeleteEmployeeCache", ctx, employee.ID).Return(nil).Once()
+	mockCache.On("DeleteEmployeesListCache", ctx).Return(nil).Once()
+	mockCache.On("DeleteOrgChartCache", ctx).Return(nil).Once()
+	mockCache.On("GetEmployeeByID", ctx, employee.ID).Return(employee, nil).Once()
+	_, err := service.TerminateEmployee(ctx, employee.ID, terminationDate, &successor.ID)
 	assert.NoError(t, err)
-	assert.Equal(t, employees, result)
-	assert.Equal(t, totalCount, count)
+
+	// the direct reports need a successor
+	mockRepo.On("GetSubordinateIDs", ctx, employee.ID).Return([]int{4}, nil).Once()
+	_, err = service.TerminateEmployee(ctx, employee.ID, terminationDate, nil)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// a successor below a direct report would make a reporting cycle
+	mockRepo.On("GetEmployeeByID", ctx, successor.ID).Return(successor, nil).Once()
+	mockRepo.On("GetManagerChain", ctx, successor.ID).Return([]int{successor.ID, 4, employee.ID}, nil).Once()
+	_, err = service.TerminateEmployee(ctx, employee.ID, terminationDate, &successor.ID)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	_, err = service.TerminateEmployee(actorCtx(3, auth.RoleManager), employee.ID, terminationDate, nil)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestHandOverReports(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	employee := genFakeEmployee()
+	employee.ID = 3
+	successor := genFakeEmployee()
+	successor.ID = 5
+
+	mockRepo.On("GetEmployeeByID", ctx, successor.ID).Return(successor, nil).Once()
+	mockRepo.On("GetManagerChain", ctx, successor.ID).Return([]int{successor.ID, 1}, nil).Once()
+	mockRepo.On("GetReportIDs", ctx, employee.ID, 1).Return([]int{4}, nil).Once()
+	mockRepo.On("GetEmployeesByIDs", ctx, []int{4}).Return([]domain.Employee{{ID: 4, ManagerID: &employee.ID}}, nil).
+		Once()
+	mockRepo.On("HandOverReports", ctx, employee.ID, &successor.ID).Return([]int{4}, nil).Once()
+	mockCache.On("DeleteEmployeeCache", ctx, 4).Return(nil).Once()
+	mockCache.On("DeleteEmployeesListCache", ctx).Return(nil).Once()
+	mockCache.On("DeleteOrgChartCache", ctx).Return(nil).Once()
+	moved, err := service.HandOverReports(ctx, employee.ID, &successor.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, moved)
+
+	// a report joined after the employee was terminated
+	mockRepo.On("GetSubordinateIDs", ctx, employee.ID).Return([]int{4}, nil).Once()
+	_, err = service.HandOverReports(ctx, employee.ID, nil)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	_, err = service.HandOverReports(actorCtx(3, auth.RoleManager), employee.ID, nil)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestGetReports(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	employees := []domain.Employee{
+		{ID: 1, Name: "Boss"},
+		{ID: 2, Name: "John Doe", ManagerID: common.GetPtr(1)},
+	}
+	expected := domain.OrgNode{ID: 1, Name: "Boss",
+		Reports: []domain.OrgNode{{ID: 2, Name: "John Doe", ManagerID: common.GetPtr(1)}}}
+
+	mockCache.On("GetReports", ctx, 1, 2).Return(domain.OrgNode{}, common_errors.ErrResourceNotFound).Once()
+	mockRepo.On("GetReportIDs", ctx, 1, 2).Return([]int{2}, nil).Once()
+	mockRepo.On("GetEmployeesByIDs", ctx, []int{2, 1}).Return(employees, nil).Once()
+	mockCache.On("SetReportsToCache", ctx, 1, 2, &expected, time.Hour).Return(nil).Once()
+	reports, err := service.GetReports(ctx, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, reports)
+
+	// cache hit
+	mockCache.On("GetReports", ctx, 1, 2).Return(expected, nil).Once()
+	reports, err = service.GetReports(ctx, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, reports)
+
+	mockCache.On("GetReports", ctx, 100, 0).Return(domain.OrgNode{}, common_errors.ErrResourceNotFound).Once()
+	mockRepo.On("GetReportIDs", ctx, 100, 0).Return(nil, nil).Once()
+	mockRepo.On("GetEmployeesByIDs", ctx, []int{100}).Return([]domain.Employee{}, nil).Once()
+	_, err = service.GetReports(ctx, 100, 0)
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+
+	_, err = service.GetReports(ctx, 1, -1)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
+
+func TestGetChain(t *testing.T) {
+	mockRepo, mockCache := newMockRepoAndCache(t)
+	logger := common.NewLogger()
+	service := NewEmployeeService(logger, mockRepo, mockCache)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	managers := []domain.Employee{
+		{ID: 1, Name: "Boss"},
+		{ID: 2, Name: "Manager", ManagerID: common.GetPtr(1)},
+	}
+
+	mockCache.On("GetChain", ctx, 3).Return(nil, common_errors.ErrResourceNotFound).Once()
+	mockRepo.On("GetManagerChain", ctx, 3).Return([]int{2, 1}, nil).Once()
+	mockRepo.On("GetEmployeesByIDs", ctx, []int{2, 1}).Return(managers, nil).Once()
+	mockCache.On("SetChainToCache", ctx, 3, mock.Anything, time.Hour).Return(nil).Once()
+	chain, err := service.GetChain(ctx, 3)
+	assert.NoError(t, err)
+	// the nearest first
+	assert.Equal(t, []domain.OrgNode{{ID: 2, Name: "Manager", ManagerID: common.GetPtr(1)}, {ID: 1, Name: "Boss"}},
+		chain)
+
+	_, err = service.GetChain(context.Background(), 3)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
 }
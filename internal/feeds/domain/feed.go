@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FeedToken lets calendar clients subscribe to the leave feeds of an employee without other credentials,
+// an employee has at most one token and only its SHA-256 is stored
+type FeedToken struct {
+	EmployeeID int       `json:"employee_id" gorm:"primaryKey;autoIncrement:false"`
+	TokenHash  string    `json:"-" gorm:"type:char(64);not null;uniqueIndex"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// IssuedFeedToken is a new token, it can not be read again once issued
+type IssuedFeedToken struct {
+	EmployeeID int       `json:"employee_id"`
+	Token      string    `json:"token"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Event is an all-day event of a calendar
+type Event struct {
+	UID         string // stays the same across the updates of the event
+	Summary     string
+	Description string
+	StartDate   time.Time
+	EndDate     time.Time // inclusive
+	Cancelled   bool
+	// Sequence is increased by every change clients have to apply over the version they have
+	Sequence int
+	Stamp    time.Time
+}
+
+// Calendar is an iCalendar (RFC 5545) feed
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// escapeText escapes a TEXT value
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldLine splits the content line into lines of at most 75 octets, the continuations start with a space
+func foldLine(line string) string {
+	const limit = 75
+	var b strings.Builder
+	width := limit
+	for len(line) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		width = limit - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// Render formats the calendar, the lines end with CRLF
+func (c Calendar) Render() string {
+	var b strings.Builder
+	write := func(format string, a ...any) {
+		b.WriteString(foldLine(fmt.Sprintf(format, a...)))
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:-//hr-system//leaves//EN")
+	write("CALSCALE:GREGORIAN")
+	write("METHOD:PUBLISH")
+	write("X-WR-CALNAME:%s", escapeText(c.Name))
+	for _, event := range c.Events {
+		status := "CONFIRMED"
+		if event.Cancelled {
+			status = "CANCELLED"
+		}
+		write("BEGIN:VEVENT")
+		write("UID:%s", event.UID)
+		write("DTSTAMP:%s", event.Stamp.UTC().Format("20060102T150405Z"))
+		write("DTSTART;VALUE=DATE:%s", event.StartDate.Format("20060102"))
+		// the end of an all-day event is exclusive
+		write("DTEND;VALUE=DATE:%s", event.EndDate.AddDate(0, 0, 1).Format("20060102"))
+		write("SUMMARY:%s", escapeText(event.Summary))
+		if event.Description != "" {
+			write("DESCRIPTION:%s", escapeText(event.Description))
+		}
+		write("STATUS:%s", status)
+		write("SEQUENCE:%d", event.Sequence)
+		write("END:VEVENT")
+	}
+	write("END:VCALENDAR")
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarRender(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 5, d, 0, 0, 0, 0, time.Local)
+	}
+	calendar := Calendar{Name: "Leaves of Jane", Events: []Event{
+		{UID: "leave-1@hr-system", Summary: "Annual leave", Description: "Trip; Tokyo, Kyoto\nback on Monday",
+			StartDate: day(1), EndDate: day(3), Stamp: time.Date(2024, 4, 2, 8, 30, 0, 0, time.UTC)},
+		{UID: "leave-2@hr-system", Summary: "Sick leave", StartDate: day(6), EndDate: day(6), Cancelled: true,
+			Sequence: 1, Stamp: time.Date(2024, 4, 3, 9, 0, 0, 0, time.UTC)},
+	}}
+
+	assert.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//hr-system//leaves//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Leaves of Jane",
+		"BEGIN:VEVENT",
+		"UID:leave-1@hr-system",
+		"DTSTAMP:20240402T083000Z",
+		"DTSTART;VALUE=DATE:20240501",
+		"DTEND;VALUE=DATE:20240504",
+		"SUMMARY:Annual leave",
+		`DESCRIPTION:Trip\; Tokyo\, Kyoto\nback on Monday`,
+		"STATUS:CONFIRMED",
+		"SEQUENCE:0",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:leave-2@hr-system",
+		"DTSTAMP:20240403T090000Z",
+		"DTSTART;VALUE=DATE:20240506",
+		"DTEND;VALUE=DATE:20240507",
+		"SUMMARY:Sick leave",
+		"STATUS:CANCELLED",
+		"SEQUENCE:1",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), calendar.Render())
+}
+
+func TestFoldLine(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("a", 66) + "é" + strings.Repeat("b", 80)
+	folded := foldLine(line)
+
+	lines := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n ")
+	for _, l := range lines {
+		assert.LessOrEqual(t, len(l), 75)
+	}
+	// a character is never split
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("a", 66), lines[0])
+	assert.Equal(t, line, strings.Join(lines, ""))
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/common"
+	"hr-system/internal/feeds/domain"
+	"hr-system/internal/feeds/service"
+	"hr-system/internal/middleware"
+)
+
+type FeedHandler struct {
+	feedService service.FeedService
+	logger      *common.Logger
+}
+
+func NewFeedHandler(logger *common.Logger, feedService service.FeedService) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+		logger:      logger,
+	}
+}
+
+// FeedTokenResponse is the new token with the paths of the feeds it opens
+type FeedTokenResponse struct {
+	domain.IssuedFeedToken
+	EmployeeFeed string `json:"employee_feed"`
+	// TeamFeed is the pattern of the team feeds, {manager_id} is the manager of the team
+	TeamFeed string `json:"team_feed"`
+}
+
+func (h *FeedHandler) IssueToken(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid employee ID"))
+		return
+	}
+
+	token, err := h.feedService.IssueToken(ctx, employeeID)
+	if err != nil {
+		middleware.HandleErr(c, err, "issue feed token")
+		return
+	}
+
+	c.JSON(http.StatusCreated, FeedTokenResponse{
+		IssuedFeedToken: token,
+		EmployeeFeed:    "/api/v1/feeds/" + token.Token + "/leaves.ics",
+		TeamFeed:        "/api/v1/feeds/" + token.Token + "/teams/{manager_id}.ics",
+	})
+}
+
+func (h *FeedHandler) RevokeToken(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid employee ID"))
+		return
+	}
+
+	if err := h.feedService.RevokeToken(ctx, employeeID); err != nil {
+		middleware.HandleErr(c, err, "revoke feed token")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *FeedHandler) writeCalendar(c *gin.Context, calendar domain.Calendar) {
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar.Render()))
+}
+
+// GetEmployeeFeed is authenticated by the token in the path alone, calendar clients have no other credentials
+func (h *FeedHandler) GetEmployeeFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	calendar, err := h.feedService.GetEmployeeFeed(ctx, c.Param("token"))
+	if err != nil {
+		middleware.HandleErr(c, err, "get feed")
+		return
+	}
+
+	h.writeCalendar(c, calendar)
+}
+
+// GetTeamFeed is authenticated by the token in the path alone, calendar clients have no other credentials
+func (h *FeedHandler) GetTeamFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	managerID, err := strconv.Atoi(strings.TrimSuffix(c.Param("manager_id"), ".ics"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid manager ID"))
+		return
+	}
+
+	calendar, err := h.feedService.GetTeamFeed(ctx, c.Param("token"), managerID)
+	if err != nil {
+		middleware.HandleErr(c, err, "get feed")
+		return
+	}
+
+	h.writeCalendar(c, calendar)
+}
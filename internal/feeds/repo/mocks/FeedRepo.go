@@ -0,0 +1,93 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/feeds/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FeedRepo is an autogenerated mock type for the FeedRepo type
+type FeedRepo struct {
+	mock.Mock
+}
+
+// DeleteToken provides a mock function with given fields: ctx, employeeID
+func (_m *FeedRepo) DeleteToken(ctx context.Context, employeeID int) error {
+	ret := _m.Called(ctx, employeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, employeeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetTokenByHash provides a mock function with given fields: ctx, hash
+func (_m *FeedRepo) GetTokenByHash(ctx context.Context, hash string) (domain.FeedToken, error) {
+	ret := _m.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTokenByHash")
+	}
+
+	var r0 domain.FeedToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.FeedToken, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.FeedToken); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		r0 = ret.Get(0).(domain.FeedToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveToken provides a mock function with given fields: ctx, token
+func (_m *FeedRepo) SaveToken(ctx context.Context, token *domain.FeedToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.FeedToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFeedRepo creates a new instance of FeedRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFeedRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FeedRepo {
+	mock := &FeedRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
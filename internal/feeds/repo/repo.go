@@ -0,0 +1,77 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/feeds/domain"
+)
+
+type FeedRepo interface {
+	// SaveToken creates the token of the employee or replaces the one they have
+	SaveToken(ctx context.Context, token *domain.FeedToken) error
+	GetTokenByHash(ctx context.Context, hash string) (domain.FeedToken, error)
+	DeleteToken(ctx context.Context, employeeID int) error
+}
+
+type feedRepo struct {
+	db *gorm.DB
+}
+
+func NewFeedRepo(db *gorm.DB) (FeedRepo, error) {
+	repo := &feedRepo{
+		db: db,
+	}
+
+	if err := repo.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *feedRepo) ensureSchema() error {
+	// AutoMigrate
+	if err := r.db.AutoMigrate(domain.FeedToken{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *feedRepo) SaveToken(ctx context.Context, token *domain.FeedToken) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "employee_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "created_at"}),
+	}).Create(token).Error
+	if err != nil {
+		return fmt.Errorf("failed to save feed token: %w", err)
+	}
+	return nil
+}
+
+func (r *feedRepo) GetTokenByHash(ctx context.Context, hash string) (domain.FeedToken, error) {
+	var token domain.FeedToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.FeedToken{}, common_errors.ErrResourceNotFound
+		}
+		return domain.FeedToken{}, fmt.Errorf("failed to find feed token: %w", err)
+	}
+	return token, nil
+}
+
+func (r *feedRepo) DeleteToken(ctx context.Context, employeeID int) error {
+	result := r.db.WithContext(ctx).Delete(&domain.FeedToken{}, employeeID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete feed token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return common_errors.ErrResourceNotFound
+	}
+	return nil
+}
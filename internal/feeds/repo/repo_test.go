@@ -0,0 +1,42 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/feeds/domain"
+)
+
+func setupTestRepo(t *testing.T) *feedRepo {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := &feedRepo{db: db}
+	assert.NoError(t, repo.ensureSchema())
+	return repo
+}
+
+func TestFeedTokens(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.SaveToken(ctx, &domain.FeedToken{EmployeeID: 1, TokenHash: "old", CreatedAt: time.Now()}))
+	// a new token replaces the old one
+	assert.NoError(t, repo.SaveToken(ctx, &domain.FeedToken{EmployeeID: 1, TokenHash: "new", CreatedAt: time.Now()}))
+	_, err := repo.GetTokenByHash(ctx, "old")
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+	token, err := repo.GetTokenByHash(ctx, "new")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, token.EmployeeID)
+
+	assert.NoError(t, repo.DeleteToken(ctx, 1))
+	_, err = repo.GetTokenByHash(ctx, "new")
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+	assert.ErrorIs(t, repo.DeleteToken(ctx, 1), common_errors.ErrResourceNotFound)
+}
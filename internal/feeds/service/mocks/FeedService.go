@@ -0,0 +1,131 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/feeds/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FeedService is an autogenerated mock type for the FeedService type
+type FeedService struct {
+	mock.Mock
+}
+
+// GetEmployeeFeed provides a mock function with given fields: ctx, token
+func (_m *FeedService) GetEmployeeFeed(ctx context.Context, token string) (domain.Calendar, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEmployeeFeed")
+	}
+
+	var r0 domain.Calendar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Calendar, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Calendar); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Get(0).(domain.Calendar)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeamFeed provides a mock function with given fields: ctx, token, managerID
+func (_m *FeedService) GetTeamFeed(ctx context.Context, token string, managerID int) (domain.Calendar, error) {
+	ret := _m.Called(ctx, token, managerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeamFeed")
+	}
+
+	var r0 domain.Calendar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (domain.Calendar, error)); ok {
+		return rf(ctx, token, managerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) domain.Calendar); ok {
+		r0 = rf(ctx, token, managerID)
+	} else {
+		r0 = ret.Get(0).(domain.Calendar)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, token, managerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueToken provides a mock function with given fields: ctx, employeeID
+func (_m *FeedService) IssueToken(ctx context.Context, employeeID int) (domain.IssuedFeedToken, error) {
+	ret := _m.Called(ctx, employeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueToken")
+	}
+
+	var r0 domain.IssuedFeedToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (domain.IssuedFeedToken, error)); ok {
+		return rf(ctx, employeeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) domain.IssuedFeedToken); ok {
+		r0 = rf(ctx, employeeID)
+	} else {
+		r0 = ret.Get(0).(domain.IssuedFeedToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, employeeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeToken provides a mock function with given fields: ctx, employeeID
+func (_m *FeedService) RevokeToken(ctx context.Context, employeeID int) error {
+	ret := _m.Called(ctx, employeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, employeeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFeedService creates a new instance of FeedService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFeedService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FeedService {
+	mock := &FeedService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
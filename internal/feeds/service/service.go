@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	employee_domain "hr-system/internal/employees/domain"
+	employee_repo "hr-system/internal/employees/repo"
+	"hr-system/internal/feeds/domain"
+	"hr-system/internal/feeds/repo"
+	leave_domain "hr-system/internal/leaves/domain"
+	leave_repo "hr-system/internal/leaves/repo"
+	review_domain "hr-system/internal/reviews/domain"
+)
+
+// historyDays is how far back the feeds go, the leaves ended before are left out
+const historyDays = 90
+
+type FeedService interface {
+	// IssueToken gives the employee a new feed token, the one they had stops working
+	IssueToken(ctx context.Context, employeeID int) (domain.IssuedFeedToken, error)
+	RevokeToken(ctx context.Context, employeeID int) error
+	// GetEmployeeFeed returns the leaves of the employee of the token
+	GetEmployeeFeed(ctx context.Context, token string) (domain.Calendar, error)
+	// GetTeamFeed returns the leaves of the employees reporting to the manager directly or indirectly,
+	// the employee of the token must be the manager or one of the managers above them
+	GetTeamFeed(ctx context.Context, token string, managerID int) (domain.Calendar, error)
+}
+
+type feedService struct {
+	feedRepo     repo.FeedRepo
+	leaveRepo    leave_repo.LeaveRepo
+	employeeRepo employee_repo.EmployeeRepo
+	logger       *common.Logger
+}
+
+func NewFeedService(logger *common.Logger, feedRepo repo.FeedRepo, leaveRepo leave_repo.LeaveRepo,
+	employeeRepo employee_repo.EmployeeRepo) FeedService {
+	return &feedService{
+		feedRepo:     feedRepo,
+		leaveRepo:    leaveRepo,
+		employeeRepo: employeeRepo,
+		logger:       logger,
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *feedService) IssueToken(ctx context.Context, employeeID int) (domain.IssuedFeedToken, error) {
+	// the token reads the calendars of the caller, nobody issues it for someone else
+	if _, err := auth.AuthorizeSelf(ctx, employeeID); err != nil {
+		return domain.IssuedFeedToken{}, err
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return domain.IssuedFeedToken{}, fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(random)
+	feedToken := domain.FeedToken{EmployeeID: employeeID, TokenHash: hashToken(token), CreatedAt: time.Now()}
+	if err := s.feedRepo.SaveToken(ctx, &feedToken); err != nil {
+		return domain.IssuedFeedToken{}, fmt.Errorf("failed to save feed token: %w", err)
+	}
+
+	return domain.IssuedFeedToken{EmployeeID: employeeID, Token: token, CreatedAt: feedToken.CreatedAt}, nil
+}
+
+func (s *feedService) RevokeToken(ctx context.Context, employeeID int) error {
+	if _, err := auth.AuthorizeSelf(ctx, employeeID, auth.RoleHRAdmin); err != nil {
+		return err
+	}
+	if err := s.feedRepo.DeleteToken(ctx, employeeID); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return common_errors.ErrResourceNotFound
+		}
+		return fmt.Errorf("failed to delete feed token: %w", err)
+	}
+	return nil
+}
+
+// getOwner returns the employee of the token, an unknown token and the token of someone who has left are not found
+func (s *feedService) getOwner(ctx context.Context, token string) (employee_domain.Employee, error) {
+	feedToken, err := s.feedRepo.GetTokenByHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return employee_domain.Employee{}, fmt.Errorf("%w, unknown feed token", common_errors.ErrResourceNotFound)
+		}
+		return employee_domain.Employee{}, fmt.Errorf("failed to get feed token: %w", err)
+	}
+	employee, err := s.employeeRepo.GetEmployeeByID(ctx, feedToken.EmployeeID)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return employee_domain.Employee{}, fmt.Errorf("%w, unknown feed token", common_errors.ErrResourceNotFound)
+		}
+		return employee_domain.Employee{}, fmt.Errorf("failed to get employee: %w", err)
+	}
+	if employee.TerminatedAt(time.Now()) {
+		return employee_domain.Employee{}, fmt.Errorf("%w, unknown feed token", common_errors.ErrResourceNotFound)
+	}
+	return employee, nil
+}
+
+func (s *feedService) GetEmployeeFeed(ctx context.Context, token string) (domain.Calendar, error) {
+	owner, err := s.getOwner(ctx, token)
+	if err != nil {
+		return domain.Calendar{}, err
+	}
+
+	leaves, err := s.getLeaves(ctx, []int{owner.ID})
+	if err != nil {
+		return domain.Calendar{}, err
+	}
+	calendar := domain.Calendar{Name: "Leaves of " + owner.Name, Events: make([]domain.Event, 0, len(leaves))}
+	for _, leave := range leaves {
+		event := leaveEvent(&leave, leaveTypeName(leave.Type))
+		// the reason is only for the employee's own calendar
+		event.Description = leave.Reason
+		calendar.Events = append(calendar.Events, event)
+	}
+	return calendar, nil
+}
+
+func (s *feedService) GetTeamFeed(ctx context.Context, token string, managerID int) (domain.Calendar, error) {
+	owner, err := s.getOwner(ctx, token)
+	if err != nil {
+		return domain.Calendar{}, err
+	}
+	if owner.ID != managerID {
+		managerIDs, err := s.employeeRepo.GetManagerChain(ctx, managerID)
+		if err != nil {
+			if errors.Is(err, common_errors.ErrResourceNotFound) {
+				return domain.Calendar{}, common_errors.ErrResourceNotFound
+			}
+			return domain.Calendar{}, fmt.Errorf("failed to get manager chain: %w", err)
+		}
+		allowed := false
+		for _, id := range managerIDs {
+			allowed = allowed || id == owner.ID
+		}
+		if !allowed {
+			return domain.Calendar{}, fmt.Errorf("%w, employee %d does not manage employee %d",
+				common_errors.ErrForbidden, owner.ID, managerID)
+		}
+	}
+
+	manager, err := s.employeeRepo.GetEmployeeByID(ctx, managerID)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Calendar{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Calendar{}, fmt.Errorf("failed to get manager: %w", err)
+	}
+	reportIDs, err := s.employeeRepo.GetSubordinateIDs(ctx, managerID)
+	if err != nil {
+		return domain.Calendar{}, fmt.Errorf("failed to get reports: %w", err)
+	}
+	calendar := domain.Calendar{Name: "Team of " + manager.Name, Events: []domain.Event{}}
+	if len(reportIDs) == 0 {
+		return calendar, nil
+	}
+
+	leaves, err := s.getLeaves(ctx, reportIDs)
+	if err != nil {
+		return domain.Calendar{}, err
+	}
+	reports, err := s.employeeRepo.GetEmployeesByIDs(ctx, reportIDs)
+	if err != nil {
+		return domain.Calendar{}, fmt.Errorf("failed to get reports: %w", err)
+	}
+	names := make(map[int]string, len(reports))
+	for _, report := range reports {
+		names[report.ID] = report.Name
+	}
+	for _, leave := range leaves {
+		summary := fmt.Sprintf("%s: %s", names[leave.EmployeeID], leaveTypeName(leave.Type))
+		calendar.Events = append(calendar.Events, leaveEvent(&leave, summary))
+	}
+	return calendar, nil
+}
+
+// getLeaves returns the leaves of the employees the feeds show, the approved ones and the ones withdrawn or revoked
+func (s *feedService) getLeaves(ctx context.Context, employeeIDs []int) ([]leave_domain.Leave, error) {
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -historyDays)
+	leaves, err := s.leaveRepo.GetLeaves(ctx, leave_domain.LeavesQuery{
+		EmployeeIDs: employeeIDs,
+		Statuses: []review_domain.ReviewStatus{
+			review_domain.ReviewStatusApproved,
+			review_domain.ReviewStatusRevoking,
+			review_domain.ReviewStatusRevoked,
+			review_domain.ReviewStatusCancelled,
+		},
+		From: &since,
+		Sort: []leave_domain.SortField{{Field: "start_date"}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaves: %w", err)
+	}
+	return leaves, nil
+}
+
+// leaveEvent turns the leave into an event, a leave stays approved while its revocation is reviewed
+func leaveEvent(leave *leave_domain.Leave, summary string) domain.Event {
+	if partOfDay := leave.PartOfDay(); partOfDay != "" {
+		summary += " (" + partOfDay + ")"
+	}
+	event := domain.Event{
+		UID:       fmt.Sprintf("leave-%d@hr-system", leave.ID),
+		Summary:   summary,
+		StartDate: leave.StartDate,
+		EndDate:   leave.EndDate,
+		Stamp:     leave.UpdatedAt,
+	}
+	if leave.Status == review_domain.ReviewStatusRevoked || leave.Status == review_domain.ReviewStatusCancelled {
+		// the cancellation replaces the event a client has
+		event.Cancelled = true
+		event.Sequence = 1
+	}
+	return event
+}
+
+func leaveTypeName(leaveType leave_domain.LeaveType) string {
+	name := string(leaveType)
+	if name == "" {
+		return "Leave"
+	}
+	return strings.ToUpper(name[:1]) + name[1:] + " leave"
+}
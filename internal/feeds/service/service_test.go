@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	employee_domain "hr-system/internal/employees/domain"
+	mocks_employee_repo "hr-system/internal/employees/repo/mocks"
+	"hr-system/internal/feeds/domain"
+	mocks_feed_repo "hr-system/internal/feeds/repo/mocks"
+	leave_domain "hr-system/internal/leaves/domain"
+	mocks_leave_repo "hr-system/internal/leaves/repo/mocks"
+	review_domain "hr-system/internal/reviews/domain"
+)
+
+func TestIssueToken(t *testing.T) {
+	mockFeedRepo := mocks_feed_repo.NewFeedRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	logger := common.NewLogger()
+
+	service := NewFeedService(logger, mockFeedRepo, mockLeaveRepo, mockEmployeeRepo)
+
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 3, Roles: []auth.Role{auth.RoleEmployee}})
+
+	var saved domain.FeedToken
+	mockFeedRepo.On("SaveToken", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		saved = *args.Get(1).(*domain.FeedToken)
+	}).Return(nil).Once()
+	token, err := service.IssueToken(ctx, 3)
+	assert.NoError(t, err)
+	assert.Len(t, token.Token, 43)
+	// the token itself is not stored
+	assert.Equal(t, hashToken(token.Token), saved.TokenHash)
+	assert.NotEqual(t, token.Token, saved.TokenHash)
+
+	// nobody issues a token for someone else
+	_, err = service.IssueToken(ctx, 4)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestGetEmployeeFeed(t *testing.T) {
+	mockFeedRepo := mocks_feed_repo.NewFeedRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	logger := common.NewLogger()
+
+	service := NewFeedService(logger, mockFeedRepo, mockLeaveRepo, mockEmployeeRepo)
+
+	ctx := context.Background()
+
+	mockFeedRepo.On("GetTokenByHash", ctx, hashToken("token")).Return(domain.FeedToken{EmployeeID: 3}, nil).Once()
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 3).Return(employee_domain.Employee{ID: 3, Name: "Jane"}, nil).Once()
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)
+	mockLeaveRepo.On("GetLeaves", ctx, mock.MatchedBy(func(query leave_domain.LeavesQuery) bool {
+		return assert.ObjectsAreEqual([]int{3}, query.EmployeeIDs) && len(query.Statuses) == 4
+	})).Return([]leave_domain.Leave{
+		{ID: 1, EmployeeID: 3, Type: leave_domain.LeaveTypeAnnual, Status: review_domain.ReviewStatusApproved,
+			StartDate: day, EndDate: day, Reason: "trip"},
+		{ID: 2, EmployeeID: 3, Type: leave_domain.LeaveTypeSick, Status: review_domain.ReviewStatusRevoked,
+			StartDate: day, EndDate: day},
+	}, nil).Once()
+
+	calendar, err := service.GetEmployeeFeed(ctx, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, "Leaves of Jane", calendar.Name)
+	assert.Equal(t, []domain.Event{
+		{UID: "leave-1@hr-system", Summary: "Annual leave", Description: "trip", StartDate: day, EndDate: day},
+		{UID: "leave-2@hr-system", Summary: "Sick leave", StartDate: day, EndDate: day, Cancelled: true, Sequence: 1},
+	}, calendar.Events)
+
+	// a revoked token or the token of someone who has left opens nothing
+	mockFeedRepo.On("GetTokenByHash", ctx, hashToken("revoked")).
+		Return(domain.FeedToken{}, common_errors.ErrResourceNotFound).Once()
+	_, err = service.GetEmployeeFeed(ctx, "revoked")
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+
+	mockFeedRepo.On("GetTokenByHash", ctx, hashToken("left")).Return(domain.FeedToken{EmployeeID: 4}, nil).Once()
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 4).Return(employee_domain.Employee{ID: 4,
+		TerminationDate: common.GetPtr(day)}, nil).Once()
+	_, err = service.GetEmployeeFeed(ctx, "left")
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+}
+
+func TestGetTeamFeed(t *testing.T) {
+	mockFeedRepo := mocks_feed_repo.NewFeedRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	logger := common.NewLogger()
+
+	service := NewFeedService(logger, mockFeedRepo, mockLeaveRepo, mockEmployeeRepo)
+
+	ctx := context.Background()
+
+	mockFeedRepo.On("GetTokenByHash", ctx, hashToken("token")).Return(domain.FeedToken{EmployeeID: 1}, nil).Twice()
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(employee_domain.Employee{ID: 1, Name: "Boss"}, nil).Twice()
+
+	// the manager above sees the team
+	mockEmployeeRepo.On("GetManagerChain", ctx, 2).Return([]int{1}, nil).Once()
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 2).Return(employee_domain.Employee{ID: 2, Name: "John"}, nil).Once()
+	mockEmployeeRepo.On("GetSubordinateIDs", ctx, 2).Return([]int{3}, nil).Once()
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)
+	mockLeaveRepo.On("GetLeaves", ctx, mock.Anything).Return([]leave_domain.Leave{
+		{ID: 1, EmployeeID: 3, Type: leave_domain.LeaveTypeAnnual, Status: review_domain.ReviewStatusApproved,
+			StartDate: day, EndDate: day, Reason: "trip"},
+	}, nil).Once()
+	mockEmployeeRepo.On("GetEmployeesByIDs", ctx, []int{3}).
+		Return([]employee_domain.Employee{{ID: 3, Name: "Jane"}}, nil).Once()
+
+	calendar, err := service.GetTeamFeed(ctx, "token", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "Team of John", calendar.Name)
+	// the reason stays private to the employee
+	assert.Equal(t, []domain.Event{
+		{UID: "leave-1@hr-system", Summary: "Jane: Annual leave", StartDate: day, EndDate: day},
+	}, calendar.Events)
+
+	// but not the team of another manager
+	mockEmployeeRepo.On("GetManagerChain", ctx, 5).Return([]int{6}, nil).Once()
+	_, err = service.GetTeamFeed(ctx, "token", 5)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
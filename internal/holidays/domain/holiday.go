@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// DefaultRegion is the region of the employees without a region
+const DefaultRegion = "default"
+
+// DefaultWeekdays is the working week of the regions which haven't defined one
+var DefaultWeekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+type Holiday struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Region    string    `json:"region" gorm:"type:varchar(50);not null;uniqueIndex:idx_region_date" validate:"required"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_region_date" validate:"required"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null" validate:"required"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// WorkingWeek defines the weekdays people work in a region
+type WorkingWeek struct {
+	Region    string         `json:"region" gorm:"primaryKey;type:varchar(50)"`
+	Weekdays  []time.Weekday `json:"weekdays" gorm:"type:varchar(50);serializer:json" validate:"required,gt=0,dive,gte=0,lte=6"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+type HolidaysQuery struct {
+	Region *string
+	From   *time.Time
+	To     *time.Time
+}
+
+// civilDate drops the time and the location of t
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// WorkingDates returns the working days from start to end, both dates are included, without time and location
+func WorkingDates(start, end time.Time, week WorkingWeek, holidays []Holiday) []time.Time {
+	isWorkday := make(map[time.Weekday]bool, len(week.Weekdays))
+	for _, weekday := range week.Weekdays {
+		isWorkday[weekday] = true
+	}
+	isHoliday := make(map[time.Time]bool, len(holidays))
+	for _, holiday := range holidays {
+		isHoliday[civilDate(holiday.Date)] = true
+	}
+
+	var dates []time.Time
+	for d := civilDate(start); !d.After(civilDate(end)); d = d.AddDate(0, 0, 1) {
+		if isWorkday[d.Weekday()] && !isHoliday[d] {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// WorkingDaysBetween counts the working days from start to end, both dates are included
+func WorkingDaysBetween(start, end time.Time, week WorkingWeek, holidays []Holiday) int {
+	return len(WorkingDates(start, end, week, holidays))
+}
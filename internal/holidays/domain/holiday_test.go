@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkingDaysBetween(t *testing.T) {
+	week := WorkingWeek{Region: DefaultRegion, Weekdays: DefaultWeekdays}
+	// 2024-05-03 is a Friday
+	day := func(d int) time.Time {
+		return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		end      time.Time
+		holidays []Holiday
+		want     int
+	}{
+		{name: "single day", start: day(3), end: day(3), want: 1},
+		{name: "friday to monday", start: day(3), end: day(6), want: 2},
+		{name: "weekend only", start: day(4), end: day(5), want: 0},
+		{name: "with holiday", start: day(1), end: day(10), holidays: []Holiday{{Date: day(1)}}, want: 7},
+		{name: "end before start", start: day(6), end: day(3), want: 0},
+		{name: "time of day is ignored", start: day(3).Add(20 * time.Hour), end: day(6).Add(time.Hour), want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WorkingDaysBetween(tt.start, tt.end, week, tt.holidays))
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/common"
+	"hr-system/internal/holidays/domain"
+	"hr-system/internal/holidays/service"
+	"hr-system/internal/middleware"
+)
+
+type HolidayHandler struct {
+	holidayService service.HolidayService
+	logger         *common.Logger
+}
+
+func NewHolidayHandler(logger *common.Logger, holidayService service.HolidayService) *HolidayHandler {
+	return &HolidayHandler{
+		holidayService: holidayService,
+		logger:         logger,
+	}
+}
+
+type HolidayRequest struct {
+	Region string    `json:"region" binding:"required"`
+	Date   time.Time `json:"date" binding:"required"`
+	Name   string    `json:"name" binding:"required"`
+}
+
+func (h *HolidayHandler) CreateHoliday(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	holiday, err := h.holidayService.CreateHoliday(ctx, &domain.Holiday{
+		Region: req.Region,
+		Date:   req.Date,
+		Name:   req.Name,
+	})
+	if err != nil {
+		middleware.HandleErr(c, err, "create holiday")
+		return
+	}
+
+	c.JSON(http.StatusCreated, holiday)
+}
+
+func (h *HolidayHandler) GetHolidays(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query := domain.HolidaysQuery{}
+	if region := c.Query("region"); region != "" {
+		query.Region = &region
+	}
+	if year := c.Query("year"); year != "" {
+		y, err := strconv.Atoi(year)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid year"))
+			return
+		}
+		from := time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(y, time.December, 31, 0, 0, 0, 0, time.UTC)
+		query.From = &from
+		query.To = &to
+	}
+
+	holidays, err := h.holidayService.GetHolidays(ctx, query)
+	if err != nil {
+		middleware.HandleErr(c, err, "get holidays")
+		return
+	}
+
+	c.JSON(http.StatusOK, holidays)
+}
+
+func (h *HolidayHandler) GetHolidayByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid holiday ID"))
+		return
+	}
+
+	holiday, err := h.holidayService.GetHolidayByID(ctx, id)
+	if err != nil {
+		middleware.HandleErr(c, err, "get holiday")
+		return
+	}
+
+	c.JSON(http.StatusOK, holiday)
+}
+
+func (h *HolidayHandler) UpdateHoliday(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid holiday ID"))
+		return
+	}
+
+	var req HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	holiday, err := h.holidayService.UpdateHoliday(ctx, &domain.Holiday{
+		ID:     id,
+		Region: req.Region,
+		Date:   req.Date,
+		Name:   req.Name,
+	})
+	if err != nil {
+		middleware.HandleErr(c, err, "update holiday")
+		return
+	}
+
+	c.JSON(http.StatusOK, holiday)
+}
+
+func (h *HolidayHandler) DeleteHoliday(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid holiday ID"))
+		return
+	}
+
+	if err := h.holidayService.DeleteHoliday(ctx, id); err != nil {
+		middleware.HandleErr(c, err, "delete holiday")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *HolidayHandler) GetWorkingWeek(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	week, err := h.holidayService.GetWorkingWeek(ctx, c.Param("region"))
+	if err != nil {
+		middleware.HandleErr(c, err, "get working week")
+		return
+	}
+
+	c.JSON(http.StatusOK, week)
+}
+
+type WorkingWeekRequest struct {
+	Weekdays []time.Weekday `json:"weekdays" binding:"required"`
+}
+
+func (h *HolidayHandler) SetWorkingWeek(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req WorkingWeekRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	week, err := h.holidayService.SetWorkingWeek(ctx, &domain.WorkingWeek{
+		Region:   c.Param("region"),
+		Weekdays: req.Weekdays,
+	})
+	if err != nil {
+		middleware.HandleErr(c, err, "set working week")
+		return
+	}
+
+	c.JSON(http.StatusOK, week)
+}
@@ -0,0 +1,187 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/holidays/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HolidayRepo is an autogenerated mock type for the HolidayRepo type
+type HolidayRepo struct {
+	mock.Mock
+}
+
+// CreateHoliday provides a mock function with given fields: ctx, holiday
+func (_m *HolidayRepo) CreateHoliday(ctx context.Context, holiday *domain.Holiday) error {
+	ret := _m.Called(ctx, holiday)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHoliday")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Holiday) error); ok {
+		r0 = rf(ctx, holiday)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteHoliday provides a mock function with given fields: ctx, id
+func (_m *HolidayRepo) DeleteHoliday(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteHoliday")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetHolidayByID provides a mock function with given fields: ctx, id
+func (_m *HolidayRepo) GetHolidayByID(ctx context.Context, id int) (domain.Holiday, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHolidayByID")
+	}
+
+	var r0 domain.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (domain.Holiday, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) domain.Holiday); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Holiday)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHolidays provides a mock function with given fields: ctx, query
+func (_m *HolidayRepo) GetHolidays(ctx context.Context, query domain.HolidaysQuery) ([]domain.Holiday, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHolidays")
+	}
+
+	var r0 []domain.Holiday
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.HolidaysQuery) ([]domain.Holiday, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.HolidaysQuery) []domain.Holiday); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Holiday)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.HolidaysQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkingWeek provides a mock function with given fields: ctx, region
+func (_m *HolidayRepo) GetWorkingWeek(ctx context.Context, region string) (domain.WorkingWeek, error) {
+	ret := _m.Called(ctx, region)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkingWeek")
+	}
+
+	var r0 domain.WorkingWeek
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.WorkingWeek, error)); ok {
+		return rf(ctx, region)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.WorkingWeek); ok {
+		r0 = rf(ctx, region)
+	} else {
+		r0 = ret.Get(0).(domain.WorkingWeek)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, region)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveWorkingWeek provides a mock function with given fields: ctx, week
+func (_m *HolidayRepo) SaveWorkingWeek(ctx context.Context, week *domain.WorkingWeek) error {
+	ret := _m.Called(ctx, week)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveWorkingWeek")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.WorkingWeek) error); ok {
+		r0 = rf(ctx, week)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateHoliday provides a mock function with given fields: ctx, holiday
+func (_m *HolidayRepo) UpdateHoliday(ctx context.Context, holiday *domain.Holiday) error {
+	ret := _m.Called(ctx, holiday)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateHoliday")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Holiday) error); ok {
+		r0 = rf(ctx, holiday)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewHolidayRepo creates a new instance of HolidayRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHolidayRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HolidayRepo {
+	mock := &HolidayRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,129 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/holidays/domain"
+)
+
+type HolidayRepo interface {
+	CreateHoliday(ctx context.Context, holiday *domain.Holiday) error
+	GetHolidayByID(ctx context.Context, id int) (domain.Holiday, error)
+	GetHolidays(ctx context.Context, query domain.HolidaysQuery) ([]domain.Holiday, error)
+	UpdateHoliday(ctx context.Context, holiday *domain.Holiday) error
+	DeleteHoliday(ctx context.Context, id int) error
+	GetWorkingWeek(ctx context.Context, region string) (domain.WorkingWeek, error)
+	SaveWorkingWeek(ctx context.Context, week *domain.WorkingWeek) error
+}
+
+type holidayRepo struct {
+	db *gorm.DB
+}
+
+func NewHolidayRepo(db *gorm.DB) (HolidayRepo, error) {
+	repo := &holidayRepo{
+		db: db,
+	}
+
+	if err := repo.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *holidayRepo) ensureSchema() error {
+	// AutoMigrate
+	if err := r.db.AutoMigrate(domain.Holiday{}); err != nil {
+		return err
+	}
+	if err := r.db.AutoMigrate(domain.WorkingWeek{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *holidayRepo) CreateHoliday(ctx context.Context, holiday *domain.Holiday) error {
+	if err := r.db.WithContext(ctx).Create(holiday).Error; err != nil {
+		return fmt.Errorf("failed to create holiday: %w", err)
+	}
+	return nil
+}
+
+func (r *holidayRepo) GetHolidayByID(ctx context.Context, id int) (domain.Holiday, error) {
+	var holiday domain.Holiday
+	if err := r.db.WithContext(ctx).First(&holiday, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Holiday{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Holiday{}, fmt.Errorf("failed to find holiday with id %d: %w", id, err)
+	}
+	return holiday, nil
+}
+
+func (r *holidayRepo) GetHolidays(ctx context.Context, query domain.HolidaysQuery) ([]domain.Holiday, error) {
+	var holidays []domain.Holiday
+
+	db := r.db.WithContext(ctx)
+	if query.Region != nil {
+		db = db.Where("region = ?", *query.Region)
+	}
+	if query.From != nil {
+		db = db.Where("date >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("date <= ?", *query.To)
+	}
+	if err := db.Order("date ASC").Find(&holidays).Error; err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+
+	return holidays, nil
+}
+
+func (r *holidayRepo) UpdateHoliday(ctx context.Context, holiday *domain.Holiday) error {
+	result := r.db.WithContext(ctx).Model(holiday).
+		Select("Region", "Date", "Name").
+		Updates(holiday)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update holiday: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return common_errors.ErrResourceNotFound
+	}
+	return nil
+}
+
+func (r *holidayRepo) DeleteHoliday(ctx context.Context, id int) error {
+	result := r.db.WithContext(ctx).Delete(&domain.Holiday{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete holiday: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return common_errors.ErrResourceNotFound
+	}
+	return nil
+}
+
+func (r *holidayRepo) GetWorkingWeek(ctx context.Context, region string) (domain.WorkingWeek, error) {
+	var week domain.WorkingWeek
+	if err := r.db.WithContext(ctx).Where("region = ?", region).First(&week).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.WorkingWeek{}, common_errors.ErrResourceNotFound
+		}
+		return domain.WorkingWeek{}, fmt.Errorf("failed to find working week of region %s: %w", region, err)
+	}
+	return week, nil
+}
+
+func (r *holidayRepo) SaveWorkingWeek(ctx context.Context, week *domain.WorkingWeek) error {
+	if err := r.db.WithContext(ctx).Save(week).Error; err != nil {
+		return fmt.Errorf("failed to save working week: %w", err)
+	}
+	return nil
+}
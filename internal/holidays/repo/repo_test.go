@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/holidays/domain"
+)
+
+func setupTestRepo(t *testing.T) *holidayRepo {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := &holidayRepo{db: db}
+	assert.NoError(t, repo.ensureSchema())
+	return repo
+}
+
+func TestHolidayCRUD(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	holiday := &domain.Holiday{Region: "TW", Date: time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC), Name: "National Day"}
+	assert.NoError(t, repo.CreateHoliday(ctx, holiday))
+	assert.NotZero(t, holiday.ID)
+
+	holiday.Name = "Double Ten Day"
+	assert.NoError(t, repo.UpdateHoliday(ctx, holiday))
+
+	fetched, err := repo.GetHolidayByID(ctx, holiday.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Double Ten Day", fetched.Name)
+
+	assert.NoError(t, repo.DeleteHoliday(ctx, holiday.ID))
+	_, err = repo.GetHolidayByID(ctx, holiday.ID)
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+	assert.ErrorIs(t, repo.DeleteHoliday(ctx, holiday.ID), common_errors.ErrResourceNotFound)
+}
+
+func TestGetHolidays(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	day := func(m time.Month, d int) time.Time {
+		return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	holidays := []*domain.Holiday{
+		{Region: "TW", Date: day(time.January, 1), Name: "New Year"},
+		{Region: "TW", Date: day(time.October, 10), Name: "National Day"},
+		{Region: "US", Date: day(time.July, 4), Name: "Independence Day"},
+	}
+	for _, holiday := range holidays {
+		assert.NoError(t, repo.CreateHoliday(ctx, holiday))
+	}
+
+	region := "TW"
+	from := day(time.February, 1)
+	fetched, err := repo.GetHolidays(ctx, domain.HolidaysQuery{Region: &region, From: &from})
+	assert.NoError(t, err)
+	assert.Len(t, fetched, 1)
+	assert.Equal(t, "National Day", fetched[0].Name)
+}
+
+func TestWorkingWeek(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.GetWorkingWeek(ctx, "AE")
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+
+	week := &domain.WorkingWeek{Region: "AE", Weekdays: []time.Weekday{time.Monday, time.Tuesday}}
+	assert.NoError(t, repo.SaveWorkingWeek(ctx, week))
+	week.Weekdays = append(week.Weekdays, time.Wednesday)
+	assert.NoError(t, repo.SaveWorkingWeek(ctx, week))
+
+	fetched, err := repo.GetWorkingWeek(ctx, "AE")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Weekday{time.Monday, time.Tuesday, time.Wednesday}, fetched.Weekdays)
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/holidays/domain"
+	"hr-system/internal/holidays/repo"
+)
+
+type HolidayService interface {
+	CreateHoliday(ctx context.Context, holiday *domain.Holiday) (domain.Holiday, error)
+	GetHolidayByID(ctx context.Context, id int) (domain.Holiday, error)
+	GetHolidays(ctx context.Context, query domain.HolidaysQuery) ([]domain.Holiday, error)
+	UpdateHoliday(ctx context.Context, holiday *domain.Holiday) (domain.Holiday, error)
+	DeleteHoliday(ctx context.Context, id int) error
+	GetWorkingWeek(ctx context.Context, region string) (domain.WorkingWeek, error)
+	SetWorkingWeek(ctx context.Context, week *domain.WorkingWeek) (domain.WorkingWeek, error)
+	// WorkingDaysBetween counts the working days of the region from start to end, both dates are included
+	WorkingDaysBetween(ctx context.Context, region string, start, end time.Time) (int, error)
+	// WorkingDates returns the working days of the region from start to end, both dates are included,
+	// without time and location
+	WorkingDates(ctx context.Context, region string, start, end time.Time) ([]time.Time, error)
+}
+
+type holidayService struct {
+	holidayRepo repo.HolidayRepo
+	logger      *common.Logger
+	validate    *validator.Validate
+}
+
+func NewHolidayService(logger *common.Logger, holidayRepo repo.HolidayRepo) HolidayService {
+	return &holidayService{
+		holidayRepo: holidayRepo,
+		logger:      logger,
+		validate:    validator.New(),
+	}
+}
+
+// checkDuplicate makes sure there is no other holiday on the same date of the region
+func (s *holidayService) checkDuplicate(ctx context.Context, holiday *domain.Holiday) error {
+	holidays, err := s.holidayRepo.GetHolidays(ctx, domain.HolidaysQuery{
+		Region: &holiday.Region,
+		From:   &holiday.Date,
+		To:     &holiday.Date,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get holidays: %w", err)
+	}
+	for _, h := range holidays {
+		if h.ID != holiday.ID {
+			return fmt.Errorf("%w, holiday %d is on the same date", common_errors.ErrStatusConflict, h.ID)
+		}
+	}
+	return nil
+}
+
+func (s *holidayService) CreateHoliday(ctx context.Context, holiday *domain.Holiday) (domain.Holiday, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.Holiday{}, err
+	}
+	if err := s.validate.Struct(holiday); err != nil {
+		return domain.Holiday{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	if err := s.checkDuplicate(ctx, holiday); err != nil {
+		return domain.Holiday{}, err
+	}
+
+	if err := s.holidayRepo.CreateHoliday(ctx, holiday); err != nil {
+		return domain.Holiday{}, fmt.Errorf("failed to create holiday: %w", err)
+	}
+	return *holiday, nil
+}
+
+func (s *holidayService) GetHolidayByID(ctx context.Context, id int) (domain.Holiday, error) {
+	holiday, err := s.holidayRepo.GetHolidayByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Holiday{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Holiday{}, fmt.Errorf("failed to get holiday: %w", err)
+	}
+	return holiday, nil
+}
+
+func (s *holidayService) GetHolidays(ctx context.Context, query domain.HolidaysQuery) ([]domain.Holiday, error) {
+	if query.From != nil && query.To != nil && query.From.After(*query.To) {
+		return nil, fmt.Errorf("%w, from must be before to", common_errors.ErrInvalidInput)
+	}
+	holidays, err := s.holidayRepo.GetHolidays(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+	return holidays, nil
+}
+
+func (s *holidayService) UpdateHoliday(ctx context.Context, holiday *domain.Holiday) (domain.Holiday, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.Holiday{}, err
+	}
+	if err := s.validate.Struct(holiday); err != nil {
+		return domain.Holiday{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	if err := s.checkDuplicate(ctx, holiday); err != nil {
+		return domain.Holiday{}, err
+	}
+
+	if err := s.holidayRepo.UpdateHoliday(ctx, holiday); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Holiday{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Holiday{}, fmt.Errorf("failed to update holiday: %w", err)
+	}
+	return s.GetHolidayByID(ctx, holiday.ID)
+}
+
+func (s *holidayService) DeleteHoliday(ctx context.Context, id int) error {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return err
+	}
+	if err := s.holidayRepo.DeleteHoliday(ctx, id); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return common_errors.ErrResourceNotFound
+		}
+		return fmt.Errorf("failed to delete holiday: %w", err)
+	}
+	return nil
+}
+
+func (s *holidayService) GetWorkingWeek(ctx context.Context, region string) (domain.WorkingWeek, error) {
+	week, err := s.holidayRepo.GetWorkingWeek(ctx, region)
+	if err == nil {
+		return week, nil
+	}
+	if !errors.Is(err, common_errors.ErrResourceNotFound) {
+		return domain.WorkingWeek{}, fmt.Errorf("failed to get working week: %w", err)
+	}
+
+	// fall back to the default working week
+	if region != domain.DefaultRegion {
+		return s.GetWorkingWeek(ctx, domain.DefaultRegion)
+	}
+	return domain.WorkingWeek{Region: region, Weekdays: domain.DefaultWeekdays}, nil
+}
+
+func (s *holidayService) SetWorkingWeek(ctx context.Context, week *domain.WorkingWeek) (domain.WorkingWeek, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.WorkingWeek{}, err
+	}
+	if err := s.validate.Struct(week); err != nil {
+		return domain.WorkingWeek{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	if err := s.holidayRepo.SaveWorkingWeek(ctx, week); err != nil {
+		return domain.WorkingWeek{}, fmt.Errorf("failed to set working week: %w", err)
+	}
+	return *week, nil
+}
+
+func (s *holidayService) WorkingDaysBetween(ctx context.Context, region string, start, end time.Time) (int, error) {
+	dates, err := s.WorkingDates(ctx, region, start, end)
+	if err != nil {
+		return 0, err
+	}
+	return len(dates), nil
+}
+
+func (s *holidayService) WorkingDates(ctx context.Context, region string, start, end time.Time) ([]time.Time, error) {
+	if region == "" {
+		region = domain.DefaultRegion
+	}
+
+	week, err := s.GetWorkingWeek(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	// holidays are stored as dates, the time of start must not exclude the holiday on the start date
+	from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	holidays, err := s.holidayRepo.GetHolidays(ctx, domain.HolidaysQuery{Region: &region, From: &from, To: &end})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+
+	return domain.WorkingDates(start, end, week, holidays), nil
+}
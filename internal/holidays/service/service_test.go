@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/holidays/domain"
+	mocks_holiday_repo "hr-system/internal/holidays/repo/mocks"
+)
+
+func TestWorkingDaysBetween(t *testing.T) {
+	mockRepo := mocks_holiday_repo.NewHolidayRepo(t)
+	service := NewHolidayService(common.NewLogger(), mockRepo)
+
+	ctx := context.Background()
+	// Friday to the next Friday
+	start := time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	// the region falls back to the default working week
+	mockRepo.On("GetWorkingWeek", ctx, "TW").Return(domain.WorkingWeek{}, common_errors.ErrResourceNotFound).Once()
+	mockRepo.On("GetWorkingWeek", ctx, domain.DefaultRegion).
+		Return(domain.WorkingWeek{}, common_errors.ErrResourceNotFound).Once()
+	mockRepo.On("GetHolidays", ctx, mock.MatchedBy(func(query domain.HolidaysQuery) bool {
+		return *query.Region == "TW" && query.From.Equal(time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC))
+	})).Return([]domain.Holiday{{Region: "TW", Date: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)}}, nil).Once()
+
+	days, err := service.WorkingDaysBetween(ctx, "TW", start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, days)
+}
+
+func TestCreateHoliday_Duplicate(t *testing.T) {
+	mockRepo := mocks_holiday_repo.NewHolidayRepo(t)
+	service := NewHolidayService(common.NewLogger(), mockRepo)
+
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 1, Roles: []auth.Role{auth.RoleHRAdmin}})
+	holiday := domain.Holiday{Region: "TW", Date: time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC), Name: "National Day"}
+
+	mockRepo.On("GetHolidays", ctx, mock.Anything).Return([]domain.Holiday{{ID: 1}}, nil).Once()
+
+	_, err := service.CreateHoliday(ctx, &holiday)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+}
@@ -2,9 +2,12 @@ package cache
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -17,10 +20,15 @@ import (
 type LeaveCache interface {
 	SetLeaveToCache(ctx context.Context, leave *domain.Leave) error
 	GetLeaveFromCache(ctx context.Context, id int) (domain.Leave, error)
-	SetLeavesToCache(ctx context.Context, query domain.LeavesQuery, leaves []domain.Leave) error
-	GetLeavesFromCache(ctx context.Context, query domain.LeavesQuery) ([]domain.Leave, error)
 	DelLeaveFromCache(ctx context.Context, id int) error
-	DelLeavesFromCache(ctx context.Context, query domain.LeavesQuery) error
+	// GetListsGeneration returns the current generation of the leaves lists, the lists cached in the older ones
+	// are stale
+	GetListsGeneration(ctx context.Context) (int64, error)
+	SetLeavesToCache(ctx context.Context, generation int64, query domain.LeavesQuery, page *domain.LeavesPage) error
+	GetLeavesFromCache(ctx context.Context, generation int64, query domain.LeavesQuery) (domain.LeavesPage, error)
+	// InvalidateLeavesLists makes every cached leaves list stale by moving to the next generation,
+	// so a change does not need to know which of the arbitrarily filtered lists it's in
+	InvalidateLeavesLists(ctx context.Context) error
 }
 
 type leaveCache struct {
@@ -71,59 +79,65 @@ func (c *leaveCache) GetLeaveFromCache(ctx context.Context, id int) (domain.Leav
 	return leave, nil
 }
 
-func (c *leaveCache) SetLeavesToCache(ctx context.Context, query domain.LeavesQuery, leaves []domain.Leave) error {
-	cacheKey := c.genCacheKey(query)
-	data, err := json.Marshal(leaves)
-	if err != nil {
-		return err
+func (c *leaveCache) genGenerationCacheKey() string {
+	return fmt.Sprintf("%s_lists_generation", c.prefix)
+}
+
+// genCacheKey returns the key of the list of the query in the generation, the query is hashed as a whole
+func (c *leaveCache) genCacheKey(generation int64, query domain.LeavesQuery) string {
+	query.Normalize()
+	data, _ := json.Marshal(query)
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s_list_gen_%d_%s", c.prefix, generation, hex.EncodeToString(sum[:8]))
+}
+
+func (c *leaveCache) GetListsGeneration(ctx context.Context) (int64, error) {
+	data, err := c.cache.Get(ctx, c.genGenerationCacheKey())
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
 	}
+	return strconv.ParseInt(data, 10, 64)
+}
 
-	err = c.cache.Set(ctx, cacheKey, string(data), 1*time.Hour)
+func (c *leaveCache) InvalidateLeavesLists(ctx context.Context) error {
+	_, err := c.cache.Incr(ctx, c.genGenerationCacheKey())
+	return err
+}
+
+func (c *leaveCache) SetLeavesToCache(ctx context.Context, generation int64, query domain.LeavesQuery,
+	page *domain.LeavesPage) error {
+	if page == nil {
+		return errors.New("page is nil")
+	}
+	data, err := json.Marshal(page)
 	if err != nil {
 		return err
 	}
-	return nil
-}
 
-func (c *leaveCache) genCacheKey(query domain.LeavesQuery) string {
-	if query.EmployeeID != nil {
-		return fmt.Sprintf("%s_employee_%d", c.prefix, *query.EmployeeID)
-	} else if query.CurrentReviewerID != nil {
-		return fmt.Sprintf("%s_reviewer_%d", c.prefix, *query.CurrentReviewerID)
-	} else {
-		// won't happen now
-		return fmt.Sprintf("%s_all", c.prefix)
-	}
+	// the stale generations are left to expire
+	return c.cache.Set(ctx, c.genCacheKey(generation, query), string(data), 1*time.Hour)
 }
 
-func (c *leaveCache) GetLeavesFromCache(ctx context.Context, query domain.LeavesQuery) ([]domain.Leave, error) {
-	cacheKey := c.genCacheKey(query)
-	data, err := c.cache.Get(ctx, cacheKey)
+func (c *leaveCache) GetLeavesFromCache(ctx context.Context, generation int64,
+	query domain.LeavesQuery) (domain.LeavesPage, error) {
+	data, err := c.cache.Get(ctx, c.genCacheKey(generation, query))
 	if errors.Is(err, redis.Nil) {
-		return nil, common_errors.ErrResourceNotFound
+		return domain.LeavesPage{}, common_errors.ErrResourceNotFound
 	} else if err != nil {
-		return nil, err
+		return domain.LeavesPage{}, err
 	}
 	if data == "" {
-		return nil, common_errors.ErrResourceNotFound
+		return domain.LeavesPage{}, common_errors.ErrResourceNotFound
 	}
 
-	var leaves []domain.Leave
-	err = json.Unmarshal([]byte(data), &leaves)
-	if err != nil {
-		return nil, err
+	var page domain.LeavesPage
+	if err := json.Unmarshal([]byte(data), &page); err != nil {
+		return domain.LeavesPage{}, err
 	}
 
-	return leaves, nil
-}
-
-func (c *leaveCache) DelLeavesFromCache(ctx context.Context, query domain.LeavesQuery) error {
-	cacheKey := c.genCacheKey(query)
-	err := c.cache.Del(ctx, cacheKey)
-	if err != nil {
-		return err
-	}
-	return nil
+	return page, nil
 }
 
 func (c *leaveCache) DelLeaveFromCache(ctx context.Context, id int) error {
@@ -32,24 +32,6 @@ func (_m *LeaveCache) DelLeaveFromCache(ctx context.Context, id int) error {
 	return r0
 }
 
-// DelLeavesFromCache provides a mock function with given fields: ctx, query
-func (_m *LeaveCache) DelLeavesFromCache(ctx context.Context, query domain.LeavesQuery) error {
-	ret := _m.Called(ctx, query)
-
-	if len(ret) == 0 {
-		panic("no return value specified for DelLeavesFromCache")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, domain.LeavesQuery) error); ok {
-		r0 = rf(ctx, query)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
 // GetLeaveFromCache provides a mock function with given fields: ctx, id
 func (_m *LeaveCache) GetLeaveFromCache(ctx context.Context, id int) (domain.Leave, error) {
 	ret := _m.Called(ctx, id)
@@ -78,29 +60,55 @@ func (_m *LeaveCache) GetLeaveFromCache(ctx context.Context, id int) (domain.Lea
 	return r0, r1
 }
 
-// GetLeavesFromCache provides a mock function with given fields: ctx, query
-func (_m *LeaveCache) GetLeavesFromCache(ctx context.Context, query domain.LeavesQuery) ([]domain.Leave, error) {
-	ret := _m.Called(ctx, query)
+// GetLeavesFromCache provides a mock function with given fields: ctx, generation, query
+func (_m *LeaveCache) GetLeavesFromCache(ctx context.Context, generation int64, query domain.LeavesQuery) (domain.LeavesPage, error) {
+	ret := _m.Called(ctx, generation, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLeavesFromCache")
 	}
 
-	var r0 []domain.Leave
+	var r0 domain.LeavesPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.LeavesQuery) (domain.LeavesPage, error)); ok {
+		return rf(ctx, generation, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.LeavesQuery) domain.LeavesPage); ok {
+		r0 = rf(ctx, generation, query)
+	} else {
+		r0 = ret.Get(0).(domain.LeavesPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.LeavesQuery) error); ok {
+		r1 = rf(ctx, generation, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetListsGeneration provides a mock function with given fields: ctx
+func (_m *LeaveCache) GetListsGeneration(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetListsGeneration")
+	}
+
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, domain.LeavesQuery) ([]domain.Leave, error)); ok {
-		return rf(ctx, query)
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, domain.LeavesQuery) []domain.Leave); ok {
-		r0 = rf(ctx, query)
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Leave)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, domain.LeavesQuery) error); ok {
-		r1 = rf(ctx, query)
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -108,6 +116,24 @@ func (_m *LeaveCache) GetLeavesFromCache(ctx context.Context, query domain.Leave
 	return r0, r1
 }
 
+// InvalidateLeavesLists provides a mock function with given fields: ctx
+func (_m *LeaveCache) InvalidateLeavesLists(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InvalidateLeavesLists")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetLeaveToCache provides a mock function with given fields: ctx, leave
 func (_m *LeaveCache) SetLeaveToCache(ctx context.Context, leave *domain.Leave) error {
 	ret := _m.Called(ctx, leave)
@@ -126,17 +152,17 @@ func (_m *LeaveCache) SetLeaveToCache(ctx context.Context, leave *domain.Leave)
 	return r0
 }
 
-// SetLeavesToCache provides a mock function with given fields: ctx, query, leaves
-func (_m *LeaveCache) SetLeavesToCache(ctx context.Context, query domain.LeavesQuery, leaves []domain.Leave) error {
-	ret := _m.Called(ctx, query, leaves)
+// SetLeavesToCache provides a mock function with given fields: ctx, generation, query, page
+func (_m *LeaveCache) SetLeavesToCache(ctx context.Context, generation int64, query domain.LeavesQuery, page *domain.LeavesPage) error {
+	ret := _m.Called(ctx, generation, query, page)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetLeavesToCache")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, domain.LeavesQuery, []domain.Leave) error); ok {
-		r0 = rf(ctx, query, leaves)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.LeavesQuery, *domain.LeavesPage) error); ok {
+		r0 = rf(ctx, generation, query, page)
 	} else {
 		r0 = ret.Error(0)
 	}
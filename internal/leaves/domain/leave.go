@@ -1,48 +1,371 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
 
+	review_domain "hr-system/internal/reviews/domain"
+)
+
+// LeaveType is the code of a leave type in the catalogue of the leavetypes package
 type LeaveType string
 
+// the built-in leave types, the others are added to the catalogue through the API
 var (
 	LeaveTypeAnnual LeaveType = "annual"
 	LeaveTypeSick   LeaveType = "sick"
+	// LeaveTypeCompOff is taken from the comp-off allowance earned by approved overtime instead of a yearly balance
+	LeaveTypeCompOff LeaveType = "comp_off"
 )
 
-type ReviewStatus string
+// LeaveUnit is how much of a day a leave takes, only a full-day leave spans several days
+type LeaveUnit string
 
 var (
-	ReviewStatusReviewing ReviewStatus = "reviewing"
-	ReviewStatusApproved  ReviewStatus = "approved"
-	ReviewStatusRejected  ReviewStatus = "rejected"
+	LeaveUnitFullDay LeaveUnit = "full_day"
+	LeaveUnitAM      LeaveUnit = "am" // the morning, before noon
+	LeaveUnitPM      LeaveUnit = "pm" // the afternoon, from noon on
+	LeaveUnitHours   LeaveUnit = "hours"
 )
 
+// WorkingHoursPerDay turns the hours of an hourly leave into days
+const WorkingHoursPerDay = 8
+
 type Leave struct {
-	ID                int           `gorm:"primaryKey;autoIncrement"`
-	EmployeeID        int           `gorm:"index:idx_employee_id" validate:"required"`
-	Type              LeaveType     `gorm:"type:varchar(50);not null" validate:"required,oneof=annual sick"`
-	StartDate         time.Time     `gorm:"type:date;not null" validate:"required"`
-	EndDate           time.Time     `gorm:"type:date;not null" validate:"required"`
-	Reason            string        `gorm:"type:varchar(255)"`
-	Status            ReviewStatus  `gorm:"type:varchar(50);not null"`
-	CurrentReviewerID *int          `gorm:"index:idx_current_reviewer_id"`
-	Reviews           []LeaveReview `gorm:"foreignKey:LeaveID"`
-	CreatedAt         time.Time     `gorm:"autoCreateTime"`
-	UpdatedAt         time.Time     `gorm:"autoUpdateTime"`
-}
-
-type LeaveReview struct {
-	ID         int          `gorm:"primaryKey;autoIncrement"`
-	LeaveID    int          `gorm:"index:idx_leave_id"`
-	ReviewerID int          `gorm:"type:int;not null"`
-	Status     ReviewStatus `gorm:"type:varchar(50);not null"`
-	Comment    string       `gorm:"type:varchar(255)"`
-	ReviewedAt *time.Time   `gorm:"type:date"`
-	CreatedAt  time.Time    `gorm:"autoCreateTime"`
-	UpdatedAt  time.Time    `gorm:"autoUpdateTime"`
+	ID         int       `gorm:"primaryKey;autoIncrement"`
+	EmployeeID int       `gorm:"index:idx_employee_id" validate:"required"`
+	Type       LeaveType `gorm:"type:varchar(50);not null" validate:"required,max=50"`
+	StartDate  time.Time `gorm:"type:date;not null" validate:"required"`
+	EndDate    time.Time `gorm:"type:date;not null" validate:"required"`
+	Unit       LeaveUnit `gorm:"type:varchar(20);not null;default:'full_day'" validate:"omitempty,oneof=full_day am pm hours"`
+	// StartTime and EndTime are the "15:04" times of an hourly leave, empty for the other units
+	StartTime string `gorm:"type:varchar(5);not null;default:''"`
+	EndTime   string `gorm:"type:varchar(5);not null;default:''"`
+	// Days is the working days taken by the leave, a half-day is 0.5 and an hour is 1/WorkingHoursPerDay
+	Days              float64                    `gorm:"type:decimal(6,2);not null;default:0"`
+	Reason            string                     `gorm:"type:varchar(255)"`
+	Status            review_domain.ReviewStatus `gorm:"type:varchar(50);not null"`
+	CurrentReviewerID *int                       `gorm:"index:idx_current_reviewer_id"`
+	Reviews           []review_domain.Review     `gorm:"polymorphic:Subject;polymorphicValue:leave"`
+	CreatedAt         time.Time                  `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time                  `gorm:"autoUpdateTime"`
+	// AttachmentIDs are the pending attachments submitted with a new leave, the attachments keep the link
+	AttachmentIDs []int `gorm:"-"`
+}
+
+func (l *Leave) String() string {
+	return fmt.Sprintf("leave %d", l.ID)
+}
+
+func (l *Leave) RequesterID() int {
+	return l.EmployeeID
+}
+
+func (l *Leave) CurrentReviewer() *int {
+	return l.CurrentReviewerID
+}
+
+func (l *Leave) SetCurrentReviewer(reviewerID *int) {
+	l.CurrentReviewerID = reviewerID
+}
+
+func (l *Leave) LastReview() (review_domain.Review, bool) {
+	if len(l.Reviews) == 0 {
+		return review_domain.Review{}, false
+	}
+	return l.Reviews[len(l.Reviews)-1], true
+}
+
+// ValidateUnit checks the unit and the times of the leave agree with its dates, an empty unit is a full day
+func (l *Leave) ValidateUnit() error {
+	switch l.Unit {
+	case "", LeaveUnitFullDay:
+		if l.StartTime != "" || l.EndTime != "" {
+			return errors.New("only an hourly leave has start and end times")
+		}
+		return nil
+	case LeaveUnitAM, LeaveUnitPM, LeaveUnitHours:
+	default:
+		return fmt.Errorf("unknown leave unit %s", l.Unit)
+	}
+
+	if l.StartDate.Format(time.DateOnly) != l.EndDate.Format(time.DateOnly) {
+		return fmt.Errorf("a leave of unit %s must start and end on the same day", l.Unit)
+	}
+	if l.Unit != LeaveUnitHours {
+		if l.StartTime != "" || l.EndTime != "" {
+			return errors.New("only an hourly leave has start and end times")
+		}
+		return nil
+	}
+
+	start, err := time.Parse("15:04", l.StartTime)
+	if err != nil {
+		return fmt.Errorf("invalid start time %q", l.StartTime)
+	}
+	end, err := time.Parse("15:04", l.EndTime)
+	if err != nil {
+		return fmt.Errorf("invalid end time %q", l.EndTime)
+	}
+	if !start.Before(end) {
+		return errors.New("start time must be before end time")
+	}
+	if end.Sub(start) > WorkingHoursPerDay*time.Hour {
+		return fmt.Errorf("an hourly leave takes at most %d hours, take a full day instead", WorkingHoursPerDay)
+	}
+	return nil
+}
+
+// DayFraction is the part of a working day the leave takes on each of its days, 1 for a full day
+func (l *Leave) DayFraction() float64 {
+	switch l.Unit {
+	case LeaveUnitAM, LeaveUnitPM:
+		return 0.5
+	case LeaveUnitHours:
+		start, _ := time.Parse("15:04", l.StartTime)
+		end, _ := time.Parse("15:04", l.EndTime)
+		// the days are stored with 2 decimals
+		return math.Round(end.Sub(start).Hours()/WorkingHoursPerDay*100) / 100
+	default:
+		return 1
+	}
+}
+
+// PartOfDay describes the part of the day a leave shorter than a day takes, e.g. "am" or "09:00-11:00",
+// it's empty for a full day
+func (l *Leave) PartOfDay() string {
+	switch l.Unit {
+	case LeaveUnitAM, LeaveUnitPM:
+		return string(l.Unit)
+	case LeaveUnitHours:
+		return l.StartTime + "-" + l.EndTime
+	default:
+		return ""
+	}
+}
+
+// minutes returns the part of its days the leave takes as the minutes from midnight
+func (l *Leave) minutes() (int, int) {
+	switch l.Unit {
+	case LeaveUnitAM:
+		return 0, 12 * 60
+	case LeaveUnitPM:
+		return 12 * 60, 24 * 60
+	case LeaveUnitHours:
+		start, _ := time.Parse("15:04", l.StartTime)
+		end, _ := time.Parse("15:04", l.EndTime)
+		return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute()
+	default:
+		return 0, 24 * 60
+	}
 }
 
+// Overlaps reports whether the leaves taking some of the same days also take some of the same hours,
+// e.g. the morning and the afternoon of a day do not overlap
+func (l *Leave) Overlaps(other *Leave) bool {
+	start, end := l.minutes()
+	otherStart, otherEnd := other.minutes()
+	return start < otherEnd && otherStart < end
+}
+
+// LeavesQuery filters the leaves, the caller must be allowed one of the employee, the current reviewer and the
+// manager, only HR and payroll list the leaves without any of them, a page at a time
 type LeavesQuery struct {
 	EmployeeID        *int
 	CurrentReviewerID *int
+	// ManagerID keeps the leaves of the employees reporting to the manager directly or indirectly
+	ManagerID *int
+	Statuses  []review_domain.ReviewStatus `validate:"dive,oneof=reviewing approved rejected cancelled revoking revoked"`
+	Types     []LeaveType                  `validate:"dive,required,max=50"`
+	// From and To keep the leaves overlapping the dates, both inclusive
+	From *time.Time
+	To   *time.Time
+	// CreatedFrom and CreatedTo keep the leaves created from CreatedFrom on and before CreatedTo
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Sort        []SortField `validate:"dive"` // the latest first when empty, ties are ordered the latest first
+
+	// EmployeeIDs restricts the list to the employees, it's resolved from ManagerID
+	EmployeeIDs []int
+	// DelegatorIDs adds the leaves waiting for the managers the current reviewer is the delegate of,
+	// except the reviewer's own ones
+	DelegatorIDs []int
+	// Limit is the size of a page, 0 for every leave
+	Limit int `validate:"gte=0"`
+	// After continues the list after the cursor
+	After *LeaveCursor
+	// WithCount also counts every leave matching the query
+	WithCount bool
+}
+
+// Normalize drops the empty lists of the query, so equal queries look the same
+func (q *LeavesQuery) Normalize() {
+	if len(q.Statuses) == 0 {
+		q.Statuses = nil
+	}
+	if len(q.Types) == 0 {
+		q.Types = nil
+	}
+	if len(q.Sort) == 0 {
+		q.Sort = nil
+	}
+}
+
+// SortField orders the leaves by a field, the sortable fields are id, start_date, end_date and created_at
+type SortField struct {
+	Field string `validate:"oneof=id start_date end_date created_at"`
+	Desc  bool
+}
+
+// ParseSort parses a comma separated list of fields, each descending if it starts with "-", e.g. "-start_date"
+func ParseSort(s string) []SortField {
+	var fields []SortField
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		fields = append(fields, SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")})
+	}
+	return fields
+}
+
+// FormatSort is the inverse of ParseSort
+func FormatSort(sort []SortField) string {
+	parts := make([]string, 0, len(sort))
+	for _, field := range sort {
+		if field.Desc {
+			parts = append(parts, "-"+field.Field)
+		} else {
+			parts = append(parts, field.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// LeavesPage is a page of leaves
+type LeavesPage struct {
+	Leaves []Leave `json:"leaves"`
+	// TotalCount is nil unless the query asks for it
+	TotalCount *int `json:"total_count,omitempty"`
+	// Next is where the next page starts, nil on the last page
+	Next *LeaveCursor `json:"next,omitempty"`
+}
+
+// LeaveCursor holds the sort values of the last leave of a page, the next page starts after them
+type LeaveCursor struct {
+	Sort      string     `json:"sort,omitempty"` // the sort the values are of, formatted by FormatSort
+	ID        int        `json:"id"`
+	StartDate *time.Time `json:"start_date,omitempty"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// NewLeaveCursor returns the cursor after the leave in the sort, it only keeps the values the sort uses
+func NewLeaveCursor(leave *Leave, sort []SortField) *LeaveCursor {
+	cursor := &LeaveCursor{Sort: FormatSort(sort), ID: leave.ID}
+	for _, field := range sort {
+		switch field.Field {
+		case "start_date":
+			startDate := leave.StartDate
+			cursor.StartDate = &startDate
+		case "end_date":
+			endDate := leave.EndDate
+			cursor.EndDate = &endDate
+		case "created_at":
+			createdAt := leave.CreatedAt
+			cursor.CreatedAt = &createdAt
+		}
+	}
+	return cursor
+}
+
+// ApprovalChain is the reviewers a leave goes through to be approved
+type ApprovalChain struct {
+	Days                 float64
+	RequiredManagerLevel int
+	RequiredApprovers    int
+	Reviewers            []ApprovalStep
+	// Satisfiable is false when the manager chain ends before the requirement is met
+	Satisfiable bool
+}
+
+// UnsatisfiableChain is an employee whose manager chain ends before a requirement of the approval policies is met,
+// their leaves needing it can not be approved
+type UnsatisfiableChain struct {
+	EmployeeID           int
+	Name                 string
+	PositionLevel        string
+	LeaveType            LeaveType
+	RequiredManagerLevel int
+	RequiredApprovers    int
+	Reviewers            []ApprovalStep
+}
+
+type ApprovalStep struct {
+	ReviewerID   int
+	Name         string
+	ManagerLevel int
+}
+
+// MaxCalendarDays bounds the date range of a team calendar
+const MaxCalendarDays = 92
+
+// Absence is a leave of a team member, approved or still being reviewed
+type Absence struct {
+	EmployeeID int
+	Name       string
+	LeaveID    int
+	Type       LeaveType
+	Status     review_domain.ReviewStatus
+	StartDate  time.Time
+	EndDate    time.Time
+	// Unit, StartTime and EndTime tell the part of the day a leave shorter than a day takes
+	Unit      LeaveUnit
+	StartTime string
+	EndTime   string
+}
+
+// TeamCalendar lists the reports of a manager out on each day from From to To, both inclusive
+type TeamCalendar struct {
+	ManagerID int
+	From      time.Time
+	To        time.Time
+	Days      []CalendarDay
+}
+
+type CalendarDay struct {
+	Date     time.Time
+	Absences []Absence
+}
+
+// LeaveDetail is a leave with what its reviewers need to decide on it
+type LeaveDetail struct {
+	Leave
+	// TeamAlsoOut are the leaves of the requester's team overlapping the leave, it's only given to the reviewers,
+	// managers and HR rather than to the requester
+	TeamAlsoOut []Absence
+}
+
+// civilDate drops the time and the location of t
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// NewTeamCalendar puts each absence on the days from from to to it covers
+func NewTeamCalendar(managerID int, from, to time.Time, absences []Absence) TeamCalendar {
+	calendar := TeamCalendar{ManagerID: managerID, From: from, To: to, Days: []CalendarDay{}}
+	for d := from; !civilDate(d).After(civilDate(to)); d = d.AddDate(0, 0, 1) {
+		day := CalendarDay{Date: d, Absences: []Absence{}}
+		for _, absence := range absences {
+			if !civilDate(absence.StartDate).After(civilDate(d)) && !civilDate(absence.EndDate).Before(civilDate(d)) {
+				day.Absences = append(day.Absences, absence)
+			}
+		}
+		calendar.Days = append(calendar.Days, day)
+	}
+	return calendar
 }
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUnit(t *testing.T) {
+	day := time.Date(2024, 5, 3, 0, 0, 0, 0, time.Local)
+	nextDay := day.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name  string
+		leave Leave
+		valid bool
+	}{
+		{"full days", Leave{StartDate: day, EndDate: nextDay}, true},
+		{"full day with times", Leave{StartDate: day, EndDate: day, StartTime: "09:00", EndTime: "12:00"}, false},
+		{"morning", Leave{Unit: LeaveUnitAM, StartDate: day, EndDate: day}, true},
+		{"afternoon over days", Leave{Unit: LeaveUnitPM, StartDate: day, EndDate: nextDay}, false},
+		{"afternoon with times", Leave{Unit: LeaveUnitPM, StartDate: day, EndDate: day, StartTime: "13:00"}, false},
+		{"hours", Leave{Unit: LeaveUnitHours, StartDate: day, EndDate: day, StartTime: "09:00", EndTime: "11:30"}, true},
+		{"hours over days",
+			Leave{Unit: LeaveUnitHours, StartDate: day, EndDate: nextDay, StartTime: "09:00", EndTime: "11:00"}, false},
+		{"hours without times", Leave{Unit: LeaveUnitHours, StartDate: day, EndDate: day}, false},
+		{"hours backwards", Leave{Unit: LeaveUnitHours, StartDate: day, EndDate: day, StartTime: "11:00", EndTime: "09:00"},
+			false},
+		{"hours over a day", Leave{Unit: LeaveUnitHours, StartDate: day, EndDate: day, StartTime: "08:00", EndTime: "17:30"},
+			false},
+		{"unknown unit", Leave{Unit: "week", StartDate: day, EndDate: day}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.leave.ValidateUnit()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestDayFraction(t *testing.T) {
+	assert.Equal(t, 1.0, (&Leave{}).DayFraction())
+	assert.Equal(t, 0.5, (&Leave{Unit: LeaveUnitAM}).DayFraction())
+	assert.Equal(t, 0.31, (&Leave{Unit: LeaveUnitHours, StartTime: "09:00", EndTime: "11:30"}).DayFraction())
+}
+
+func TestOverlaps(t *testing.T) {
+	morning := &Leave{Unit: LeaveUnitAM}
+	afternoon := &Leave{Unit: LeaveUnitPM}
+	fullDay := &Leave{Unit: LeaveUnitFullDay}
+
+	assert.False(t, morning.Overlaps(afternoon))
+	assert.True(t, morning.Overlaps(fullDay))
+	assert.True(t, afternoon.Overlaps(&Leave{Unit: LeaveUnitHours, StartTime: "11:00", EndTime: "13:00"}))
+	assert.False(t, afternoon.Overlaps(&Leave{Unit: LeaveUnitHours, StartTime: "09:00", EndTime: "12:00"}))
+}
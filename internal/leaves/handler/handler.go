@@ -1,7 +1,7 @@
 package handler
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,67 +9,145 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"hr-system/internal/common"
-	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/common/cursor"
 	"hr-system/internal/leaves/domain"
 	"hr-system/internal/leaves/service"
 	"hr-system/internal/middleware"
+	review_domain "hr-system/internal/reviews/domain"
 )
 
 type LeaveHandler struct {
 	leaveService service.LeaveService
+	cursors      *cursor.Codec
 	logger       *common.Logger
 }
 
-func NewLeaveHandler(logger *common.Logger, leaveService service.LeaveService) *LeaveHandler {
+func NewLeaveHandler(logger *common.Logger, leaveService service.LeaveService, cursors *cursor.Codec) *LeaveHandler {
 	return &LeaveHandler{
 		leaveService: leaveService,
+		cursors:      cursors,
 		logger:       logger,
 	}
 }
 
 type CreateLeaveRequest struct {
-	EmployeeID int       `json:"employee_id" binding:"required"`
-	Type       string    `json:"type" binding:"required"`
-	StartDate  time.Time `json:"start_date" binding:"required"`
-	EndDate    time.Time `json:"end_date" binding:"required"`
-	Reason     string    `json:"reason"`
+	Type      string    `json:"type" binding:"required"`
+	StartDate time.Time `json:"start_date" binding:"required"`
+	EndDate   time.Time `json:"end_date" binding:"required"`
+	// Unit is full_day by default, am, pm and hours take a part of a single day
+	Unit string `json:"unit" binding:"omitempty,oneof=full_day am pm hours"`
+	// StartTime and EndTime ("15:04") are required by an hourly leave only
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Reason    string `json:"reason"`
+	// AttachmentIDs are uploaded beforehand, a leave type may not be submitted without them
+	AttachmentIDs []int `json:"attachment_ids"`
+}
+
+// toLeave returns the leave of the request, it fails when the unit does not agree with the dates and times
+func (r *CreateLeaveRequest) toLeave(employeeID int) (*domain.Leave, error) {
+	leave := &domain.Leave{
+		EmployeeID: employeeID,
+		Type:       domain.LeaveType(r.Type),
+		StartDate:  r.StartDate,
+		EndDate:    r.EndDate,
+		Unit:       domain.LeaveUnit(r.Unit),
+		StartTime:  r.StartTime,
+		EndTime:    r.EndTime,
+		Reason:     r.Reason,
+		// only a new leave takes them, a dry run ignores them
+		AttachmentIDs: r.AttachmentIDs,
+	}
+	if err := leave.ValidateUnit(); err != nil {
+		return nil, err
+	}
+	return leave, nil
 }
 
 func (h *LeaveHandler) CreateLeave(c *gin.Context) {
-	// TODO: check employee rest leaves & leave date conflict
 	ctx := c.Request.Context()
 
+	actor, ok := middleware.GetActor(c)
+	if !ok {
+		return
+	}
+
 	var req CreateLeaveRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
 		return
 	}
 
-	leave, err := h.leaveService.CreateLeave(ctx, &domain.Leave{
-		EmployeeID: req.EmployeeID,
-		Type:       domain.LeaveType(req.Type),
-		StartDate:  req.StartDate,
-		EndDate:    req.EndDate,
-		Reason:     req.Reason,
-	})
+	request, err := req.toLeave(actor.EmployeeID)
 	if err != nil {
-		if errors.Is(err, common_errors.ErrResourceNotFound) {
-			c.JSON(http.StatusNotFound, middleware.CreateErrResp("employee not found"))
-		} else if errors.Is(err, common_errors.ErrInvalidInput) {
-			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid input, cause: %v", err))
-		} else {
-			c.JSON(http.StatusInternalServerError, middleware.CreateErrResp("Failed to create leave: %v", err))
-		}
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	leave, err := h.leaveService.CreateLeave(ctx, request)
+	if err != nil {
+		middleware.HandleErr(c, err, "create leave")
 		return
 	}
 
 	c.JSON(http.StatusCreated, &leave)
 }
 
+type DryRunApprovalChainRequest struct {
+	CreateLeaveRequest
+	EmployeeID int `json:"employee_id"` // the caller by default
+}
+
+func (h *LeaveHandler) DryRunApprovalChain(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, ok := middleware.GetActor(c)
+	if !ok {
+		return
+	}
+
+	var req DryRunApprovalChainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+	if req.EmployeeID == 0 {
+		req.EmployeeID = actor.EmployeeID
+	}
+
+	request, err := req.toLeave(req.EmployeeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	chain, err := h.leaveService.PreviewApprovalChain(ctx, request)
+	if err != nil {
+		middleware.HandleErr(c, err, "preview approval chain")
+		return
+	}
+
+	c.JSON(http.StatusOK, &chain)
+}
+
+// GetUnsatisfiableChains reports the employees whose leaves can not be approved by their manager chain
+func (h *LeaveHandler) GetUnsatisfiableChains(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	chains, err := h.leaveService.GetUnsatisfiableChains(ctx)
+	if err != nil {
+		middleware.HandleErr(c, err, "get unsatisfiable chains")
+		return
+	}
+
+	c.JSON(http.StatusOK, chains)
+}
+
 type ReviewLeaveRequest struct {
-	ReviewerID int                 `json:"reviewer_id" binding:"required"`
-	Decision   domain.ReviewStatus `json:"decision" binding:"required,oneof=approved rejected"`
-	Comment    string              `json:"comment"`
+	Decision review_domain.ReviewStatus `json:"decision" binding:"required,oneof=approved rejected"`
+	Comment  string                     `json:"comment"`
+	// Override approves the leave although it breaks the coverage rule of the team, if the rule allows it
+	Override bool `json:"override"`
 }
 
 func (h *LeaveHandler) ReviewLeave(c *gin.Context) {
@@ -81,6 +159,11 @@ func (h *LeaveHandler) ReviewLeave(c *gin.Context) {
 		return
 	}
 
+	actor, ok := middleware.GetActor(c)
+	if !ok {
+		return
+	}
+
 	var req ReviewLeaveRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Errorf("Failed to bind review leave request: %v", err)
@@ -88,16 +171,41 @@ func (h *LeaveHandler) ReviewLeave(c *gin.Context) {
 		return
 	}
 
-	// TODO: Use JWT to get the reviewer ID
-	err = h.leaveService.ReviewLeave(ctx, leaveID, req.ReviewerID, req.Decision, req.Comment)
+	err = h.leaveService.ReviewLeave(ctx, leaveID, actor.EmployeeID, req.Decision, req.Comment, req.Override)
 	if err != nil {
-		if errors.Is(err, common_errors.ErrResourceNotFound) {
-			c.JSON(http.StatusNotFound, middleware.CreateErrResp("leave not found, cause: %v", err))
-		} else if errors.Is(err, common_errors.ErrInvalidInput) {
-			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid input, cause: %v", err))
-		} else {
-			c.JSON(http.StatusInternalServerError, middleware.CreateErrResp("failed to review leave, cause: %v", err))
-		}
+		middleware.HandleErr(c, err, "review leave")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *LeaveHandler) CancelLeave(c *gin.Context) {
+	h.withdrawLeave(c, "cancel", h.leaveService.CancelLeave)
+}
+
+func (h *LeaveHandler) RevokeLeave(c *gin.Context) {
+	h.withdrawLeave(c, "revoke", h.leaveService.RevokeLeave)
+}
+
+// withdrawLeave handles the requests of an employee giving up their own leave
+func (h *LeaveHandler) withdrawLeave(c *gin.Context, action string,
+	withdraw func(ctx context.Context, leaveID, employeeID int) error) {
+	ctx := c.Request.Context()
+
+	leaveID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid leave ID"))
+		return
+	}
+
+	actor, ok := middleware.GetActor(c)
+	if !ok {
+		return
+	}
+
+	if err := withdraw(ctx, leaveID, actor.EmployeeID); err != nil {
+		middleware.HandleErr(c, err, action+" leave")
 		return
 	}
 
@@ -128,17 +236,84 @@ func (h *LeaveHandler) GetLeaves(c *gin.Context) {
 		query.CurrentReviewerID = &id
 	}
 
-	leaves, err := h.leaveService.GetLeaves(ctx, query)
+	managerID := c.Query("manager_id")
+	if managerID != "" {
+		id, err := strconv.Atoi(managerID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid manager_id"))
+			return
+		}
+		query.ManagerID = &id
+	}
+
+	// the values of the lists are validated by the service
+	for _, status := range middleware.GetListQuery(c, "status") {
+		query.Statuses = append(query.Statuses, review_domain.ReviewStatus(status))
+	}
+	for _, leaveType := range middleware.GetListQuery(c, "type") {
+		query.Types = append(query.Types, domain.LeaveType(leaveType))
+	}
+	query.Sort = domain.ParseSort(c.Query("sort"))
+
+	var ok bool
+	if query.From, ok = middleware.GetDateQuery(c, "from"); !ok {
+		return
+	}
+	if query.To, ok = middleware.GetDateQuery(c, "to"); !ok {
+		return
+	}
+	if query.CreatedFrom, ok = middleware.GetDateQuery(c, "created_from"); !ok {
+		return
+	}
+	if query.CreatedTo, ok = middleware.GetDateQuery(c, "created_to"); !ok {
+		return
+	}
+	if query.CreatedTo != nil {
+		// created_to is inclusive, the leaves created on the day are kept
+		query.CreatedTo = common.GetPtr(query.CreatedTo.AddDate(0, 0, 1))
+	}
+
+	// without a cursor or a limit every leave of the scope is returned as it always was
+	params, ok := middleware.GetCursorParams(c, 20)
+	if !ok {
+		return
+	}
+	if query.WithCount, ok = middleware.GetBoolQuery(c, "count", false); !ok {
+		return
+	}
+	if params.Enabled {
+		query.Limit = params.Limit
+		if params.Cursor != "" {
+			query.After = &domain.LeaveCursor{}
+			if err := h.cursors.Decode(params.Cursor, query.After); err != nil {
+				c.JSON(http.StatusBadRequest, middleware.CreateErrResp(err.Error()))
+				return
+			}
+		}
+	}
+
+	page, err := h.leaveService.GetLeaves(ctx, query)
 	if err != nil {
-		if errors.Is(err, common_errors.ErrInvalidInput) {
-			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid input, cause: %v", err))
-		} else {
-			c.JSON(http.StatusInternalServerError, middleware.CreateErrResp("failed to get leaves: %v", err))
+		middleware.HandleErr(c, err, "get leaves")
+		return
+	}
+
+	if params.Enabled {
+		response := cursor.Page[domain.Leave]{Data: page.Leaves, TotalCount: page.TotalCount}
+		if page.Next != nil {
+			if response.NextCursor, err = h.cursors.Encode(page.Next); err != nil {
+				c.JSON(http.StatusInternalServerError, middleware.CreateErrResp("failed to get leaves: %v", err))
+				return
+			}
 		}
+		c.JSON(http.StatusOK, response)
 		return
 	}
 
-	c.JSON(http.StatusOK, leaves)
+	if page.TotalCount != nil {
+		c.Header("X-Total-Count", strconv.Itoa(*page.TotalCount))
+	}
+	c.JSON(http.StatusOK, page.Leaves)
 }
 
 func (h *LeaveHandler) GetLeaveByID(c *gin.Context) {
@@ -150,15 +325,45 @@ func (h *LeaveHandler) GetLeaveByID(c *gin.Context) {
 		return
 	}
 
-	leave, err := h.leaveService.GetLeaveByID(ctx, leaveID)
+	leave, err := h.leaveService.GetLeaveDetail(ctx, leaveID)
 	if err != nil {
-		if errors.Is(err, common_errors.ErrResourceNotFound) {
-			c.JSON(http.StatusNotFound, middleware.CreateErrResp("leave not found, cause: %v", err))
-		} else {
-			c.JSON(http.StatusInternalServerError, middleware.CreateErrResp("failed to get leave: %v", err))
-		}
+		middleware.HandleErr(c, err, "get leave")
 		return
 	}
 
 	c.JSON(http.StatusOK, leave)
 }
+
+// GetTeamCalendar responds the leaves of a manager's reports day by day, the next four weeks by default
+func (h *LeaveHandler) GetTeamCalendar(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	managerID, err := strconv.Atoi(c.Param("manager_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid manager ID"))
+		return
+	}
+	from, ok := middleware.GetDateQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := middleware.GetDateQuery(c, "to")
+	if !ok {
+		return
+	}
+	if from == nil {
+		now := time.Now()
+		from = common.GetPtr(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local))
+	}
+	if to == nil {
+		to = common.GetPtr(from.AddDate(0, 0, 27))
+	}
+
+	calendar, err := h.leaveService.GetTeamCalendar(ctx, managerID, *from, *to)
+	if err != nil {
+		middleware.HandleErr(c, err, "get team calendar")
+		return
+	}
+
+	c.JSON(http.StatusOK, calendar)
+}
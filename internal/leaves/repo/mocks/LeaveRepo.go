@@ -6,8 +6,11 @@ import (
 	context "context"
 	employeesrepo "hr-system/internal/employees/repo"
 	domain "hr-system/internal/leaves/domain"
+	review_domain "hr-system/internal/reviews/domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // LeaveRepo is an autogenerated mock type for the LeaveRepo type
@@ -15,6 +18,34 @@ type LeaveRepo struct {
 	mock.Mock
 }
 
+// CountLeaves provides a mock function with given fields: ctx, query
+func (_m *LeaveRepo) CountLeaves(ctx context.Context, query domain.LeavesQuery) (int, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountLeaves")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LeavesQuery) (int, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LeavesQuery) int); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.LeavesQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateLeave provides a mock function with given fields: ctx, leave
 func (_m *LeaveRepo) CreateLeave(ctx context.Context, leave *domain.Leave) error {
 	ret := _m.Called(ctx, leave)
@@ -91,6 +122,126 @@ func (_m *LeaveRepo) GetLeaves(ctx context.Context, query domain.LeavesQuery) ([
 	return r0, r1
 }
 
+// GetLeavesPendingOn provides a mock function with given fields: ctx, reviewerID
+func (_m *LeaveRepo) GetLeavesPendingOn(ctx context.Context, reviewerID int) ([]domain.Leave, error) {
+	ret := _m.Called(ctx, reviewerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLeavesPendingOn")
+	}
+
+	var r0 []domain.Leave
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.Leave, error)); ok {
+		return rf(ctx, reviewerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.Leave); ok {
+		r0 = rf(ctx, reviewerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Leave)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, reviewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLeavesWithStaleReviews provides a mock function with given fields: ctx, before
+func (_m *LeaveRepo) GetLeavesWithStaleReviews(ctx context.Context, before time.Time) ([]domain.Leave, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLeavesWithStaleReviews")
+	}
+
+	var r0 []domain.Leave
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]domain.Leave, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []domain.Leave); ok {
+		r0 = rf(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Leave)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLeavesWithoutDays provides a mock function with given fields: ctx
+func (_m *LeaveRepo) GetLeavesWithoutDays(ctx context.Context) ([]domain.Leave, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLeavesWithoutDays")
+	}
+
+	var r0 []domain.Leave
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Leave, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Leave); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Leave)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOverlappingLeaves provides a mock function with given fields: ctx, employeeID, startDate, endDate, statuses
+func (_m *LeaveRepo) GetOverlappingLeaves(ctx context.Context, employeeID int, startDate time.Time, endDate time.Time, statuses []review_domain.ReviewStatus) ([]domain.Leave, error) {
+	ret := _m.Called(ctx, employeeID, startDate, endDate, statuses)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOverlappingLeaves")
+	}
+
+	var r0 []domain.Leave
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time, []review_domain.ReviewStatus) ([]domain.Leave, error)); ok {
+		return rf(ctx, employeeID, startDate, endDate, statuses)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time, []review_domain.ReviewStatus) []domain.Leave); ok {
+		r0 = rf(ctx, employeeID, startDate, endDate, statuses)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Leave)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, time.Time, []review_domain.ReviewStatus) error); ok {
+		r1 = rf(ctx, employeeID, startDate, endDate, statuses)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SeedData provides a mock function with given fields: ctx, employeeRepo
 func (_m *LeaveRepo) SeedData(ctx context.Context, employeeRepo employeesrepo.EmployeeRepo) error {
 	ret := _m.Called(ctx, employeeRepo)
@@ -110,7 +261,7 @@ func (_m *LeaveRepo) SeedData(ctx context.Context, employeeRepo employeesrepo.Em
 }
 
 // UpdateLeaveAndReviews provides a mock function with given fields: ctx, leave, reviews
-func (_m *LeaveRepo) UpdateLeaveAndReviews(ctx context.Context, leave *domain.Leave, reviews []domain.LeaveReview) error {
+func (_m *LeaveRepo) UpdateLeaveAndReviews(ctx context.Context, leave *domain.Leave, reviews []review_domain.Review) error {
 	ret := _m.Called(ctx, leave, reviews)
 
 	if len(ret) == 0 {
@@ -118,7 +269,7 @@ func (_m *LeaveRepo) UpdateLeaveAndReviews(ctx context.Context, leave *domain.Le
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Leave, []domain.LeaveReview) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Leave, []review_domain.Review) error); ok {
 		r0 = rf(ctx, leave, reviews)
 	} else {
 		r0 = ret.Error(0)
@@ -127,6 +278,24 @@ func (_m *LeaveRepo) UpdateLeaveAndReviews(ctx context.Context, leave *domain.Le
 	return r0
 }
 
+// UpdateLeaveDays provides a mock function with given fields: ctx, id, days
+func (_m *LeaveRepo) UpdateLeaveDays(ctx context.Context, id int, days float64) error {
+	ret := _m.Called(ctx, id, days)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLeaveDays")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, float64) error); ok {
+		r0 = rf(ctx, id, days)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewLeaveRepo creates a new instance of LeaveRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewLeaveRepo(t interface {
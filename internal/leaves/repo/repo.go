@@ -4,12 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"hr-system/internal/common/cursor"
 	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/common/transaction"
 	employee_repo "hr-system/internal/employees/repo"
 	"hr-system/internal/leaves/domain"
+	review_domain "hr-system/internal/reviews/domain"
 )
 
 type LeaveRepo interface {
@@ -17,8 +22,23 @@ type LeaveRepo interface {
 	SeedData(ctx context.Context, employeeRepo employee_repo.EmployeeRepo) error
 	CreateLeave(ctx context.Context, leave *domain.Leave) error
 	GetLeaveByID(ctx context.Context, id int) (domain.Leave, error)
+	// GetLeaves returns the leaves of the query in its sort, after the cursor of the query if there is one
 	GetLeaves(ctx context.Context, query domain.LeavesQuery) ([]domain.Leave, error)
-	UpdateLeaveAndReviews(ctx context.Context, leave *domain.Leave, reviews []domain.LeaveReview) error
+	// CountLeaves counts the leaves of the query regardless of its page
+	CountLeaves(ctx context.Context, query domain.LeavesQuery) (int, error)
+	UpdateLeaveAndReviews(ctx context.Context, leave *domain.Leave, reviews []review_domain.Review) error
+	// GetOverlappingLeaves returns the leaves of the employee in the given statuses which intersect the date range
+	GetOverlappingLeaves(ctx context.Context, employeeID int, startDate, endDate time.Time,
+		statuses []review_domain.ReviewStatus) ([]domain.Leave, error)
+	// GetLeavesWithStaleReviews returns the leaves whose pending review was created before the time
+	GetLeavesWithStaleReviews(ctx context.Context, before time.Time) ([]domain.Leave, error)
+	// GetLeavesPendingOn returns the leaves whose pending review is on the reviewer, whether they review it
+	// themselves, as a delegate or through their own delegate
+	GetLeavesPendingOn(ctx context.Context, reviewerID int) ([]domain.Leave, error)
+	// GetLeavesWithoutDays returns the leaves created before their working days were counted
+	GetLeavesWithoutDays(ctx context.Context) ([]domain.Leave, error)
+	// UpdateLeaveDays sets the working days of the leave
+	UpdateLeaveDays(ctx context.Context, id int, days float64) error
 }
 
 type leaveRepo struct {
@@ -49,14 +69,42 @@ func (r *leaveRepo) ensureSchema() error {
 	if err := r.db.AutoMigrate(domain.Leave{}); err != nil {
 		return err
 	}
-	if err := r.db.AutoMigrate(domain.LeaveReview{}); err != nil {
+	if err := r.db.AutoMigrate(review_domain.Review{}); err != nil {
 		return err
 	}
+	return r.moveLegacyReviews()
+}
+
+// legacyReviewsTable kept the reviews of the leaves before the TOIL requests shared the table of the reviews
+const legacyReviewsTable = "leave_reviews"
+
+// moveLegacyReviews copies the reviews of the legacy table once, in their order, the legacy table is left for the
+// operators to drop
+func (r *leaveRepo) moveLegacyReviews() error {
+	if !r.db.Migrator().HasTable(legacyReviewsTable) {
+		return nil
+	}
+	var moved int64
+	if err := r.db.Model(&review_domain.Review{}).Where("subject_type = ?", review_domain.SubjectTypeLeave).
+		Count(&moved).Error; err != nil {
+		return fmt.Errorf("failed to count reviews of leaves: %w", err)
+	}
+	if moved > 0 {
+		return nil
+	}
+
+	columns := "reviewer_id, on_behalf_of_id, status, comment, reviewed_at, revocation, escalated, unescalatable, " +
+		"coverage_override, overridden_by_id, created_at, updated_at"
+	err := r.db.Exec("INSERT INTO reviews (subject_type, subject_id, "+columns+") "+
+		"SELECT ?, leave_id, "+columns+" FROM "+legacyReviewsTable+" ORDER BY id", review_domain.SubjectTypeLeave).Error
+	if err != nil {
+		return fmt.Errorf("failed to move reviews of leaves: %w", err)
+	}
 	return nil
 }
 
 func (r *leaveRepo) CreateLeave(ctx context.Context, leave *domain.Leave) error {
-	if err := r.db.WithContext(ctx).Create(leave).Error; err != nil {
+	if err := transaction.DB(ctx, r.db).Create(leave).Error; err != nil {
 		return fmt.Errorf("failed to create leave: %w", err)
 	}
 	return nil
@@ -70,7 +118,7 @@ func preloadReviews(db *gorm.DB) *gorm.DB {
 
 func (r *leaveRepo) GetLeaveByID(ctx context.Context, id int) (domain.Leave, error) {
 	var leave domain.Leave
-	db := r.db.WithContext(ctx)
+	db := transaction.DB(ctx, r.db)
 	db = preloadReviews(db)
 	if err := db.First(&leave, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -81,26 +129,9 @@ func (r *leaveRepo) GetLeaveByID(ctx context.Context, id int) (domain.Leave, err
 	return leave, nil
 }
 
-func doTrans(db *gorm.DB, op func(*gorm.DB) error) (err error) {
-	tx := db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-			panic(r)
-		}
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	if err := op(tx); err != nil {
-		return err
-	}
-	return tx.Commit().Error
-}
-
-func (r *leaveRepo) UpdateLeaveAndReviews(ctx context.Context, leave *domain.Leave, reviews []domain.LeaveReview) error {
-	err := doTrans(r.db.WithContext(ctx), func(tx *gorm.DB) error {
+func (r *leaveRepo) UpdateLeaveAndReviews(ctx context.Context, leave *domain.Leave,
+	reviews []review_domain.Review) error {
+	err := transaction.Run(ctx, r.db, func(tx *gorm.DB) error {
 		result := tx.Save(leave)
 		if result.Error != nil {
 			return fmt.Errorf("failed to update leave: %w", result.Error)
@@ -110,6 +141,8 @@ func (r *leaveRepo) UpdateLeaveAndReviews(ctx context.Context, leave *domain.Lea
 		}
 
 		for _, review := range reviews {
+			review.SubjectType = review_domain.SubjectTypeLeave
+			review.SubjectID = leave.ID
 			if review.ID == 0 {
 				// if review.ID == 0, it's a new review
 				result := tx.Create(&review)
@@ -134,20 +167,191 @@ func (r *leaveRepo) UpdateLeaveAndReviews(ctx context.Context, leave *domain.Lea
 	return nil
 }
 
-func (r *leaveRepo) GetLeaves(ctx context.Context, query domain.LeavesQuery) ([]domain.Leave, error) {
-	// TODO: pagination
-	var leaves []domain.Leave
-
-	db := r.db.WithContext(ctx).Preload("Reviews")
+func filterLeaves(db *gorm.DB, query domain.LeavesQuery) *gorm.DB {
 	if query.EmployeeID != nil {
 		db = db.Where("employee_id = ?", *query.EmployeeID)
 	}
+	if query.EmployeeIDs != nil {
+		db = db.Where("employee_id IN ?", query.EmployeeIDs)
+	}
 	if query.CurrentReviewerID != nil {
-		db = db.Where("current_reviewer_id = ?", *query.CurrentReviewerID)
+		if len(query.DelegatorIDs) > 0 {
+			db = db.Where("(current_reviewer_id = ? OR (current_reviewer_id IN ? AND employee_id <> ?))",
+				*query.CurrentReviewerID, query.DelegatorIDs, *query.CurrentReviewerID)
+		} else {
+			db = db.Where("current_reviewer_id = ?", *query.CurrentReviewerID)
+		}
+	}
+	if len(query.Statuses) > 0 {
+		db = db.Where("status IN ?", query.Statuses)
+	}
+	if len(query.Types) > 0 {
+		db = db.Where("type IN ?", query.Types)
+	}
+	if query.From != nil {
+		db = db.Where("end_date >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("start_date <= ?", *query.To)
+	}
+	if query.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		db = db.Where("created_at < ?", *query.CreatedTo)
+	}
+	return db
+}
+
+// leaveSortColumns maps the sortable fields of the leaves to their columns
+var leaveSortColumns = map[string]string{
+	"id":         "id",
+	"start_date": "start_date",
+	"end_date":   "end_date",
+	"created_at": "created_at",
+}
+
+// leaveKeys returns the columns the leaves are ordered by, the sort and then the latest first,
+// with their values in the cursor if there is one
+func leaveKeys(sort []domain.SortField, after *domain.LeaveCursor) ([]cursor.Key, error) {
+	values := map[string]interface{}{}
+	if after != nil {
+		values["id"] = after.ID
+		for field, value := range map[string]*time.Time{
+			"start_date": after.StartDate,
+			"end_date":   after.EndDate,
+			"created_at": after.CreatedAt,
+		} {
+			if value != nil {
+				values[field] = *value
+			}
+		}
+	}
+
+	var keys []cursor.Key
+	for _, field := range append(sort[:len(sort):len(sort)], domain.SortField{Field: "id", Desc: true}) {
+		column, ok := leaveSortColumns[field.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w, can not sort by %s", common_errors.ErrInvalidInput, field.Field)
+		}
+		keys = append(keys, cursor.Key{Column: column, Desc: field.Desc, Value: values[field.Field]})
+		if field.Field == "id" {
+			// the IDs are unique, the fields after it never break a tie
+			break
+		}
+	}
+	return keys, nil
+}
+
+func (r *leaveRepo) GetLeaves(ctx context.Context, query domain.LeavesQuery) ([]domain.Leave, error) {
+	var leaves []domain.Leave
+
+	keys, err := leaveKeys(query.Sort, query.After)
+	if err != nil {
+		return nil, err
+	}
+	db := filterLeaves(preloadReviews(transaction.DB(ctx, r.db)), query)
+	if query.After != nil {
+		condition, args := cursor.Condition(keys)
+		db = db.Where(condition, args...)
+	}
+	columns := make([]clause.OrderByColumn, 0, len(keys))
+	for _, key := range keys {
+		columns = append(columns, clause.OrderByColumn{Column: clause.Column{Name: key.Column}, Desc: key.Desc})
+	}
+	db = db.Order(clause.OrderBy{Columns: columns})
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
 	}
-	if err := db.Order("id desc").Find(&leaves).Error; err != nil {
+	if err := db.Find(&leaves).Error; err != nil {
 		return nil, fmt.Errorf("failed to get leaves: %w", err)
 	}
 
 	return leaves, nil
 }
+
+func (r *leaveRepo) CountLeaves(ctx context.Context, query domain.LeavesQuery) (int, error) {
+	var count int64
+	if err := filterLeaves(transaction.DB(ctx, r.db).Model(&domain.Leave{}), query).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count leaves: %w", err)
+	}
+	return int(count), nil
+}
+
+func (r *leaveRepo) GetOverlappingLeaves(ctx context.Context, employeeID int, startDate, endDate time.Time,
+	statuses []review_domain.ReviewStatus) ([]domain.Leave, error) {
+	var leaves []domain.Leave
+
+	err := transaction.DB(ctx, r.db).
+		Where("employee_id = ?", employeeID).
+		Where("start_date <= ? AND end_date >= ?", endDate, startDate).
+		Where("status IN ?", statuses).
+		Order("id ASC").
+		Find(&leaves).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overlapping leaves: %w", err)
+	}
+
+	return leaves, nil
+}
+
+// reviewedStatuses are the statuses of the leaves waiting for a review
+var reviewedStatuses = []review_domain.ReviewStatus{
+	review_domain.ReviewStatusReviewing,
+	review_domain.ReviewStatusRevoking,
+}
+
+func (r *leaveRepo) GetLeavesWithStaleReviews(ctx context.Context, before time.Time) ([]domain.Leave, error) {
+	var leaves []domain.Leave
+
+	staleReviews := r.db.Model(&review_domain.Review{}).
+		Select("subject_id").
+		Where("subject_type = ?", review_domain.SubjectTypeLeave).
+		Where("status = ? AND created_at < ? AND unescalatable = ?", review_domain.ReviewStatusReviewing, before, false)
+	err := preloadReviews(transaction.DB(ctx, r.db)).
+		Where("status IN ?", reviewedStatuses).
+		Where("id IN (?)", staleReviews).
+		Order("id ASC").
+		Find(&leaves).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaves with stale reviews: %w", err)
+	}
+
+	return leaves, nil
+}
+
+func (r *leaveRepo) GetLeavesPendingOn(ctx context.Context, reviewerID int) ([]domain.Leave, error) {
+	var leaves []domain.Leave
+
+	pendingReviews := r.db.Model(&review_domain.Review{}).
+		Select("subject_id").
+		Where("subject_type = ?", review_domain.SubjectTypeLeave).
+		Where("status = ? AND (reviewer_id = ? OR on_behalf_of_id = ?)", review_domain.ReviewStatusReviewing, reviewerID,
+			reviewerID)
+	err := preloadReviews(transaction.DB(ctx, r.db)).
+		Where("status IN ?", reviewedStatuses).
+		Where("id IN (?)", pendingReviews).
+		Order("id ASC").
+		Find(&leaves).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaves pending on %d: %w", reviewerID, err)
+	}
+
+	return leaves, nil
+}
+
+func (r *leaveRepo) GetLeavesWithoutDays(ctx context.Context) ([]domain.Leave, error) {
+	var leaves []domain.Leave
+	if err := transaction.DB(ctx, r.db).Where("days = 0").Order("id ASC").Find(&leaves).Error; err != nil {
+		return nil, fmt.Errorf("failed to get leaves without days: %w", err)
+	}
+	return leaves, nil
+}
+
+func (r *leaveRepo) UpdateLeaveDays(ctx context.Context, id int, days float64) error {
+	err := transaction.DB(ctx, r.db).Model(&domain.Leave{}).Where("id = ?", id).Update("days", days).Error
+	if err != nil {
+		return fmt.Errorf("failed to update days of leave %d: %w", id, err)
+	}
+	return nil
+}
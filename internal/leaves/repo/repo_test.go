@@ -3,12 +3,15 @@ package repo
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
+	"hr-system/internal/common"
 	"hr-system/internal/leaves/domain"
+	review_domain "hr-system/internal/reviews/domain"
 )
 
 func setupTestDB() (*gorm.DB, error) {
@@ -16,7 +19,7 @@ func setupTestDB() (*gorm.DB, error) {
 	if err != nil {
 		return nil, err
 	}
-	if err := db.AutoMigrate(&domain.Leave{}, &domain.LeaveReview{}); err != nil {
+	if err := db.AutoMigrate(&domain.Leave{}, &review_domain.Review{}); err != nil {
 		return nil, err
 	}
 	return db, nil
@@ -56,19 +59,19 @@ func TestUpdateLeaveAndReviews(t *testing.T) {
 
 	repo := &leaveRepo{db: db}
 	leave := &domain.Leave{EmployeeID: 2, Reason: "Vacation",
-		Reviews: []domain.LeaveReview{
+		Reviews: []review_domain.Review{
 			{
 				ReviewerID: 1,
-				Status:     domain.ReviewStatusReviewing,
+				Status:     review_domain.ReviewStatusReviewing,
 			},
 		},
 	}
 	err = repo.CreateLeave(context.Background(), leave)
 	assert.NoError(t, err)
 
-	leave.Status = domain.ReviewStatusApproved
-	reviews := []domain.LeaveReview{leave.Reviews[0]}
-	reviews[0].Status = domain.ReviewStatusApproved
+	leave.Status = review_domain.ReviewStatusApproved
+	reviews := []review_domain.Review{leave.Reviews[0]}
+	reviews[0].Status = review_domain.ReviewStatusApproved
 
 	err = repo.UpdateLeaveAndReviews(context.Background(), leave, reviews)
 	assert.NoError(t, err)
@@ -100,3 +103,267 @@ func TestGetLeaves(t *testing.T) {
 	assert.Equal(t, leave1.EmployeeID, leaves[0].EmployeeID)
 	assert.Equal(t, leave1.Reason, leaves[0].Reason)
 }
+
+func TestGetLeaves_Page(t *testing.T) {
+	db, err := setupTestDB()
+	assert.NoError(t, err)
+
+	repo := &leaveRepo{db: db}
+	ctx := context.Background()
+	reviewerID, managerID := 5, 2
+	// the reviewer's own leave waiting for the manager is not theirs to review
+	for _, leave := range []*domain.Leave{
+		{EmployeeID: 1, CurrentReviewerID: &reviewerID},
+		{EmployeeID: 3, CurrentReviewerID: &managerID},
+		{EmployeeID: reviewerID, CurrentReviewerID: &managerID},
+		{EmployeeID: 4, CurrentReviewerID: &reviewerID},
+		{EmployeeID: 6, CurrentReviewerID: common.GetPtr(7)},
+	} {
+		assert.NoError(t, repo.CreateLeave(ctx, leave))
+	}
+
+	query := domain.LeavesQuery{CurrentReviewerID: &reviewerID, DelegatorIDs: []int{managerID}, Limit: 2}
+	leaves, err := repo.GetLeaves(ctx, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 2}, leaveIDs(leaves))
+
+	query.After = &domain.LeaveCursor{ID: 2}
+	leaves, err = repo.GetLeaves(ctx, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, leaveIDs(leaves))
+
+	count, err := repo.CountLeaves(ctx, query)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestGetLeaves_Filters(t *testing.T) {
+	db, err := setupTestDB()
+	assert.NoError(t, err)
+
+	repo := &leaveRepo{db: db}
+	ctx := context.Background()
+	day := func(d int) time.Time {
+		return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC)
+	}
+	for _, leave := range []*domain.Leave{
+		{EmployeeID: 1, Type: domain.LeaveTypeAnnual, StartDate: day(1), EndDate: day(3),
+			Status: review_domain.ReviewStatusApproved, CreatedAt: day(1)},
+		{EmployeeID: 2, Type: domain.LeaveTypeSick, StartDate: day(5), EndDate: day(5),
+			Status: review_domain.ReviewStatusReviewing, CreatedAt: day(2)},
+		{EmployeeID: 3, Type: domain.LeaveTypeAnnual, StartDate: day(4), EndDate: day(6),
+			Status: review_domain.ReviewStatusRejected, CreatedAt: day(3)},
+		{EmployeeID: 2, Type: domain.LeaveTypeAnnual, StartDate: day(8), EndDate: day(10),
+			Status: review_domain.ReviewStatusApproved, CreatedAt: day(4)},
+	} {
+		assert.NoError(t, repo.CreateLeave(ctx, leave))
+	}
+
+	for _, tc := range []struct {
+		name  string
+		query domain.LeavesQuery
+		want  []int
+	}{
+		{"statuses", domain.LeavesQuery{
+			Statuses: []review_domain.ReviewStatus{
+				review_domain.ReviewStatusApproved,
+				review_domain.ReviewStatusRejected,
+			}}, []int{4, 3, 1}},
+		{"types", domain.LeavesQuery{Types: []domain.LeaveType{domain.LeaveTypeSick}}, []int{2}},
+		{"overlapping the range", domain.LeavesQuery{From: common.GetPtr(day(3)), To: common.GetPtr(day(5))},
+			[]int{3, 2, 1}},
+		{"created in the range", domain.LeavesQuery{CreatedFrom: common.GetPtr(day(2)),
+			CreatedTo: common.GetPtr(day(4))}, []int{3, 2}},
+		{"employees", domain.LeavesQuery{EmployeeIDs: []int{2, 3}}, []int{4, 3, 2}},
+		{"empty team", domain.LeavesQuery{EmployeeIDs: []int{}}, []int{}},
+		{"sorted", domain.LeavesQuery{Sort: []domain.SortField{{Field: "start_date"}}}, []int{1, 3, 2, 4}},
+		{"after the cursor", domain.LeavesQuery{Sort: []domain.SortField{{Field: "start_date"}},
+			After: &domain.LeaveCursor{ID: 3, StartDate: common.GetPtr(day(4))}}, []int{2, 4}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			leaves, err := repo.GetLeaves(ctx, tc.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, leaveIDs(leaves))
+		})
+	}
+}
+
+func leaveIDs(leaves []domain.Leave) []int {
+	ids := make([]int, 0, len(leaves))
+	for _, leave := range leaves {
+		ids = append(ids, leave.ID)
+	}
+	return ids
+}
+
+func TestGetOverlappingLeaves(t *testing.T) {
+	db, err := setupTestDB()
+	assert.NoError(t, err)
+
+	repo := &leaveRepo{db: db}
+	day := func(d int) time.Time {
+		return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC)
+	}
+	leaves := []*domain.Leave{
+		{EmployeeID: 1, StartDate: day(1), EndDate: day(3), Status: review_domain.ReviewStatusApproved},
+		{EmployeeID: 1, StartDate: day(5), EndDate: day(5), Status: review_domain.ReviewStatusReviewing},
+		{EmployeeID: 1, StartDate: day(4), EndDate: day(6), Status: review_domain.ReviewStatusRejected},
+		{EmployeeID: 2, StartDate: day(1), EndDate: day(10), Status: review_domain.ReviewStatusApproved},
+	}
+	for _, leave := range leaves {
+		assert.NoError(t, repo.CreateLeave(context.Background(), leave))
+	}
+
+	statuses := []review_domain.ReviewStatus{review_domain.ReviewStatusReviewing, review_domain.ReviewStatusApproved}
+	overlaps, err := repo.GetOverlappingLeaves(context.Background(), 1, day(3), day(5), statuses)
+	assert.NoError(t, err)
+	assert.Len(t, overlaps, 2)
+	assert.Equal(t, leaves[0].ID, overlaps[0].ID)
+	assert.Equal(t, leaves[1].ID, overlaps[1].ID)
+
+	overlaps, err = repo.GetOverlappingLeaves(context.Background(), 1, day(6), day(9), statuses)
+	assert.NoError(t, err)
+	assert.Len(t, overlaps, 0)
+}
+
+func TestGetLeavesWithStaleReviews(t *testing.T) {
+	db, err := setupTestDB()
+	assert.NoError(t, err)
+
+	repo := &leaveRepo{db: db}
+	ctx := context.Background()
+	leaves := []*domain.Leave{
+		{EmployeeID: 1, Status: review_domain.ReviewStatusReviewing,
+			Reviews: []review_domain.Review{{ReviewerID: 2, Status: review_domain.ReviewStatusReviewing}}},
+		{EmployeeID: 1, Status: review_domain.ReviewStatusApproved,
+			Reviews: []review_domain.Review{{ReviewerID: 2, Status: review_domain.ReviewStatusApproved}}},
+		// the reviewer has nobody to escalate it to
+		{EmployeeID: 1, Status: review_domain.ReviewStatusReviewing,
+			Reviews: []review_domain.Review{{ReviewerID: 2, Status: review_domain.ReviewStatusReviewing,
+				Unescalatable: true}}},
+	}
+	for _, leave := range leaves {
+		assert.NoError(t, repo.CreateLeave(ctx, leave))
+	}
+	// the stale review of a TOIL request with the ID of a leave
+	assert.NoError(t, db.Create(&review_domain.Review{SubjectType: review_domain.SubjectTypeTOIL,
+		SubjectID: leaves[2].ID, ReviewerID: 2, Status: review_domain.ReviewStatusReviewing}).Error)
+
+	stale, err := repo.GetLeavesWithStaleReviews(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, stale, 1)
+	assert.Equal(t, leaves[0].ID, stale[0].ID)
+	assert.Len(t, stale[0].Reviews, 1)
+
+	stale, err = repo.GetLeavesWithStaleReviews(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, stale, 0)
+}
+
+func TestNewLeaveRepo_MovesLegacyReviews(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	// the table of the reviews before the TOIL requests shared it
+	type leaveReview struct {
+		ID               int
+		LeaveID          int
+		ReviewerID       int
+		OnBehalfOfID     *int
+		Status           review_domain.ReviewStatus
+		Comment          string
+		ReviewedAt       *time.Time
+		Revocation       bool
+		Escalated        bool
+		Unescalatable    bool
+		CoverageOverride bool
+		OverriddenByID   *int
+		CreatedAt        time.Time
+		UpdatedAt        time.Time
+	}
+	assert.NoError(t, db.Table(legacyReviewsTable).AutoMigrate(&leaveReview{}))
+	assert.NoError(t, db.AutoMigrate(&domain.Leave{}))
+	leave := domain.Leave{EmployeeID: 1, Status: review_domain.ReviewStatusReviewing}
+	assert.NoError(t, db.Omit("Reviews").Create(&leave).Error)
+	legacy := []leaveReview{
+		{LeaveID: leave.ID, ReviewerID: 2, Status: review_domain.ReviewStatusEscalated},
+		{LeaveID: leave.ID, ReviewerID: 5, OnBehalfOfID: common.GetPtr(1), Status: review_domain.ReviewStatusReviewing,
+			Escalated: true},
+	}
+	assert.NoError(t, db.Table(legacyReviewsTable).Create(&legacy).Error)
+
+	repo, err := NewLeaveRepo(db)
+	assert.NoError(t, err)
+	// they are moved once
+	_, err = NewLeaveRepo(db)
+	assert.NoError(t, err)
+
+	fetched, err := repo.GetLeaveByID(context.Background(), leave.ID)
+	assert.NoError(t, err)
+	assert.Len(t, fetched.Reviews, 2)
+	assert.Equal(t, review_domain.ReviewStatusEscalated, fetched.Reviews[0].Status)
+	assert.Equal(t, 5, fetched.Reviews[1].ReviewerID)
+	assert.Equal(t, common.GetPtr(1), fetched.Reviews[1].OnBehalfOfID)
+	assert.True(t, fetched.Reviews[1].Escalated)
+	assert.Equal(t, review_domain.SubjectTypeLeave, fetched.Reviews[1].SubjectType)
+}
+
+func TestGetLeavesPendingOn(t *testing.T) {
+	db, err := setupTestDB()
+	assert.NoError(t, err)
+
+	repo := &leaveRepo{db: db}
+	ctx := context.Background()
+	leaves := []*domain.Leave{
+		{EmployeeID: 1, Status: review_domain.ReviewStatusReviewing,
+			Reviews: []review_domain.Review{{ReviewerID: 2, Status: review_domain.ReviewStatusReviewing}}},
+		// delegated on behalf of the reviewer
+		{EmployeeID: 1, Status: review_domain.ReviewStatusRevoking,
+			Reviews: []review_domain.Review{{ReviewerID: 5, OnBehalfOfID: common.GetPtr(2),
+				Status: review_domain.ReviewStatusReviewing, Revocation: true}}},
+		{EmployeeID: 1, Status: review_domain.ReviewStatusApproved,
+			Reviews: []review_domain.Review{{ReviewerID: 2, Status: review_domain.ReviewStatusApproved}}},
+		{EmployeeID: 1, Status: review_domain.ReviewStatusReviewing,
+			Reviews: []review_domain.Review{{ReviewerID: 3, Status: review_domain.ReviewStatusReviewing}}},
+	}
+	for _, leave := range leaves {
+		assert.NoError(t, repo.CreateLeave(ctx, leave))
+	}
+
+	pending, err := repo.GetLeavesPendingOn(ctx, 2)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+	assert.Equal(t, leaves[0].ID, pending[0].ID)
+	assert.Equal(t, leaves[1].ID, pending[1].ID)
+	assert.Len(t, pending[1].Reviews, 1)
+
+	pending, err = repo.GetLeavesPendingOn(ctx, 5)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, leaves[1].ID, pending[0].ID)
+}
+
+func TestBackfillLeaveDays(t *testing.T) {
+	db, err := setupTestDB()
+	assert.NoError(t, err)
+
+	repo := &leaveRepo{db: db}
+	ctx := context.Background()
+	leaves := []*domain.Leave{{EmployeeID: 1}, {EmployeeID: 1, Days: 3}}
+	for _, leave := range leaves {
+		assert.NoError(t, repo.CreateLeave(ctx, leave))
+	}
+
+	found, err := repo.GetLeavesWithoutDays(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, leaves[0].ID, found[0].ID)
+
+	assert.NoError(t, repo.UpdateLeaveDays(ctx, leaves[0].ID, 1.5))
+	found, err = repo.GetLeavesWithoutDays(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, found, 0)
+	leave, err := repo.GetLeaveByID(ctx, leaves[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, leave.Days)
+}
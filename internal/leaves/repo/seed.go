@@ -6,15 +6,19 @@ import (
 	"time"
 
 	"hr-system/internal/common"
+	employee_domain "hr-system/internal/employees/domain"
 	employee_repo "hr-system/internal/employees/repo"
+	holiday_domain "hr-system/internal/holidays/domain"
 	"hr-system/internal/leaves/domain"
+	review_domain "hr-system/internal/reviews/domain"
 )
 
 func (r *leaveRepo) SeedLeaveData(ctx context.Context, employeeRepo employee_repo.EmployeeRepo) error {
-	employees, _, err := employeeRepo.GetEmployees(ctx, 1, 1000)
+	page, err := employeeRepo.GetEmployees(ctx, employee_domain.EmployeesQuery{Page: 1, PageSize: 1000, SkipCount: true})
 	if err != nil {
 		return fmt.Errorf("failed to fetch employees: %w", err)
 	}
+	employees := page.Employees
 
 	leaves := []domain.Leave{
 		{
@@ -23,7 +27,7 @@ func (r *leaveRepo) SeedLeaveData(ctx context.Context, employeeRepo employee_rep
 			StartDate:  time.Now().AddDate(0, 0, -5), // 5 days ago
 			EndDate:    time.Now().AddDate(0, 0, -3), // 3 days ago
 			Reason:     "Vacation",
-			Status:     domain.ReviewStatusApproved,
+			Status:     review_domain.ReviewStatusApproved,
 		},
 		{
 			EmployeeID: employees[1].ID,
@@ -31,11 +35,11 @@ func (r *leaveRepo) SeedLeaveData(ctx context.Context, employeeRepo employee_rep
 			StartDate:  time.Now().AddDate(0, 0, -7), // 7 days ago
 			EndDate:    time.Now().AddDate(0, 0, -1), // 1 day ago
 			Reason:     "Sick leave",
-			Status:     domain.ReviewStatusRejected,
-			Reviews: []domain.LeaveReview{
+			Status:     review_domain.ReviewStatusRejected,
+			Reviews: []review_domain.Review{
 				{
 					ReviewerID: employees[0].ID,
-					Status:     domain.ReviewStatusRejected,
+					Status:     review_domain.ReviewStatusRejected,
 					Comment:    "Not enough evidence",
 					ReviewedAt: common.GetPtr(time.Now().AddDate(0, 0, -10)),
 				},
@@ -47,19 +51,21 @@ func (r *leaveRepo) SeedLeaveData(ctx context.Context, employeeRepo employee_rep
 			StartDate:         time.Now().AddDate(0, 0, 2),  // 2 days later
 			EndDate:           time.Now().AddDate(0, 0, 20), // 20 days later
 			Reason:            "Medical appointment",
-			Status:            domain.ReviewStatusReviewing,
+			Status:            review_domain.ReviewStatusReviewing,
 			CurrentReviewerID: common.GetPtr(employees[2].ID),
-			Reviews: []domain.LeaveReview{
+			Reviews: []review_domain.Review{
 				{
 					ReviewerID: employees[2].ID,
-					Status:     domain.ReviewStatusReviewing,
+					Status:     review_domain.ReviewStatusReviewing,
 				},
 			},
 		},
 	}
 
+	week := holiday_domain.WorkingWeek{Region: holiday_domain.DefaultRegion, Weekdays: holiday_domain.DefaultWeekdays}
 	for i := range leaves {
 		leave := leaves[i]
+		leave.Days = float64(holiday_domain.WorkingDaysBetween(leave.StartDate, leave.EndDate, week, nil))
 		if err := r.db.WithContext(ctx).Create(&leave).Error; err != nil {
 			return fmt.Errorf("failed to seed leave data: %w", err)
 		}
@@ -5,8 +5,11 @@ package mocks
 import (
 	context "context"
 	domain "hr-system/internal/leaves/domain"
+	review_domain "hr-system/internal/reviews/domain"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // LeaveService is an autogenerated mock type for the LeaveService type
@@ -14,6 +17,80 @@ type LeaveService struct {
 	mock.Mock
 }
 
+// BackfillDays provides a mock function with given fields: ctx
+func (_m *LeaveService) BackfillDays(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackfillDays")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CancelLeave provides a mock function with given fields: ctx, leaveID, employeeID
+func (_m *LeaveService) CancelLeave(ctx context.Context, leaveID int, employeeID int) error {
+	ret := _m.Called(ctx, leaveID, employeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelLeave")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, leaveID, employeeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CancelLeavesAfterTermination provides a mock function with given fields: ctx, employeeID
+func (_m *LeaveService) CancelLeavesAfterTermination(ctx context.Context, employeeID int) (int, error) {
+	ret := _m.Called(ctx, employeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelLeavesAfterTermination")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, employeeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, employeeID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, employeeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateLeave provides a mock function with given fields: ctx, leave
 func (_m *LeaveService) CreateLeave(ctx context.Context, leave *domain.Leave) (domain.Leave, error) {
 	ret := _m.Called(ctx, leave)
@@ -42,6 +119,34 @@ func (_m *LeaveService) CreateLeave(ctx context.Context, leave *domain.Leave) (d
 	return r0, r1
 }
 
+// EscalateStaleReviews provides a mock function with given fields: ctx, sla
+func (_m *LeaveService) EscalateStaleReviews(ctx context.Context, sla time.Duration) (int, error) {
+	ret := _m.Called(ctx, sla)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EscalateStaleReviews")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (int, error)); ok {
+		return rf(ctx, sla)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) int); ok {
+		r0 = rf(ctx, sla)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, sla)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLeaveByID provides a mock function with given fields: ctx, id
 func (_m *LeaveService) GetLeaveByID(ctx context.Context, id int) (domain.Leave, error) {
 	ret := _m.Called(ctx, id)
@@ -70,25 +175,51 @@ func (_m *LeaveService) GetLeaveByID(ctx context.Context, id int) (domain.Leave,
 	return r0, r1
 }
 
+// GetLeaveDetail provides a mock function with given fields: ctx, id
+func (_m *LeaveService) GetLeaveDetail(ctx context.Context, id int) (domain.LeaveDetail, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLeaveDetail")
+	}
+
+	var r0 domain.LeaveDetail
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (domain.LeaveDetail, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) domain.LeaveDetail); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.LeaveDetail)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLeaves provides a mock function with given fields: ctx, query
-func (_m *LeaveService) GetLeaves(ctx context.Context, query domain.LeavesQuery) ([]domain.Leave, error) {
+func (_m *LeaveService) GetLeaves(ctx context.Context, query domain.LeavesQuery) (domain.LeavesPage, error) {
 	ret := _m.Called(ctx, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLeaves")
 	}
 
-	var r0 []domain.Leave
+	var r0 domain.LeavesPage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, domain.LeavesQuery) ([]domain.Leave, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LeavesQuery) (domain.LeavesPage, error)); ok {
 		return rf(ctx, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, domain.LeavesQuery) []domain.Leave); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LeavesQuery) domain.LeavesPage); ok {
 		r0 = rf(ctx, query)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Leave)
-		}
+		r0 = ret.Get(0).(domain.LeavesPage)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, domain.LeavesQuery) error); ok {
@@ -100,17 +231,149 @@ func (_m *LeaveService) GetLeaves(ctx context.Context, query domain.LeavesQuery)
 	return r0, r1
 }
 
-// ReviewLeave provides a mock function with given fields: ctx, leaveID, reviewerID, decision, comment
-func (_m *LeaveService) ReviewLeave(ctx context.Context, leaveID int, reviewerID int, decision domain.ReviewStatus, comment string) error {
-	ret := _m.Called(ctx, leaveID, reviewerID, decision, comment)
+// GetTeamCalendar provides a mock function with given fields: ctx, managerID, from, to
+func (_m *LeaveService) GetTeamCalendar(ctx context.Context, managerID int, from time.Time, to time.Time) (domain.TeamCalendar, error) {
+	ret := _m.Called(ctx, managerID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeamCalendar")
+	}
+
+	var r0 domain.TeamCalendar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time) (domain.TeamCalendar, error)); ok {
+		return rf(ctx, managerID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time) domain.TeamCalendar); ok {
+		r0 = rf(ctx, managerID, from, to)
+	} else {
+		r0 = ret.Get(0).(domain.TeamCalendar)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, managerID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUnsatisfiableChains provides a mock function with given fields: ctx
+func (_m *LeaveService) GetUnsatisfiableChains(ctx context.Context) ([]domain.UnsatisfiableChain, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUnsatisfiableChains")
+	}
+
+	var r0 []domain.UnsatisfiableChain
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.UnsatisfiableChain, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.UnsatisfiableChain); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.UnsatisfiableChain)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PreviewApprovalChain provides a mock function with given fields: ctx, leave
+func (_m *LeaveService) PreviewApprovalChain(ctx context.Context, leave *domain.Leave) (domain.ApprovalChain, error) {
+	ret := _m.Called(ctx, leave)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviewApprovalChain")
+	}
+
+	var r0 domain.ApprovalChain
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Leave) (domain.ApprovalChain, error)); ok {
+		return rf(ctx, leave)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Leave) domain.ApprovalChain); ok {
+		r0 = rf(ctx, leave)
+	} else {
+		r0 = ret.Get(0).(domain.ApprovalChain)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Leave) error); ok {
+		r1 = rf(ctx, leave)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReassignReviews provides a mock function with given fields: ctx, reviewerID
+func (_m *LeaveService) ReassignReviews(ctx context.Context, reviewerID int) (int, error) {
+	ret := _m.Called(ctx, reviewerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReassignReviews")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, reviewerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, reviewerID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, reviewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReviewLeave provides a mock function with given fields: ctx, leaveID, reviewerID, decision, comment, override
+func (_m *LeaveService) ReviewLeave(ctx context.Context, leaveID int, reviewerID int, decision review_domain.ReviewStatus, comment string, override bool) error {
+	ret := _m.Called(ctx, leaveID, reviewerID, decision, comment, override)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ReviewLeave")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int, domain.ReviewStatus, string) error); ok {
-		r0 = rf(ctx, leaveID, reviewerID, decision, comment)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, review_domain.ReviewStatus, string, bool) error); ok {
+		r0 = rf(ctx, leaveID, reviewerID, decision, comment, override)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeLeave provides a mock function with given fields: ctx, leaveID, employeeID
+func (_m *LeaveService) RevokeLeave(ctx context.Context, leaveID int, employeeID int) error {
+	ret := _m.Called(ctx, leaveID, employeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeLeave")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, leaveID, employeeID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -4,43 +4,112 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 
+	approval_domain "hr-system/internal/approvals/domain"
+	approval_service "hr-system/internal/approvals/service"
+	attachment_service "hr-system/internal/attachments/service"
+	"hr-system/internal/auth"
+	balance_service "hr-system/internal/balances/service"
 	"hr-system/internal/common"
 	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/common/transaction"
+	coverage_service "hr-system/internal/coverage/service"
+	delegation_service "hr-system/internal/delegations/service"
 	employee_domain "hr-system/internal/employees/domain"
 	employee_repo "hr-system/internal/employees/repo"
+	holiday_service "hr-system/internal/holidays/service"
 	"hr-system/internal/leaves/cache"
 	"hr-system/internal/leaves/domain"
 	"hr-system/internal/leaves/repo"
+	leavetype_domain "hr-system/internal/leavetypes/domain"
+	leavetype_service "hr-system/internal/leavetypes/service"
+	review_domain "hr-system/internal/reviews/domain"
+	review_service "hr-system/internal/reviews/service"
+	toil_service "hr-system/internal/toil/service"
 )
 
 type LeaveService interface {
 	CreateLeave(ctx context.Context, leave *domain.Leave) (domain.Leave, error)
-	GetLeaves(ctx context.Context, query domain.LeavesQuery) ([]domain.Leave, error)
-	ReviewLeave(ctx context.Context, leaveID, reviewerID int, decision domain.ReviewStatus, comment string) error
+	GetLeaves(ctx context.Context, query domain.LeavesQuery) (domain.LeavesPage, error)
+	// ReviewLeave decides on the leave as the reviewer, override approves the leave although it breaks a coverage rule
+	// which allows it
+	ReviewLeave(ctx context.Context, leaveID, reviewerID int, decision review_domain.ReviewStatus, comment string,
+		override bool) error
 	GetLeaveByID(ctx context.Context, id int) (domain.Leave, error)
+	// GetLeaveDetail returns the leave with the leaves of the requester's team overlapping it
+	GetLeaveDetail(ctx context.Context, id int) (domain.LeaveDetail, error)
+	// GetTeamCalendar returns the approved and pending leaves of the employees reporting to the manager directly or
+	// indirectly, day by day from from to to
+	GetTeamCalendar(ctx context.Context, managerID int, from, to time.Time) (domain.TeamCalendar, error)
+	// PreviewApprovalChain returns the reviewers a leave would go through without creating it
+	PreviewApprovalChain(ctx context.Context, leave *domain.Leave) (domain.ApprovalChain, error)
+	// GetUnsatisfiableChains lists the active employees whose manager chain can not meet the highest requirement
+	// of the approval policies for a leave type
+	GetUnsatisfiableChains(ctx context.Context) ([]domain.UnsatisfiableChain, error)
+	CancelLeave(ctx context.Context, leaveID, employeeID int) error
+	RevokeLeave(ctx context.Context, leaveID, employeeID int) error
+	// EscalateStaleReviews passes the reviews pending longer than the SLA to the reviewer's manager,
+	// it returns the number of escalated reviews
+	EscalateStaleReviews(ctx context.Context, sla time.Duration) (int, error)
+	// CancelLeavesAfterTermination withdraws the leaves of a terminated employee which start after their last day
+	// and shortens the ones which end after it, it returns the number of withdrawn leaves
+	CancelLeavesAfterTermination(ctx context.Context, employeeID int) (int, error)
+	// ReassignReviews passes the reviews pending on the reviewer, or on their delegate for them, to the reviewer's
+	// manager and hands the ones they review as a delegate back to the manager they act for,
+	// it returns the number of reassigned reviews
+	ReassignReviews(ctx context.Context, reviewerID int) (int, error)
+	// BackfillDays counts the working days of the leaves created before they were counted,
+	// it returns the number of updated leaves
+	BackfillDays(ctx context.Context) (int, error)
 }
 
 type leaveService struct {
-	leaveRepo    repo.LeaveRepo
-	leaveCache   cache.LeaveCache
-	employeeRepo employee_repo.EmployeeRepo
-	logger       *common.Logger
-	validate     *validator.Validate
+	leaveRepo         repo.LeaveRepo
+	leaveCache        cache.LeaveCache
+	employeeRepo      employee_repo.EmployeeRepo
+	balanceService    balance_service.BalanceService
+	holidayService    holiday_service.HolidayService
+	policyService     approval_service.PolicyService
+	delegationService delegation_service.DelegationService
+	reviewChain       review_service.ReviewChain[*domain.Leave]
+	coverageService   coverage_service.RuleService
+	leaveTypeService  leavetype_service.TypeService
+	attachmentService attachment_service.AttachmentService
+	toilService       toil_service.TOILService
+	transactor        transaction.Transactor
+	logger            *common.Logger
+	validate          *validator.Validate
 }
 
 func NewLeaveService(logger *common.Logger, leaveRepo repo.LeaveRepo, employeeRepo employee_repo.EmployeeRepo,
-	leaveCache cache.LeaveCache) LeaveService {
-	return &leaveService{
-		leaveRepo:    leaveRepo,
-		employeeRepo: employeeRepo,
-		leaveCache:   leaveCache,
-		logger:       logger,
-		validate:     validator.New(),
+	leaveCache cache.LeaveCache, balanceService balance_service.BalanceService,
+	holidayService holiday_service.HolidayService, policyService approval_service.PolicyService,
+	delegationService delegation_service.DelegationService,
+	coverageService coverage_service.RuleService, leaveTypeService leavetype_service.TypeService,
+	attachmentService attachment_service.AttachmentService, toilService toil_service.TOILService,
+	transactor transaction.Transactor) LeaveService {
+	s := &leaveService{
+		leaveRepo:         leaveRepo,
+		employeeRepo:      employeeRepo,
+		leaveCache:        leaveCache,
+		balanceService:    balanceService,
+		holidayService:    holidayService,
+		policyService:     policyService,
+		delegationService: delegationService,
+		coverageService:   coverageService,
+		leaveTypeService:  leaveTypeService,
+		attachmentService: attachmentService,
+		toilService:       toilService,
+		transactor:        transactor,
+		logger:            logger,
+		validate:          validator.New(),
 	}
+	s.reviewChain = review_service.NewReviewChain(logger, employeeRepo, delegationService, s.saveReviews)
+	return s
 }
 
 func (s *leaveService) validateCreateLeave(leave *domain.Leave) error {
@@ -51,56 +120,239 @@ func (s *leaveService) validateCreateLeave(leave *domain.Leave) error {
 	if leave.StartDate.After(leave.EndDate) {
 		return fmt.Errorf("start date must be before end date")
 	}
+	if err := leave.ValidateUnit(); err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (s *leaveService) CreateLeave(ctx context.Context, leave *domain.Leave) (domain.Leave, error) {
+// activeStatuses are the statuses of leaves which are taken or may be taken
+var activeStatuses = []review_domain.ReviewStatus{
+	review_domain.ReviewStatusReviewing,
+	review_domain.ReviewStatusApproved,
+	review_domain.ReviewStatusRevoking,
+}
+
+// takenStatuses are the statuses of leaves which have been approved
+var takenStatuses = []review_domain.ReviewStatus{
+	review_domain.ReviewStatusApproved,
+	review_domain.ReviewStatusRevoking,
+}
+
+// checkOverlap makes sure none of the other leaves of the employee in the given statuses intersects the leave
+func (s *leaveService) checkOverlap(ctx context.Context, leave *domain.Leave,
+	statuses []review_domain.ReviewStatus) error {
+	leaves, err := s.leaveRepo.GetOverlappingLeaves(ctx, leave.EmployeeID, leave.StartDate, leave.EndDate, statuses)
+	if err != nil {
+		return fmt.Errorf("failed to get overlapping leaves: %w", err)
+	}
+
+	var conflictIDs []int
+	for _, l := range leaves {
+		if l.ID != leave.ID && leave.Overlaps(&l) {
+			conflictIDs = append(conflictIDs, l.ID)
+		}
+	}
+	if len(conflictIDs) > 0 {
+		return fmt.Errorf("%w, leave overlaps with leaves %v", common_errors.ErrStatusConflict, conflictIDs)
+	}
+
+	return nil
+}
+
+// checkBalance makes sure the remaining days of the employee are enough for the leave in each year it takes days of,
+// as they are debited, the leaves which are still being reviewed are counted as taken.
+func (s *leaveService) checkBalance(ctx context.Context, leave *domain.Leave) error {
+	days, err := s.balanceService.DaysByYear(ctx, leave)
+	if err != nil {
+		return fmt.Errorf("failed to split leave days by year: %w", err)
+	}
+
+	leaves, err := s.leaveRepo.GetLeaves(ctx, domain.LeavesQuery{EmployeeID: &leave.EmployeeID})
+	if err != nil {
+		return fmt.Errorf("failed to get leaves: %w", err)
+	}
+	pending := make(map[int]float64)
+	for i := range leaves {
+		if leaves[i].Status != review_domain.ReviewStatusReviewing || leaves[i].Type != leave.Type ||
+			leaves[i].EndDate.Year() < leave.StartDate.Year() || leaves[i].StartDate.Year() > leave.EndDate.Year() {
+			continue
+		}
+		pendingDays, err := s.balanceService.DaysByYear(ctx, &leaves[i])
+		if err != nil {
+			return fmt.Errorf("failed to split leave days by year: %w", err)
+		}
+		for year, d := range pendingDays {
+			pending[year] += d
+		}
+	}
+
+	for year := leave.StartDate.Year(); year <= leave.EndDate.Year(); year++ {
+		if days[year] == 0 {
+			continue
+		}
+		balance, err := s.balanceService.GetBalance(ctx, leave.EmployeeID, leave.Type, year)
+		if err != nil {
+			return fmt.Errorf("failed to get leave balance: %w", err)
+		}
+		if days[year] > balance.Remaining-pending[year] {
+			return fmt.Errorf("%w, insufficient %s leave balance of %d, remaining: %.1f days, pending: %.1f days, "+
+				"requested: %.1f days", common_errors.ErrInvalidInput, leave.Type, year, balance.Remaining,
+				pending[year], days[year])
+		}
+	}
+
+	return nil
+}
+
+// getLeaveType returns the type of the catalogue a new leave is requested with
+func (s *leaveService) getLeaveType(ctx context.Context, code domain.LeaveType) (leavetype_domain.Type, error) {
+	leaveType, err := s.leaveTypeService.GetTypeByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return leavetype_domain.Type{}, fmt.Errorf("%w, unknown leave type %s", common_errors.ErrInvalidInput, code)
+		}
+		return leavetype_domain.Type{}, fmt.Errorf("failed to get leave type: %w", err)
+	}
+	if leaveType.Disabled {
+		return leavetype_domain.Type{}, fmt.Errorf("%w, leave type %s is disabled", common_errors.ErrInvalidInput, code)
+	}
+	return leaveType, nil
+}
+
+// checkAttachments makes sure the leave is submitted with the attachments its type asks for,
+// and that the attachments are pending uploads of the caller
+func (s *leaveService) checkAttachments(ctx context.Context, leave *domain.Leave,
+	leaveType *leavetype_domain.Type) error {
+	if len(leave.AttachmentIDs) == 0 {
+		if leaveType.NeedsAttachment(leave.Days) {
+			return fmt.Errorf("%w, a %s leave of more than %.1f days needs an attachment",
+				common_errors.ErrInvalidInput, leave.Type, leaveType.AttachmentAfterDays)
+		}
+		return nil
+	}
+	return s.attachmentService.CheckPending(ctx, leave.AttachmentIDs)
+}
+
+// prepareLeave validates the leave against its type and counts its days,
+// it returns the employee requesting the leave and the type of the leave
+func (s *leaveService) prepareLeave(ctx context.Context, leave *domain.Leave) (employee_domain.Employee,
+	leavetype_domain.Type, error) {
 	if err := s.validateCreateLeave(leave); err != nil {
-		return domain.Leave{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+		return employee_domain.Employee{}, leavetype_domain.Type{},
+			fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	if leave.Unit == "" {
+		leave.Unit = domain.LeaveUnitFullDay
+	}
+
+	leaveType, err := s.getLeaveType(ctx, leave.Type)
+	if err != nil {
+		return employee_domain.Employee{}, leavetype_domain.Type{}, err
 	}
 
 	employee, err := s.employeeRepo.GetEmployeeByID(ctx, leave.EmployeeID)
 	if err != nil {
 		if errors.Is(err, common_errors.ErrResourceNotFound) {
-			return domain.Leave{}, common_errors.ErrResourceNotFound
+			return employee_domain.Employee{}, leavetype_domain.Type{}, common_errors.ErrResourceNotFound
 		}
-		return domain.Leave{}, fmt.Errorf("failed to get manager IDs: %w", err)
+		return employee_domain.Employee{}, leavetype_domain.Type{}, fmt.Errorf("failed to get manager IDs: %w", err)
+	}
+	if employee.TerminatedAt(leave.EndDate) {
+		return employee_domain.Employee{}, leavetype_domain.Type{}, fmt.Errorf("%w, leave ends after the employee's last day",
+			common_errors.ErrInvalidInput)
 	}
 
-	// status
-	leave.Status = domain.ReviewStatusReviewing
-	if employee.ManagerID == nil {
-		leave.Status = domain.ReviewStatusApproved
+	workingDays, err := s.holidayService.WorkingDaysBetween(ctx, employee.Region, leave.StartDate, leave.EndDate)
+	if err != nil {
+		return employee_domain.Employee{}, leavetype_domain.Type{}, fmt.Errorf("failed to count working days: %w", err)
+	}
+	if workingDays == 0 {
+		return employee_domain.Employee{}, leavetype_domain.Type{}, fmt.Errorf("%w, leave does not contain any working day",
+			common_errors.ErrInvalidInput)
+	}
+	// a leave shorter than a day is on a single day
+	leave.Days = float64(workingDays) * leave.DayFraction()
+	if err := leaveType.CheckLength(leave.Days); err != nil {
+		return employee_domain.Employee{}, leavetype_domain.Type{},
+			fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
 	}
 
-	// currentReviewerID
-	leave.CurrentReviewerID = employee.ManagerID
+	return employee, leaveType, nil
+}
 
-	// reviews
-	if employee.ManagerID != nil {
-		leave.Reviews = []domain.LeaveReview{
-			{
-				ReviewerID: *employee.ManagerID,
-				Status:     domain.ReviewStatusReviewing,
-			},
+func (s *leaveService) CreateLeave(ctx context.Context, leave *domain.Leave) (domain.Leave, error) {
+	if _, err := auth.AuthorizeSelf(ctx, leave.EmployeeID, auth.RoleHRAdmin); err != nil {
+		return domain.Leave{}, err
+	}
+
+	employee, leaveType, err := s.prepareLeave(ctx, leave)
+	if err != nil {
+		return domain.Leave{}, err
+	}
+
+	if err := s.checkOverlap(ctx, leave, activeStatuses); err != nil {
+		return domain.Leave{}, err
+	}
+
+	// a comp-off leave is drawn from the allowance of the approved overtime,
+	// an unpaid leave is not drawn from a balance and is not debited either
+	switch {
+	case leave.Type == domain.LeaveTypeCompOff:
+		if err := s.toilService.CheckAllowance(ctx, leave); err != nil {
+			return domain.Leave{}, err
+		}
+	case leaveType.Paid:
+		if err := s.checkBalance(ctx, leave); err != nil {
+			return domain.Leave{}, err
 		}
 	}
 
-	if err := s.leaveRepo.CreateLeave(ctx, leave); err != nil {
-		return domain.Leave{}, fmt.Errorf("failed to create leave: %w", err)
+	if err := s.checkAttachments(ctx, leave, &leaveType); err != nil {
+		return domain.Leave{}, err
 	}
 
-	// delete cache of this employee
-	if err := s.leaveCache.DelLeavesFromCache(ctx, domain.LeavesQuery{EmployeeID: &leave.EmployeeID}); err != nil {
-		s.logger.Errorf("failed to delete employee %d cache, cause: %s", leave.EmployeeID, err)
+	// status
+	leave.Status = review_domain.ReviewStatusReviewing
+	if employee.ManagerID == nil {
+		leave.Status = review_domain.ReviewStatusApproved
 	}
-	// delete cache of this reviewer
-	if leave.CurrentReviewerID != nil {
-		err := s.leaveCache.DelLeavesFromCache(ctx, domain.LeavesQuery{CurrentReviewerID: leave.CurrentReviewerID})
+
+	// currentReviewerID & reviews
+	leave.CurrentReviewerID = nil
+	if employee.ManagerID != nil {
+		review, err := s.reviewChain.NewReview(ctx, leave, *employee.ManagerID, false)
 		if err != nil {
-			s.logger.Errorf("failed to delete reviewer %d cache, cause: %s", leave.CurrentReviewerID, err)
+			return domain.Leave{}, err
+		}
+		leave.Reviews = []review_domain.Review{review}
+	}
+
+	// the leave is not created without its attachments and its debit
+	err = s.transactor.Do(ctx, func(ctx context.Context) error {
+		if err := s.leaveRepo.CreateLeave(ctx, leave); err != nil {
+			return fmt.Errorf("failed to create leave: %w", err)
 		}
+		if len(leave.AttachmentIDs) > 0 {
+			if err := s.attachmentService.AttachToLeave(ctx, leave.ID, leave.AttachmentIDs); err != nil {
+				return fmt.Errorf("failed to attach attachments to leave %d: %w", leave.ID, err)
+			}
+		}
+		if leave.Status == review_domain.ReviewStatusApproved && leaveType.Paid {
+			if err := s.balanceService.DebitLeave(ctx, leave); err != nil {
+				return fmt.Errorf("failed to debit leave balance: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Leave{}, err
+	}
+
+	// the lists the leave is in are stale
+	if err := s.leaveCache.InvalidateLeavesLists(ctx); err != nil {
+		s.logger.Errorf("failed to invalidate leaves lists cache, cause: %s", err)
 	}
 
 	if err := s.leaveCache.SetLeaveToCache(ctx, leave); err != nil {
@@ -110,27 +362,192 @@ func (s *leaveService) CreateLeave(ctx context.Context, leave *domain.Leave) (do
 	return *leave, nil
 }
 
-// check if the leave needs to be reviewed by the next reviewer
-func needNextReviewer(leave *domain.Leave, approver *employee_domain.Employee) bool {
-	days := int(leave.EndDate.Sub(leave.StartDate).Hours() / 24)
+// check if the leave needs to be reviewed by the next reviewer,
+// approvals is the number of approvals including the one of the approver who reviews at the time
+func needNextReviewer(approver *employee_domain.Employee, at time.Time, approvals int,
+	requirement approval_domain.Requirement) bool {
+	return approver.PositionAt(at).ManagerLevel < requirement.ManagerLevel || approvals < requirement.Approvers
+}
 
-	var needManagerLevel int
-	if days <= 5 {
-		needManagerLevel = 0
-	} else if days <= 10 {
-		needManagerLevel = 3
-	} else {
-		needManagerLevel = 5
+// getRequirement returns what the leave needs to be approved by the policy,
+// by the position the employee has at the time of the review
+func (s *leaveService) getRequirement(ctx context.Context, leave *domain.Leave,
+	employee *employee_domain.Employee, at time.Time) (approval_domain.Requirement, error) {
+	level := employee.PositionAt(at).Level
+	requirement, err := s.policyService.GetRequirement(ctx, leave.Type, level, leave.Days)
+	if err != nil {
+		return approval_domain.Requirement{}, fmt.Errorf("failed to get approval requirement: %w", err)
+	}
+	return requirement, nil
+}
+
+// countApprovals counts the approvals of the review round the last review belongs to, the last review excluded
+func countApprovals(reviews []review_domain.Review) int {
+	if len(reviews) == 0 {
+		return 0
 	}
+	revocation := reviews[len(reviews)-1].Revocation
 
-	return approver.Positions[0].ManagerLevel < needManagerLevel
+	approvals := 0
+	for i := len(reviews) - 2; i >= 0; i-- {
+		if reviews[i].Revocation != revocation {
+			break
+		}
+		if reviews[i].Status == review_domain.ReviewStatusEscalated ||
+			reviews[i].Status == review_domain.ReviewStatusReassigned {
+			// nobody reviewed it, the chain goes on
+			continue
+		}
+		if reviews[i].Status != review_domain.ReviewStatusApproved {
+			break
+		}
+		approvals++
+	}
+	return approvals
 }
 
-func (s *leaveService) ReviewLeave(ctx context.Context, leaveID int, reviewerID int, decision domain.ReviewStatus,
-	comment string) error {
-	if decision != domain.ReviewStatusApproved && decision != domain.ReviewStatusRejected {
+func (s *leaveService) PreviewApprovalChain(ctx context.Context, leave *domain.Leave) (domain.ApprovalChain, error) {
+	if _, err := auth.AuthorizeEmployee(ctx, s.employeeRepo, leave.EmployeeID); err != nil {
+		return domain.ApprovalChain{}, err
+	}
+
+	employee, _, err := s.prepareLeave(ctx, leave)
+	if err != nil {
+		return domain.ApprovalChain{}, err
+	}
+
+	now := time.Now()
+	requirement, err := s.getRequirement(ctx, leave, &employee, now)
+	if err != nil {
+		return domain.ApprovalChain{}, err
+	}
+
+	chain := domain.ApprovalChain{
+		Days:                 leave.Days,
+		RequiredManagerLevel: requirement.ManagerLevel,
+		RequiredApprovers:    requirement.Approvers,
+	}
+	chain.Reviewers, chain.Satisfiable, err = approvalSteps(&employee, func(id int) (employee_domain.Employee, error) {
+		return s.employeeRepo.GetEmployeeByID(ctx, id)
+	}, requirement, now)
+	if err != nil {
+		return domain.ApprovalChain{}, err
+	}
+
+	return chain, nil
+}
+
+// approvalSteps walks up the manager chain of the employee until the requirement is met,
+// it reports false when the chain ends before
+func approvalSteps(employee *employee_domain.Employee, getReviewer func(id int) (employee_domain.Employee, error),
+	requirement approval_domain.Requirement, at time.Time) ([]domain.ApprovalStep, bool, error) {
+	steps := []domain.ApprovalStep{}
+	// approved without review
+	if employee.ManagerID == nil {
+		return steps, true, nil
+	}
+
+	visited := map[int]bool{employee.ID: true}
+	for reviewerID := employee.ManagerID; reviewerID != nil && !visited[*reviewerID]; {
+		visited[*reviewerID] = true
+		reviewer, err := getReviewer(*reviewerID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get reviewer %d: %w", *reviewerID, err)
+		}
+		steps = append(steps, domain.ApprovalStep{
+			ReviewerID:   reviewer.ID,
+			Name:         reviewer.Name,
+			ManagerLevel: reviewer.PositionAt(at).ManagerLevel,
+		})
+		if !needNextReviewer(&reviewer, at, len(steps), requirement) {
+			return steps, true, nil
+		}
+		reviewerID = reviewer.ManagerID
+	}
+	return steps, false, nil
+}
+
+func (s *leaveService) GetUnsatisfiableChains(ctx context.Context) ([]domain.UnsatisfiableChain, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return nil, err
+	}
+
+	policies, err := s.policyService.GetPolicies(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policies: %w", err)
+	}
+	leaveTypes, err := s.leaveTypeService.GetTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leave types: %w", err)
+	}
+	// the ones who have left may still be in a manager chain
+	employees, err := employee_repo.GetAllEmployees(ctx, s.employeeRepo)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*employee_domain.Employee, len(employees))
+	for i := range employees {
+		byID[employees[i].ID] = &employees[i]
+	}
+	getReviewer := func(id int) (employee_domain.Employee, error) {
+		if reviewer, ok := byID[id]; ok {
+			return *reviewer, nil
+		}
+		return employee_domain.Employee{}, common_errors.ErrResourceNotFound
+	}
+
+	now := time.Now()
+	chains := []domain.UnsatisfiableChain{}
+	for i := range employees {
+		employee := &employees[i]
+		if employee.TerminatedAt(now) {
+			continue
+		}
+		level := employee.PositionAt(now).Level
+		for _, leaveType := range leaveTypes {
+			if leaveType.Disabled {
+				continue
+			}
+			var unmet *domain.UnsatisfiableChain
+			for _, requirement := range approval_domain.Requirements(policies, leaveType.Code, level,
+				leaveType.Thresholds) {
+				steps, ok, err := approvalSteps(employee, getReviewer, requirement, now)
+				if err != nil {
+					return nil, err
+				}
+				if ok || (unmet != nil && (requirement.ManagerLevel < unmet.RequiredManagerLevel ||
+					requirement.ManagerLevel == unmet.RequiredManagerLevel &&
+						requirement.Approvers <= unmet.RequiredApprovers)) {
+					continue
+				}
+				unmet = &domain.UnsatisfiableChain{
+					EmployeeID:           employee.ID,
+					Name:                 employee.Name,
+					PositionLevel:        level,
+					LeaveType:            leaveType.Code,
+					RequiredManagerLevel: requirement.ManagerLevel,
+					RequiredApprovers:    requirement.Approvers,
+					Reviewers:            steps,
+				}
+			}
+			if unmet != nil {
+				chains = append(chains, *unmet)
+			}
+		}
+	}
+
+	return chains, nil
+}
+
+func (s *leaveService) ReviewLeave(ctx context.Context, leaveID int, reviewerID int,
+	decision review_domain.ReviewStatus, comment string, override bool) error {
+	if decision != review_domain.ReviewStatusApproved && decision != review_domain.ReviewStatusRejected {
 		return fmt.Errorf("%w, invalid decision: %s", common_errors.ErrInvalidInput, decision)
 	}
+	// nobody reviews in the name of someone else, the delegation covers that
+	if _, err := auth.AuthorizeSelf(ctx, reviewerID); err != nil {
+		return err
+	}
 
 	leave, err := s.leaveRepo.GetLeaveByID(ctx, leaveID)
 	if err != nil {
@@ -139,121 +556,635 @@ func (s *leaveService) ReviewLeave(ctx context.Context, leaveID int, reviewerID
 		}
 		return fmt.Errorf("failed to retrieve leave: %w", err)
 	}
-	if leave.Status != domain.ReviewStatusReviewing {
-		return fmt.Errorf("%w, leave is not in reviewing status", common_errors.ErrStatusConflict)
-	}
-
-	// check reviewer permission
-	if leave.CurrentReviewerID == nil || *leave.CurrentReviewerID != reviewerID {
-		return fmt.Errorf("%w, it's not waiting for this reviewer to review", common_errors.ErrStatusConflict)
+	if leave.Status != review_domain.ReviewStatusReviewing && leave.Status != review_domain.ReviewStatusRevoking {
+		return fmt.Errorf("%w, leave is not in reviewing or revoking status", common_errors.ErrStatusConflict)
 	}
 
-	// update review comment & status
-	now := time.Now()
 	if len(leave.Reviews) == 0 {
 		return fmt.Errorf("unexpected error: no review found")
 	}
-	updateReviews := []domain.LeaveReview{
+	updateReviews := []review_domain.Review{
 		leave.Reviews[len(leave.Reviews)-1],
 	}
+
+	// check reviewer permission
+	managerID, err := s.reviewChain.CheckReviewer(ctx, &leave, &updateReviews[0], reviewerID)
+	if err != nil {
+		return err
+	}
+
+	// update review comment & status
+	now := time.Now()
 	updateReviews[0].Comment = comment
 	updateReviews[0].ReviewedAt = &now
 	updateReviews[0].Status = decision
 
-	// update leave
-	if decision == domain.ReviewStatusApproved {
-		// approved
-		reviewer, err := s.employeeRepo.GetEmployeeByID(ctx, reviewerID)
+	// a revocation goes through the same manager chain as the leave itself,
+	// approving it revokes the leave and rejecting it keeps the leave approved
+	var nextStatus review_domain.ReviewStatus
+	var employee employee_domain.Employee
+	if decision == review_domain.ReviewStatusApproved {
+		// approved, with the authority of the manager when a delegate reviews
+		reviewer, err := s.employeeRepo.GetEmployeeByID(ctx, managerID)
 		if err != nil {
 			if errors.Is(err, common_errors.ErrResourceNotFound) {
 				return common_errors.ErrResourceNotFound
 			}
 			return fmt.Errorf("failed to get manager IDs: %w", err)
 		}
-		if needNextReviewer(&leave, &reviewer) {
+		employee, err = s.employeeRepo.GetEmployeeByID(ctx, leave.EmployeeID)
+		if err != nil {
+			return fmt.Errorf("failed to get employee: %w", err)
+		}
+		requirement, err := s.getRequirement(ctx, &leave, &employee, now)
+		if err != nil {
+			return err
+		}
+		if needNextReviewer(&reviewer, now, countApprovals(leave.Reviews)+1, requirement) {
 			// pass to next reviewer
 			if reviewer.ManagerID == nil {
-				return fmt.Errorf("unexpected error: reviewer does not have a manager")
+				return fmt.Errorf("%w, the manager chain of employee %d ends before the approval requirement is met",
+					common_errors.ErrStatusConflict, leave.EmployeeID)
+			}
+			review, err := s.reviewChain.NewReview(ctx, &leave, *reviewer.ManagerID, updateReviews[0].Revocation)
+			if err != nil {
+				return err
 			}
-			updateReviews = append(updateReviews, domain.LeaveReview{
-				LeaveID:    leaveID,
-				ReviewerID: *reviewer.ManagerID,
-				Status:     domain.ReviewStatusReviewing,
-			})
-			leave.CurrentReviewerID = reviewer.ManagerID
+			updateReviews = append(updateReviews, review)
+			nextStatus = leave.Status
+		} else if leave.Status == review_domain.ReviewStatusRevoking {
+			// revocation approved
+			nextStatus = review_domain.ReviewStatusRevoked
+			leave.CurrentReviewerID = nil
 		} else {
 			// leave approved
-			leave.Status = domain.ReviewStatusApproved
+			nextStatus = review_domain.ReviewStatusApproved
 			leave.CurrentReviewerID = nil
 		}
+	} else if leave.Status == review_domain.ReviewStatusRevoking {
+		// revocation rejected
+		nextStatus = review_domain.ReviewStatusApproved
+		leave.CurrentReviewerID = nil
 	} else {
 		// rejected
-		leave.Status = domain.ReviewStatusRejected
+		nextStatus = review_domain.ReviewStatusRejected
 		leave.CurrentReviewerID = nil
 	}
 
-	err = s.leaveRepo.UpdateLeaveAndReviews(ctx, &leave, updateReviews)
+	if !leave.Status.CanTransitionTo(nextStatus) {
+		return fmt.Errorf("%w, leave can not move from %s to %s", common_errors.ErrStatusConflict, leave.Status, nextStatus)
+	}
+	// overlapping leaves may still be in review when the leave is created, the first one approved wins
+	if leave.Status == review_domain.ReviewStatusReviewing && nextStatus == review_domain.ReviewStatusApproved {
+		if err := s.checkOverlap(ctx, &leave, takenStatuses); err != nil {
+			return err
+		}
+		overridden, err := s.checkCoverage(ctx, &leave, &employee, override)
+		if err != nil {
+			return err
+		}
+		if overridden {
+			updateReviews[0].CoverageOverride = true
+			updateReviews[0].OverriddenByID = common.GetPtr(reviewerID)
+		}
+	}
+	prevStatus := leave.Status
+	leave.Status = nextStatus
+
+	// an unpaid leave is not debited when it's approved, the type may have been disabled since the leave was requested
+	debit := false
+	if prevStatus == review_domain.ReviewStatusReviewing && nextStatus == review_domain.ReviewStatusApproved {
+		leaveType, err := s.leaveTypeService.GetTypeByCode(ctx, leave.Type)
+		if err != nil {
+			return fmt.Errorf("failed to get leave type: %w", err)
+		}
+		debit = leaveType.Paid
+	}
+
+	// the balance is updated with the leave or not at all
+	err = s.transactor.Do(ctx, func(ctx context.Context) error {
+		if err := s.leaveRepo.UpdateLeaveAndReviews(ctx, &leave, updateReviews); err != nil {
+			return fmt.Errorf("failed to update leave review: %w", err)
+		}
+
+		// update leave balance
+		if debit {
+			if err := s.balanceService.DebitLeave(ctx, &leave); err != nil {
+				return fmt.Errorf("failed to debit leave balance: %w", err)
+			}
+		} else if nextStatus == review_domain.ReviewStatusRevoked {
+			if err := s.balanceService.CreditLeave(ctx, &leave); err != nil {
+				return fmt.Errorf("failed to credit leave balance: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.delLeaveCaches(ctx, &leave)
+
+	return nil
+}
+
+func (s *leaveService) getOwnLeave(ctx context.Context, leaveID, employeeID int) (domain.Leave, error) {
+	if _, err := auth.AuthorizeSelf(ctx, employeeID); err != nil {
+		return domain.Leave{}, err
+	}
+
+	leave, err := s.leaveRepo.GetLeaveByID(ctx, leaveID)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Leave{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Leave{}, fmt.Errorf("failed to retrieve leave: %w", err)
+	}
+	if leave.EmployeeID != employeeID {
+		return domain.Leave{}, fmt.Errorf("%w, leave %d does not belong to employee %d",
+			common_errors.ErrInvalidInput, leaveID, employeeID)
+	}
+	return leave, nil
+}
+
+// checkCoverage checks the coverage rule of the direct reports of the requester's manager on every working day of the
+// leave, the requester counts as out for the part of the day the leave takes. A leave breaking a rule which allows no
+// override is a status conflict, one breaking a rule which allows it needs override and true is returned when it's
+// overridden
+func (s *leaveService) checkCoverage(ctx context.Context, leave *domain.Leave, employee *employee_domain.Employee,
+	override bool) (bool, error) {
+	if employee.ManagerID == nil {
+		return false, nil
+	}
+	rule, err := s.coverageService.GetRuleOfManager(ctx, *employee.ManagerID)
 	if err != nil {
-		return fmt.Errorf("failed to update leave review: %w", err)
+		return false, fmt.Errorf("failed to get coverage rule: %w", err)
+	}
+	if rule == nil {
+		return false, nil
 	}
 
-	// delete cache of this leave
-	if err := s.leaveCache.DelLeaveFromCache(ctx, leaveID); err != nil {
-		s.logger.Errorf("failed to delete leave %d cache, cause: %s", leaveID, err)
+	reportIDs, err := s.employeeRepo.GetReportIDs(ctx, *employee.ManagerID, 1)
+	if err != nil {
+		return false, fmt.Errorf("failed to get reports: %w", err)
 	}
-	// delete cache of this employee
-	if err := s.leaveCache.DelLeavesFromCache(ctx, domain.LeavesQuery{EmployeeID: &leave.EmployeeID}); err != nil {
-		s.logger.Errorf("failed to delete employee %d cache, cause: %s", leave.EmployeeID, err)
+	reports, err := s.employeeRepo.GetEmployeesByIDs(ctx, reportIDs)
+	if err != nil {
+		return false, fmt.Errorf("failed to get reports: %w", err)
 	}
-	// delete cache of old reviewer
-	if err := s.leaveCache.DelLeavesFromCache(ctx, domain.LeavesQuery{CurrentReviewerID: &reviewerID}); err != nil {
-		s.logger.Errorf("failed to delete reviewer %d cache, cause: %s", reviewerID, err)
+	leaves, err := s.leaveRepo.GetLeaves(ctx, domain.LeavesQuery{
+		EmployeeIDs: reportIDs,
+		Statuses:    takenStatuses,
+		From:        &leave.StartDate,
+		To:          &leave.EndDate,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get leaves: %w", err)
 	}
-	// delete cache of new reviewer
-	if leave.CurrentReviewerID != nil && *leave.CurrentReviewerID != reviewerID {
-		err = s.leaveCache.DelLeavesFromCache(ctx, domain.LeavesQuery{CurrentReviewerID: leave.CurrentReviewerID})
+	dates, err := s.holidayService.WorkingDates(ctx, employee.Region, leave.StartDate, leave.EndDate)
+	if err != nil {
+		return false, fmt.Errorf("failed to get working dates: %w", err)
+	}
+
+	// a half-day or hourly leave keeps the employee working for the rest of the day
+	outOn := func(employeeID int, date string) float64 {
+		out := 0.0
+		if employeeID == leave.EmployeeID {
+			out = leave.DayFraction()
+		}
+		for _, l := range leaves {
+			if l.EmployeeID == employeeID && l.StartDate.Format(time.DateOnly) <= date &&
+				date <= l.EndDate.Format(time.DateOnly) {
+				out += l.DayFraction()
+			}
+		}
+		return min(out, 1)
+	}
+	for _, date := range dates {
+		day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+		total, working := 0, 0.0
+		for _, report := range reports {
+			if report.TerminatedAt(day) {
+				continue
+			}
+			total++
+			working += 1 - outOn(report.ID, day.Format(time.DateOnly))
+		}
+		if rule.MetBy(working, total) {
+			continue
+		}
+
+		if !rule.AllowOverride {
+			return false, fmt.Errorf("%w, only %.1f of %d reports of manager %d would work on %s, coverage rule %d "+
+				"asks for %d%%", common_errors.ErrStatusConflict, working, total, rule.ManagerID,
+				day.Format(time.DateOnly), rule.ID, rule.MinWorkingPercent)
+		}
+		if !override {
+			return false, fmt.Errorf("%w, only %.1f of %d reports of manager %d would work on %s, coverage rule %d "+
+				"asks for %d%%, approve it with override to go ahead", common_errors.ErrOverrideRequired, working,
+				total, rule.ManagerID, day.Format(time.DateOnly), rule.ID, rule.MinWorkingPercent)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// CancelLeave withdraws a leave which is still waiting for review
+func (s *leaveService) CancelLeave(ctx context.Context, leaveID, employeeID int) error {
+	leave, err := s.getOwnLeave(ctx, leaveID, employeeID)
+	if err != nil {
+		return err
+	}
+	if !leave.Status.CanTransitionTo(review_domain.ReviewStatusCancelled) {
+		return fmt.Errorf("%w, leave in %s status can not be cancelled", common_errors.ErrStatusConflict, leave.Status)
+	}
+
+	return s.closeLeave(ctx, &leave, review_domain.ReviewStatusCancelled)
+}
+
+// closeLeave moves the leave to a final status without review and cancels its pending review,
+// the balance is credited back when the leave was approved
+func (s *leaveService) closeLeave(ctx context.Context, leave *domain.Leave, status review_domain.ReviewStatus) error {
+	var updateReviews []review_domain.Review
+	if len(leave.Reviews) > 0 && leave.Reviews[len(leave.Reviews)-1].Status == review_domain.ReviewStatusReviewing {
+		now := time.Now()
+		review := leave.Reviews[len(leave.Reviews)-1]
+		review.Status = review_domain.ReviewStatusCancelled
+		review.ReviewedAt = &now
+		updateReviews = append(updateReviews, review)
+	}
+
+	// an approved leave or one waiting for its revocation has been debited
+	debited := leave.Status != review_domain.ReviewStatusReviewing
+	leave.Status = status
+	leave.CurrentReviewerID = nil
+
+	err := s.transactor.Do(ctx, func(ctx context.Context) error {
+		if err := s.leaveRepo.UpdateLeaveAndReviews(ctx, leave, updateReviews); err != nil {
+			return fmt.Errorf("failed to close leave: %w", err)
+		}
+		if status == review_domain.ReviewStatusRevoked && debited {
+			if err := s.balanceService.CreditLeave(ctx, leave); err != nil {
+				return fmt.Errorf("failed to credit leave balance: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.delLeaveCaches(ctx, leave)
+
+	return nil
+}
+
+// RevokeLeave asks the manager chain to sign off the revocation of an approved leave which has not started yet
+func (s *leaveService) RevokeLeave(ctx context.Context, leaveID, employeeID int) error {
+	leave, err := s.getOwnLeave(ctx, leaveID, employeeID)
+	if err != nil {
+		return err
+	}
+	if !leave.Status.CanTransitionTo(review_domain.ReviewStatusRevoking) {
+		return fmt.Errorf("%w, leave in %s status can not be revoked", common_errors.ErrStatusConflict, leave.Status)
+	}
+	if !leave.StartDate.After(time.Now()) {
+		return fmt.Errorf("%w, leave has already started", common_errors.ErrStatusConflict)
+	}
+
+	employee, err := s.employeeRepo.GetEmployeeByID(ctx, leave.EmployeeID)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return common_errors.ErrResourceNotFound
+		}
+		return fmt.Errorf("failed to get manager IDs: %w", err)
+	}
+
+	var updateReviews []review_domain.Review
+	if employee.ManagerID == nil {
+		// nobody to sign off
+		leave.Status = review_domain.ReviewStatusRevoked
+		leave.CurrentReviewerID = nil
+	} else {
+		leave.Status = review_domain.ReviewStatusRevoking
+		review, err := s.reviewChain.NewReview(ctx, &leave, *employee.ManagerID, true)
 		if err != nil {
-			s.logger.Errorf("failed to delete reviewer %d cache, cause: %s", *leave.CurrentReviewerID, err)
+			return err
+		}
+		updateReviews = append(updateReviews, review)
+	}
+
+	err = s.transactor.Do(ctx, func(ctx context.Context) error {
+		if err := s.leaveRepo.UpdateLeaveAndReviews(ctx, &leave, updateReviews); err != nil {
+			return fmt.Errorf("failed to revoke leave: %w", err)
+		}
+		if leave.Status == review_domain.ReviewStatusRevoked {
+			if err := s.balanceService.CreditLeave(ctx, &leave); err != nil {
+				return fmt.Errorf("failed to credit leave balance: %w", err)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
+	s.delLeaveCaches(ctx, &leave)
+
 	return nil
 }
 
-func (s *leaveService) GetLeaves(ctx context.Context, query domain.LeavesQuery) ([]domain.Leave, error) {
-	if query.EmployeeID == nil && query.CurrentReviewerID == nil {
-		return nil, fmt.Errorf("%w, employee ID or current reviewer ID must be provided", common_errors.ErrInvalidInput)
+// delLeaveCaches deletes the cache of the leave and invalidates every cached leaves list
+func (s *leaveService) delLeaveCaches(ctx context.Context, leave *domain.Leave) {
+	if err := s.leaveCache.DelLeaveFromCache(ctx, leave.ID); err != nil {
+		s.logger.Errorf("failed to delete leave %d cache, cause: %s", leave.ID, err)
 	}
-	if query.EmployeeID != nil && query.CurrentReviewerID != nil {
-		// for index and cache
-		return nil, fmt.Errorf("%w, only one of employee ID or current reviewer ID can be provided", common_errors.ErrInvalidInput)
+	if err := s.leaveCache.InvalidateLeavesLists(ctx); err != nil {
+		s.logger.Errorf("failed to invalidate leaves lists cache, cause: %s", err)
 	}
+}
 
-	// get from cache
-	leaves, err := s.leaveCache.GetLeavesFromCache(ctx, query)
-	if err == nil {
-		s.logger.Infof("[Cache Hit] leaves query: %+v", query)
-		return leaves, nil
+func (s *leaveService) GetLeaves(ctx context.Context, query domain.LeavesQuery) (domain.LeavesPage, error) {
+	query.Normalize()
+	if err := s.validate.Struct(query); err != nil {
+		return domain.LeavesPage{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
 	}
-	if err != nil && !errors.Is(err, common_errors.ErrResourceNotFound) {
+	if query.From != nil && query.To != nil && query.To.Before(*query.From) {
+		return domain.LeavesPage{}, fmt.Errorf("%w, the date range ends before it starts", common_errors.ErrInvalidInput)
+	}
+	if query.After != nil && query.After.Sort != domain.FormatSort(query.Sort) {
+		return domain.LeavesPage{}, fmt.Errorf("%w, the cursor is of another sort", common_errors.ErrInvalidInput)
+	}
+	if err := s.checkQueryTypes(ctx, query.Types); err != nil {
+		return domain.LeavesPage{}, err
+	}
+	if err := s.scopeLeavesQuery(ctx, &query); err != nil {
+		return domain.LeavesPage{}, err
+	}
+
+	// the generation is read before the repo, so a page read before a change is never cached after it
+	generation, err := s.leaveCache.GetListsGeneration(ctx)
+	cached := err == nil
+	if err != nil {
+		s.logger.Warnf("failed to get leaves lists generation, cause: %s", err)
+	} else if page, err := s.leaveCache.GetLeavesFromCache(ctx, generation, query); err == nil {
+		s.logger.Infof("[Cache Hit] leaves query: %+v", query)
+		return page, nil
+	} else if !errors.Is(err, common_errors.ErrResourceNotFound) {
 		s.logger.Warnf("failed to get leaves from cache, cause: %s", err)
 	}
 
-	// get from repo
-	leaves, err = s.leaveRepo.GetLeaves(ctx, query)
+	var page domain.LeavesPage
+	page.Leaves, page.Next, err = s.getLeavesPage(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get leaves: %w", err)
+		return domain.LeavesPage{}, err
+	}
+	if query.WithCount {
+		count, err := s.leaveRepo.CountLeaves(ctx, query)
+		if err != nil {
+			return domain.LeavesPage{}, fmt.Errorf("failed to count leaves: %w", err)
+		}
+		page.TotalCount = &count
+	}
+
+	if cached {
+		if err := s.leaveCache.SetLeavesToCache(ctx, generation, query, &page); err != nil {
+			s.logger.Errorf("failed to cache leaves data: %v", err)
+		}
+	}
+
+	return page, nil
+}
+
+// checkQueryTypes makes sure the leave types the leaves are filtered by are of the catalogue,
+// the disabled ones included since the leaves taken before keep them
+func (s *leaveService) checkQueryTypes(ctx context.Context, types []domain.LeaveType) error {
+	for _, code := range types {
+		if _, err := s.leaveTypeService.GetTypeByCode(ctx, code); err != nil {
+			if errors.Is(err, common_errors.ErrResourceNotFound) {
+				return fmt.Errorf("%w, unknown leave type %s", common_errors.ErrInvalidInput, code)
+			}
+			return fmt.Errorf("failed to get leave type: %w", err)
+		}
 	}
+	return nil
+}
 
-	// set cache
-	if err := s.leaveCache.SetLeavesToCache(ctx, query, leaves); err != nil {
-		s.logger.Errorf("failed to cache leaves data: %v", err)
+// scopeLeavesQuery checks the caller can list the leaves of the query and resolves the employees and the delegators
+// of it. The results are in the scope of each of the employee, the reviewer and the manager, so being allowed one
+// of them is enough.
+func (s *leaveService) scopeLeavesQuery(ctx context.Context, query *domain.LeavesQuery) error {
+	var err error
+	if query.EmployeeID == nil && query.CurrentReviewerID == nil && query.ManagerID == nil {
+		if _, err := auth.Authorize(ctx, auth.RoleHRAdmin, auth.RolePayroll); err != nil {
+			return err
+		}
+		// the leaves of the whole company are too many for a single response
+		if query.Limit == 0 {
+			return fmt.Errorf("%w, a limit is required to list the leaves of every employee",
+				common_errors.ErrInvalidInput)
+		}
+	} else {
+		err = common_errors.ErrForbidden
+		if query.EmployeeID != nil {
+			_, err = auth.AuthorizeEmployee(ctx, s.employeeRepo, *query.EmployeeID)
+		}
+		if err != nil && query.CurrentReviewerID != nil {
+			_, err = auth.AuthorizeSelf(ctx, *query.CurrentReviewerID, auth.RoleHRAdmin)
+		}
+		if err != nil && query.ManagerID != nil {
+			_, err = auth.AuthorizeEmployee(ctx, s.employeeRepo, *query.ManagerID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	query.EmployeeIDs, query.DelegatorIDs = nil, nil
+	if query.ManagerID != nil {
+		ids, err := s.employeeRepo.GetSubordinateIDs(ctx, *query.ManagerID)
+		if err != nil {
+			return fmt.Errorf("failed to get subordinates: %w", err)
+		}
+		// an empty team has no leaves rather than no restriction
+		query.EmployeeIDs = append([]int{}, ids...)
+	}
+	// the leaves waiting for the managers the reviewer is the delegate of
+	if query.CurrentReviewerID != nil {
+		managerIDs, err := s.delegationService.GetDelegators(ctx, *query.CurrentReviewerID, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to get delegators: %w", err)
+		}
+		query.DelegatorIDs = managerIDs
 	}
 
-	return leaves, nil
+	return nil
+}
+
+// getLeavesPage gets a page of the leaves of the query, one more leave is fetched to know whether there is a next page
+func (s *leaveService) getLeavesPage(ctx context.Context, query domain.LeavesQuery) ([]domain.Leave,
+	*domain.LeaveCursor, error) {
+	limit := query.Limit
+	if limit > 0 {
+		query.Limit = limit + 1
+	}
+	leaves, err := s.leaveRepo.GetLeaves(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get leaves: %w", err)
+	}
+	if limit == 0 || len(leaves) <= limit {
+		return leaves, nil, nil
+	}
+	leaves = leaves[:limit]
+	return leaves, domain.NewLeaveCursor(&leaves[limit-1], query.Sort), nil
 }
 
 func (s *leaveService) GetLeaveByID(ctx context.Context, id int) (domain.Leave, error) {
+	leave, err := s.getLeaveByID(ctx, id)
+	if err != nil {
+		return domain.Leave{}, err
+	}
+	if err := s.authorizeLeave(ctx, &leave); err != nil {
+		return domain.Leave{}, err
+	}
+	return leave, nil
+}
+
+// authorizeLeave lets the reviewers of the leave see it on top of the ones who can access its employee
+func (s *leaveService) authorizeLeave(ctx context.Context, leave *domain.Leave) error {
+	actor, err := auth.Authorize(ctx)
+	if err != nil {
+		return err
+	}
+	for _, review := range leave.Reviews {
+		if review.ReviewerID == actor.EmployeeID ||
+			(review.OnBehalfOfID != nil && *review.OnBehalfOfID == actor.EmployeeID) {
+			return nil
+		}
+	}
+	if _, err := auth.AuthorizeEmployee(ctx, s.employeeRepo, leave.EmployeeID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *leaveService) GetLeaveDetail(ctx context.Context, id int) (domain.LeaveDetail, error) {
+	leave, err := s.GetLeaveByID(ctx, id)
+	if err != nil {
+		return domain.LeaveDetail{}, err
+	}
+	detail := domain.LeaveDetail{Leave: leave}
+	// the requester does not see the leaves of their colleagues
+	if actor, ok := auth.ActorFromContext(ctx); ok && actor.EmployeeID == leave.EmployeeID {
+		return detail, nil
+	}
+
+	// the team is everyone reporting to the requester's manager
+	managerIDs, err := s.employeeRepo.GetManagerChain(ctx, leave.EmployeeID)
+	if err != nil {
+		return domain.LeaveDetail{}, fmt.Errorf("failed to get manager chain: %w", err)
+	}
+	var teamIDs []int
+	if len(managerIDs) > 0 {
+		reportIDs, err := s.employeeRepo.GetSubordinateIDs(ctx, managerIDs[0])
+		if err != nil {
+			return domain.LeaveDetail{}, fmt.Errorf("failed to get team: %w", err)
+		}
+		for _, reportID := range reportIDs {
+			if reportID != leave.EmployeeID {
+				teamIDs = append(teamIDs, reportID)
+			}
+		}
+	}
+	if detail.TeamAlsoOut, err = s.getAbsences(ctx, teamIDs, leave.StartDate, leave.EndDate); err != nil {
+		return domain.LeaveDetail{}, err
+	}
+
+	return detail, nil
+}
+
+func (s *leaveService) GetTeamCalendar(ctx context.Context, managerID int, from, to time.Time) (domain.TeamCalendar,
+	error) {
+	if to.Before(from) {
+		return domain.TeamCalendar{}, fmt.Errorf("%w, to is before from", common_errors.ErrInvalidInput)
+	}
+	if !to.Before(from.AddDate(0, 0, domain.MaxCalendarDays)) {
+		return domain.TeamCalendar{}, fmt.Errorf("%w, a calendar covers at most %d days",
+			common_errors.ErrInvalidInput, domain.MaxCalendarDays)
+	}
+	if _, err := auth.AuthorizeEmployee(ctx, s.employeeRepo, managerID); err != nil {
+		return domain.TeamCalendar{}, err
+	}
+
+	reportIDs, err := s.employeeRepo.GetSubordinateIDs(ctx, managerID)
+	if err != nil {
+		return domain.TeamCalendar{}, fmt.Errorf("failed to get reports: %w", err)
+	}
+	absences, err := s.getAbsences(ctx, reportIDs, from, to)
+	if err != nil {
+		return domain.TeamCalendar{}, err
+	}
+
+	return domain.NewTeamCalendar(managerID, from, to, absences), nil
+}
+
+// getAbsences returns the approved and pending leaves of the employees overlapping the dates, the earliest first
+func (s *leaveService) getAbsences(ctx context.Context, employeeIDs []int, from, to time.Time) ([]domain.Absence,
+	error) {
+	absences := []domain.Absence{}
+	if len(employeeIDs) == 0 {
+		return absences, nil
+	}
+
+	leaves, err := s.leaveRepo.GetLeaves(ctx, domain.LeavesQuery{
+		EmployeeIDs: employeeIDs,
+		Statuses:    activeStatuses,
+		From:        &from,
+		To:          &to,
+		Sort:        []domain.SortField{{Field: "start_date"}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaves: %w", err)
+	}
+	if len(leaves) == 0 {
+		return absences, nil
+	}
+
+	var absentIDs []int
+	seen := map[int]bool{}
+	for _, leave := range leaves {
+		if !seen[leave.EmployeeID] {
+			seen[leave.EmployeeID] = true
+			absentIDs = append(absentIDs, leave.EmployeeID)
+		}
+	}
+	employees, err := s.employeeRepo.GetEmployeesByIDs(ctx, absentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employees: %w", err)
+	}
+	names := make(map[int]string, len(employees))
+	for _, employee := range employees {
+		names[employee.ID] = employee.Name
+	}
+
+	for _, leave := range leaves {
+		absences = append(absences, domain.Absence{
+			EmployeeID: leave.EmployeeID,
+			Name:       names[leave.EmployeeID],
+			LeaveID:    leave.ID,
+			Type:       leave.Type,
+			Status:     leave.Status,
+			StartDate:  leave.StartDate,
+			EndDate:    leave.EndDate,
+			Unit:       leave.Unit,
+			StartTime:  leave.StartTime,
+			EndTime:    leave.EndTime,
+		})
+	}
+	return absences, nil
+}
+
+func (s *leaveService) getLeaveByID(ctx context.Context, id int) (domain.Leave, error) {
 	leave, err := s.leaveCache.GetLeaveFromCache(ctx, id)
 	if err == nil {
 		s.logger.Infof("[Cache Hit] leave id: %d", id)
@@ -277,3 +1208,176 @@ func (s *leaveService) GetLeaveByID(ctx context.Context, id int) (domain.Leave,
 
 	return leave, nil
 }
+
+func (s *leaveService) EscalateStaleReviews(ctx context.Context, sla time.Duration) (int, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return 0, err
+	}
+
+	leaves, err := s.leaveRepo.GetLeavesWithStaleReviews(ctx, time.Now().Add(-sla))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get leaves with stale reviews: %w", err)
+	}
+
+	return s.reviewChain.Escalate(ctx, common.ElemPtrs(leaves)), nil
+}
+
+// saveReviews saves the leave with the reviews the review chain has passed on
+func (s *leaveService) saveReviews(ctx context.Context, leave *domain.Leave, reviews []review_domain.Review) error {
+	if err := s.leaveRepo.UpdateLeaveAndReviews(ctx, leave, reviews); err != nil {
+		return err
+	}
+	s.delLeaveCaches(ctx, leave)
+	return nil
+}
+
+func (s *leaveService) CancelLeavesAfterTermination(ctx context.Context, employeeID int) (int, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return 0, err
+	}
+
+	employee, err := s.employeeRepo.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return 0, common_errors.ErrResourceNotFound
+		}
+		return 0, fmt.Errorf("failed to get employee: %w", err)
+	}
+	if employee.TerminationDate == nil {
+		return 0, fmt.Errorf("%w, employee %d is not terminated", common_errors.ErrStatusConflict, employeeID)
+	}
+
+	leaves, err := s.leaveRepo.GetLeaves(ctx, domain.LeavesQuery{EmployeeID: &employeeID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get leaves: %w", err)
+	}
+
+	cancelled := 0
+	for i := range leaves {
+		leave := &leaves[i]
+		if !employee.TerminatedAt(leave.StartDate) {
+			if !employee.TerminatedAt(leave.EndDate) || !slices.Contains(activeStatuses, leave.Status) {
+				continue
+			}
+			// the leave straddles the last day, it is withdrawn like the later ones if nothing is left before it
+			shortened, err := s.shortenLeave(ctx, leave, employee)
+			if err != nil {
+				return cancelled, fmt.Errorf("failed to shorten leave %d: %w", leave.ID, err)
+			}
+			if shortened {
+				continue
+			}
+		}
+		status := review_domain.ReviewStatusRevoked
+		if leave.Status == review_domain.ReviewStatusReviewing {
+			status = review_domain.ReviewStatusCancelled
+		}
+		if !leave.Status.CanTransitionTo(status) {
+			continue
+		}
+		if err := s.closeLeave(ctx, leave, status); err != nil {
+			return cancelled, fmt.Errorf("failed to withdraw leave %d: %w", leave.ID, err)
+		}
+		cancelled++
+	}
+
+	return cancelled, nil
+}
+
+// shortenLeave brings the end of a leave forward to the last day of the employee and gives back the days it no
+// longer takes, it reports false when no working day is left and the leave has to be withdrawn instead
+func (s *leaveService) shortenLeave(ctx context.Context, leave *domain.Leave,
+	employee employee_domain.Employee) (bool, error) {
+	workingDays, err := s.holidayService.WorkingDaysBetween(ctx, employee.Region, leave.StartDate,
+		*employee.TerminationDate)
+	if err != nil {
+		return false, fmt.Errorf("failed to count working days: %w", err)
+	}
+	if workingDays == 0 {
+		return false, nil
+	}
+	leave.EndDate = *employee.TerminationDate
+	leave.Days = float64(workingDays) * leave.DayFraction()
+
+	// a leave waiting for its approval is debited with its new days when it is approved
+	debited := leave.Status != review_domain.ReviewStatusReviewing
+	err = s.transactor.Do(ctx, func(ctx context.Context) error {
+		if err := s.leaveRepo.UpdateLeaveAndReviews(ctx, leave, nil); err != nil {
+			return fmt.Errorf("failed to update leave: %w", err)
+		}
+		if debited {
+			if err := s.balanceService.ShortenLeave(ctx, leave); err != nil {
+				return fmt.Errorf("failed to shorten leave balance: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	s.delLeaveCaches(ctx, leave)
+	return true, nil
+}
+
+func (s *leaveService) ReassignReviews(ctx context.Context, reviewerID int) (int, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return 0, err
+	}
+
+	leaves, err := s.leaveRepo.GetLeavesPendingOn(ctx, reviewerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get leaves: %w", err)
+	}
+
+	return s.reviewChain.Reassign(ctx, common.ElemPtrs(leaves), reviewerID)
+}
+
+func (s *leaveService) BackfillDays(ctx context.Context) (int, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return 0, err
+	}
+
+	leaves, err := s.leaveRepo.GetLeavesWithoutDays(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(leaves) == 0 {
+		return 0, nil
+	}
+
+	employeeIDs := make([]int, 0, len(leaves))
+	for _, leave := range leaves {
+		employeeIDs = append(employeeIDs, leave.EmployeeID)
+	}
+	employees, err := s.employeeRepo.GetEmployeesByIDs(ctx, employeeIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get employees: %w", err)
+	}
+	regions := make(map[int]string, len(employees))
+	for _, employee := range employees {
+		regions[employee.ID] = employee.Region
+	}
+
+	updated := 0
+	for i := range leaves {
+		leave := &leaves[i]
+		workingDays, err := s.holidayService.WorkingDaysBetween(ctx, regions[leave.EmployeeID], leave.StartDate,
+			leave.EndDate)
+		if err != nil {
+			return updated, fmt.Errorf("failed to count working days of leave %d: %w", leave.ID, err)
+		}
+		if workingDays == 0 {
+			// the calendar changed since the leave was created, there is nothing to count
+			s.logger.Warnf("leave %d does not contain any working day", leave.ID)
+			continue
+		}
+		if err := s.leaveRepo.UpdateLeaveDays(ctx, leave.ID, float64(workingDays)*leave.DayFraction()); err != nil {
+			return updated, err
+		}
+		s.delLeaveCaches(ctx, leave)
+		updated++
+	}
+
+	return updated, nil
+}
@@ -2,20 +2,94 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	approval_domain "hr-system/internal/approvals/domain"
+	mocks_approval_service "hr-system/internal/approvals/service/mocks"
+	mocks_attachment_service "hr-system/internal/attachments/service/mocks"
+	"hr-system/internal/auth"
+	balance_domain "hr-system/internal/balances/domain"
+	mocks_balance_service "hr-system/internal/balances/service/mocks"
 	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	mocks_transaction "hr-system/internal/common/transaction/mocks"
+	coverage_domain "hr-system/internal/coverage/domain"
+	mocks_coverage_service "hr-system/internal/coverage/service/mocks"
+	mocks_delegation_service "hr-system/internal/delegations/service/mocks"
 	employee_domain "hr-system/internal/employees/domain"
 	mocks_employee_repo "hr-system/internal/employees/repo/mocks"
+	mocks_holiday_service "hr-system/internal/holidays/service/mocks"
 	mocks_leave_cache "hr-system/internal/leaves/cache/mocks"
 	"hr-system/internal/leaves/domain"
 	mocks_leave_repo "hr-system/internal/leaves/repo/mocks"
+	leavetype_domain "hr-system/internal/leavetypes/domain"
+	mocks_leavetype_service "hr-system/internal/leavetypes/service/mocks"
+	review_domain "hr-system/internal/reviews/domain"
+	mocks_toil_service "hr-system/internal/toil/service/mocks"
 )
 
+func actorCtx(employeeID int, roles ...auth.Role) context.Context {
+	return auth.WithActor(context.Background(), auth.Actor{EmployeeID: employeeID, Roles: roles})
+}
+
+// inTransaction runs the operation passed to the mocked transactor
+func inTransaction(ctx context.Context, op func(ctx context.Context) error) error {
+	return op(ctx)
+}
+
+// serviceMocks are the mocked dependencies of the leave service under test
+type serviceMocks struct {
+	leaveRepo         *mocks_leave_repo.LeaveRepo
+	employeeRepo      *mocks_employee_repo.EmployeeRepo
+	leaveCache        *mocks_leave_cache.LeaveCache
+	balanceService    *mocks_balance_service.BalanceService
+	holidayService    *mocks_holiday_service.HolidayService
+	policyService     *mocks_approval_service.PolicyService
+	delegationService *mocks_delegation_service.DelegationService
+	coverageService   *mocks_coverage_service.RuleService
+	leaveTypeService  *mocks_leavetype_service.TypeService
+	attachmentService *mocks_attachment_service.AttachmentService
+	toilService       *mocks_toil_service.TOILService
+	transactor        *mocks_transaction.Transactor
+}
+
+// newMockService creates the leave service on mocked dependencies, the transactions run the operation as is
+func newMockService(t *testing.T) (LeaveService, *serviceMocks) {
+	mocks := &serviceMocks{
+		leaveRepo:         mocks_leave_repo.NewLeaveRepo(t),
+		employeeRepo:      mocks_employee_repo.NewEmployeeRepo(t),
+		leaveCache:        mocks_leave_cache.NewLeaveCache(t),
+		balanceService:    mocks_balance_service.NewBalanceService(t),
+		holidayService:    mocks_holiday_service.NewHolidayService(t),
+		policyService:     mocks_approval_service.NewPolicyService(t),
+		delegationService: mocks_delegation_service.NewDelegationService(t),
+		coverageService:   mocks_coverage_service.NewRuleService(t),
+		leaveTypeService:  mocks_leavetype_service.NewTypeService(t),
+		attachmentService: mocks_attachment_service.NewAttachmentService(t),
+		toilService:       mocks_toil_service.NewTOILService(t),
+		transactor:        mocks_transaction.NewTransactor(t),
+	}
+	mocks.transactor.On("Do", mock.Anything, mock.Anything).Return(inTransaction).Maybe()
+
+	service := NewLeaveService(common.NewLogger(), mocks.leaveRepo, mocks.employeeRepo, mocks.leaveCache,
+		mocks.balanceService, mocks.holidayService, mocks.policyService, mocks.delegationService,
+		mocks.coverageService, mocks.leaveTypeService, mocks.attachmentService, mocks.toilService, mocks.transactor)
+	return service, mocks
+}
+
+// daysInStartYear splits the days of a leave by year for the mocked balance service, all in its first year
+func daysInStartYear(_ context.Context, leave *domain.Leave) (map[int]float64, error) {
+	return map[int]float64{leave.StartDate.Year(): leave.Days}, nil
+}
+
+// annualType is the leave type of the fake leaves in the catalogue
+var annualType = leavetype_domain.Type{Code: domain.LeaveTypeAnnual, Name: "Annual leave", Paid: true}
+
 func genFakeLeave() domain.Leave {
 	startDate := time.Now().Truncate(time.Second)
 	endDate := startDate.Add(time.Hour * 24)
@@ -26,15 +100,17 @@ func genFakeLeave() domain.Leave {
 		Type:              domain.LeaveTypeAnnual,
 		StartDate:         startDate,
 		EndDate:           endDate,
+		Days:              2,
 		Reason:            "Vacation",
-		Status:            domain.ReviewStatusReviewing,
+		Status:            review_domain.ReviewStatusReviewing,
 		CurrentReviewerID: common.GetPtr(2),
-		Reviews: []domain.LeaveReview{
+		Reviews: []review_domain.Review{
 			{
-				ID:         1,
-				LeaveID:    1,
-				ReviewerID: 2,
-				Status:     domain.ReviewStatusReviewing,
+				ID:          1,
+				SubjectType: review_domain.SubjectTypeLeave,
+				SubjectID:   1,
+				ReviewerID:  2,
+				Status:      review_domain.ReviewStatusReviewing,
 			},
 		},
 	}
@@ -43,21 +119,26 @@ func genFakeLeave() domain.Leave {
 }
 
 func TestCreateLeave(t *testing.T) {
-	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
-	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
-	mockLeaveCache := mocks_leave_cache.NewLeaveCache(t)
-	logger := common.NewLogger()
-
-	service := NewLeaveService(logger, mockLeaveRepo, mockEmployeeRepo, mockLeaveCache)
+	service, mocks := newMockService(t)
 
-	ctx := context.Background()
+	ctx := actorCtx(3, auth.RoleEmployee)
 	leave := genFakeLeave()
 
-	mockEmployeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+	mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
 		Return(employee_domain.Employee{ID: 1, ManagerID: nil}, nil).Once()
-	mockLeaveRepo.On("CreateLeave", ctx, &leave).Return(nil).Once()
-	mockLeaveCache.On("DelLeavesFromCache", ctx, mock.Anything).Return(nil).Once()
-	mockLeaveCache.On("SetLeaveToCache", ctx, &leave).Return(nil).Once()
+	mocks.holidayService.On("WorkingDaysBetween", ctx, "", leave.StartDate, leave.EndDate).Return(2, nil).Once()
+	mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate, activeStatuses).
+		Return([]domain.Leave{}, nil).Once()
+	mocks.balanceService.On("DaysByYear", ctx, mock.Anything).Return(daysInStartYear)
+	mocks.balanceService.On("GetBalance", ctx, leave.EmployeeID, leave.Type, leave.StartDate.Year()).
+		Return(balance_domain.Balance{Remaining: 2}, nil).Once()
+	mocks.leaveRepo.On("GetLeaves", ctx, domain.LeavesQuery{EmployeeID: &leave.EmployeeID}).
+		Return([]domain.Leave{}, nil).Once()
+	mocks.leaveRepo.On("CreateLeave", ctx, &leave).Return(nil).Once()
+	mocks.balanceService.On("DebitLeave", ctx, &leave).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+	mocks.leaveCache.On("SetLeaveToCache", ctx, &leave).Return(nil).Once()
 
 	createdLeave, err := service.CreateLeave(ctx, &leave)
 	assert.NoError(t, err)
@@ -65,20 +146,15 @@ func TestCreateLeave(t *testing.T) {
 }
 
 func TestReviewLeave(t *testing.T) {
-	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
-	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
-	mockLeaveCache := mocks_leave_cache.NewLeaveCache(t)
-	logger := common.NewLogger()
+	service, mocks := newMockService(t)
 
-	service := NewLeaveService(logger, mockLeaveRepo, mockEmployeeRepo, mockLeaveCache)
-
-	ctx := context.Background()
+	ctx := actorCtx(2, auth.RoleManager)
 
 	leave := genFakeLeave()
 	reviewerID := leave.Reviews[0].ReviewerID
 
-	mockLeaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
-	mockEmployeeRepo.On("GetEmployeeByID", ctx, reviewerID).
+	mocks.leaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, reviewerID).
 		Return(employee_domain.Employee{ID: 1,
 			Positions: []employee_domain.Position{
 				{
@@ -86,10 +162,1215 @@ func TestReviewLeave(t *testing.T) {
 				},
 			}}, nil).
 		Once()
-	mockLeaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).Return(nil).Once()
-	mockLeaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
-	mockLeaveCache.On("DelLeavesFromCache", ctx, mock.Anything).Return(nil).Twice()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: leave.EmployeeID}, nil).Once()
+	mocks.policyService.On("GetRequirement", ctx, leave.Type, "", leave.Days).
+		Return(approval_domain.Requirement{ManagerLevel: 0, Approvers: 1}, nil).Once()
+	mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate, takenStatuses).
+		Return([]domain.Leave{leave}, nil).Once()
+	mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+	mocks.balanceService.On("DebitLeave", ctx, mock.Anything).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+
+	err := service.ReviewLeave(ctx, leave.ID, reviewerID, review_domain.ReviewStatusApproved, "", false)
+	assert.NoError(t, err)
+}
+
+func TestCreateLeave_InsufficientBalance(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	leave := genFakeLeave()
+	pendingLeave := genFakeLeave()
+	pendingLeave.ID = 2
+
+	mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: 1, ManagerID: common.GetPtr(2)}, nil).Once()
+	mocks.holidayService.On("WorkingDaysBetween", ctx, "", leave.StartDate, leave.EndDate).Return(2, nil).Once()
+	mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate, activeStatuses).
+		Return([]domain.Leave{}, nil).Once()
+	mocks.balanceService.On("DaysByYear", ctx, mock.Anything).Return(daysInStartYear)
+	mocks.balanceService.On("GetBalance", ctx, leave.EmployeeID, leave.Type, leave.StartDate.Year()).
+		Return(balance_domain.Balance{Remaining: 3}, nil).Once()
+	// the 2 days in review leave only 1 day to take
+	mocks.leaveRepo.On("GetLeaves", ctx, domain.LeavesQuery{EmployeeID: &leave.EmployeeID}).
+		Return([]domain.Leave{pendingLeave}, nil).Once()
+
+	_, err := service.CreateLeave(ctx, &leave)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
+
+func TestCreateLeave_AcrossYears(t *testing.T) {
+	ctx := actorCtx(3, auth.RoleEmployee)
+	// 3 working days in December and 1 in January, after the new year's day
+	newLeave := func() domain.Leave {
+		leave := genFakeLeave()
+		leave.StartDate = time.Date(2026, 12, 29, 0, 0, 0, 0, time.Local)
+		leave.EndDate = time.Date(2027, 1, 3, 0, 0, 0, 0, time.Local)
+		leave.Reviews = nil
+		return leave
+	}
+	// a day of January is waiting for review already
+	pendingLeave := genFakeLeave()
+	pendingLeave.ID = 2
+	pendingLeave.StartDate = time.Date(2027, 1, 11, 0, 0, 0, 0, time.Local)
+	pendingLeave.EndDate = pendingLeave.StartDate
+	pendingLeave.Days = 1
+
+	expectLeave := func(mocks *serviceMocks, leave *domain.Leave, remaining2026 float64) {
+		mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+		mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+			Return(employee_domain.Employee{ID: leave.EmployeeID}, nil).Once()
+		mocks.holidayService.On("WorkingDaysBetween", ctx, "", leave.StartDate, leave.EndDate).Return(4, nil).Once()
+		mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate,
+			activeStatuses).Return([]domain.Leave{}, nil).Once()
+		mocks.balanceService.On("DaysByYear", ctx, leave).Return(map[int]float64{2026: 3, 2027: 1}, nil).Once()
+		mocks.leaveRepo.On("GetLeaves", ctx, domain.LeavesQuery{EmployeeID: &leave.EmployeeID}).
+			Return([]domain.Leave{pendingLeave}, nil).Once()
+		mocks.balanceService.On("DaysByYear", ctx, &pendingLeave).Return(daysInStartYear).Once()
+		mocks.balanceService.On("GetBalance", ctx, leave.EmployeeID, leave.Type, 2026).
+			Return(balance_domain.Balance{Remaining: remaining2026}, nil).Once()
+	}
+
+	t.Run("enough in each year", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		// the 3 days of December are all there are of 2026, the day of January is left after the pending one
+		leave := newLeave()
+		expectLeave(mocks, &leave, 3)
+		mocks.balanceService.On("GetBalance", ctx, leave.EmployeeID, leave.Type, 2027).
+			Return(balance_domain.Balance{Remaining: 2}, nil).Once()
+		mocks.leaveRepo.On("CreateLeave", ctx, &leave).Return(nil).Once()
+		mocks.balanceService.On("DebitLeave", ctx, &leave).Return(nil).Once()
+		mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+		mocks.leaveCache.On("SetLeaveToCache", ctx, &leave).Return(nil).Once()
+
+		_, err := service.CreateLeave(ctx, &leave)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not enough next year", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		// plenty of 2026 is left, the pending leave takes the only day of 2027
+		leave := newLeave()
+		expectLeave(mocks, &leave, 10)
+		mocks.balanceService.On("GetBalance", ctx, leave.EmployeeID, leave.Type, 2027).
+			Return(balance_domain.Balance{Remaining: 1}, nil).Once()
+
+		_, err := service.CreateLeave(ctx, &leave)
+		assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+	})
+}
+
+func TestReviewLeave_Revocation(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(2, auth.RoleManager)
+
+	leave := genFakeLeave()
+	leave.Status = review_domain.ReviewStatusRevoking
+	reviewerID := leave.Reviews[0].ReviewerID
+
+	mocks.leaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*domain.Leave)
+			assert.Equal(t, review_domain.ReviewStatusApproved, updated.Status)
+			assert.Nil(t, updated.CurrentReviewerID)
+		}).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+
+	// rejecting a revocation keeps the leave approved
+	err := service.ReviewLeave(ctx, leave.ID, reviewerID, review_domain.ReviewStatusRejected, "", false)
+	assert.NoError(t, err)
+}
+
+func TestCancelLeave(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	leave := genFakeLeave()
+
+	mocks.leaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*domain.Leave)
+			reviews := args.Get(2).([]review_domain.Review)
+			assert.Equal(t, review_domain.ReviewStatusCancelled, updated.Status)
+			assert.Nil(t, updated.CurrentReviewerID)
+			assert.Len(t, reviews, 1)
+			assert.Equal(t, review_domain.ReviewStatusCancelled, reviews[0].Status)
+		}).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+
+	err := service.CancelLeave(ctx, leave.ID, leave.EmployeeID)
+	assert.NoError(t, err)
+
+	// approved leave can not be cancelled
+	leave.Status = review_domain.ReviewStatusApproved
+	mocks.leaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
+	err = service.CancelLeave(ctx, leave.ID, leave.EmployeeID)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+}
+
+func TestRevokeLeave(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	leave := genFakeLeave()
+	leave.Status = review_domain.ReviewStatusApproved
+	leave.CurrentReviewerID = nil
+	leave.StartDate = leave.StartDate.AddDate(0, 0, 7)
+	leave.EndDate = leave.EndDate.AddDate(0, 0, 7)
+
+	mocks.leaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: leave.EmployeeID, ManagerID: common.GetPtr(2)}, nil).Once()
+	mocks.delegationService.On("GetDelegate", ctx, 2, mock.Anything).Return(nil, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*domain.Leave)
+			reviews := args.Get(2).([]review_domain.Review)
+			assert.Equal(t, review_domain.ReviewStatusRevoking, updated.Status)
+			assert.Equal(t, 2, *updated.CurrentReviewerID)
+			assert.Len(t, reviews, 1)
+			assert.Equal(t, review_domain.ReviewStatusReviewing, reviews[0].Status)
+		}).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+
+	err := service.RevokeLeave(ctx, leave.ID, leave.EmployeeID)
+	assert.NoError(t, err)
+
+	// started leave can not be revoked
+	leave.StartDate = time.Now().AddDate(0, 0, -1)
+	mocks.leaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
+	err = service.RevokeLeave(ctx, leave.ID, leave.EmployeeID)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+}
+
+func TestCreateLeave_Overlap(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	leave := genFakeLeave()
+	leave.ID = 0
+	existingLeave := genFakeLeave()
+
+	mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: 1, ManagerID: common.GetPtr(2)}, nil).Once()
+	mocks.holidayService.On("WorkingDaysBetween", ctx, "", leave.StartDate, leave.EndDate).Return(2, nil).Once()
+	mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate, activeStatuses).
+		Return([]domain.Leave{existingLeave}, nil).Once()
+
+	_, err := service.CreateLeave(ctx, &leave)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+	assert.Contains(t, err.Error(), "[1]")
+}
+
+func TestCreateLeave_HalfDay(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	leave := genFakeLeave()
+	leave.ID = 0
+	leave.EndDate = leave.StartDate
+	leave.Unit = domain.LeaveUnitPM
+	// the morning of the day is already taken
+	morningLeave := genFakeLeave()
+	morningLeave.EndDate = morningLeave.StartDate
+	morningLeave.Unit = domain.LeaveUnitAM
 
-	err := service.ReviewLeave(ctx, leave.ID, reviewerID, domain.ReviewStatusApproved, "")
+	mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: 1, ManagerID: nil}, nil).Once()
+	mocks.holidayService.On("WorkingDaysBetween", ctx, "", leave.StartDate, leave.EndDate).Return(1, nil).Once()
+	mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate, activeStatuses).
+		Return([]domain.Leave{morningLeave}, nil).Once()
+	mocks.balanceService.On("DaysByYear", ctx, mock.Anything).Return(daysInStartYear)
+	mocks.balanceService.On("GetBalance", ctx, leave.EmployeeID, leave.Type, leave.StartDate.Year()).
+		Return(balance_domain.Balance{Remaining: 1}, nil).Once()
+	mocks.leaveRepo.On("GetLeaves", ctx, domain.LeavesQuery{EmployeeID: &leave.EmployeeID}).
+		Return([]domain.Leave{}, nil).Once()
+	mocks.leaveRepo.On("CreateLeave", ctx, &leave).Return(nil).Once()
+	mocks.balanceService.On("DebitLeave", ctx, &leave).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+	mocks.leaveCache.On("SetLeaveToCache", ctx, &leave).Return(nil).Once()
+
+	createdLeave, err := service.CreateLeave(ctx, &leave)
 	assert.NoError(t, err)
+	assert.Equal(t, 0.5, createdLeave.Days)
+}
+
+func TestCreateLeave_LeaveType(t *testing.T) {
+	ctx := actorCtx(3, auth.RoleEmployee)
+
+	t.Run("unknown", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		leave := genFakeLeave()
+		leave.Type = "study"
+		mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).
+			Return(leavetype_domain.Type{}, common_errors.ErrResourceNotFound).Once()
+
+		_, err := service.CreateLeave(ctx, &leave)
+		assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		leave := genFakeLeave()
+		mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).
+			Return(leavetype_domain.Type{Code: leave.Type, Disabled: true}, nil).Once()
+
+		_, err := service.CreateLeave(ctx, &leave)
+		assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		leave := genFakeLeave()
+		mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).
+			Return(leavetype_domain.Type{Code: leave.Type, MaxConsecutiveDays: common.GetPtr(1.5)}, nil).Once()
+		mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+			Return(employee_domain.Employee{ID: leave.EmployeeID}, nil).Once()
+		mocks.holidayService.On("WorkingDaysBetween", ctx, "", leave.StartDate, leave.EndDate).Return(2, nil).Once()
+
+		_, err := service.CreateLeave(ctx, &leave)
+		assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+	})
+
+	t.Run("unpaid", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		leave := genFakeLeave()
+		leave.Type = "unpaid"
+		mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).
+			Return(leavetype_domain.Type{Code: leave.Type}, nil).Once()
+		mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+			Return(employee_domain.Employee{ID: leave.EmployeeID}, nil).Once()
+		mocks.holidayService.On("WorkingDaysBetween", ctx, "", leave.StartDate, leave.EndDate).Return(2, nil).Once()
+		mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate,
+			activeStatuses).Return([]domain.Leave{}, nil).Once()
+		// no balance to check
+		mocks.leaveRepo.On("CreateLeave", ctx, &leave).Return(nil).Once()
+		// nor a debit
+		mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+		mocks.leaveCache.On("SetLeaveToCache", ctx, &leave).Return(nil).Once()
+
+		_, err := service.CreateLeave(ctx, &leave)
+		assert.NoError(t, err)
+	})
+}
+
+func TestCreateLeave_CompOff(t *testing.T) {
+	ctx := actorCtx(3, auth.RoleEmployee)
+	compOffType := leavetype_domain.Type{Code: domain.LeaveTypeCompOff, Paid: true}
+
+	service, mocks := newMockService(t)
+
+	leave := genFakeLeave()
+	leave.Type = domain.LeaveTypeCompOff
+	mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(compOffType, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: leave.EmployeeID}, nil).Once()
+	mocks.holidayService.On("WorkingDaysBetween", ctx, "", leave.StartDate, leave.EndDate).Return(2, nil).Once()
+	mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate,
+		activeStatuses).Return([]domain.Leave{}, nil).Once()
+	// taken from the overtime allowance instead of the yearly balance
+	mocks.toilService.On("CheckAllowance", ctx, &leave).
+		Return(fmt.Errorf("%w, insufficient comp-off allowance", common_errors.ErrInvalidInput)).Once()
+
+	_, err := service.CreateLeave(ctx, &leave)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
+
+func TestCreateLeave_Attachment(t *testing.T) {
+	ctx := actorCtx(3, auth.RoleEmployee)
+	// a doctor's note is needed after 1 day
+	sickType := leavetype_domain.Type{Code: domain.LeaveTypeSick, Paid: true, RequiresAttachment: true,
+		AttachmentAfterDays: 1}
+
+	expectLeave := func(mocks *serviceMocks, leave *domain.Leave) {
+		mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(sickType, nil).Once()
+		mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+			Return(employee_domain.Employee{ID: leave.EmployeeID}, nil).Once()
+		mocks.holidayService.On("WorkingDaysBetween", ctx, "", leave.StartDate, leave.EndDate).Return(2, nil).Once()
+		mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate,
+			activeStatuses).Return([]domain.Leave{}, nil).Once()
+		mocks.balanceService.On("DaysByYear", ctx, mock.Anything).Return(daysInStartYear)
+		mocks.balanceService.On("GetBalance", ctx, leave.EmployeeID, leave.Type, leave.StartDate.Year()).
+			Return(balance_domain.Balance{Remaining: 5}, nil).Once()
+		mocks.leaveRepo.On("GetLeaves", ctx, domain.LeavesQuery{EmployeeID: &leave.EmployeeID}).
+			Return([]domain.Leave{}, nil).Once()
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		leave := genFakeLeave()
+		leave.Type = domain.LeaveTypeSick
+		expectLeave(mocks, &leave)
+
+		_, err := service.CreateLeave(ctx, &leave)
+		assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+		assert.Contains(t, err.Error(), "needs an attachment")
+	})
+
+	t.Run("submitted", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		leave := genFakeLeave()
+		leave.Type = domain.LeaveTypeSick
+		leave.AttachmentIDs = []int{7}
+		expectLeave(mocks, &leave)
+		mocks.attachmentService.On("CheckPending", ctx, []int{7}).Return(nil).Once()
+		mocks.leaveRepo.On("CreateLeave", ctx, &leave).Return(nil).Once()
+		mocks.attachmentService.On("AttachToLeave", ctx, leave.ID, []int{7}).Return(nil).Once()
+		mocks.balanceService.On("DebitLeave", ctx, &leave).Return(nil).Once()
+		mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+		mocks.leaveCache.On("SetLeaveToCache", ctx, &leave).Return(nil).Once()
+
+		_, err := service.CreateLeave(ctx, &leave)
+		assert.NoError(t, err)
+	})
+}
+
+func TestReviewLeave_OverlapApproved(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(2, auth.RoleManager)
+	leave := genFakeLeave()
+	approvedLeave := genFakeLeave()
+	approvedLeave.ID = 2
+	approvedLeave.Status = review_domain.ReviewStatusApproved
+	reviewerID := leave.Reviews[0].ReviewerID
+
+	mocks.leaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, reviewerID).
+		Return(employee_domain.Employee{ID: reviewerID,
+			Positions: []employee_domain.Position{{ManagerLevel: 5}}}, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: leave.EmployeeID}, nil).Once()
+	mocks.policyService.On("GetRequirement", ctx, leave.Type, "", leave.Days).
+		Return(approval_domain.Requirement{ManagerLevel: 0, Approvers: 1}, nil).Once()
+	// the overlapping leave was approved while this one was still in review
+	mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate, takenStatuses).
+		Return([]domain.Leave{approvedLeave}, nil).Once()
+
+	err := service.ReviewLeave(ctx, leave.ID, reviewerID, review_domain.ReviewStatusApproved, "", false)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+}
+
+func TestReviewLeave_Coverage(t *testing.T) {
+	ctx := actorCtx(2, auth.RoleManager)
+	leave := genFakeLeave()
+	reviewerID := leave.Reviews[0].ReviewerID
+	day := time.Date(leave.StartDate.Year(), leave.StartDate.Month(), leave.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+	absence := domain.Leave{ID: 9, EmployeeID: 4, Status: review_domain.ReviewStatusApproved, StartDate: leave.StartDate,
+		EndDate: leave.StartDate}
+
+	// employee 4 is already out, approving the leave leaves 1 of the 3 reports working
+	setup := func(mocks *serviceMocks, rule *coverage_domain.Rule) {
+		mocks.leaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
+		mocks.employeeRepo.On("GetEmployeeByID", ctx, reviewerID).
+			Return(employee_domain.Employee{ID: reviewerID,
+				Positions: []employee_domain.Position{{ManagerLevel: 5}}}, nil).Once()
+		mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+			Return(employee_domain.Employee{ID: leave.EmployeeID, ManagerID: common.GetPtr(2)}, nil).Once()
+		mocks.policyService.On("GetRequirement", ctx, leave.Type, "", leave.Days).
+			Return(approval_domain.Requirement{ManagerLevel: 0, Approvers: 1}, nil).Once()
+		mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate, takenStatuses).
+			Return([]domain.Leave{}, nil).Once()
+		mocks.coverageService.On("GetRuleOfManager", ctx, 2).Return(rule, nil).Once()
+		mocks.employeeRepo.On("GetReportIDs", ctx, 2, 1).Return([]int{3, 4, 5}, nil).Once()
+		mocks.employeeRepo.On("GetEmployeesByIDs", ctx, []int{3, 4, 5}).
+			Return([]employee_domain.Employee{{ID: 3}, {ID: 4}, {ID: 5}}, nil).Once()
+		mocks.leaveRepo.On("GetLeaves", ctx, mock.Anything).Return([]domain.Leave{absence}, nil).Once()
+		mocks.holidayService.On("WorkingDates", ctx, "", leave.StartDate, leave.EndDate).
+			Return([]time.Time{day}, nil).Once()
+	}
+
+	t.Run("blocked", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		setup(mocks, &coverage_domain.Rule{ID: 1, ManagerID: 2, MinWorkingPercent: 60})
+		err := service.ReviewLeave(ctx, leave.ID, reviewerID, review_domain.ReviewStatusApproved, "", true)
+		assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+	})
+
+	t.Run("half day", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		// employee 4 is out for the afternoon only, 1.5 of 3 working meets a rule of 50%
+		absence.Unit = domain.LeaveUnitPM
+		defer func() { absence.Unit = "" }()
+		setup(mocks, &coverage_domain.Rule{ID: 1, ManagerID: 2, MinWorkingPercent: 50})
+		mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+		mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+		mocks.balanceService.On("DebitLeave", ctx, mock.Anything).Return(nil).Once()
+		mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+		mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+		err := service.ReviewLeave(ctx, leave.ID, reviewerID, review_domain.ReviewStatusApproved, "", false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("needs override", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		setup(mocks, &coverage_domain.Rule{ID: 1, ManagerID: 2, MinWorkingPercent: 60, AllowOverride: true})
+		err := service.ReviewLeave(ctx, leave.ID, reviewerID, review_domain.ReviewStatusApproved, "", false)
+		assert.ErrorIs(t, err, common_errors.ErrOverrideRequired)
+	})
+
+	t.Run("overridden", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		setup(mocks, &coverage_domain.Rule{ID: 1, ManagerID: 2, MinWorkingPercent: 60, AllowOverride: true})
+		mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+		mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything,
+			mock.MatchedBy(func(reviews []review_domain.Review) bool {
+				return reviews[0].CoverageOverride && common.PtrEqual(reviews[0].OverriddenByID, &reviewerID)
+			})).Return(nil).Once()
+		mocks.balanceService.On("DebitLeave", ctx, mock.Anything).Return(nil).Once()
+		mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+		mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+		err := service.ReviewLeave(ctx, leave.ID, reviewerID, review_domain.ReviewStatusApproved, "", true)
+		assert.NoError(t, err)
+	})
+
+	t.Run("met", func(t *testing.T) {
+		service, mocks := newMockService(t)
+
+		// 1 of 3 working meets a rule of 30%, no override is recorded
+		setup(mocks, &coverage_domain.Rule{ID: 1, ManagerID: 2, MinWorkingPercent: 30})
+		mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+		mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything,
+			mock.MatchedBy(func(reviews []review_domain.Review) bool {
+				return !reviews[0].CoverageOverride && reviews[0].OverriddenByID == nil
+			})).Return(nil).Once()
+		mocks.balanceService.On("DebitLeave", ctx, mock.Anything).Return(nil).Once()
+		mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+		mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+		err := service.ReviewLeave(ctx, leave.ID, reviewerID, review_domain.ReviewStatusApproved, "", true)
+		assert.NoError(t, err)
+	})
+}
+
+func TestCreateLeave_NoWorkingDay(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	leave := genFakeLeave()
+
+	mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: 1, Region: "TW"}, nil).Once()
+	mocks.holidayService.On("WorkingDaysBetween", ctx, "TW", leave.StartDate, leave.EndDate).Return(0, nil).Once()
+
+	_, err := service.CreateLeave(ctx, &leave)
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+}
+
+func TestReviewLeave_PolicyNeedsMoreApprovers(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(2, auth.RoleManager)
+	leave := genFakeLeave()
+	reviewerID := leave.Reviews[0].ReviewerID
+
+	mocks.leaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, reviewerID).
+		Return(employee_domain.Employee{ID: reviewerID, ManagerID: common.GetPtr(1),
+			Positions: []employee_domain.Position{{ManagerLevel: 5}}}, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: leave.EmployeeID,
+			Positions: []employee_domain.Position{{Level: "senior"}}}, nil).Once()
+	mocks.policyService.On("GetRequirement", ctx, leave.Type, "senior", leave.Days).
+		Return(approval_domain.Requirement{ManagerLevel: 0, Approvers: 2}, nil).Once()
+	mocks.delegationService.On("GetDelegate", ctx, 1, mock.Anything).Return(nil, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*domain.Leave)
+			reviews := args.Get(2).([]review_domain.Review)
+			assert.Equal(t, review_domain.ReviewStatusReviewing, updated.Status)
+			assert.Equal(t, 1, *updated.CurrentReviewerID)
+			assert.Len(t, reviews, 2)
+			assert.Equal(t, review_domain.ReviewStatusApproved, reviews[0].Status)
+			assert.Equal(t, 1, reviews[1].ReviewerID)
+		}).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+
+	// the manager level is high enough but the policy asks for a second approver
+	err := service.ReviewLeave(ctx, leave.ID, reviewerID, review_domain.ReviewStatusApproved, "", false)
+	assert.NoError(t, err)
+}
+
+func TestPreviewApprovalChain(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	leave := genFakeLeave()
+
+	mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: leave.EmployeeID, ManagerID: common.GetPtr(2)}, nil).Once()
+	mocks.holidayService.On("WorkingDaysBetween", ctx, "", leave.StartDate, leave.EndDate).Return(2, nil).Once()
+	mocks.policyService.On("GetRequirement", ctx, leave.Type, "", 2.0).
+		Return(approval_domain.Requirement{ManagerLevel: 3, Approvers: 1}, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, 2).
+		Return(employee_domain.Employee{ID: 2, Name: "Lead", ManagerID: common.GetPtr(1),
+			Positions: []employee_domain.Position{{ManagerLevel: 1}}}, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, 1).
+		Return(employee_domain.Employee{ID: 1, Name: "Director",
+			Positions: []employee_domain.Position{{ManagerLevel: 3}}}, nil).Once()
+
+	chain, err := service.PreviewApprovalChain(ctx, &leave)
+	assert.NoError(t, err)
+	assert.True(t, chain.Satisfiable)
+	assert.Equal(t, 3, chain.RequiredManagerLevel)
+	assert.Equal(t, []domain.ApprovalStep{
+		{ReviewerID: 2, Name: "Lead", ManagerLevel: 1},
+		{ReviewerID: 1, Name: "Director", ManagerLevel: 3},
+	}, chain.Reviewers)
+}
+
+func TestCreateLeave_Delegated(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	leave := genFakeLeave()
+	leave.ID = 0
+	leave.Reviews = nil
+
+	mocks.leaveTypeService.On("GetTypeByCode", ctx, leave.Type).Return(annualType, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: leave.EmployeeID, ManagerID: common.GetPtr(2)}, nil).Once()
+	mocks.holidayService.On("WorkingDaysBetween", ctx, "", leave.StartDate, leave.EndDate).Return(2, nil).Once()
+	mocks.leaveRepo.On("GetOverlappingLeaves", ctx, leave.EmployeeID, leave.StartDate, leave.EndDate, activeStatuses).
+		Return([]domain.Leave{}, nil).Once()
+	mocks.balanceService.On("DaysByYear", ctx, mock.Anything).Return(daysInStartYear)
+	mocks.balanceService.On("GetBalance", ctx, leave.EmployeeID, leave.Type, leave.StartDate.Year()).
+		Return(balance_domain.Balance{Remaining: 2}, nil).Once()
+	mocks.leaveRepo.On("GetLeaves", ctx, domain.LeavesQuery{EmployeeID: &leave.EmployeeID}).
+		Return([]domain.Leave{}, nil).Once()
+	// the manager is out of office
+	mocks.delegationService.On("GetDelegate", ctx, 2, mock.Anything).Return(common.GetPtr(5), nil).Once()
+	mocks.leaveRepo.On("CreateLeave", ctx, &leave).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+	mocks.leaveCache.On("SetLeaveToCache", ctx, &leave).Return(nil).Once()
+
+	createdLeave, err := service.CreateLeave(ctx, &leave)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, *createdLeave.CurrentReviewerID)
+	assert.Len(t, createdLeave.Reviews, 1)
+	assert.Equal(t, 5, createdLeave.Reviews[0].ReviewerID)
+	assert.Equal(t, 2, *createdLeave.Reviews[0].OnBehalfOfID)
+}
+
+func TestReviewLeave_Delegate(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(5, auth.RoleEmployee)
+	// the leave was waiting for the manager before the delegation started
+	leave := genFakeLeave()
+	managerID := leave.Reviews[0].ReviewerID
+	delegateID := 5
+
+	mocks.leaveRepo.On("GetLeaveByID", ctx, leave.ID).Return(leave, nil).Once()
+	mocks.delegationService.On("GetDelegate", ctx, managerID, mock.Anything).
+		Return(common.GetPtr(delegateID), nil).Once()
+	// the authority of the manager decides the next reviewer
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, managerID).
+		Return(employee_domain.Employee{ID: managerID, ManagerID: common.GetPtr(1),
+			Positions: []employee_domain.Position{{ManagerLevel: 1}}}, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, leave.EmployeeID).
+		Return(employee_domain.Employee{ID: leave.EmployeeID}, nil).Once()
+	mocks.policyService.On("GetRequirement", ctx, leave.Type, "", leave.Days).
+		Return(approval_domain.Requirement{ManagerLevel: 3, Approvers: 1}, nil).Once()
+	mocks.delegationService.On("GetDelegate", ctx, 1, mock.Anything).Return(nil, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*domain.Leave)
+			reviews := args.Get(2).([]review_domain.Review)
+			assert.Equal(t, 1, *updated.CurrentReviewerID)
+			assert.Len(t, reviews, 2)
+			assert.Equal(t, delegateID, reviews[0].ReviewerID)
+			assert.Equal(t, managerID, *reviews[0].OnBehalfOfID)
+			assert.Equal(t, 1, reviews[1].ReviewerID)
+			assert.Nil(t, reviews[1].OnBehalfOfID)
+		}).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+
+	err := service.ReviewLeave(ctx, leave.ID, delegateID, review_domain.ReviewStatusApproved, "", false)
+	assert.NoError(t, err)
+
+	// others can not review on behalf of the manager
+	otherCtx := actorCtx(6, auth.RoleManager)
+	mocks.leaveRepo.On("GetLeaveByID", otherCtx, leave.ID).Return(leave, nil).Once()
+	mocks.delegationService.On("GetDelegate", otherCtx, managerID, mock.Anything).Return(nil, nil).Once()
+	err = service.ReviewLeave(otherCtx, leave.ID, 6, review_domain.ReviewStatusApproved, "", false)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+
+	// nor in the name of the delegate
+	err = service.ReviewLeave(otherCtx, leave.ID, delegateID, review_domain.ReviewStatusApproved, "", false)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestGetLeaves_Delegated(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(5, auth.RoleEmployee)
+	delegateID := 5
+	ownLeave := genFakeLeave()
+	ownLeave.CurrentReviewerID = &delegateID
+	delegatedLeave := genFakeLeave()
+	delegatedLeave.ID = 2
+	// the delegate does not review their own leave
+	leaveOfDelegate := genFakeLeave()
+	leaveOfDelegate.ID = 3
+	leaveOfDelegate.EmployeeID = delegateID
+
+	// the leaves waiting for the manager are queried together with the reviewer's own queue
+	query := domain.LeavesQuery{CurrentReviewerID: &delegateID}
+	mocks.delegationService.On("GetDelegators", ctx, delegateID, mock.Anything).Return([]int{2}, nil).Once()
+	repoQuery := query
+	repoQuery.DelegatorIDs = []int{2}
+	mocks.leaveCache.On("GetListsGeneration", ctx).Return(int64(4), nil).Once()
+	mocks.leaveCache.On("GetLeavesFromCache", ctx, int64(4), repoQuery).
+		Return(domain.LeavesPage{}, common_errors.ErrResourceNotFound).Once()
+	mocks.leaveRepo.On("GetLeaves", ctx, repoQuery).Return([]domain.Leave{delegatedLeave, ownLeave}, nil).Once()
+	mocks.leaveCache.On("SetLeavesToCache", ctx, int64(4), repoQuery, mock.Anything).Return(nil).Once()
+
+	page, err := service.GetLeaves(ctx, query)
+	assert.NoError(t, err)
+	assert.Len(t, page.Leaves, 2)
+	assert.Equal(t, delegatedLeave.ID, page.Leaves[0].ID)
+	assert.Equal(t, ownLeave.ID, page.Leaves[1].ID)
+	assert.Nil(t, page.Next)
+	assert.Nil(t, page.TotalCount)
+}
+
+func TestGetLeaves_Page(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(5, auth.RoleEmployee)
+	delegateID := 5
+	leaves := []domain.Leave{genFakeLeave(), genFakeLeave(), genFakeLeave()}
+	leaves[0].ID, leaves[1].ID, leaves[2].ID = 9, 8, 7
+
+	query := domain.LeavesQuery{CurrentReviewerID: &delegateID, Limit: 2, WithCount: true}
+	mocks.delegationService.On("GetDelegators", ctx, delegateID, mock.Anything).Return([]int{2}, nil).Twice()
+	mocks.leaveCache.On("GetListsGeneration", ctx).Return(int64(0), nil).Twice()
+	mocks.leaveCache.On("GetLeavesFromCache", ctx, int64(0), mock.Anything).
+		Return(domain.LeavesPage{}, common_errors.ErrResourceNotFound).Twice()
+	mocks.leaveCache.On("SetLeavesToCache", ctx, int64(0), mock.Anything, mock.Anything).Return(nil).Twice()
+	repoQuery := query
+	repoQuery.DelegatorIDs = []int{2}
+	repoQuery.Limit = 3
+	mocks.leaveRepo.On("GetLeaves", ctx, repoQuery).Return(leaves, nil).Once()
+	repoQuery.Limit = 2
+	mocks.leaveRepo.On("CountLeaves", ctx, repoQuery).Return(3, nil).Once()
+
+	page, err := service.GetLeaves(ctx, query)
+	assert.NoError(t, err)
+	assert.Equal(t, leaves[:2], page.Leaves)
+	assert.Equal(t, &domain.LeaveCursor{ID: 8}, page.Next)
+	assert.Equal(t, common.GetPtr(3), page.TotalCount)
+
+	// the last page
+	query.After, query.WithCount = page.Next, false
+	repoQuery = query
+	repoQuery.DelegatorIDs = []int{2}
+	repoQuery.Limit = 3
+	mocks.leaveRepo.On("GetLeaves", ctx, repoQuery).Return(leaves[2:], nil).Once()
+
+	page, err = service.GetLeaves(ctx, query)
+	assert.NoError(t, err)
+	assert.Equal(t, leaves[2:], page.Leaves)
+	assert.Nil(t, page.Next)
+	assert.Nil(t, page.TotalCount)
+}
+
+func TestEscalateStaleReviews(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := auth.AsSystem(context.Background())
+	leave := genFakeLeave()
+	reviewerID := leave.Reviews[0].ReviewerID
+	// the top of the chain has nobody to escalate to
+	topLeave := genFakeLeave()
+	topLeave.ID = 2
+	topLeave.CurrentReviewerID = common.GetPtr(1)
+	topLeave.Reviews[0].ReviewerID = 1
+
+	mocks.leaveRepo.On("GetLeavesWithStaleReviews", ctx, mock.Anything).
+		Return([]domain.Leave{leave, topLeave}, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, reviewerID).
+		Return(employee_domain.Employee{ID: reviewerID, ManagerID: common.GetPtr(1)}, nil).Once()
+	mocks.delegationService.On("GetDelegate", ctx, 1, mock.Anything).Return(nil, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx,
+		mock.MatchedBy(func(l *domain.Leave) bool { return l.ID == leave.ID }), mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*domain.Leave)
+			reviews := args.Get(2).([]review_domain.Review)
+			assert.Equal(t, review_domain.ReviewStatusReviewing, updated.Status)
+			assert.Equal(t, 1, *updated.CurrentReviewerID)
+			assert.Len(t, reviews, 2)
+			assert.Equal(t, review_domain.ReviewStatusEscalated, reviews[0].Status)
+			assert.Equal(t, 1, reviews[1].ReviewerID)
+			assert.True(t, reviews[1].Escalated)
+		}).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, 1).Return(employee_domain.Employee{ID: 1}, nil).Once()
+	// the review is kept on the top of the chain and not tried again
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx,
+		mock.MatchedBy(func(l *domain.Leave) bool { return l.ID == topLeave.ID }), mock.Anything).
+		Run(func(args mock.Arguments) {
+			reviews := args.Get(2).([]review_domain.Review)
+			assert.Len(t, reviews, 1)
+			assert.Equal(t, review_domain.ReviewStatusReviewing, reviews[0].Status)
+			assert.True(t, reviews[0].Unescalatable)
+		}).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, topLeave.ID).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Twice()
+
+	escalated, err := service.EscalateStaleReviews(ctx, 72*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, escalated)
+}
+
+func TestCountApprovals(t *testing.T) {
+	reviews := []review_domain.Review{
+		{Status: review_domain.ReviewStatusApproved},
+		{Status: review_domain.ReviewStatusApproved},
+		{Status: review_domain.ReviewStatusEscalated},
+		{Status: review_domain.ReviewStatusReassigned},
+		{Status: review_domain.ReviewStatusReviewing},
+	}
+	// reviews passed up do not break the chain of approvals
+	assert.Equal(t, 2, countApprovals(reviews))
+
+	// approvals of the leave do not count for its revocation
+	reviews[4].Revocation = true
+	assert.Equal(t, 0, countApprovals(reviews))
+}
+
+func TestGetLeaves_Scoped(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	employeeID := 3
+	query := domain.LeavesQuery{EmployeeID: &employeeID}
+
+	// an employee sees only their own leaves
+	ctx := actorCtx(4, auth.RoleEmployee)
+	_, err := service.GetLeaves(ctx, query)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+
+	// so is the review queue of someone else
+	_, err = service.GetLeaves(ctx, domain.LeavesQuery{CurrentReviewerID: common.GetPtr(2)})
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+
+	// a manager sees the leaves of their reporting subtree
+	ctx = actorCtx(1, auth.RoleManager)
+	mocks.employeeRepo.On("GetManagerChain", ctx, employeeID).Return([]int{2, 1}, nil).Once()
+	mocks.leaveCache.On("GetListsGeneration", ctx).Return(int64(1), nil).Once()
+	mocks.leaveCache.On("GetLeavesFromCache", ctx, int64(1), query).
+		Return(domain.LeavesPage{Leaves: []domain.Leave{genFakeLeave()}}, nil).Once()
+	page, err := service.GetLeaves(ctx, query)
+	assert.NoError(t, err)
+	assert.Len(t, page.Leaves, 1)
+
+	// the team of a manager is resolved to their reporting subtree
+	teamQuery := domain.LeavesQuery{ManagerID: common.GetPtr(2)}
+	mocks.employeeRepo.On("GetManagerChain", ctx, 2).Return([]int{1}, nil).Once()
+	mocks.employeeRepo.On("GetSubordinateIDs", ctx, 2).Return([]int{3, 5}, nil).Once()
+	repoQuery := teamQuery
+	repoQuery.EmployeeIDs = []int{3, 5}
+	mocks.leaveCache.On("GetListsGeneration", ctx).Return(int64(1), nil).Once()
+	mocks.leaveCache.On("GetLeavesFromCache", ctx, int64(1), repoQuery).
+		Return(domain.LeavesPage{}, common_errors.ErrResourceNotFound).Once()
+	mocks.leaveRepo.On("GetLeaves", ctx, repoQuery).Return([]domain.Leave{genFakeLeave()}, nil).Once()
+	mocks.leaveCache.On("SetLeavesToCache", ctx, int64(1), repoQuery, mock.Anything).Return(nil).Once()
+	page, err = service.GetLeaves(ctx, teamQuery)
+	assert.NoError(t, err)
+	assert.Len(t, page.Leaves, 1)
+
+	// leaves of the whole company are for HR and payroll
+	_, err = service.GetLeaves(ctx, domain.LeavesQuery{})
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+
+	// the date range must not be reversed
+	hrCtx := actorCtx(9, auth.RoleHRAdmin)
+	from := time.Date(2024, 3, 10, 0, 0, 0, 0, time.Local)
+	_, err = service.GetLeaves(hrCtx, domain.LeavesQuery{From: &from, To: common.GetPtr(from.AddDate(0, 0, -1))})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// the leaves of the whole company are listed a page at a time
+	_, err = service.GetLeaves(hrCtx, domain.LeavesQuery{})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// the types are of the catalogue
+	mocks.leaveTypeService.On("GetTypeByCode", hrCtx, domain.LeaveType("sabbatical")).
+		Return(leavetype_domain.Type{}, common_errors.ErrResourceNotFound).Once()
+	_, err = service.GetLeaves(hrCtx, domain.LeavesQuery{Types: []domain.LeaveType{"sabbatical"}, Limit: 20})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	sickQuery := domain.LeavesQuery{Types: []domain.LeaveType{domain.LeaveTypeSick}, Limit: 20}
+	mocks.leaveTypeService.On("GetTypeByCode", hrCtx, domain.LeaveTypeSick).
+		Return(leavetype_domain.Type{Code: domain.LeaveTypeSick, Paid: true}, nil).Once()
+	mocks.leaveCache.On("GetListsGeneration", hrCtx).Return(int64(1), nil).Once()
+	mocks.leaveCache.On("GetLeavesFromCache", hrCtx, int64(1), sickQuery).
+		Return(domain.LeavesPage{Leaves: []domain.Leave{genFakeLeave()}}, nil).Once()
+	page, err = service.GetLeaves(hrCtx, sickQuery)
+	assert.NoError(t, err)
+	assert.Len(t, page.Leaves, 1)
+}
+
+func TestGetLeaveByID_Scoped(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	leave := genFakeLeave()
+
+	// the reviewer sees the leave they review
+	ctx := actorCtx(2, auth.RoleEmployee)
+	mocks.leaveCache.On("GetLeaveFromCache", ctx, leave.ID).Return(leave, nil).Once()
+	_, err := service.GetLeaveByID(ctx, leave.ID)
+	assert.NoError(t, err)
+
+	ctx = actorCtx(4, auth.RoleManager)
+	mocks.leaveCache.On("GetLeaveFromCache", ctx, leave.ID).Return(leave, nil).Once()
+	mocks.employeeRepo.On("GetManagerChain", ctx, leave.EmployeeID).Return([]int{2, 1}, nil).Once()
+	_, err = service.GetLeaveByID(ctx, leave.ID)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestGetLeaveDetail(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	leave := genFakeLeave()
+	teamLeave := domain.Leave{ID: 5, EmployeeID: 4, Type: domain.LeaveTypeSick,
+		Status: review_domain.ReviewStatusApproved, StartDate: leave.StartDate, EndDate: leave.StartDate}
+
+	// the reviewer sees who else of the team is out
+	ctx := actorCtx(2, auth.RoleManager)
+	mocks.leaveCache.On("GetLeaveFromCache", ctx, leave.ID).Return(leave, nil).Once()
+	mocks.employeeRepo.On("GetManagerChain", ctx, leave.EmployeeID).Return([]int{2, 1}, nil).Once()
+	mocks.employeeRepo.On("GetSubordinateIDs", ctx, 2).Return([]int{3, 4, 5}, nil).Once()
+	mocks.leaveRepo.On("GetLeaves", ctx, mock.MatchedBy(func(query domain.LeavesQuery) bool {
+		return assert.ObjectsAreEqual([]int{4, 5}, query.EmployeeIDs) && query.From.Equal(leave.StartDate) &&
+			query.To.Equal(leave.EndDate)
+	})).Return([]domain.Leave{teamLeave}, nil).Once()
+	mocks.employeeRepo.On("GetEmployeesByIDs", ctx, []int{4}).
+		Return([]employee_domain.Employee{{ID: 4, Name: "Jane"}}, nil).Once()
+	detail, err := service.GetLeaveDetail(ctx, leave.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, leave, detail.Leave)
+	assert.Equal(t, []domain.Absence{{EmployeeID: 4, Name: "Jane", LeaveID: 5, Type: domain.LeaveTypeSick,
+		Status: review_domain.ReviewStatusApproved, StartDate: leave.StartDate, EndDate: leave.StartDate}},
+		detail.TeamAlsoOut)
+
+	// but the requester does not
+	ctx = actorCtx(leave.EmployeeID, auth.RoleEmployee)
+	mocks.leaveCache.On("GetLeaveFromCache", ctx, leave.ID).Return(leave, nil).Once()
+	detail, err = service.GetLeaveDetail(ctx, leave.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, detail.TeamAlsoOut)
+}
+
+func TestGetTeamCalendar(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	day := func(d int) time.Time {
+		return time.Date(2024, 5, d, 0, 0, 0, 0, time.Local)
+	}
+	ctx := actorCtx(2, auth.RoleManager)
+
+	_, err := service.GetTeamCalendar(ctx, 2, day(3), day(1))
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+	_, err = service.GetTeamCalendar(ctx, 2, day(1), day(1).AddDate(0, 0, domain.MaxCalendarDays))
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	mocks.employeeRepo.On("GetSubordinateIDs", ctx, 2).Return([]int{3, 4}, nil).Once()
+	leaves := []domain.Leave{
+		{ID: 7, EmployeeID: 3, Status: review_domain.ReviewStatusApproved, StartDate: day(1), EndDate: day(2)},
+		{ID: 8, EmployeeID: 4, Status: review_domain.ReviewStatusReviewing, StartDate: day(2), EndDate: day(5)},
+	}
+	mocks.leaveRepo.On("GetLeaves", ctx, domain.LeavesQuery{
+		EmployeeIDs: []int{3, 4},
+		Statuses:    activeStatuses,
+		From:        common.GetPtr(day(1)),
+		To:          common.GetPtr(day(3)),
+		Sort:        []domain.SortField{{Field: "start_date"}},
+	}).Return(leaves, nil).Once()
+	mocks.employeeRepo.On("GetEmployeesByIDs", ctx, []int{3, 4}).
+		Return([]employee_domain.Employee{{ID: 3, Name: "John"}, {ID: 4, Name: "Jane"}}, nil).Once()
+
+	calendar, err := service.GetTeamCalendar(ctx, 2, day(1), day(3))
+	assert.NoError(t, err)
+	assert.Len(t, calendar.Days, 3)
+	var out [][]int
+	for _, d := range calendar.Days {
+		var ids []int
+		for _, absence := range d.Absences {
+			ids = append(ids, absence.LeaveID)
+		}
+		out = append(out, ids)
+	}
+	assert.Equal(t, [][]int{{7}, {7, 8}, {8}}, out)
+	assert.Equal(t, "Jane", calendar.Days[2].Absences[0].Name)
+
+	// the manager of another team can not see it
+	ctx = actorCtx(5, auth.RoleManager)
+	mocks.employeeRepo.On("GetManagerChain", ctx, 2).Return([]int{1}, nil).Once()
+	_, err = service.GetTeamCalendar(ctx, 2, day(1), day(3))
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestCancelLeavesAfterTermination(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	reviewing := genFakeLeave()
+	approved := genFakeLeave()
+	approved.ID = 2
+	approved.Status = review_domain.ReviewStatusApproved
+	approved.CurrentReviewerID = nil
+	approved.Reviews[0].Status = review_domain.ReviewStatusApproved
+	// taken before the last day
+	past := genFakeLeave()
+	past.ID = 3
+	past.Status = review_domain.ReviewStatusApproved
+	past.StartDate = past.StartDate.AddDate(0, 0, -10)
+	past.EndDate = past.StartDate.AddDate(0, 0, 1)
+	// straddling the last day
+	straddling := genFakeLeave()
+	straddling.ID = 4
+	straddling.Status = review_domain.ReviewStatusApproved
+	straddling.StartDate = straddling.StartDate.AddDate(0, 0, -3)
+	straddling.Days = 4
+	employee := employee_domain.Employee{ID: reviewing.EmployeeID,
+		TerminationDate: common.GetPtr(reviewing.StartDate.AddDate(0, 0, -1))}
+
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, employee.ID).Return(employee, nil).Once()
+	mocks.leaveRepo.On("GetLeaves", ctx, domain.LeavesQuery{EmployeeID: &employee.ID}).
+		Return([]domain.Leave{reviewing, approved, past, straddling}, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*domain.Leave)
+			reviews := args.Get(2).([]review_domain.Review)
+			assert.Equal(t, review_domain.ReviewStatusCancelled, updated.Status)
+			assert.Nil(t, updated.CurrentReviewerID)
+			assert.Len(t, reviews, 1)
+		}).Return(nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*domain.Leave)
+			assert.Equal(t, approved.ID, updated.ID)
+			assert.Equal(t, review_domain.ReviewStatusRevoked, updated.Status)
+		}).Return(nil).Once()
+	mocks.balanceService.On("CreditLeave", ctx, mock.Anything).Return(nil).Once()
+	// the straddling leave stays approved until the last day, the days after it are given back
+	mocks.holidayService.On("WorkingDaysBetween", ctx, employee.Region, straddling.StartDate, *employee.TerminationDate).
+		Return(3, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*domain.Leave)
+			assert.Equal(t, straddling.ID, updated.ID)
+			assert.Equal(t, review_domain.ReviewStatusApproved, updated.Status)
+			assert.Equal(t, *employee.TerminationDate, updated.EndDate)
+			assert.Equal(t, float64(3), updated.Days)
+		}).Return(nil).Once()
+	mocks.balanceService.On("ShortenLeave", ctx, mock.MatchedBy(func(leave *domain.Leave) bool {
+		return leave.ID == straddling.ID
+	})).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, mock.Anything).Return(nil).Times(3)
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Times(3)
+
+	cancelled, err := service.CancelLeavesAfterTermination(ctx, employee.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cancelled)
+
+	// nothing to cancel before the employee is terminated
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, employee.ID).
+		Return(employee_domain.Employee{ID: employee.ID}, nil).Once()
+	_, err = service.CancelLeavesAfterTermination(ctx, employee.ID)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+
+	_, err = service.CancelLeavesAfterTermination(actorCtx(1, auth.RoleManager), employee.ID)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestReassignReviews(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	leave := genFakeLeave()
+	reviewerID := *leave.CurrentReviewerID
+
+	mocks.leaveRepo.On("GetLeavesPendingOn", ctx, reviewerID).Return([]domain.Leave{leave}, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, reviewerID).
+		Return(employee_domain.Employee{ID: reviewerID, ManagerID: common.GetPtr(1)}, nil).Once()
+	mocks.delegationService.On("GetDelegate", ctx, 1, mock.Anything).Return(nil, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*domain.Leave)
+			reviews := args.Get(2).([]review_domain.Review)
+			assert.Equal(t, 1, *updated.CurrentReviewerID)
+			assert.Len(t, reviews, 2)
+			assert.Equal(t, review_domain.ReviewStatusReassigned, reviews[0].Status)
+			assert.Equal(t, 1, reviews[1].ReviewerID)
+			assert.False(t, reviews[1].Escalated)
+		}).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+
+	reassigned, err := service.ReassignReviews(ctx, reviewerID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reassigned)
+}
+
+func TestReassignReviews_Delegate(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	leave := genFakeLeave()
+	leave.Reviews[0].ReviewerID = 5
+	leave.Reviews[0].OnBehalfOfID = common.GetPtr(2)
+	leave.CurrentReviewerID = common.GetPtr(5)
+
+	// the departing delegate gives the review back to the manager they act for, who is not delegated to again
+	mocks.leaveRepo.On("GetLeavesPendingOn", ctx, 5).Return([]domain.Leave{leave}, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveAndReviews", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			updated := args.Get(1).(*domain.Leave)
+			reviews := args.Get(2).([]review_domain.Review)
+			assert.Equal(t, 2, *updated.CurrentReviewerID)
+			assert.Len(t, reviews, 2)
+			assert.Equal(t, review_domain.ReviewStatusReassigned, reviews[0].Status)
+			assert.Equal(t, 2, reviews[1].ReviewerID)
+			assert.Nil(t, reviews[1].OnBehalfOfID)
+			assert.Equal(t, review_domain.ReviewStatusReviewing, reviews[1].Status)
+		}).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, leave.ID).Return(nil).Once()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Once()
+
+	reassigned, err := service.ReassignReviews(ctx, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reassigned)
+}
+
+func TestReassignReviews_NoManager(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	leave := genFakeLeave()
+	leave.Reviews[0].OnBehalfOfID = common.GetPtr(4)
+	leave.Reviews[0].ReviewerID = 5
+	leave.CurrentReviewerID = common.GetPtr(5)
+
+	// the review is delegated on behalf of the departing reviewer, who has no manager to take it
+	mocks.leaveRepo.On("GetLeavesPendingOn", ctx, 4).Return([]domain.Leave{leave}, nil).Once()
+	mocks.employeeRepo.On("GetEmployeeByID", ctx, 4).Return(employee_domain.Employee{ID: 4}, nil).Once()
+
+	reassigned, err := service.ReassignReviews(ctx, 4)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+	assert.Equal(t, 0, reassigned)
+}
+
+func TestBackfillDays(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	leave := genFakeLeave()
+	leave.Days = 0
+	halfDay := genFakeLeave()
+	halfDay.ID = 2
+	halfDay.Days = 0
+	halfDay.Unit = domain.LeaveUnitAM
+	halfDay.EndDate = halfDay.StartDate
+
+	mocks.leaveRepo.On("GetLeavesWithoutDays", ctx).Return([]domain.Leave{leave, halfDay}, nil).Once()
+	mocks.employeeRepo.On("GetEmployeesByIDs", ctx, []int{3, 3}).
+		Return([]employee_domain.Employee{{ID: 3, Region: "TW"}}, nil).Once()
+	mocks.holidayService.On("WorkingDaysBetween", ctx, "TW", leave.StartDate, leave.EndDate).Return(2, nil).Once()
+	mocks.holidayService.On("WorkingDaysBetween", ctx, "TW", halfDay.StartDate, halfDay.EndDate).Return(1, nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveDays", ctx, 1, 2.0).Return(nil).Once()
+	mocks.leaveRepo.On("UpdateLeaveDays", ctx, 2, 0.5).Return(nil).Once()
+	mocks.leaveCache.On("DelLeaveFromCache", ctx, mock.Anything).Return(nil).Twice()
+	mocks.leaveCache.On("InvalidateLeavesLists", ctx).Return(nil).Twice()
+
+	backfilled, err := service.BackfillDays(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, backfilled)
+}
+
+func TestGetUnsatisfiableChains(t *testing.T) {
+	service, mocks := newMockService(t)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	yesterday := time.Now().AddDate(0, 0, -1)
+	employees := []employee_domain.Employee{
+		{ID: 1, Name: "Director", Positions: []employee_domain.Position{{ManagerLevel: 3, StartDate: yesterday}}},
+		{ID: 2, Name: "Lead", ManagerID: common.GetPtr(1),
+			Positions: []employee_domain.Position{{Level: "Lead", ManagerLevel: 1, StartDate: yesterday}}},
+		{ID: 3, Name: "Engineer", ManagerID: common.GetPtr(2),
+			Positions: []employee_domain.Position{{Level: "Junior", StartDate: yesterday}}},
+	}
+	// a long sick leave needs a manager level the chain does not reach, every other leave is approved by level 3
+	policies := []approval_domain.Policy{
+		{LeaveType: domain.LeaveTypeSick, Rules: []approval_domain.Rule{
+			{MinDays: 0, MaxDays: common.GetPtr(3.0), RequiredManagerLevel: 3, RequiredApprovers: 1},
+			{MinDays: 3, RequiredManagerLevel: 5, RequiredApprovers: 1},
+		}},
+		{LeaveType: domain.LeaveTypeAnnual, Rules: []approval_domain.Rule{
+			{MinDays: 0, RequiredManagerLevel: 3, RequiredApprovers: 1},
+		}},
+	}
+
+	mocks.policyService.On("GetPolicies", ctx, (*domain.LeaveType)(nil)).Return(policies, nil).Once()
+	// nobody can take a disabled type anymore
+	mocks.leaveTypeService.On("GetTypes", ctx).Return([]leavetype_domain.Type{
+		annualType,
+		{Code: domain.LeaveTypeSick, Paid: true},
+		{Code: "sabbatical", Disabled: true,
+			Thresholds: []approval_domain.Rule{{MinDays: 0, RequiredManagerLevel: 9, RequiredApprovers: 1}}},
+	}, nil).Once()
+	// two pages to follow the cursor
+	query := employee_domain.EmployeesQuery{Page: 1, PageSize: 500, Status: employee_domain.EmployeeStatusAll,
+		SkipCount: true}
+	next := &employee_domain.EmployeeCursor{ID: employees[1].ID}
+	mocks.employeeRepo.On("GetEmployees", ctx, query).
+		Return(employee_domain.EmployeesPage{Employees: employees[:2], Next: next}, nil).Once()
+	query.After = next
+	mocks.employeeRepo.On("GetEmployees", ctx, query).
+		Return(employee_domain.EmployeesPage{Employees: employees[2:]}, nil).Once()
+
+	chains, err := service.GetUnsatisfiableChains(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.UnsatisfiableChain{
+		{EmployeeID: 2, Name: "Lead", PositionLevel: "Lead", LeaveType: domain.LeaveTypeSick,
+			RequiredManagerLevel: 5, RequiredApprovers: 1,
+			Reviewers: []domain.ApprovalStep{{ReviewerID: 1, Name: "Director", ManagerLevel: 3}}},
+		{EmployeeID: 3, Name: "Engineer", PositionLevel: "Junior", LeaveType: domain.LeaveTypeSick,
+			RequiredManagerLevel: 5, RequiredApprovers: 1,
+			Reviewers: []domain.ApprovalStep{
+				{ReviewerID: 2, Name: "Lead", ManagerLevel: 1},
+				{ReviewerID: 1, Name: "Director", ManagerLevel: 3},
+			}},
+	}, chains)
+
+	_, err = service.GetUnsatisfiableChains(actorCtx(1, auth.RoleManager))
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
 }
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	approval_domain "hr-system/internal/approvals/domain"
+	leave_domain "hr-system/internal/leaves/domain"
+)
+
+// Type is a leave type of the catalogue, the leaves refer to it by its code
+type Type struct {
+	Code leave_domain.LeaveType `json:"code" gorm:"primaryKey;type:varchar(50)" validate:"required,max=50,lowercase"`
+	Name string                 `json:"name" gorm:"type:varchar(100);not null" validate:"required,max=100"`
+	// Paid leaves are drawn from the balance of the type, unpaid leaves are taken without one
+	Paid bool `json:"paid" gorm:"not null"`
+	// RequiresAttachment asks for a supporting document, e.g. a doctor's note,
+	// with every leave taking more than AttachmentAfterDays
+	RequiresAttachment  bool    `json:"requires_attachment" gorm:"not null;default:false"`
+	AttachmentAfterDays float64 `json:"attachment_after_days" gorm:"type:decimal(6,2);not null;default:0" validate:"gte=0"`
+	// MaxConsecutiveDays is the most working days a single leave of the type takes, nil means no limit
+	MaxConsecutiveDays *float64 `json:"max_consecutive_days" gorm:"type:decimal(6,2)" validate:"omitempty,gt=0"`
+	// Thresholds decide who approves the leaves of the type when no approval policy is set for it,
+	// approval_domain.DefaultRules apply when they are empty
+	Thresholds []approval_domain.Rule `json:"thresholds" gorm:"type:text;serializer:json" validate:"dive"`
+	// Disabled types are kept for the leaves taken before but can not be requested anymore
+	Disabled  bool      `json:"disabled" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// BuiltInTypes are seeded into an empty catalogue
+var BuiltInTypes = []Type{
+	{Code: leave_domain.LeaveTypeAnnual, Name: "Annual leave", Paid: true},
+	{Code: leave_domain.LeaveTypeSick, Name: "Sick leave", Paid: true},
+	{Code: leave_domain.LeaveTypeCompOff, Name: "Compensatory time off", Paid: true},
+}
+
+// NeedsAttachment reports whether a leave of the type taking the days can only be submitted with an attachment
+func (t *Type) NeedsAttachment(days float64) bool {
+	return t.RequiresAttachment && days > t.AttachmentAfterDays
+}
+
+// CheckLength makes sure a leave of the type may take the days
+func (t *Type) CheckLength(days float64) error {
+	if t.MaxConsecutiveDays != nil && days > *t.MaxConsecutiveDays {
+		return fmt.Errorf("a %s leave takes at most %.1f days, requested: %.1f days", t.Code, *t.MaxConsecutiveDays, days)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	approval_domain "hr-system/internal/approvals/domain"
+	"hr-system/internal/common"
+	leave_domain "hr-system/internal/leaves/domain"
+	"hr-system/internal/leavetypes/domain"
+	"hr-system/internal/leavetypes/service"
+	"hr-system/internal/middleware"
+)
+
+type TypeHandler struct {
+	typeService service.TypeService
+	logger      *common.Logger
+}
+
+func NewTypeHandler(logger *common.Logger, typeService service.TypeService) *TypeHandler {
+	return &TypeHandler{
+		typeService: typeService,
+		logger:      logger,
+	}
+}
+
+type TypeRequest struct {
+	Name                string                 `json:"name" binding:"required"`
+	Paid                bool                   `json:"paid"`
+	RequiresAttachment  bool                   `json:"requires_attachment"`
+	AttachmentAfterDays float64                `json:"attachment_after_days"`
+	MaxConsecutiveDays  *float64               `json:"max_consecutive_days"`
+	Thresholds          []approval_domain.Rule `json:"thresholds"`
+	Disabled            bool                   `json:"disabled"`
+}
+
+type CreateTypeRequest struct {
+	Code string `json:"code" binding:"required"`
+	TypeRequest
+}
+
+func (r *TypeRequest) toType(code string) *domain.Type {
+	return &domain.Type{
+		Code:                leave_domain.LeaveType(code),
+		Name:                r.Name,
+		Paid:                r.Paid,
+		RequiresAttachment:  r.RequiresAttachment,
+		AttachmentAfterDays: r.AttachmentAfterDays,
+		MaxConsecutiveDays:  r.MaxConsecutiveDays,
+		Thresholds:          r.Thresholds,
+		Disabled:            r.Disabled,
+	}
+}
+
+func (h *TypeHandler) CreateType(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	leaveType, err := h.typeService.CreateType(ctx, req.toType(req.Code))
+	if err != nil {
+		middleware.HandleErr(c, err, "create leave type")
+		return
+	}
+
+	c.JSON(http.StatusCreated, leaveType)
+}
+
+func (h *TypeHandler) GetTypes(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	leaveTypes, err := h.typeService.GetTypes(ctx)
+	if err != nil {
+		middleware.HandleErr(c, err, "get leave types")
+		return
+	}
+
+	c.JSON(http.StatusOK, leaveTypes)
+}
+
+func (h *TypeHandler) GetTypeByCode(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	leaveType, err := h.typeService.GetTypeByCode(ctx, leave_domain.LeaveType(c.Param("code")))
+	if err != nil {
+		middleware.HandleErr(c, err, "get leave type")
+		return
+	}
+
+	c.JSON(http.StatusOK, leaveType)
+}
+
+func (h *TypeHandler) UpdateType(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req TypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	leaveType, err := h.typeService.UpdateType(ctx, req.toType(c.Param("code")))
+	if err != nil {
+		middleware.HandleErr(c, err, "update leave type")
+		return
+	}
+
+	c.JSON(http.StatusOK, leaveType)
+}
@@ -0,0 +1,142 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	leavesdomain "hr-system/internal/leaves/domain"
+	domain "hr-system/internal/leavetypes/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TypeRepo is an autogenerated mock type for the TypeRepo type
+type TypeRepo struct {
+	mock.Mock
+}
+
+// CreateType provides a mock function with given fields: ctx, leaveType
+func (_m *TypeRepo) CreateType(ctx context.Context, leaveType *domain.Type) error {
+	ret := _m.Called(ctx, leaveType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateType")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Type) error); ok {
+		r0 = rf(ctx, leaveType)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetTypeByCode provides a mock function with given fields: ctx, code
+func (_m *TypeRepo) GetTypeByCode(ctx context.Context, code leavesdomain.LeaveType) (domain.Type, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTypeByCode")
+	}
+
+	var r0 domain.Type
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, leavesdomain.LeaveType) (domain.Type, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, leavesdomain.LeaveType) domain.Type); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Get(0).(domain.Type)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, leavesdomain.LeaveType) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTypes provides a mock function with given fields: ctx
+func (_m *TypeRepo) GetTypes(ctx context.Context) ([]domain.Type, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTypes")
+	}
+
+	var r0 []domain.Type
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Type, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Type); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Type)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SeedData provides a mock function with given fields: ctx
+func (_m *TypeRepo) SeedData(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SeedData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateType provides a mock function with given fields: ctx, leaveType
+func (_m *TypeRepo) UpdateType(ctx context.Context, leaveType *domain.Type) error {
+	ret := _m.Called(ctx, leaveType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateType")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Type) error); ok {
+		r0 = rf(ctx, leaveType)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTypeRepo creates a new instance of TypeRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTypeRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TypeRepo {
+	mock := &TypeRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	common_errors "hr-system/internal/common/errors"
+	leave_domain "hr-system/internal/leaves/domain"
+	"hr-system/internal/leavetypes/domain"
+)
+
+type TypeRepo interface {
+	SeedData(ctx context.Context) error
+	CreateType(ctx context.Context, leaveType *domain.Type) error
+	GetTypeByCode(ctx context.Context, code leave_domain.LeaveType) (domain.Type, error)
+	// GetTypes lists every type of the catalogue including the disabled ones
+	GetTypes(ctx context.Context) ([]domain.Type, error)
+	UpdateType(ctx context.Context, leaveType *domain.Type) error
+}
+
+type typeRepo struct {
+	db *gorm.DB
+}
+
+func NewTypeRepo(db *gorm.DB) (TypeRepo, error) {
+	repo := &typeRepo{
+		db: db,
+	}
+
+	if err := repo.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *typeRepo) ensureSchema() error {
+	// AutoMigrate
+	if err := r.db.AutoMigrate(domain.Type{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SeedData seeds the built-in types which are not in the catalogue yet
+func (r *typeRepo) SeedData(ctx context.Context) error {
+	for _, leaveType := range domain.BuiltInTypes {
+		var count int64
+		err := r.db.WithContext(ctx).Model(&domain.Type{}).Where("code = ?", leaveType.Code).Count(&count).Error
+		if err != nil {
+			return fmt.Errorf("failed to count %s leave types: %w", leaveType.Code, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := r.db.WithContext(ctx).Create(&leaveType).Error; err != nil {
+			return fmt.Errorf("failed to seed %s leave type: %w", leaveType.Code, err)
+		}
+	}
+	return nil
+}
+
+func (r *typeRepo) CreateType(ctx context.Context, leaveType *domain.Type) error {
+	if err := r.db.WithContext(ctx).Create(leaveType).Error; err != nil {
+		return fmt.Errorf("failed to create leave type: %w", err)
+	}
+	return nil
+}
+
+func (r *typeRepo) GetTypeByCode(ctx context.Context, code leave_domain.LeaveType) (domain.Type, error) {
+	var leaveType domain.Type
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&leaveType).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Type{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Type{}, fmt.Errorf("failed to find leave type %s: %w", code, err)
+	}
+	return leaveType, nil
+}
+
+func (r *typeRepo) GetTypes(ctx context.Context) ([]domain.Type, error) {
+	var leaveTypes []domain.Type
+	if err := r.db.WithContext(ctx).Order("code ASC").Find(&leaveTypes).Error; err != nil {
+		return nil, fmt.Errorf("failed to get leave types: %w", err)
+	}
+	return leaveTypes, nil
+}
+
+func (r *typeRepo) UpdateType(ctx context.Context, leaveType *domain.Type) error {
+	result := r.db.WithContext(ctx).Model(leaveType).
+		Select("Name", "Paid", "RequiresAttachment", "AttachmentAfterDays", "MaxConsecutiveDays", "Thresholds",
+			"Disabled").
+		Updates(leaveType)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update leave type: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return common_errors.ErrResourceNotFound
+	}
+	return nil
+}
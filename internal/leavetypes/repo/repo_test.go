@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	approval_domain "hr-system/internal/approvals/domain"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/leavetypes/domain"
+)
+
+func setupTestRepo(t *testing.T) *typeRepo {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := &typeRepo{db: db}
+	assert.NoError(t, repo.ensureSchema())
+	return repo
+}
+
+func TestTypes(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.SeedData(ctx))
+	assert.NoError(t, repo.SeedData(ctx))
+	leaveTypes, err := repo.GetTypes(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, leaveTypes, len(domain.BuiltInTypes))
+
+	unpaid := domain.Type{
+		Code:               "unpaid",
+		Name:               "Unpaid leave",
+		MaxConsecutiveDays: common.GetPtr(20.0),
+		Thresholds:         []approval_domain.Rule{{MinDays: 0, RequiredManagerLevel: 3, RequiredApprovers: 1}},
+	}
+	assert.NoError(t, repo.CreateType(ctx, &unpaid))
+
+	found, err := repo.GetTypeByCode(ctx, "unpaid")
+	assert.NoError(t, err)
+	assert.False(t, found.Paid)
+	assert.Equal(t, 20.0, *found.MaxConsecutiveDays)
+	assert.Equal(t, unpaid.Thresholds, found.Thresholds)
+
+	// the limit and the thresholds can be removed again
+	found.MaxConsecutiveDays = nil
+	found.Thresholds = nil
+	found.Disabled = true
+	assert.NoError(t, repo.UpdateType(ctx, &found))
+	found, err = repo.GetTypeByCode(ctx, "unpaid")
+	assert.NoError(t, err)
+	assert.Nil(t, found.MaxConsecutiveDays)
+	assert.Empty(t, found.Thresholds)
+	assert.True(t, found.Disabled)
+
+	_, err = repo.GetTypeByCode(ctx, "study")
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+	err = repo.UpdateType(ctx, &domain.Type{Code: "study", Name: "Study leave"})
+	assert.ErrorIs(t, err, common_errors.ErrResourceNotFound)
+}
@@ -0,0 +1,144 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	leavesdomain "hr-system/internal/leaves/domain"
+	domain "hr-system/internal/leavetypes/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TypeService is an autogenerated mock type for the TypeService type
+type TypeService struct {
+	mock.Mock
+}
+
+// CreateType provides a mock function with given fields: ctx, leaveType
+func (_m *TypeService) CreateType(ctx context.Context, leaveType *domain.Type) (domain.Type, error) {
+	ret := _m.Called(ctx, leaveType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateType")
+	}
+
+	var r0 domain.Type
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Type) (domain.Type, error)); ok {
+		return rf(ctx, leaveType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Type) domain.Type); ok {
+		r0 = rf(ctx, leaveType)
+	} else {
+		r0 = ret.Get(0).(domain.Type)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Type) error); ok {
+		r1 = rf(ctx, leaveType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTypeByCode provides a mock function with given fields: ctx, code
+func (_m *TypeService) GetTypeByCode(ctx context.Context, code leavesdomain.LeaveType) (domain.Type, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTypeByCode")
+	}
+
+	var r0 domain.Type
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, leavesdomain.LeaveType) (domain.Type, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, leavesdomain.LeaveType) domain.Type); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Get(0).(domain.Type)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, leavesdomain.LeaveType) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTypes provides a mock function with given fields: ctx
+func (_m *TypeService) GetTypes(ctx context.Context) ([]domain.Type, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTypes")
+	}
+
+	var r0 []domain.Type
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Type, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Type); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Type)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateType provides a mock function with given fields: ctx, leaveType
+func (_m *TypeService) UpdateType(ctx context.Context, leaveType *domain.Type) (domain.Type, error) {
+	ret := _m.Called(ctx, leaveType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateType")
+	}
+
+	var r0 domain.Type
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Type) (domain.Type, error)); ok {
+		return rf(ctx, leaveType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Type) domain.Type); ok {
+		r0 = rf(ctx, leaveType)
+	} else {
+		r0 = ret.Get(0).(domain.Type)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Type) error); ok {
+		r1 = rf(ctx, leaveType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTypeService creates a new instance of TypeService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTypeService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TypeService {
+	mock := &TypeService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
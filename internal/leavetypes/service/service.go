@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+
+	approval_domain "hr-system/internal/approvals/domain"
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	leave_domain "hr-system/internal/leaves/domain"
+	"hr-system/internal/leavetypes/domain"
+	"hr-system/internal/leavetypes/repo"
+)
+
+type TypeService interface {
+	CreateType(ctx context.Context, leaveType *domain.Type) (domain.Type, error)
+	GetTypeByCode(ctx context.Context, code leave_domain.LeaveType) (domain.Type, error)
+	GetTypes(ctx context.Context) ([]domain.Type, error)
+	UpdateType(ctx context.Context, leaveType *domain.Type) (domain.Type, error)
+}
+
+type typeService struct {
+	typeRepo repo.TypeRepo
+	logger   *common.Logger
+	validate *validator.Validate
+}
+
+func NewTypeService(logger *common.Logger, typeRepo repo.TypeRepo) TypeService {
+	return &typeService{
+		typeRepo: typeRepo,
+		logger:   logger,
+		validate: validator.New(),
+	}
+}
+
+func (s *typeService) validateType(leaveType *domain.Type) error {
+	if err := s.validate.Struct(leaveType); err != nil {
+		return fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	if len(leaveType.Thresholds) > 0 {
+		if err := approval_domain.ValidateRules(leaveType.Thresholds); err != nil {
+			return fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+		}
+	}
+	return nil
+}
+
+func (s *typeService) CreateType(ctx context.Context, leaveType *domain.Type) (domain.Type, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.Type{}, err
+	}
+	if err := s.validateType(leaveType); err != nil {
+		return domain.Type{}, err
+	}
+
+	// the code is the key the leaves refer to the type by
+	_, err := s.typeRepo.GetTypeByCode(ctx, leaveType.Code)
+	if err == nil {
+		return domain.Type{}, fmt.Errorf("%w, leave type %s already exists", common_errors.ErrStatusConflict,
+			leaveType.Code)
+	}
+	if !errors.Is(err, common_errors.ErrResourceNotFound) {
+		return domain.Type{}, fmt.Errorf("failed to get leave type: %w", err)
+	}
+
+	if err := s.typeRepo.CreateType(ctx, leaveType); err != nil {
+		return domain.Type{}, fmt.Errorf("failed to create leave type: %w", err)
+	}
+	return *leaveType, nil
+}
+
+func (s *typeService) GetTypeByCode(ctx context.Context, code leave_domain.LeaveType) (domain.Type, error) {
+	leaveType, err := s.typeRepo.GetTypeByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Type{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Type{}, fmt.Errorf("failed to get leave type: %w", err)
+	}
+	return leaveType, nil
+}
+
+func (s *typeService) GetTypes(ctx context.Context) ([]domain.Type, error) {
+	leaveTypes, err := s.typeRepo.GetTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leave types: %w", err)
+	}
+	return leaveTypes, nil
+}
+
+func (s *typeService) UpdateType(ctx context.Context, leaveType *domain.Type) (domain.Type, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.Type{}, err
+	}
+	if err := s.validateType(leaveType); err != nil {
+		return domain.Type{}, err
+	}
+	if err := s.typeRepo.UpdateType(ctx, leaveType); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Type{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Type{}, fmt.Errorf("failed to update leave type: %w", err)
+	}
+	return s.GetTypeByCode(ctx, leaveType.Code)
+}
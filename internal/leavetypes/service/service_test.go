@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	approval_domain "hr-system/internal/approvals/domain"
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/leavetypes/domain"
+	mocks_type_repo "hr-system/internal/leavetypes/repo/mocks"
+)
+
+func TestCreateType(t *testing.T) {
+	mockRepo := mocks_type_repo.NewTypeRepo(t)
+	service := NewTypeService(common.NewLogger(), mockRepo)
+
+	ctx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 9, Roles: []auth.Role{auth.RoleHRAdmin}})
+	leaveType := domain.Type{Code: "parental", Name: "Parental leave", Paid: true}
+
+	mockRepo.On("GetTypeByCode", ctx, leaveType.Code).Return(domain.Type{}, common_errors.ErrResourceNotFound).Once()
+	mockRepo.On("CreateType", ctx, &leaveType).Return(nil).Once()
+	_, err := service.CreateType(ctx, &leaveType)
+	assert.NoError(t, err)
+
+	// the code is unique
+	mockRepo.On("GetTypeByCode", ctx, leaveType.Code).Return(leaveType, nil).Once()
+	_, err = service.CreateType(ctx, &domain.Type{Code: "parental", Name: "Parental leave"})
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+
+	_, err = service.CreateType(ctx, &domain.Type{Code: "Study", Name: "Study leave"})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// the thresholds cover every leave length
+	_, err = service.CreateType(ctx, &domain.Type{Code: "study", Name: "Study leave",
+		Thresholds: []approval_domain.Rule{{MinDays: 0, MaxDays: common.GetPtr(5.0), RequiredApprovers: 1}}})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// only HR manages the catalogue
+	employeeCtx := auth.WithActor(context.Background(), auth.Actor{EmployeeID: 2, Roles: []auth.Role{auth.RoleEmployee}})
+	_, err = service.CreateType(employeeCtx, &domain.Type{Code: "study", Name: "Study leave"})
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestNeedsAttachment(t *testing.T) {
+	// sick leaves longer than 2 days need a doctor's note
+	leaveType := domain.Type{Code: "sick", RequiresAttachment: true, AttachmentAfterDays: 2}
+	assert.False(t, leaveType.NeedsAttachment(2))
+	assert.True(t, leaveType.NeedsAttachment(2.5))
+	assert.False(t, (&domain.Type{AttachmentAfterDays: 0}).NeedsAttachment(1))
+}
+
+func TestCheckLength(t *testing.T) {
+	leaveType := domain.Type{Code: "bereavement", MaxConsecutiveDays: common.GetPtr(3.0)}
+	assert.NoError(t, leaveType.CheckLength(3))
+	assert.Error(t, leaveType.CheckLength(3.5))
+	assert.NoError(t, (&domain.Type{}).CheckLength(100))
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/auth"
+)
+
+// AuthMiddleware validates the bearer token and puts its actor into the request context
+func AuthMiddleware(verifier *auth.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		tokenString, found := strings.CutPrefix(header, "Bearer ")
+		if !found || tokenString == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, CreateErrResp("missing bearer token"))
+			return
+		}
+
+		actor, err := verifier.Verify(tokenString)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, CreateErrResp("%v", err))
+			return
+		}
+
+		ctx := auth.WithActor(c.Request.Context(), actor)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// GetActor returns the actor of the request, it responds 401 if the request is not authenticated
+func GetActor(c *gin.Context) (auth.Actor, bool) {
+	actor, ok := auth.ActorFromContext(c.Request.Context())
+	if !ok {
+		c.JSON(http.StatusUnauthorized, CreateErrResp("unauthenticated"))
+		return auth.Actor{}, false
+	}
+	return actor, true
+}
@@ -1,11 +1,114 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	common_errors "hr-system/internal/common/errors"
 )
 
 func CreateErrResp(format string, a ...any) interface{} {
 	return gin.H{"error": fmt.Sprintf(format, a...)}
 }
+
+// CreateCodedErrResp is an error response with a code telling the error apart from the others of its status
+func CreateCodedErrResp(code string, format string, a ...any) interface{} {
+	return gin.H{"error": fmt.Sprintf(format, a...), "code": code}
+}
+
+// ErrCodeOverrideRequired tells a leave breaking a coverage rule, which the reviewer can approve with override,
+// from the other conflicts
+const ErrCodeOverrideRequired = "override_required"
+
+// HandleErr responds with the status the error of a service stands for, every handler maps the errors of its
+// service here so the endpoints answer alike. An error of none of the kinds is an internal one failing the action.
+func HandleErr(c *gin.Context, err error, action string) {
+	if errors.Is(err, common_errors.ErrResourceNotFound) {
+		c.JSON(http.StatusNotFound, CreateErrResp("not found, cause: %v", err))
+	} else if errors.Is(err, common_errors.ErrInvalidInput) {
+		c.JSON(http.StatusBadRequest, CreateErrResp("invalid input, cause: %v", err))
+	} else if errors.Is(err, common_errors.ErrOverrideRequired) {
+		c.JSON(http.StatusConflict, CreateCodedErrResp(ErrCodeOverrideRequired, "override required, cause: %v", err))
+	} else if errors.Is(err, common_errors.ErrStatusConflict) {
+		c.JSON(http.StatusConflict, CreateErrResp("status conflict, cause: %v", err))
+	} else if errors.Is(err, common_errors.ErrPreconditionFailed) {
+		c.JSON(http.StatusPreconditionFailed, CreateErrResp("precondition failed, cause: %v", err))
+	} else if errors.Is(err, common_errors.ErrForbidden) {
+		c.JSON(http.StatusForbidden, CreateErrResp("forbidden, cause: %v", err))
+	} else {
+		c.JSON(http.StatusInternalServerError, CreateErrResp("failed to %s, cause: %v", action, err))
+	}
+}
+
+// MaxLimit is the largest page a list request can ask for
+const MaxLimit = 100
+
+// CursorParams is the cursor paging of a list request, a request giving a cursor or a limit is paged by cursors
+type CursorParams struct {
+	Enabled bool
+	Cursor  string // empty for the first page
+	Limit   int
+}
+
+// GetCursorParams parses the cursor paging of the request, it responds 400 and returns false on an invalid limit or
+// one over MaxLimit
+func GetCursorParams(c *gin.Context, defaultLimit int) (CursorParams, bool) {
+	cursor, hasCursor := c.GetQuery("cursor")
+	limitParam, hasLimit := c.GetQuery("limit")
+	params := CursorParams{Enabled: hasCursor || hasLimit, Cursor: cursor, Limit: defaultLimit}
+	if hasLimit {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			c.JSON(http.StatusBadRequest, CreateErrResp("invalid limit: %s", limitParam))
+			return CursorParams{}, false
+		}
+		params.Limit = limit
+	}
+	return params, true
+}
+
+// GetBoolQuery parses the boolean query parameter, it responds 400 and returns false if it's not a boolean
+func GetBoolQuery(c *gin.Context, key string, defaultValue bool) (bool, bool) {
+	param := c.Query(key)
+	if param == "" {
+		return defaultValue, true
+	}
+	value, err := strconv.ParseBool(param)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CreateErrResp("invalid %s: %s", key, param))
+		return false, false
+	}
+	return value, true
+}
+
+// GetDateQuery parses the date query parameter formatted as 2006-01-02 in the local time zone, it's nil if the
+// parameter is not given, it responds 400 and returns false if it's not a date
+func GetDateQuery(c *gin.Context, key string) (*time.Time, bool) {
+	param := c.Query(key)
+	if param == "" {
+		return nil, true
+	}
+	date, err := time.ParseInLocation(time.DateOnly, param, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, CreateErrResp("invalid %s: %s", key, param))
+		return nil, false
+	}
+	return &date, true
+}
+
+// GetListQuery splits the comma separated query parameter, it's nil if the parameter is not given
+func GetListQuery(c *gin.Context, key string) []string {
+	var values []string
+	for _, value := range strings.Split(c.Query(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
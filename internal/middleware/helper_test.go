@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	common_errors "hr-system/internal/common/errors"
+)
+
+func TestHandleErr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, test := range []struct {
+		err    error
+		status int
+	}{
+		{common_errors.ErrResourceNotFound, http.StatusNotFound},
+		{fmt.Errorf("%w, bad date", common_errors.ErrInvalidInput), http.StatusBadRequest},
+		{common_errors.ErrStatusConflict, http.StatusConflict},
+		{common_errors.ErrOverrideRequired, http.StatusConflict},
+		{common_errors.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		{common_errors.ErrForbidden, http.StatusForbidden},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		HandleErr(c, test.err, "get leave")
+		assert.Equal(t, test.status, w.Code, test.err.Error())
+	}
+
+	// the override needed is told apart from the other conflicts
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	HandleErr(c, common_errors.ErrOverrideRequired, "review leave")
+	assert.Contains(t, w.Body.String(), `"code":"override_required"`)
+}
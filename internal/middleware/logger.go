@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedTokenPattern matches the token of the calendar feeds, which is the credential of the feed reader
+var feedTokenPattern = regexp.MustCompile(`(/feeds/)[^/?]+`)
+
+// Logger logs the requests like the default logger of gin, without the feed tokens in their paths
+func Logger() gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{Formatter: redactedLogFormatter})
+}
+
+func redactedLogFormatter(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		feedTokenPattern.ReplaceAllString(param.Path, "${1}REDACTED"),
+		param.ErrorMessage,
+	)
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var out bytes.Buffer
+	r := gin.New()
+	r.Use(gin.LoggerWithConfig(gin.LoggerConfig{Formatter: redactedLogFormatter, Output: &out}))
+	r.GET("/api/v1/feeds/:token/leaves.ics", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/api/v1/feeds/:token/teams/:manager_id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/api/v1/feeds/s3cr3t/leaves.ics", "/api/v1/feeds/s3cr3t/teams/2?from=2024-01-01"} {
+		out.Reset()
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		assert.NotContains(t, out.String(), "s3cr3t")
+		assert.Contains(t, out.String(), "/api/v1/feeds/REDACTED/")
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	employee_domain "hr-system/internal/employees/domain"
+)
+
+// Offboarding is the request to let an employee leave the company after TerminationDate,
+// it is kept until their direct reports and reviews are handed over once they have left
+type Offboarding struct {
+	EmployeeID      int        `gorm:"primaryKey;autoIncrement:false" validate:"required"`
+	TerminationDate time.Time  `gorm:"type:date;not null;index:idx_pending" validate:"required"`
+	SuccessorID     *int       // the employee taking the direct reports, required if there are any
+	HandedOverAt    *time.Time `gorm:"index:idx_pending"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime"`
+}
+
+// Result is what has been done to offboard the employee
+type Result struct {
+	Employee          employee_domain.Employee `json:"employee"`
+	CancelledLeaves   int                      `json:"cancelled_leaves"`   // leaves starting after the last day
+	ReassignedReviews int                      `json:"reassigned_reviews"` // leave and TOIL reviews handed over
+	// HandedOver is false until the employee has left, their reports and reviews are then handed over by a job
+	HandedOver bool `json:"handed_over"`
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/common"
+	"hr-system/internal/middleware"
+	"hr-system/internal/offboarding/domain"
+	"hr-system/internal/offboarding/service"
+)
+
+type OffboardingHandler struct {
+	offboardingService service.OffboardingService
+	logger             *common.Logger
+}
+
+func NewOffboardingHandler(logger *common.Logger, offboardingService service.OffboardingService) *OffboardingHandler {
+	return &OffboardingHandler{
+		offboardingService: offboardingService,
+		logger:             logger,
+	}
+}
+
+type OffboardRequest struct {
+	TerminationDate time.Time `json:"termination_date" binding:"required"` // the last day in the company
+	SuccessorID     *int      `json:"successor_id"`
+}
+
+func (h *OffboardingHandler) Offboard(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid employee ID"))
+		return
+	}
+
+	var req OffboardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	date := req.TerminationDate
+	result, err := h.offboardingService.Offboard(ctx, &domain.Offboarding{
+		EmployeeID:      id,
+		TerminationDate: time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location()),
+		SuccessorID:     req.SuccessorID,
+	})
+	if err != nil {
+		middleware.HandleErr(c, err, "offboard employee")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/offboarding/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OffboardingRepo is an autogenerated mock type for the OffboardingRepo type
+type OffboardingRepo struct {
+	mock.Mock
+}
+
+// CreateOffboarding provides a mock function with given fields: ctx, offboarding
+func (_m *OffboardingRepo) CreateOffboarding(ctx context.Context, offboarding *domain.Offboarding) error {
+	ret := _m.Called(ctx, offboarding)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOffboarding")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Offboarding) error); ok {
+		r0 = rf(ctx, offboarding)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPendingOffboardings provides a mock function with given fields: ctx, before
+func (_m *OffboardingRepo) GetPendingOffboardings(ctx context.Context, before time.Time) ([]domain.Offboarding, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingOffboardings")
+	}
+
+	var r0 []domain.Offboarding
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]domain.Offboarding, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []domain.Offboarding); ok {
+		r0 = rf(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Offboarding)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkHandedOver provides a mock function with given fields: ctx, employeeID, at
+func (_m *OffboardingRepo) MarkHandedOver(ctx context.Context, employeeID int, at time.Time) error {
+	ret := _m.Called(ctx, employeeID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkHandedOver")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) error); ok {
+		r0 = rf(ctx, employeeID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOffboardingRepo creates a new instance of OffboardingRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOffboardingRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OffboardingRepo {
+	mock := &OffboardingRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
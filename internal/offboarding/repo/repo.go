@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"hr-system/internal/offboarding/domain"
+)
+
+type OffboardingRepo interface {
+	// CreateOffboarding keeps the offboarding of the employee, the one kept by a previous attempt stays as it is
+	CreateOffboarding(ctx context.Context, offboarding *domain.Offboarding) error
+	// GetPendingOffboardings returns the offboardings not handed over whose last day is before the date
+	GetPendingOffboardings(ctx context.Context, before time.Time) ([]domain.Offboarding, error)
+	MarkHandedOver(ctx context.Context, employeeID int, at time.Time) error
+}
+
+type offboardingRepo struct {
+	db *gorm.DB
+}
+
+func NewOffboardingRepo(db *gorm.DB) (OffboardingRepo, error) {
+	repo := &offboardingRepo{
+		db: db,
+	}
+
+	if err := repo.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *offboardingRepo) ensureSchema() error {
+	// AutoMigrate
+	if err := r.db.AutoMigrate(domain.Offboarding{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *offboardingRepo) CreateOffboarding(ctx context.Context, offboarding *domain.Offboarding) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(offboarding).Error
+	if err != nil {
+		return fmt.Errorf("failed to create offboarding: %w", err)
+	}
+	return nil
+}
+
+func (r *offboardingRepo) GetPendingOffboardings(ctx context.Context,
+	before time.Time) ([]domain.Offboarding, error) {
+	var offboardings []domain.Offboarding
+	err := r.db.WithContext(ctx).
+		Where("handed_over_at IS NULL AND termination_date < ?", before).
+		Order("termination_date ASC, employee_id ASC").
+		Find(&offboardings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending offboardings: %w", err)
+	}
+	return offboardings, nil
+}
+
+func (r *offboardingRepo) MarkHandedOver(ctx context.Context, employeeID int, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&domain.Offboarding{}).
+		Where("employee_id = ? AND handed_over_at IS NULL", employeeID).
+		Update("handed_over_at", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark offboarding of employee %d handed over: %w", employeeID, err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"hr-system/internal/common"
+	"hr-system/internal/offboarding/domain"
+)
+
+func setupTestRepo(t *testing.T) *offboardingRepo {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	repo := &offboardingRepo{db: db}
+	assert.NoError(t, repo.ensureSchema())
+	return repo
+}
+
+func TestPendingOffboardings(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	day := func(d int) time.Time {
+		return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC)
+	}
+	offboardings := []*domain.Offboarding{
+		{EmployeeID: 3, TerminationDate: day(10), SuccessorID: common.GetPtr(4)},
+		{EmployeeID: 5, TerminationDate: day(20)},
+	}
+	for _, offboarding := range offboardings {
+		assert.NoError(t, repo.CreateOffboarding(ctx, offboarding))
+	}
+	// a retry keeps the first one
+	assert.NoError(t, repo.CreateOffboarding(ctx, &domain.Offboarding{EmployeeID: 3, TerminationDate: day(11)}))
+
+	pending, err := repo.GetPendingOffboardings(ctx, day(20))
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, 3, pending[0].EmployeeID)
+	assert.True(t, day(10).Equal(pending[0].TerminationDate))
+	assert.Equal(t, 4, *pending[0].SuccessorID)
+
+	assert.NoError(t, repo.MarkHandedOver(ctx, 3, day(11)))
+	pending, err = repo.GetPendingOffboardings(ctx, day(21))
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, 5, pending[0].EmployeeID)
+}
@@ -0,0 +1,85 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/offboarding/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OffboardingService is an autogenerated mock type for the OffboardingService type
+type OffboardingService struct {
+	mock.Mock
+}
+
+// HandOverDue provides a mock function with given fields: ctx
+func (_m *OffboardingService) HandOverDue(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HandOverDue")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Offboard provides a mock function with given fields: ctx, offboarding
+func (_m *OffboardingService) Offboard(ctx context.Context, offboarding *domain.Offboarding) (domain.Result, error) {
+	ret := _m.Called(ctx, offboarding)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Offboard")
+	}
+
+	var r0 domain.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Offboarding) (domain.Result, error)); ok {
+		return rf(ctx, offboarding)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Offboarding) domain.Result); ok {
+		r0 = rf(ctx, offboarding)
+	} else {
+		r0 = ret.Get(0).(domain.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Offboarding) error); ok {
+		r1 = rf(ctx, offboarding)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewOffboardingService creates a new instance of OffboardingService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOffboardingService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OffboardingService {
+	mock := &OffboardingService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
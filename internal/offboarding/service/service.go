@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	employee_domain "hr-system/internal/employees/domain"
+	employee_service "hr-system/internal/employees/service"
+	leave_service "hr-system/internal/leaves/service"
+	"hr-system/internal/offboarding/domain"
+	"hr-system/internal/offboarding/repo"
+	toil_service "hr-system/internal/toil/service"
+)
+
+type OffboardingService interface {
+	// Offboard terminates the employee and withdraws their leaves after the last day, their direct reports and
+	// reviews are handed over at once if they have already left, or by HandOverDue after their last day.
+	// It can be retried with the same request when one of the steps fails.
+	Offboard(ctx context.Context, offboarding *domain.Offboarding) (domain.Result, error)
+	// HandOverDue hands over the direct reports and reviews of the employees who have left since they were
+	// offboarded, it returns the number of handed over employees
+	HandOverDue(ctx context.Context) (int, error)
+}
+
+type offboardingService struct {
+	offboardingRepo repo.OffboardingRepo
+	employeeService employee_service.EmployeeService
+	leaveService    leave_service.LeaveService
+	toilService     toil_service.TOILService
+	logger          *common.Logger
+	validate        *validator.Validate
+}
+
+func NewOffboardingService(logger *common.Logger, offboardingRepo repo.OffboardingRepo,
+	employeeService employee_service.EmployeeService, leaveService leave_service.LeaveService,
+	toilService toil_service.TOILService) OffboardingService {
+	return &offboardingService{
+		offboardingRepo: offboardingRepo,
+		employeeService: employeeService,
+		leaveService:    leaveService,
+		toilService:     toilService,
+		logger:          logger,
+		validate:        validator.New(),
+	}
+}
+
+func (s *offboardingService) Offboard(ctx context.Context, offboarding *domain.Offboarding) (domain.Result, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return domain.Result{}, err
+	}
+	if err := s.validate.Struct(offboarding); err != nil {
+		return domain.Result{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+
+	employee, err := s.employeeService.TerminateEmployee(ctx, offboarding.EmployeeID, offboarding.TerminationDate,
+		offboarding.SuccessorID)
+	if errors.Is(err, common_errors.ErrStatusConflict) {
+		// terminated by a previous attempt, the steps below may not have been done
+		employee, err = s.terminated(ctx, offboarding)
+	}
+	if err != nil {
+		return domain.Result{}, err
+	}
+
+	if err := s.offboardingRepo.CreateOffboarding(ctx, offboarding); err != nil {
+		return domain.Result{}, err
+	}
+
+	cancelled, err := s.leaveService.CancelLeavesAfterTermination(ctx, offboarding.EmployeeID)
+	if err != nil {
+		return domain.Result{}, fmt.Errorf("failed to cancel leaves: %w", err)
+	}
+	result := domain.Result{
+		Employee:        employee,
+		CancelledLeaves: cancelled,
+	}
+
+	// the employee keeps their reports and reviews until they have left
+	if employee.TerminatedAt(time.Now()) {
+		result.ReassignedReviews, err = s.handOver(ctx, offboarding)
+		if err != nil {
+			return domain.Result{}, err
+		}
+		result.HandedOver = true
+	}
+
+	s.logger.Infof("employee %d offboarded, %d leaves cancelled and %d reviews reassigned",
+		offboarding.EmployeeID, cancelled, result.ReassignedReviews)
+	return result, nil
+}
+
+func (s *offboardingService) HandOverDue(ctx context.Context) (int, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return 0, err
+	}
+
+	// the employees whose last day was before today have left
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	offboardings, err := s.offboardingRepo.GetPendingOffboardings(ctx, today)
+	if err != nil {
+		return 0, err
+	}
+
+	handedOver := 0
+	var errs []error
+	for i := range offboardings {
+		// one which fails does not hold up the others, it is tried again in the next run
+		if _, err := s.handOver(ctx, &offboardings[i]); err != nil {
+			errs = append(errs, fmt.Errorf("failed to hand over employee %d: %w", offboardings[i].EmployeeID, err))
+			continue
+		}
+		handedOver++
+	}
+
+	return handedOver, errors.Join(errs...)
+}
+
+// handOver moves the direct reports of the employee to the successor and reassigns their reviews of leaves and
+// TOIL requests, it returns the number of reassigned reviews
+func (s *offboardingService) handOver(ctx context.Context, offboarding *domain.Offboarding) (int, error) {
+	moved, err := s.employeeService.HandOverReports(ctx, offboarding.EmployeeID, offboarding.SuccessorID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to hand over reports: %w", err)
+	}
+	reassigned, err := s.leaveService.ReassignReviews(ctx, offboarding.EmployeeID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reassign reviews: %w", err)
+	}
+	reassignedTOIL, err := s.toilService.ReassignReviews(ctx, offboarding.EmployeeID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reassign TOIL reviews: %w", err)
+	}
+	reassigned += reassignedTOIL
+	if err := s.offboardingRepo.MarkHandedOver(ctx, offboarding.EmployeeID, time.Now()); err != nil {
+		return 0, err
+	}
+
+	s.logger.Infof("employee %d handed over, %d reports moved and %d reviews reassigned",
+		offboarding.EmployeeID, moved, reassigned)
+	return reassigned, nil
+}
+
+// terminated returns the employee already terminated on the termination date of the request
+func (s *offboardingService) terminated(ctx context.Context,
+	offboarding *domain.Offboarding) (employee_domain.Employee, error) {
+	employee, err := s.employeeService.GetEmployeeByID(ctx, offboarding.EmployeeID)
+	if err != nil {
+		return employee_domain.Employee{}, err
+	}
+	if employee.TerminationDate == nil ||
+		employee.TerminationDate.Format(time.DateOnly) != offboarding.TerminationDate.Format(time.DateOnly) {
+		return employee_domain.Employee{}, fmt.Errorf("%w, employee %d is already terminated on another date",
+			common_errors.ErrStatusConflict, offboarding.EmployeeID)
+	}
+	return employee, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	employee_domain "hr-system/internal/employees/domain"
+	mocks_employee_service "hr-system/internal/employees/service/mocks"
+	mocks_leave_service "hr-system/internal/leaves/service/mocks"
+	"hr-system/internal/offboarding/domain"
+	mocks_offboarding_repo "hr-system/internal/offboarding/repo/mocks"
+	mocks_toil_service "hr-system/internal/toil/service/mocks"
+)
+
+func actorCtx(employeeID int, roles ...auth.Role) context.Context {
+	return auth.WithActor(context.Background(), auth.Actor{EmployeeID: employeeID, Roles: roles})
+}
+
+func TestOffboard(t *testing.T) {
+	offboardingRepo := mocks_offboarding_repo.NewOffboardingRepo(t)
+	employeeService := mocks_employee_service.NewEmployeeService(t)
+	leaveService := mocks_leave_service.NewLeaveService(t)
+	toilService := mocks_toil_service.NewTOILService(t)
+	service := NewOffboardingService(common.NewLogger(), offboardingRepo, employeeService, leaveService, toilService)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	terminationDate := time.Date(2024, 6, 30, 0, 0, 0, 0, time.Local)
+	offboarding := &domain.Offboarding{EmployeeID: 3, TerminationDate: terminationDate, SuccessorID: common.GetPtr(4)}
+	employee := employee_domain.Employee{ID: 3, TerminationDate: &terminationDate}
+
+	employeeService.On("TerminateEmployee", ctx, 3, terminationDate, offboarding.SuccessorID).
+		Return(employee, nil).Once()
+	offboardingRepo.On("CreateOffboarding", ctx, offboarding).Return(nil).Once()
+	leaveService.On("CancelLeavesAfterTermination", ctx, 3).Return(2, nil).Once()
+	// the employee has left, the reports and reviews are handed over at once
+	employeeService.On("HandOverReports", ctx, 3, offboarding.SuccessorID).Return(2, nil).Once()
+	leaveService.On("ReassignReviews", ctx, 3).Return(1, nil).Once()
+	toilService.On("ReassignReviews", ctx, 3).Return(1, nil).Once()
+	offboardingRepo.On("MarkHandedOver", ctx, 3, mock.Anything).Return(nil).Once()
+
+	result, err := service.Offboard(ctx, offboarding)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Result{Employee: employee, CancelledLeaves: 2, ReassignedReviews: 2, HandedOver: true},
+		result)
+}
+
+func TestOffboard_Future(t *testing.T) {
+	offboardingRepo := mocks_offboarding_repo.NewOffboardingRepo(t)
+	employeeService := mocks_employee_service.NewEmployeeService(t)
+	leaveService := mocks_leave_service.NewLeaveService(t)
+	toilService := mocks_toil_service.NewTOILService(t)
+	service := NewOffboardingService(common.NewLogger(), offboardingRepo, employeeService, leaveService, toilService)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	now := time.Now()
+	// the employee is still there on their last day
+	terminationDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	offboarding := &domain.Offboarding{EmployeeID: 3, TerminationDate: terminationDate, SuccessorID: common.GetPtr(4)}
+	employee := employee_domain.Employee{ID: 3, TerminationDate: &terminationDate}
+
+	employeeService.On("TerminateEmployee", ctx, 3, terminationDate, offboarding.SuccessorID).
+		Return(employee, nil).Once()
+	offboardingRepo.On("CreateOffboarding", ctx, offboarding).Return(nil).Once()
+	leaveService.On("CancelLeavesAfterTermination", ctx, 3).Return(1, nil).Once()
+
+	result, err := service.Offboard(ctx, offboarding)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Result{Employee: employee, CancelledLeaves: 1}, result)
+}
+
+func TestHandOverDue(t *testing.T) {
+	offboardingRepo := mocks_offboarding_repo.NewOffboardingRepo(t)
+	employeeService := mocks_employee_service.NewEmployeeService(t)
+	leaveService := mocks_leave_service.NewLeaveService(t)
+	toilService := mocks_toil_service.NewTOILService(t)
+	service := NewOffboardingService(common.NewLogger(), offboardingRepo, employeeService, leaveService, toilService)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	offboardings := []domain.Offboarding{
+		{EmployeeID: 3, TerminationDate: today.AddDate(0, 0, -1), SuccessorID: common.GetPtr(4)},
+		{EmployeeID: 5, TerminationDate: today.AddDate(0, 0, -1)},
+	}
+
+	offboardingRepo.On("GetPendingOffboardings", ctx, today).Return(offboardings, nil).Once()
+	employeeService.On("HandOverReports", ctx, 3, offboardings[0].SuccessorID).Return(2, nil).Once()
+	leaveService.On("ReassignReviews", ctx, 3).Return(1, nil).Once()
+	toilService.On("ReassignReviews", ctx, 3).Return(0, nil).Once()
+	offboardingRepo.On("MarkHandedOver", ctx, 3, mock.Anything).Return(nil).Once()
+	// the second one fails without holding up the first, it stays pending for the next run
+	employeeService.On("HandOverReports", ctx, 5, (*int)(nil)).Return(1, nil).Once()
+	noManager := fmt.Errorf("%w, reviewer 5 has no manager", common_errors.ErrStatusConflict)
+	leaveService.On("ReassignReviews", ctx, 5).Return(0, noManager).Once()
+
+	handedOver, err := service.HandOverDue(ctx)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+	assert.Equal(t, 1, handedOver)
+
+	_, err = service.HandOverDue(actorCtx(3, auth.RoleManager))
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestOffboard_Retry(t *testing.T) {
+	offboardingRepo := mocks_offboarding_repo.NewOffboardingRepo(t)
+	employeeService := mocks_employee_service.NewEmployeeService(t)
+	leaveService := mocks_leave_service.NewLeaveService(t)
+	toilService := mocks_toil_service.NewTOILService(t)
+	service := NewOffboardingService(common.NewLogger(), offboardingRepo, employeeService, leaveService, toilService)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	terminationDate := time.Date(2024, 6, 30, 0, 0, 0, 0, time.Local)
+	offboarding := &domain.Offboarding{EmployeeID: 3, TerminationDate: terminationDate}
+	employee := employee_domain.Employee{ID: 3, TerminationDate: &terminationDate}
+	terminated := fmt.Errorf("%w, employee 3 is already terminated", common_errors.ErrStatusConflict)
+
+	// the employee was terminated by the previous attempt, the leaves are still cleaned up
+	employeeService.On("TerminateEmployee", ctx, 3, terminationDate, offboarding.SuccessorID).
+		Return(employee_domain.Employee{}, terminated).Once()
+	employeeService.On("GetEmployeeByID", ctx, 3).Return(employee, nil).Once()
+	offboardingRepo.On("CreateOffboarding", ctx, offboarding).Return(nil).Once()
+	leaveService.On("CancelLeavesAfterTermination", ctx, 3).Return(0, nil).Once()
+	employeeService.On("HandOverReports", ctx, 3, offboarding.SuccessorID).Return(0, nil).Once()
+	leaveService.On("ReassignReviews", ctx, 3).Return(0, nil).Once()
+	toilService.On("ReassignReviews", ctx, 3).Return(0, nil).Once()
+	offboardingRepo.On("MarkHandedOver", ctx, 3, mock.Anything).Return(nil).Once()
+
+	result, err := service.Offboard(ctx, offboarding)
+	assert.NoError(t, err)
+	assert.Equal(t, employee, result.Employee)
+
+	// but not on another date
+	offboarding.TerminationDate = terminationDate.AddDate(0, 0, 1)
+	employeeService.On("TerminateEmployee", ctx, 3, offboarding.TerminationDate, offboarding.SuccessorID).
+		Return(employee_domain.Employee{}, terminated).Once()
+	employeeService.On("GetEmployeeByID", ctx, 3).Return(employee, nil).Once()
+	_, err = service.Offboard(ctx, offboarding)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+
+	_, err = service.Offboard(actorCtx(3, auth.RoleManager), offboarding)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
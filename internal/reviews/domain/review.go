@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReviewStatus is the status of a review and of the leave or the TOIL request it reviews
+type ReviewStatus string
+
+var (
+	ReviewStatusReviewing ReviewStatus = "reviewing"
+	ReviewStatusApproved  ReviewStatus = "approved"
+	ReviewStatusRejected  ReviewStatus = "rejected"
+	ReviewStatusCancelled ReviewStatus = "cancelled"
+	// ReviewStatusRevoking means an approved leave is waiting for the manager chain to sign off its revocation
+	ReviewStatusRevoking ReviewStatus = "revoking"
+	ReviewStatusRevoked  ReviewStatus = "revoked"
+	// ReviewStatusEscalated is only used by reviews, it means the review was passed to the reviewer's manager
+	// because nobody reviewed it in time
+	ReviewStatusEscalated ReviewStatus = "escalated"
+	// ReviewStatusReassigned is only used by reviews, it means the review was passed to the reviewer's manager
+	// because the reviewer left the company
+	ReviewStatusReassigned ReviewStatus = "reassigned"
+)
+
+// statusTransitions lists the statuses a leave is allowed to move to from each status.
+// Statuses without an entry are final.
+var statusTransitions = map[ReviewStatus][]ReviewStatus{
+	ReviewStatusReviewing: {ReviewStatusReviewing, ReviewStatusApproved, ReviewStatusRejected, ReviewStatusCancelled},
+	ReviewStatusApproved:  {ReviewStatusRevoking, ReviewStatusRevoked},
+	ReviewStatusRevoking:  {ReviewStatusRevoking, ReviewStatusApproved, ReviewStatusRevoked},
+}
+
+// CanTransitionTo reports whether a leave in status s may move to status next.
+func (s ReviewStatus) CanTransitionTo(next ReviewStatus) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// the subject types the reviews are kept under, they are the polymorphic values of the Reviews of the subjects
+const (
+	SubjectTypeLeave = "leave"
+	SubjectTypeTOIL  = "toil"
+)
+
+// Review is the decision of a reviewer on a leave or a TOIL request, the reviews of both are kept in one table
+type Review struct {
+	ID           int          `gorm:"primaryKey;autoIncrement"`
+	SubjectType  string       `gorm:"type:varchar(20);not null;index:idx_subject"`
+	SubjectID    int          `gorm:"not null;index:idx_subject"`
+	ReviewerID   int          `gorm:"type:int;not null"`
+	OnBehalfOfID *int         `gorm:"type:int"` // the manager the reviewer acts for as their delegate
+	Status       ReviewStatus `gorm:"type:varchar(50);not null"`
+	Comment      string       `gorm:"type:varchar(255)"`
+	ReviewedAt   *time.Time   `gorm:"type:date"`
+	Revocation   bool         `gorm:"not null;default:false"` // marks the reviews of a revocation request
+	Escalated    bool         `gorm:"not null;default:false"` // marks the reviews escalated from a stale review
+	// Unescalatable marks a stale review whose reviewer has no manager to escalate it to, it is not tried again
+	Unescalatable bool `gorm:"not null;default:false"`
+	// CoverageOverride marks the approval of a leave breaking the coverage rule of the requester's team,
+	// OverriddenByID is the reviewer who chose to override it
+	CoverageOverride bool      `gorm:"not null;default:false"`
+	OverriddenByID   *int      `gorm:"type:int"`
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime"`
+}
+
+// Subject is what goes through the review chain of the requester's managers, a leave or a TOIL request,
+// it's named by String in the messages of the chain
+type Subject interface {
+	fmt.Stringer
+	// RequesterID is the employee asking for it, who never reviews it
+	RequesterID() int
+	// CurrentReviewer is who the pending review is on, nil when nothing is pending
+	CurrentReviewer() *int
+	SetCurrentReviewer(reviewerID *int)
+	// LastReview returns the latest review, false when there is none
+	LastReview() (Review, bool)
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	delegation_service "hr-system/internal/delegations/service"
+	employee_repo "hr-system/internal/employees/repo"
+	"hr-system/internal/reviews/domain"
+)
+
+// ReviewChain passes the reviews of the leaves or the TOIL requests along the managers of the requester and their
+// delegates, the service of the subjects decides on the reviews and saves them
+type ReviewChain[S domain.Subject] interface {
+	// NewReview creates a pending review of the manager and makes it the current one of the subject,
+	// the review goes to the delegate when the manager has delegated their reviews for today
+	NewReview(ctx context.Context, subject S, managerID int, revocation bool) (domain.Review, error)
+	// CheckReviewer makes sure the reviewer can review the pending review and records who reviews it,
+	// it returns the manager whose authority the review is made with
+	CheckReviewer(ctx context.Context, subject S, review *domain.Review, reviewerID int) (int, error)
+	// Escalate passes the stale pending reviews of the subjects to the reviewer's manager,
+	// it returns the number of escalated reviews
+	Escalate(ctx context.Context, subjects []S) int
+	// Reassign passes the reviews of the subjects pending on the reviewer, or on their delegate for them, to the
+	// reviewer's manager and hands the ones they review as a delegate back to the manager they act for,
+	// it returns the number of reassigned reviews
+	Reassign(ctx context.Context, subjects []S, reviewerID int) (int, error)
+}
+
+// SaveFunc saves the subject with its new and updated reviews
+type SaveFunc[S domain.Subject] func(ctx context.Context, subject S, reviews []domain.Review) error
+
+type reviewChain[S domain.Subject] struct {
+	employeeRepo      employee_repo.EmployeeRepo
+	delegationService delegation_service.DelegationService
+	save              SaveFunc[S]
+	logger            *common.Logger
+}
+
+func NewReviewChain[S domain.Subject](logger *common.Logger, employeeRepo employee_repo.EmployeeRepo,
+	delegationService delegation_service.DelegationService, save SaveFunc[S]) ReviewChain[S] {
+	return &reviewChain[S]{
+		employeeRepo:      employeeRepo,
+		delegationService: delegationService,
+		save:              save,
+		logger:            logger,
+	}
+}
+
+func (c *reviewChain[S]) NewReview(ctx context.Context, subject S, managerID int,
+	revocation bool) (domain.Review, error) {
+	review := domain.Review{
+		ReviewerID: managerID,
+		Status:     domain.ReviewStatusReviewing,
+		Revocation: revocation,
+	}
+
+	delegateID, err := c.delegationService.GetDelegate(ctx, managerID, time.Now())
+	if err != nil {
+		return domain.Review{}, fmt.Errorf("failed to get delegate of reviewer %d: %w", managerID, err)
+	}
+	// nobody reviews their own request
+	if delegateID != nil && *delegateID != subject.RequesterID() {
+		review.ReviewerID = *delegateID
+		review.OnBehalfOfID = common.GetPtr(managerID)
+	}
+
+	subject.SetCurrentReviewer(common.GetPtr(review.ReviewerID))
+	return review, nil
+}
+
+func (c *reviewChain[S]) CheckReviewer(ctx context.Context, subject S, review *domain.Review,
+	reviewerID int) (int, error) {
+	currentReviewerID := subject.CurrentReviewer()
+	if currentReviewerID == nil {
+		return 0, fmt.Errorf("%w, it's not waiting for this reviewer to review", common_errors.ErrStatusConflict)
+	}
+	managerID := *currentReviewerID
+	if review.OnBehalfOfID != nil {
+		managerID = *review.OnBehalfOfID
+	}
+
+	switch {
+	case *currentReviewerID == reviewerID:
+		// the assigned reviewer
+	case managerID == reviewerID:
+		// the manager reviews in person although the review was delegated
+		review.ReviewerID = reviewerID
+		review.OnBehalfOfID = nil
+	default:
+		delegateID, err := c.delegationService.GetDelegate(ctx, managerID, time.Now())
+		if err != nil {
+			return 0, fmt.Errorf("failed to get delegate of reviewer %d: %w", managerID, err)
+		}
+		if delegateID == nil || *delegateID != reviewerID || reviewerID == subject.RequesterID() {
+			return 0, fmt.Errorf("%w, it's not waiting for this reviewer to review", common_errors.ErrStatusConflict)
+		}
+		review.ReviewerID = reviewerID
+		review.OnBehalfOfID = common.GetPtr(managerID)
+	}
+
+	return managerID, nil
+}
+
+func (c *reviewChain[S]) Escalate(ctx context.Context, subjects []S) int {
+	escalated := 0
+	for _, subject := range subjects {
+		ok, err := c.passReviewUp(ctx, subject, domain.ReviewStatusEscalated)
+		if err != nil {
+			// the others can still be escalated
+			c.logger.Errorf("failed to escalate review of %s, cause: %s", subject, err)
+			continue
+		}
+		if ok {
+			escalated++
+		}
+	}
+	return escalated
+}
+
+func (c *reviewChain[S]) Reassign(ctx context.Context, subjects []S, reviewerID int) (int, error) {
+	reassigned := 0
+	for _, subject := range subjects {
+		var err error
+		pendingReview, _ := subject.LastReview()
+		if pendingReview.ReviewerID == reviewerID && pendingReview.OnBehalfOfID != nil {
+			err = c.handBackReview(ctx, subject, pendingReview)
+		} else {
+			_, err = c.passReviewUp(ctx, subject, domain.ReviewStatusReassigned)
+		}
+		if err != nil {
+			return reassigned, fmt.Errorf("failed to reassign review of %s: %w", subject, err)
+		}
+		reassigned++
+	}
+	return reassigned, nil
+}
+
+// passReviewUp closes the pending review of the subject with the status, escalated or reassigned,
+// and passes it to the manager of the reviewer, it reports false when the reviewer has no manager to escalate it to,
+// and fails with common_errors.ErrStatusConflict when there is none to reassign it to
+func (c *reviewChain[S]) passReviewUp(ctx context.Context, subject S, status domain.ReviewStatus) (bool, error) {
+	pendingReview, ok := subject.LastReview()
+	if !ok || subject.CurrentReviewer() == nil {
+		return false, fmt.Errorf("unexpected error: no pending review found")
+	}
+	if pendingReview.Status != domain.ReviewStatusReviewing {
+		return false, fmt.Errorf("unexpected error: last review is %s", pendingReview.Status)
+	}
+
+	// a delegate reviews with the authority of the manager, so it goes to the manager's manager
+	managerID := pendingReview.ReviewerID
+	if pendingReview.OnBehalfOfID != nil {
+		managerID = *pendingReview.OnBehalfOfID
+	}
+	manager, err := c.employeeRepo.GetEmployeeByID(ctx, managerID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get reviewer %d: %w", managerID, err)
+	}
+	if manager.ManagerID == nil || *manager.ManagerID == subject.RequesterID() {
+		if status == domain.ReviewStatusReassigned {
+			// the review would stay with someone who is gone, HR has to settle it first
+			return false, fmt.Errorf("%w, reviewer %d has no manager to take the review of %s",
+				common_errors.ErrStatusConflict, managerID, subject)
+		}
+		c.logger.Warnf("review %d of %s is not %s, reviewer %d has no manager to pass it to",
+			pendingReview.ID, subject, status, managerID)
+		// the top of the chain is warned about once, the review stays pending on the reviewer
+		pendingReview.Unescalatable = true
+		if err := c.save(ctx, subject, []domain.Review{pendingReview}); err != nil {
+			return false, fmt.Errorf("failed to mark review as unescalatable: %w", err)
+		}
+		return false, nil
+	}
+
+	pendingReview.Status = status
+	review, err := c.NewReview(ctx, subject, *manager.ManagerID, pendingReview.Revocation)
+	if err != nil {
+		return false, err
+	}
+	review.Escalated = status == domain.ReviewStatusEscalated
+
+	if err := c.save(ctx, subject, []domain.Review{pendingReview, review}); err != nil {
+		return false, fmt.Errorf("failed to pass review up: %w", err)
+	}
+	return true, nil
+}
+
+// handBackReview closes the pending review of a delegate as reassigned and gives it back to the manager they act for,
+// the manager reviews it themselves since the delegate is no longer there to act for them
+func (c *reviewChain[S]) handBackReview(ctx context.Context, subject S, pendingReview domain.Review) error {
+	pendingReview.Status = domain.ReviewStatusReassigned
+	review := domain.Review{
+		ReviewerID: *pendingReview.OnBehalfOfID,
+		Status:     domain.ReviewStatusReviewing,
+		Revocation: pendingReview.Revocation,
+	}
+	subject.SetCurrentReviewer(common.GetPtr(review.ReviewerID))
+
+	if err := c.save(ctx, subject, []domain.Review{pendingReview, review}); err != nil {
+		return fmt.Errorf("failed to hand back review: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	mocks_delegation_service "hr-system/internal/delegations/service/mocks"
+	employee_domain "hr-system/internal/employees/domain"
+	mocks_employee_repo "hr-system/internal/employees/repo/mocks"
+	"hr-system/internal/reviews/domain"
+)
+
+// subject is requested by employee 3 and pending on the first review
+type subject struct {
+	id                int
+	currentReviewerID *int
+	reviews           []domain.Review
+}
+
+func (s *subject) String() string                     { return fmt.Sprintf("subject %d", s.id) }
+func (s *subject) RequesterID() int                   { return 3 }
+func (s *subject) CurrentReviewer() *int              { return s.currentReviewerID }
+func (s *subject) SetCurrentReviewer(reviewerID *int) { s.currentReviewerID = reviewerID }
+
+func (s *subject) LastReview() (domain.Review, bool) {
+	if len(s.reviews) == 0 {
+		return domain.Review{}, false
+	}
+	return s.reviews[len(s.reviews)-1], true
+}
+
+func newSubject(id, reviewerID int) *subject {
+	return &subject{
+		id:                id,
+		currentReviewerID: common.GetPtr(reviewerID),
+		reviews:           []domain.Review{{ID: id, ReviewerID: reviewerID, Status: domain.ReviewStatusReviewing}},
+	}
+}
+
+// saved records the reviews the chain saves by subject
+type saved map[int][]domain.Review
+
+func newChain(t *testing.T) (ReviewChain[*subject], *mocks_employee_repo.EmployeeRepo,
+	*mocks_delegation_service.DelegationService, saved) {
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockDelegationService := mocks_delegation_service.NewDelegationService(t)
+	reviews := saved{}
+	save := func(ctx context.Context, s *subject, r []domain.Review) error {
+		reviews[s.id] = r
+		return nil
+	}
+	return NewReviewChain(common.NewLogger(), mockEmployeeRepo, mockDelegationService, save), mockEmployeeRepo,
+		mockDelegationService, reviews
+}
+
+func TestNewReview(t *testing.T) {
+	ctx := context.Background()
+	chain, _, mockDelegationService, _ := newChain(t)
+
+	s := &subject{id: 1}
+	mockDelegationService.On("GetDelegate", ctx, 2, mock.Anything).Return(common.GetPtr(5), nil).Once()
+	review, err := chain.NewReview(ctx, s, 2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, review.ReviewerID)
+	assert.Equal(t, common.GetPtr(2), review.OnBehalfOfID)
+	assert.True(t, review.Revocation)
+	assert.Equal(t, common.GetPtr(5), s.currentReviewerID)
+
+	// nobody reviews their own request as a delegate
+	mockDelegationService.On("GetDelegate", ctx, 2, mock.Anything).Return(common.GetPtr(3), nil).Once()
+	review, err = chain.NewReview(ctx, s, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, review.ReviewerID)
+	assert.Nil(t, review.OnBehalfOfID)
+	assert.Equal(t, common.GetPtr(2), s.currentReviewerID)
+}
+
+func TestCheckReviewer(t *testing.T) {
+	ctx := context.Background()
+	chain, _, mockDelegationService, _ := newChain(t)
+
+	t.Run("assigned reviewer", func(t *testing.T) {
+		s := newSubject(1, 2)
+		review := s.reviews[0]
+		managerID, err := chain.CheckReviewer(ctx, s, &review, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, managerID)
+	})
+
+	t.Run("manager in person", func(t *testing.T) {
+		s := newSubject(1, 5)
+		review := s.reviews[0]
+		review.OnBehalfOfID = common.GetPtr(2)
+		managerID, err := chain.CheckReviewer(ctx, s, &review, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, managerID)
+		assert.Equal(t, 2, review.ReviewerID)
+		assert.Nil(t, review.OnBehalfOfID)
+	})
+
+	t.Run("delegate of today", func(t *testing.T) {
+		s := newSubject(1, 2)
+		review := s.reviews[0]
+		mockDelegationService.On("GetDelegate", ctx, 2, mock.Anything).Return(common.GetPtr(5), nil).Once()
+		managerID, err := chain.CheckReviewer(ctx, s, &review, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, managerID)
+		assert.Equal(t, 5, review.ReviewerID)
+		assert.Equal(t, common.GetPtr(2), review.OnBehalfOfID)
+	})
+
+	t.Run("someone else", func(t *testing.T) {
+		s := newSubject(1, 2)
+		review := s.reviews[0]
+		mockDelegationService.On("GetDelegate", ctx, 2, mock.Anything).Return(nil, nil).Once()
+		_, err := chain.CheckReviewer(ctx, s, &review, 6)
+		assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+	})
+}
+
+func TestEscalate(t *testing.T) {
+	ctx := context.Background()
+	chain, mockEmployeeRepo, mockDelegationService, reviews := newChain(t)
+
+	// the manager of reviewer 1 is at the top, reviewer 7 has no manager
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 2).
+		Return(employee_domain.Employee{ID: 2, ManagerID: common.GetPtr(1)}, nil).Once()
+	mockDelegationService.On("GetDelegate", ctx, 1, mock.Anything).Return(nil, nil).Once()
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 7).Return(employee_domain.Employee{ID: 7}, nil).Once()
+
+	escalated := chain.Escalate(ctx, []*subject{newSubject(1, 2), newSubject(2, 7)})
+	assert.Equal(t, 1, escalated)
+
+	assert.Len(t, reviews[1], 2)
+	assert.Equal(t, domain.ReviewStatusEscalated, reviews[1][0].Status)
+	assert.Equal(t, 1, reviews[1][1].ReviewerID)
+	assert.True(t, reviews[1][1].Escalated)
+	assert.Len(t, reviews[2], 1)
+	assert.True(t, reviews[2][0].Unescalatable)
+	assert.Equal(t, domain.ReviewStatusReviewing, reviews[2][0].Status)
+}
+
+func TestReassign(t *testing.T) {
+	ctx := context.Background()
+	chain, mockEmployeeRepo, mockDelegationService, reviews := newChain(t)
+
+	// reviewer 2 reviews the second one as the delegate of manager 4
+	delegated := newSubject(2, 2)
+	delegated.reviews[0].OnBehalfOfID = common.GetPtr(4)
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 2).
+		Return(employee_domain.Employee{ID: 2, ManagerID: common.GetPtr(1)}, nil).Once()
+	mockDelegationService.On("GetDelegate", ctx, 1, mock.Anything).Return(nil, nil).Once()
+
+	reassigned, err := chain.Reassign(ctx, []*subject{newSubject(1, 2), delegated}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reassigned)
+	assert.Equal(t, domain.ReviewStatusReassigned, reviews[1][0].Status)
+	assert.Equal(t, 1, reviews[1][1].ReviewerID)
+	assert.False(t, reviews[1][1].Escalated)
+	assert.Equal(t, domain.ReviewStatusReassigned, reviews[2][0].Status)
+	assert.Equal(t, 4, reviews[2][1].ReviewerID)
+	assert.Nil(t, reviews[2][1].OnBehalfOfID)
+	assert.Equal(t, common.GetPtr(4), delegated.currentReviewerID)
+
+	// nobody to take it
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 2).Return(employee_domain.Employee{ID: 2}, nil).Once()
+	_, err = chain.Reassign(ctx, []*subject{newSubject(3, 2)}, 2)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+}
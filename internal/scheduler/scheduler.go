@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/cache"
+	"hr-system/internal/common"
+)
+
+// Job is a task run by the scheduler periodically
+type Job func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      Job
+}
+
+// Scheduler runs jobs periodically in the service process. Every replica runs the scheduler,
+// a Redis lock held for the interval makes a job run once an interval among all of them,
+// the lock is renewed while the job runs longer than that and cut back to the rest of the interval once it's done.
+type Scheduler struct {
+	cache  *cache.Cache
+	prefix string
+	owner  string
+	logger *common.Logger
+	jobs   []job
+}
+
+func NewScheduler(logger *common.Logger, cache *cache.Cache, prefix string) *Scheduler {
+	hostname, _ := os.Hostname()
+	return &Scheduler{
+		cache:  cache,
+		prefix: prefix,
+		owner:  fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		logger: logger,
+	}
+}
+
+// Every registers the job to run every interval, it must be called before Start
+func (s *Scheduler) Every(name string, interval time.Duration, run Job) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+// Start runs the jobs until the context is done
+func (s *Scheduler) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, j := range s.jobs {
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx, j.name, j.interval, j.run)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs the job unless it has run in the last interval, it reports whether the job ran
+func (s *Scheduler) RunOnce(ctx context.Context, name string, interval time.Duration, run Job) bool {
+	// the lock is kept until it expires so the other replicas skip this interval
+	key := fmt.Sprintf("%s_lock_%s", s.prefix, name)
+	ok, err := s.cache.TryLock(ctx, key, s.owner, interval)
+	if err != nil {
+		s.logger.Errorf("failed to lock job %s, cause: %s", name, err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+
+	start := time.Now()
+	done, renewed := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(renewed)
+		s.renewLock(ctx, key, interval, done)
+	}()
+	// the lock is not renewed any more once the job is done
+	stopRenewal := func() {
+		close(done)
+		<-renewed
+	}
+
+	// nobody is authenticated in a job, it passes the access checks of the services
+	if err := run(auth.AsSystem(ctx)); err != nil {
+		stopRenewal()
+		s.logger.Errorf("job %s failed, cause: %s", name, err)
+		// let the next tick retry
+		if err := s.cache.Unlock(ctx, key, s.owner); err != nil {
+			s.logger.Errorf("failed to unlock job %s, cause: %s", name, err)
+		}
+		return true
+	}
+	stopRenewal()
+	s.logger.Infof("job %s finished in %s", name, time.Since(start))
+	s.keepLockForInterval(ctx, key, interval, start)
+	return true
+}
+
+// keepLockForInterval cuts the lock of a finished job back to the rest of the interval it started in, a renewal
+// would otherwise keep it past the next tick and make the job skip it. The lock is let go a little early so the tick
+// of the same replica, which came a little before the lock was taken, finds it free.
+func (s *Scheduler) keepLockForInterval(ctx context.Context, key string, interval time.Duration, start time.Time) {
+	left := interval - time.Since(start) - min(interval/10, time.Second)
+	if left <= 0 {
+		if err := s.cache.Unlock(ctx, key, s.owner); err != nil {
+			s.logger.Errorf("failed to unlock %s, cause: %s", key, err)
+		}
+		return
+	}
+	if _, err := s.cache.ExtendLock(ctx, key, s.owner, left); err != nil {
+		s.logger.Errorf("failed to shorten lock %s, cause: %s", key, err)
+	}
+}
+
+// renewLock keeps the lock of a running job for another interval until the job is done,
+// so no other replica starts the job before it finishes
+func (s *Scheduler) renewLock(ctx context.Context, key string, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := s.cache.ExtendLock(ctx, key, s.owner, interval)
+			if err != nil {
+				s.logger.Errorf("failed to renew lock %s, cause: %s", key, err)
+				continue
+			}
+			if !ok {
+				s.logger.Warnf("lock %s is lost, the job may run on another replica", key)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"hr-system/internal/cache"
+	"hr-system/internal/common"
+)
+
+func setupTestRedis() (*miniredis.Miniredis, *cache.Cache) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	return mr, cache.NewCache(rdb)
+}
+
+func TestRunOnce(t *testing.T) {
+	mr, c := setupTestRedis()
+	defer mr.Close()
+
+	ctx := context.Background()
+	// two replicas share the same redis
+	replica1 := NewScheduler(common.NewLogger(), c, "test")
+	replica2 := NewScheduler(common.NewLogger(), c, "test")
+
+	runs := 0
+	job := func(ctx context.Context) error {
+		runs++
+		return nil
+	}
+
+	assert.True(t, replica1.RunOnce(ctx, "job", time.Minute, job))
+	assert.False(t, replica2.RunOnce(ctx, "job", time.Minute, job))
+	assert.Equal(t, 1, runs)
+
+	// the next interval
+	mr.FastForward(time.Minute)
+	assert.True(t, replica2.RunOnce(ctx, "job", time.Minute, job))
+	assert.Equal(t, 2, runs)
+}
+
+func TestRunOnce_Failed(t *testing.T) {
+	mr, c := setupTestRedis()
+	defer mr.Close()
+
+	ctx := context.Background()
+	replica1 := NewScheduler(common.NewLogger(), c, "test")
+	replica2 := NewScheduler(common.NewLogger(), c, "test")
+
+	failed := func(ctx context.Context) error {
+		return errors.New("failed")
+	}
+	assert.True(t, replica1.RunOnce(ctx, "job", time.Minute, failed))
+
+	// a failed job releases the lock for the others to retry
+	assert.True(t, replica2.RunOnce(ctx, "job", time.Minute, func(ctx context.Context) error { return nil }))
+}
+
+func TestRunOnce_LongJob(t *testing.T) {
+	mr, c := setupTestRedis()
+	defer mr.Close()
+
+	ctx := context.Background()
+	replica1 := NewScheduler(common.NewLogger(), c, "test")
+	replica2 := NewScheduler(common.NewLogger(), c, "test")
+
+	interval := 60 * time.Millisecond
+	long := func(ctx context.Context) error {
+		// the job outlives the interval several times
+		for i := 0; i < 5; i++ {
+			mr.FastForward(40 * time.Millisecond)
+			time.Sleep(30 * time.Millisecond)
+		}
+		// the lock is still held by the running job
+		assert.False(t, replica2.RunOnce(ctx, "job", interval, func(ctx context.Context) error { return nil }))
+		return nil
+	}
+	assert.True(t, replica1.RunOnce(ctx, "job", interval, long))
+}
+
+func TestRunOnce_RenewedJob(t *testing.T) {
+	mr, c := setupTestRedis()
+	defer mr.Close()
+
+	ctx := context.Background()
+	scheduler := NewScheduler(common.NewLogger(), c, "test")
+
+	interval := 300 * time.Millisecond
+	// the lock is renewed for another interval while the job runs
+	renewed := func(ctx context.Context) error {
+		time.Sleep(interval / 2)
+		return nil
+	}
+	assert.True(t, scheduler.RunOnce(ctx, "job", interval, renewed))
+
+	// it's cut back to the rest of the interval, so the next tick of the replica runs the job again
+	ttl := mr.TTL("test_lock_job")
+	assert.Greater(t, ttl, time.Duration(0))
+	assert.Less(t, ttl, interval/2)
+	mr.FastForward(interval / 2)
+	assert.True(t, scheduler.RunOnce(ctx, "job", interval, func(ctx context.Context) error { return nil }))
+}
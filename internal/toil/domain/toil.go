@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	leave_domain "hr-system/internal/leaves/domain"
+	review_domain "hr-system/internal/reviews/domain"
+)
+
+// MaxHoursPerDay is the most overtime logged for a single day
+const MaxHoursPerDay = 24
+
+// TOILRequest logs the extra hours an employee worked on a day, once the manager approves them they become a
+// comp-off allowance taken through comp_off leaves until ExpiresAt.
+// A request is reviewed like a leave, by the manager of the employee or their delegate, and is escalated or reassigned
+// like one.
+type TOILRequest struct {
+	ID         int                        `json:"id" gorm:"primaryKey;autoIncrement"`
+	EmployeeID int                        `json:"employee_id" gorm:"index:idx_employee_id;not null" validate:"required"`
+	WorkDate   time.Time                  `json:"work_date" gorm:"type:date;not null" validate:"required"`
+	Hours      float64                    `json:"hours" gorm:"type:decimal(5,2);not null" validate:"gt=0,lte=24"`
+	Reason     string                     `json:"reason" gorm:"type:varchar(255)" validate:"max=255"`
+	Status     review_domain.ReviewStatus `json:"status" gorm:"type:varchar(50);not null"`
+	// ExpiresAt is set on the approval, the allowance can not be taken from then on
+	ExpiresAt         *time.Time             `json:"expires_at,omitempty" gorm:"type:date"`
+	CurrentReviewerID *int                   `json:"current_reviewer_id,omitempty" gorm:"index:idx_current_reviewer_id"`
+	Reviews           []review_domain.Review `json:"reviews,omitempty" gorm:"polymorphic:Subject;polymorphicValue:toil"`
+	CreatedAt         time.Time              `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time              `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Days is the comp-off days the hours are worth
+func (r *TOILRequest) Days() float64 {
+	return r.Hours / leave_domain.WorkingHoursPerDay
+}
+
+func (r *TOILRequest) String() string {
+	return fmt.Sprintf("TOIL request %d", r.ID)
+}
+
+func (r *TOILRequest) RequesterID() int {
+	return r.EmployeeID
+}
+
+func (r *TOILRequest) CurrentReviewer() *int {
+	return r.CurrentReviewerID
+}
+
+func (r *TOILRequest) SetCurrentReviewer(reviewerID *int) {
+	r.CurrentReviewerID = reviewerID
+}
+
+func (r *TOILRequest) LastReview() (review_domain.Review, bool) {
+	if len(r.Reviews) == 0 {
+		return review_domain.Review{}, false
+	}
+	return r.Reviews[len(r.Reviews)-1], true
+}
+
+// TOILRequestsQuery filters the requests, the caller must be allowed the employee or be the current reviewer,
+// only HR lists the requests without any of them
+type TOILRequestsQuery struct {
+	EmployeeID        *int
+	CurrentReviewerID *int
+	// DelegatorIDs are the managers the current reviewer reviews on behalf of, their requests are kept too
+	DelegatorIDs []int
+	Statuses     []review_domain.ReviewStatus `validate:"dive,oneof=reviewing approved rejected cancelled"`
+}
+
+// Grant is the comp-off days of an approved request and how many of them the comp_off leaves have taken
+type Grant struct {
+	RequestID int       `json:"request_id"`
+	WorkDate  time.Time `json:"work_date"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Days      float64   `json:"days"`
+	Taken     float64   `json:"taken"`
+}
+
+// Allowance is the comp-off days an employee can take on a day
+type Allowance struct {
+	EmployeeID int       `json:"employee_id"`
+	Date       time.Time `json:"date"`
+	Remaining  float64   `json:"remaining"`
+	// Grants are the ones which have not expired on the day
+	Grants []Grant `json:"grants"`
+}
+
+// NewGrants returns the grants of the approved requests, the ones expiring first come first
+func NewGrants(requests []TOILRequest) []Grant {
+	var grants []Grant
+	for i := range requests {
+		if requests[i].Status != review_domain.ReviewStatusApproved || requests[i].ExpiresAt == nil {
+			continue
+		}
+		grants = append(grants, Grant{
+			RequestID: requests[i].ID,
+			WorkDate:  requests[i].WorkDate,
+			ExpiresAt: *requests[i].ExpiresAt,
+			Days:      requests[i].Days(),
+		})
+	}
+	sort.SliceStable(grants, func(i, j int) bool {
+		return grants[i].ExpiresAt.Before(grants[j].ExpiresAt)
+	})
+	return grants
+}
+
+// Take takes the days of the comp_off leaves from the grants in the order of the leaves, each leave from the grants
+// worked before it starts and not expired by then, the ones expiring first first.
+// It returns the days of each leave none of the grants could cover.
+func Take(grants []Grant, leaves []leave_domain.Leave) []float64 {
+	order := make([]int, len(leaves))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return leaves[order[i]].StartDate.Before(leaves[order[j]].StartDate)
+	})
+
+	uncovered := make([]float64, len(leaves))
+	for _, i := range order {
+		need := leaves[i].Days
+		for g := range grants {
+			if need <= 0 {
+				break
+			}
+			if !grants[g].Covers(leaves[i].StartDate) {
+				continue
+			}
+			taken := min(need, grants[g].Days-grants[g].Taken)
+			if taken <= 0 {
+				continue
+			}
+			grants[g].Taken += taken
+			need -= taken
+		}
+		if need > 0.001 {
+			uncovered[i] = need
+		}
+	}
+	return uncovered
+}
+
+// Covers reports whether a leave starting on the day can be taken from the grant
+func (g *Grant) Covers(day time.Time) bool {
+	return !day.Before(g.WorkDate) && day.Before(g.ExpiresAt)
+}
+
+// AllowanceOn returns the allowance left on the day after the grants have been taken
+func AllowanceOn(employeeID int, grants []Grant, day time.Time) Allowance {
+	allowance := Allowance{EmployeeID: employeeID, Date: day, Grants: []Grant{}}
+	for _, grant := range grants {
+		if !day.Before(grant.ExpiresAt) {
+			continue
+		}
+		allowance.Grants = append(allowance.Grants, grant)
+		allowance.Remaining += grant.Days - grant.Taken
+	}
+	return allowance
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"hr-system/internal/common"
+	leave_domain "hr-system/internal/leaves/domain"
+	review_domain "hr-system/internal/reviews/domain"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2024, month, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestTake(t *testing.T) {
+	requests := []TOILRequest{
+		{ID: 1, WorkDate: day(1, 6), Hours: 8, Status: review_domain.ReviewStatusApproved,
+			ExpiresAt: common.GetPtr(day(4, 10))},
+		{ID: 2, WorkDate: day(1, 13), Hours: 4, Status: review_domain.ReviewStatusApproved,
+			ExpiresAt: common.GetPtr(day(3, 1))},
+		// not approved
+		{ID: 3, WorkDate: day(1, 20), Hours: 8, Status: review_domain.ReviewStatusReviewing},
+	}
+	grants := NewGrants(requests)
+	assert.Len(t, grants, 2)
+	// the one expiring first comes first
+	assert.Equal(t, 2, grants[0].RequestID)
+	assert.Equal(t, 0.5, grants[0].Days)
+
+	leaves := []leave_domain.Leave{
+		// taken from both
+		{StartDate: day(2, 5), Days: 1},
+		// only the first one is left
+		{StartDate: day(3, 4), Days: 1},
+		// before the overtime was worked
+		{StartDate: day(1, 2), Days: 0.5},
+	}
+	uncovered := Take(grants, leaves)
+	assert.Equal(t, []float64{0, 0.5, 0.5}, uncovered)
+	assert.Equal(t, 0.5, grants[0].Taken)
+	assert.Equal(t, 1.0, grants[1].Taken)
+
+	allowance := AllowanceOn(7, grants, day(3, 4))
+	assert.Equal(t, 0.0, allowance.Remaining)
+	assert.Len(t, allowance.Grants, 1)
+
+	// every grant has expired
+	allowance = AllowanceOn(7, grants, day(4, 10))
+	assert.Equal(t, 0.0, allowance.Remaining)
+	assert.Empty(t, allowance.Grants)
+}
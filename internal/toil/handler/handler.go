@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-system/internal/common"
+	"hr-system/internal/middleware"
+	review_domain "hr-system/internal/reviews/domain"
+	"hr-system/internal/toil/domain"
+	"hr-system/internal/toil/service"
+)
+
+type TOILHandler struct {
+	toilService service.TOILService
+	logger      *common.Logger
+}
+
+func NewTOILHandler(logger *common.Logger, toilService service.TOILService) *TOILHandler {
+	return &TOILHandler{
+		toilService: toilService,
+		logger:      logger,
+	}
+}
+
+type CreateTOILRequest struct {
+	WorkDate time.Time `json:"work_date" binding:"required"`
+	Hours    float64   `json:"hours" binding:"required"`
+	Reason   string    `json:"reason"`
+}
+
+type ReviewTOILRequest struct {
+	Decision review_domain.ReviewStatus `json:"decision" binding:"required,oneof=approved rejected"`
+	Comment  string                     `json:"comment"`
+}
+
+func (h *TOILHandler) CreateRequest(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, ok := middleware.GetActor(c)
+	if !ok {
+		return
+	}
+
+	var req CreateTOILRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	request, err := h.toilService.CreateRequest(ctx, &domain.TOILRequest{
+		EmployeeID: actor.EmployeeID,
+		WorkDate:   req.WorkDate,
+		Hours:      req.Hours,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		middleware.HandleErr(c, err, "create TOIL request")
+		return
+	}
+
+	c.JSON(http.StatusCreated, request)
+}
+
+func (h *TOILHandler) GetRequests(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query := domain.TOILRequestsQuery{}
+	if employeeID := c.Query("employee_id"); employeeID != "" {
+		id, err := strconv.Atoi(employeeID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid employee_id"))
+			return
+		}
+		query.EmployeeID = &id
+	}
+	if reviewerID := c.Query("current_reviewer_id"); reviewerID != "" {
+		id, err := strconv.Atoi(reviewerID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid current_reviewer_id"))
+			return
+		}
+		query.CurrentReviewerID = &id
+	}
+	// the statuses are validated by the service
+	for _, status := range middleware.GetListQuery(c, "status") {
+		query.Statuses = append(query.Statuses, review_domain.ReviewStatus(status))
+	}
+
+	requests, err := h.toilService.GetRequests(ctx, query)
+	if err != nil {
+		middleware.HandleErr(c, err, "get TOIL requests")
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+func (h *TOILHandler) GetRequestByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid TOIL request ID"))
+		return
+	}
+
+	request, err := h.toilService.GetRequestByID(ctx, id)
+	if err != nil {
+		middleware.HandleErr(c, err, "get TOIL request")
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+func (h *TOILHandler) ReviewRequest(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid TOIL request ID"))
+		return
+	}
+
+	actor, ok := middleware.GetActor(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewTOILRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid request body, cause: %v", err))
+		return
+	}
+
+	if err := h.toilService.ReviewRequest(ctx, id, actor.EmployeeID, req.Decision, req.Comment); err != nil {
+		middleware.HandleErr(c, err, "review TOIL request")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TOILHandler) CancelRequest(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("invalid TOIL request ID"))
+		return
+	}
+
+	actor, ok := middleware.GetActor(c)
+	if !ok {
+		return
+	}
+
+	if err := h.toilService.CancelRequest(ctx, id, actor.EmployeeID); err != nil {
+		middleware.HandleErr(c, err, "cancel TOIL request")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TOILHandler) GetAllowance(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, middleware.CreateErrResp("Invalid employee ID"))
+		return
+	}
+
+	allowance, err := h.toilService.GetAllowance(ctx, employeeID)
+	if err != nil {
+		middleware.HandleErr(c, err, "get comp-off allowance")
+		return
+	}
+
+	c.JSON(http.StatusOK, allowance)
+}
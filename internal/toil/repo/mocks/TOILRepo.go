@@ -0,0 +1,187 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/toil/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	reviewsdomain "hr-system/internal/reviews/domain"
+
+	time "time"
+)
+
+// TOILRepo is an autogenerated mock type for the TOILRepo type
+type TOILRepo struct {
+	mock.Mock
+}
+
+// CreateRequest provides a mock function with given fields: ctx, request
+func (_m *TOILRepo) CreateRequest(ctx context.Context, request *domain.TOILRequest) error {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TOILRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRequestByID provides a mock function with given fields: ctx, id
+func (_m *TOILRepo) GetRequestByID(ctx context.Context, id int) (domain.TOILRequest, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequestByID")
+	}
+
+	var r0 domain.TOILRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (domain.TOILRequest, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) domain.TOILRequest); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.TOILRequest)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRequests provides a mock function with given fields: ctx, query
+func (_m *TOILRepo) GetRequests(ctx context.Context, query domain.TOILRequestsQuery) ([]domain.TOILRequest, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequests")
+	}
+
+	var r0 []domain.TOILRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TOILRequestsQuery) ([]domain.TOILRequest, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TOILRequestsQuery) []domain.TOILRequest); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TOILRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TOILRequestsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRequestsPendingOn provides a mock function with given fields: ctx, reviewerID
+func (_m *TOILRepo) GetRequestsPendingOn(ctx context.Context, reviewerID int) ([]domain.TOILRequest, error) {
+	ret := _m.Called(ctx, reviewerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequestsPendingOn")
+	}
+
+	var r0 []domain.TOILRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.TOILRequest, error)); ok {
+		return rf(ctx, reviewerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.TOILRequest); ok {
+		r0 = rf(ctx, reviewerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TOILRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, reviewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRequestsWithStaleReviews provides a mock function with given fields: ctx, before
+func (_m *TOILRepo) GetRequestsWithStaleReviews(ctx context.Context, before time.Time) ([]domain.TOILRequest, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequestsWithStaleReviews")
+	}
+
+	var r0 []domain.TOILRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]domain.TOILRequest, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []domain.TOILRequest); ok {
+		r0 = rf(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TOILRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRequestAndReviews provides a mock function with given fields: ctx, request, reviews
+func (_m *TOILRepo) UpdateRequestAndReviews(ctx context.Context, request *domain.TOILRequest, reviews []reviewsdomain.Review) error {
+	ret := _m.Called(ctx, request, reviews)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRequestAndReviews")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TOILRequest, []reviewsdomain.Review) error); ok {
+		r0 = rf(ctx, request, reviews)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTOILRepo creates a new instance of TOILRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTOILRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TOILRepo {
+	mock := &TOILRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,170 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	common_errors "hr-system/internal/common/errors"
+	"hr-system/internal/common/transaction"
+	review_domain "hr-system/internal/reviews/domain"
+	"hr-system/internal/toil/domain"
+)
+
+type TOILRepo interface {
+	// CreateRequest creates the request with its reviews
+	CreateRequest(ctx context.Context, request *domain.TOILRequest) error
+	GetRequestByID(ctx context.Context, id int) (domain.TOILRequest, error)
+	GetRequests(ctx context.Context, query domain.TOILRequestsQuery) ([]domain.TOILRequest, error)
+	// UpdateRequestAndReviews updates the request and creates or updates the reviews in a transaction
+	UpdateRequestAndReviews(ctx context.Context, request *domain.TOILRequest, reviews []review_domain.Review) error
+	// GetRequestsWithStaleReviews returns the requests whose pending review was created before the time
+	// and can be escalated
+	GetRequestsWithStaleReviews(ctx context.Context, before time.Time) ([]domain.TOILRequest, error)
+	// GetRequestsPendingOn returns the requests whose pending review is on the reviewer, whether they review it
+	// themselves, as a delegate or through their own delegate
+	GetRequestsPendingOn(ctx context.Context, reviewerID int) ([]domain.TOILRequest, error)
+}
+
+type toilRepo struct {
+	db *gorm.DB
+}
+
+func NewTOILRepo(db *gorm.DB) (TOILRepo, error) {
+	repo := &toilRepo{
+		db: db,
+	}
+
+	if err := repo.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *toilRepo) ensureSchema() error {
+	// AutoMigrate
+	if err := r.db.AutoMigrate(domain.TOILRequest{}); err != nil {
+		return err
+	}
+	if err := r.db.AutoMigrate(review_domain.Review{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *toilRepo) CreateRequest(ctx context.Context, request *domain.TOILRequest) error {
+	if err := r.db.WithContext(ctx).Create(request).Error; err != nil {
+		return fmt.Errorf("failed to create TOIL request: %w", err)
+	}
+	return nil
+}
+
+func preloadReviews(db *gorm.DB) *gorm.DB {
+	return db.Preload("Reviews", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	})
+}
+
+func (r *toilRepo) GetRequestByID(ctx context.Context, id int) (domain.TOILRequest, error) {
+	var request domain.TOILRequest
+	db := r.db.WithContext(ctx)
+	db = preloadReviews(db)
+	if err := db.First(&request, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.TOILRequest{}, common_errors.ErrResourceNotFound
+		}
+		return domain.TOILRequest{}, fmt.Errorf("failed to find TOIL request with id %d: %w", id, err)
+	}
+	return request, nil
+}
+
+func (r *toilRepo) GetRequests(ctx context.Context, query domain.TOILRequestsQuery) ([]domain.TOILRequest, error) {
+	db := r.db.WithContext(ctx)
+	if query.EmployeeID != nil {
+		db = db.Where("employee_id = ?", *query.EmployeeID)
+	}
+	if query.CurrentReviewerID != nil {
+		if len(query.DelegatorIDs) > 0 {
+			db = db.Where("(current_reviewer_id = ? OR (current_reviewer_id IN ? AND employee_id <> ?))",
+				*query.CurrentReviewerID, query.DelegatorIDs, *query.CurrentReviewerID)
+		} else {
+			db = db.Where("current_reviewer_id = ?", *query.CurrentReviewerID)
+		}
+	}
+	if len(query.Statuses) > 0 {
+		db = db.Where("status IN ?", query.Statuses)
+	}
+
+	var requests []domain.TOILRequest
+	if err := preloadReviews(db).Order("work_date DESC, id DESC").Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("failed to get TOIL requests: %w", err)
+	}
+	return requests, nil
+}
+
+func (r *toilRepo) UpdateRequestAndReviews(ctx context.Context, request *domain.TOILRequest,
+	reviews []review_domain.Review) error {
+	err := transaction.Run(ctx, r.db, func(tx *gorm.DB) error {
+		// the reviews are saved on their own
+		result := tx.Omit("Reviews").Save(request)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update TOIL request: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("no rows affected, TOIL request ID may not exist")
+		}
+
+		for _, review := range reviews {
+			review.SubjectType = review_domain.SubjectTypeTOIL
+			review.SubjectID = request.ID
+			if err := tx.Save(&review).Error; err != nil {
+				return fmt.Errorf("failed to save TOIL review: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update TOIL request and reviews: %w", err)
+	}
+	return nil
+}
+
+func (r *toilRepo) GetRequestsWithStaleReviews(ctx context.Context, before time.Time) ([]domain.TOILRequest, error) {
+	staleReviews := r.db.Model(&review_domain.Review{}).
+		Select("subject_id").
+		Where("subject_type = ?", review_domain.SubjectTypeTOIL).
+		Where("status = ? AND created_at < ? AND unescalatable = ?", review_domain.ReviewStatusReviewing, before, false)
+
+	var requests []domain.TOILRequest
+	err := preloadReviews(r.db.WithContext(ctx)).
+		Where("status = ? AND id IN (?)", review_domain.ReviewStatusReviewing, staleReviews).
+		Order("id ASC").
+		Find(&requests).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TOIL requests with stale reviews: %w", err)
+	}
+	return requests, nil
+}
+
+func (r *toilRepo) GetRequestsPendingOn(ctx context.Context, reviewerID int) ([]domain.TOILRequest, error) {
+	pendingReviews := r.db.Model(&review_domain.Review{}).
+		Select("subject_id").
+		Where("subject_type = ?", review_domain.SubjectTypeTOIL).
+		Where("status = ? AND (reviewer_id = ? OR on_behalf_of_id = ?)", review_domain.ReviewStatusReviewing, reviewerID,
+			reviewerID)
+
+	var requests []domain.TOILRequest
+	err := preloadReviews(r.db.WithContext(ctx)).
+		Where("status = ? AND id IN (?)", review_domain.ReviewStatusReviewing, pendingReviews).
+		Order("id ASC").
+		Find(&requests).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TOIL requests pending on %d: %w", reviewerID, err)
+	}
+	return requests, nil
+}
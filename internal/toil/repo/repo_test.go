@@ -0,0 +1,171 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"hr-system/internal/common"
+	review_domain "hr-system/internal/reviews/domain"
+	"hr-system/internal/toil/domain"
+)
+
+func setupTestDB() (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&domain.TOILRequest{}, &review_domain.Review{}); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func TestCreateAndGetRequest(t *testing.T) {
+	db, err := setupTestDB()
+	assert.NoError(t, err)
+
+	repo := &toilRepo{db: db}
+	request := &domain.TOILRequest{
+		EmployeeID:        3,
+		WorkDate:          time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		Hours:             4,
+		Status:            review_domain.ReviewStatusReviewing,
+		CurrentReviewerID: common.GetPtr(2),
+		Reviews:           []review_domain.Review{{ReviewerID: 2, Status: review_domain.ReviewStatusReviewing}},
+	}
+	err = repo.CreateRequest(context.Background(), request)
+	assert.NoError(t, err)
+	assert.NotZero(t, request.ID)
+
+	fetched, err := repo.GetRequestByID(context.Background(), request.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, fetched.Hours)
+	assert.Len(t, fetched.Reviews, 1)
+	assert.Equal(t, review_domain.SubjectTypeTOIL, fetched.Reviews[0].SubjectType)
+	assert.Equal(t, request.ID, fetched.Reviews[0].SubjectID)
+
+	_, err = repo.GetRequestByID(context.Background(), request.ID+1)
+	assert.Error(t, err)
+}
+
+func TestGetRequests(t *testing.T) {
+	db, err := setupTestDB()
+	assert.NoError(t, err)
+
+	repo := &toilRepo{db: db}
+	requests := []domain.TOILRequest{
+		{EmployeeID: 3, WorkDate: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Hours: 4,
+			Status: review_domain.ReviewStatusReviewing, CurrentReviewerID: common.GetPtr(2)},
+		{EmployeeID: 3, WorkDate: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), Hours: 8,
+			Status: review_domain.ReviewStatusApproved},
+		// waiting for manager 5, who delegated to 2
+		{EmployeeID: 4, WorkDate: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Hours: 2,
+			Status: review_domain.ReviewStatusReviewing, CurrentReviewerID: common.GetPtr(5)},
+	}
+	for i := range requests {
+		assert.NoError(t, repo.CreateRequest(context.Background(), &requests[i]))
+	}
+
+	fetched, err := repo.GetRequests(context.Background(), domain.TOILRequestsQuery{EmployeeID: common.GetPtr(3)})
+	assert.NoError(t, err)
+	assert.Len(t, fetched, 2)
+	// the latest work date first
+	assert.Equal(t, requests[1].ID, fetched[0].ID)
+
+	fetched, err = repo.GetRequests(context.Background(), domain.TOILRequestsQuery{
+		EmployeeID: common.GetPtr(3),
+		Statuses:   []review_domain.ReviewStatus{review_domain.ReviewStatusApproved},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, fetched, 1)
+
+	fetched, err = repo.GetRequests(context.Background(), domain.TOILRequestsQuery{
+		CurrentReviewerID: common.GetPtr(2),
+		DelegatorIDs:      []int{5},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, fetched, 2)
+}
+
+func TestUpdateRequestAndReviews(t *testing.T) {
+	db, err := setupTestDB()
+	assert.NoError(t, err)
+
+	repo := &toilRepo{db: db}
+	request := &domain.TOILRequest{
+		EmployeeID:        3,
+		WorkDate:          time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		Hours:             4,
+		Status:            review_domain.ReviewStatusReviewing,
+		CurrentReviewerID: common.GetPtr(2),
+		Reviews:           []review_domain.Review{{ReviewerID: 2, Status: review_domain.ReviewStatusReviewing}},
+	}
+	assert.NoError(t, repo.CreateRequest(context.Background(), request))
+
+	review := request.Reviews[0]
+	review.Status = review_domain.ReviewStatusApproved
+	review.Comment = "thanks"
+	request.Status = review_domain.ReviewStatusApproved
+	request.CurrentReviewerID = nil
+	request.ExpiresAt = common.GetPtr(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	err = repo.UpdateRequestAndReviews(context.Background(), request, []review_domain.Review{review})
+	assert.NoError(t, err)
+
+	fetched, err := repo.GetRequestByID(context.Background(), request.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, review_domain.ReviewStatusApproved, fetched.Status)
+	assert.Nil(t, fetched.CurrentReviewerID)
+	assert.NotNil(t, fetched.ExpiresAt)
+	assert.Len(t, fetched.Reviews, 1)
+	assert.Equal(t, "thanks", fetched.Reviews[0].Comment)
+}
+
+func TestGetRequestsWithPendingReviews(t *testing.T) {
+	db, err := setupTestDB()
+	assert.NoError(t, err)
+
+	repo := &toilRepo{db: db}
+	ctx := context.Background()
+	requests := []*domain.TOILRequest{
+		{EmployeeID: 3, Status: review_domain.ReviewStatusReviewing,
+			Reviews: []review_domain.Review{{ReviewerID: 2, Status: review_domain.ReviewStatusReviewing}}},
+		// delegated on behalf of the reviewer
+		{EmployeeID: 3, Status: review_domain.ReviewStatusReviewing,
+			Reviews: []review_domain.Review{{ReviewerID: 5, OnBehalfOfID: common.GetPtr(2),
+				Status: review_domain.ReviewStatusReviewing}}},
+		{EmployeeID: 3, Status: review_domain.ReviewStatusApproved,
+			Reviews: []review_domain.Review{{ReviewerID: 2, Status: review_domain.ReviewStatusApproved}}},
+		// the reviewer has nobody to escalate it to
+		{EmployeeID: 3, Status: review_domain.ReviewStatusReviewing,
+			Reviews: []review_domain.Review{{ReviewerID: 1, Status: review_domain.ReviewStatusReviewing,
+				Unescalatable: true}}},
+	}
+	for _, request := range requests {
+		request.WorkDate = time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+		request.Hours = 4
+		assert.NoError(t, repo.CreateRequest(ctx, request))
+	}
+
+	stale, err := repo.GetRequestsWithStaleReviews(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, stale, 2)
+	assert.Equal(t, requests[0].ID, stale[0].ID)
+	assert.Equal(t, requests[1].ID, stale[1].ID)
+	stale, err = repo.GetRequestsWithStaleReviews(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, stale)
+
+	pending, err := repo.GetRequestsPendingOn(ctx, 2)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+	assert.Len(t, pending[1].Reviews, 1)
+	pending, err = repo.GetRequestsPendingOn(ctx, 5)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, requests[1].ID, pending[0].ID)
+}
@@ -0,0 +1,259 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "hr-system/internal/leaves/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	reviewsdomain "hr-system/internal/reviews/domain"
+
+	time "time"
+
+	toildomain "hr-system/internal/toil/domain"
+)
+
+// TOILService is an autogenerated mock type for the TOILService type
+type TOILService struct {
+	mock.Mock
+}
+
+// CancelRequest provides a mock function with given fields: ctx, requestID, employeeID
+func (_m *TOILService) CancelRequest(ctx context.Context, requestID int, employeeID int) error {
+	ret := _m.Called(ctx, requestID, employeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, requestID, employeeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckAllowance provides a mock function with given fields: ctx, leave
+func (_m *TOILService) CheckAllowance(ctx context.Context, leave *domain.Leave) error {
+	ret := _m.Called(ctx, leave)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckAllowance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Leave) error); ok {
+		r0 = rf(ctx, leave)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateRequest provides a mock function with given fields: ctx, request
+func (_m *TOILService) CreateRequest(ctx context.Context, request *toildomain.TOILRequest) (toildomain.TOILRequest, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRequest")
+	}
+
+	var r0 toildomain.TOILRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *toildomain.TOILRequest) (toildomain.TOILRequest, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *toildomain.TOILRequest) toildomain.TOILRequest); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(toildomain.TOILRequest)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *toildomain.TOILRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EscalateStaleReviews provides a mock function with given fields: ctx, sla
+func (_m *TOILService) EscalateStaleReviews(ctx context.Context, sla time.Duration) (int, error) {
+	ret := _m.Called(ctx, sla)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EscalateStaleReviews")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (int, error)); ok {
+		return rf(ctx, sla)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) int); ok {
+		r0 = rf(ctx, sla)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, sla)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllowance provides a mock function with given fields: ctx, employeeID
+func (_m *TOILService) GetAllowance(ctx context.Context, employeeID int) (toildomain.Allowance, error) {
+	ret := _m.Called(ctx, employeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllowance")
+	}
+
+	var r0 toildomain.Allowance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (toildomain.Allowance, error)); ok {
+		return rf(ctx, employeeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) toildomain.Allowance); ok {
+		r0 = rf(ctx, employeeID)
+	} else {
+		r0 = ret.Get(0).(toildomain.Allowance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, employeeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRequestByID provides a mock function with given fields: ctx, id
+func (_m *TOILService) GetRequestByID(ctx context.Context, id int) (toildomain.TOILRequest, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequestByID")
+	}
+
+	var r0 toildomain.TOILRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (toildomain.TOILRequest, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) toildomain.TOILRequest); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(toildomain.TOILRequest)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRequests provides a mock function with given fields: ctx, query
+func (_m *TOILService) GetRequests(ctx context.Context, query toildomain.TOILRequestsQuery) ([]toildomain.TOILRequest, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRequests")
+	}
+
+	var r0 []toildomain.TOILRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, toildomain.TOILRequestsQuery) ([]toildomain.TOILRequest, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, toildomain.TOILRequestsQuery) []toildomain.TOILRequest); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]toildomain.TOILRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, toildomain.TOILRequestsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReassignReviews provides a mock function with given fields: ctx, reviewerID
+func (_m *TOILService) ReassignReviews(ctx context.Context, reviewerID int) (int, error) {
+	ret := _m.Called(ctx, reviewerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReassignReviews")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, reviewerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, reviewerID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, reviewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReviewRequest provides a mock function with given fields: ctx, requestID, reviewerID, decision, comment
+func (_m *TOILService) ReviewRequest(ctx context.Context, requestID int, reviewerID int, decision reviewsdomain.ReviewStatus, comment string) error {
+	ret := _m.Called(ctx, requestID, reviewerID, decision, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReviewRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, reviewsdomain.ReviewStatus, string) error); ok {
+		r0 = rf(ctx, requestID, reviewerID, decision, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTOILService creates a new instance of TOILService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTOILService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TOILService {
+	mock := &TOILService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	delegation_service "hr-system/internal/delegations/service"
+	employee_repo "hr-system/internal/employees/repo"
+	leave_domain "hr-system/internal/leaves/domain"
+	leave_repo "hr-system/internal/leaves/repo"
+	review_domain "hr-system/internal/reviews/domain"
+	review_service "hr-system/internal/reviews/service"
+	"hr-system/internal/toil/domain"
+	"hr-system/internal/toil/repo"
+)
+
+type TOILService interface {
+	// CreateRequest logs the overtime of the employee for their manager to review,
+	// it's approved at once when the employee has no manager
+	CreateRequest(ctx context.Context, request *domain.TOILRequest) (domain.TOILRequest, error)
+	GetRequestByID(ctx context.Context, id int) (domain.TOILRequest, error)
+	GetRequests(ctx context.Context, query domain.TOILRequestsQuery) ([]domain.TOILRequest, error)
+	// ReviewRequest decides on the request as the reviewer, approving it grants the hours as comp-off allowance
+	ReviewRequest(ctx context.Context, requestID, reviewerID int, decision review_domain.ReviewStatus,
+		comment string) error
+	// CancelRequest withdraws a request which is still waiting for review
+	CancelRequest(ctx context.Context, requestID, employeeID int) error
+	// GetAllowance returns the comp-off allowance the employee has today, the comp_off leaves waiting for review
+	// are counted as taken
+	GetAllowance(ctx context.Context, employeeID int) (domain.Allowance, error)
+	// CheckAllowance makes sure the allowance of the employee covers the comp_off leave on top of their other
+	// comp_off leaves, approved or waiting for review
+	CheckAllowance(ctx context.Context, leave *leave_domain.Leave) error
+	// EscalateStaleReviews passes the reviews pending longer than the SLA to the reviewer's manager,
+	// it returns the number of escalated reviews
+	EscalateStaleReviews(ctx context.Context, sla time.Duration) (int, error)
+	// ReassignReviews passes the reviews pending on the reviewer, or on their delegate for them, to the reviewer's
+	// manager and hands the ones they review as a delegate back to the manager they act for,
+	// it returns the number of reassigned reviews
+	ReassignReviews(ctx context.Context, reviewerID int) (int, error)
+}
+
+type toilService struct {
+	toilRepo          repo.TOILRepo
+	employeeRepo      employee_repo.EmployeeRepo
+	leaveRepo         leave_repo.LeaveRepo
+	delegationService delegation_service.DelegationService
+	reviewChain       review_service.ReviewChain[*domain.TOILRequest]
+	// validity is how long the approved hours can be taken as comp-off
+	validity time.Duration
+	logger   *common.Logger
+	validate *validator.Validate
+}
+
+func NewTOILService(logger *common.Logger, toilRepo repo.TOILRepo, employeeRepo employee_repo.EmployeeRepo,
+	leaveRepo leave_repo.LeaveRepo, delegationService delegation_service.DelegationService,
+	validity time.Duration) TOILService {
+	return &toilService{
+		toilRepo:          toilRepo,
+		employeeRepo:      employeeRepo,
+		leaveRepo:         leaveRepo,
+		delegationService: delegationService,
+		reviewChain: review_service.NewReviewChain(logger, employeeRepo, delegationService,
+			toilRepo.UpdateRequestAndReviews),
+		validity: validity,
+		logger:   logger,
+		validate: validator.New(),
+	}
+}
+
+// activeLeaveStatuses are the statuses of the comp_off leaves which take or may take the allowance
+var activeLeaveStatuses = []review_domain.ReviewStatus{
+	review_domain.ReviewStatusReviewing,
+	review_domain.ReviewStatusApproved,
+	review_domain.ReviewStatusRevoking,
+}
+
+func (s *toilService) CreateRequest(ctx context.Context, request *domain.TOILRequest) (domain.TOILRequest, error) {
+	if _, err := auth.AuthorizeSelf(ctx, request.EmployeeID, auth.RoleHRAdmin); err != nil {
+		return domain.TOILRequest{}, err
+	}
+
+	if err := s.validate.Struct(request); err != nil {
+		return domain.TOILRequest{}, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+	if request.WorkDate.After(time.Now()) {
+		return domain.TOILRequest{}, fmt.Errorf("%w, overtime can only be logged once it's worked",
+			common_errors.ErrInvalidInput)
+	}
+
+	employee, err := s.employeeRepo.GetEmployeeByID(ctx, request.EmployeeID)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.TOILRequest{}, common_errors.ErrResourceNotFound
+		}
+		return domain.TOILRequest{}, fmt.Errorf("failed to get employee: %w", err)
+	}
+	if employee.PositionAt(request.WorkDate).StartDate.IsZero() || employee.TerminatedAt(request.WorkDate) {
+		return domain.TOILRequest{}, fmt.Errorf("%w, employee %d was not employed on %s", common_errors.ErrInvalidInput,
+			request.EmployeeID, request.WorkDate.Format(time.DateOnly))
+	}
+
+	// the hours logged for a day, approved or waiting for review, can not add up to more than the day
+	requests, err := s.toilRepo.GetRequests(ctx, domain.TOILRequestsQuery{
+		EmployeeID: &request.EmployeeID,
+		Statuses: []review_domain.ReviewStatus{
+			review_domain.ReviewStatusReviewing,
+			review_domain.ReviewStatusApproved,
+		},
+	})
+	if err != nil {
+		return domain.TOILRequest{}, fmt.Errorf("failed to get TOIL requests: %w", err)
+	}
+	hours := request.Hours
+	for _, r := range requests {
+		if r.WorkDate.Format(time.DateOnly) == request.WorkDate.Format(time.DateOnly) {
+			hours += r.Hours
+		}
+	}
+	if hours > domain.MaxHoursPerDay {
+		return domain.TOILRequest{}, fmt.Errorf("%w, %.2f hours are logged for %s", common_errors.ErrStatusConflict,
+			hours, request.WorkDate.Format(time.DateOnly))
+	}
+
+	request.Reviews = nil
+	request.CurrentReviewerID = nil
+	if employee.ManagerID == nil {
+		// nobody to review it
+		request.Status = review_domain.ReviewStatusApproved
+		request.ExpiresAt = s.expiresAt(time.Now())
+	} else {
+		request.Status = review_domain.ReviewStatusReviewing
+		review, err := s.reviewChain.NewReview(ctx, request, *employee.ManagerID, false)
+		if err != nil {
+			return domain.TOILRequest{}, err
+		}
+		request.Reviews = []review_domain.Review{review}
+	}
+
+	if err := s.toilRepo.CreateRequest(ctx, request); err != nil {
+		return domain.TOILRequest{}, fmt.Errorf("failed to create TOIL request: %w", err)
+	}
+
+	return *request, nil
+}
+
+// expiresAt returns the day the allowance of the hours approved at the time expires
+func (s *toilService) expiresAt(approvedAt time.Time) *time.Time {
+	day := time.Date(approvedAt.Year(), approvedAt.Month(), approvedAt.Day(), 0, 0, 0, 0, time.Local)
+	return common.GetPtr(day.Add(s.validity))
+}
+
+func (s *toilService) ReviewRequest(ctx context.Context, requestID, reviewerID int, decision review_domain.ReviewStatus,
+	comment string) error {
+	if decision != review_domain.ReviewStatusApproved && decision != review_domain.ReviewStatusRejected {
+		return fmt.Errorf("%w, invalid decision: %s", common_errors.ErrInvalidInput, decision)
+	}
+	// nobody reviews in the name of someone else, the delegation covers that
+	if _, err := auth.AuthorizeSelf(ctx, reviewerID); err != nil {
+		return err
+	}
+
+	request, err := s.getRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if request.Status != review_domain.ReviewStatusReviewing || len(request.Reviews) == 0 {
+		return fmt.Errorf("%w, TOIL request is not in reviewing status", common_errors.ErrStatusConflict)
+	}
+
+	review := request.Reviews[len(request.Reviews)-1]
+	if _, err := s.reviewChain.CheckReviewer(ctx, &request, &review, reviewerID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	review.Comment = comment
+	review.ReviewedAt = &now
+	review.Status = decision
+
+	// the manager of the employee decides alone
+	request.Status = decision
+	request.CurrentReviewerID = nil
+	if decision == review_domain.ReviewStatusApproved {
+		request.ExpiresAt = s.expiresAt(now)
+	}
+
+	if err := s.toilRepo.UpdateRequestAndReviews(ctx, &request, []review_domain.Review{review}); err != nil {
+		return fmt.Errorf("failed to update TOIL review: %w", err)
+	}
+	return nil
+}
+
+func (s *toilService) CancelRequest(ctx context.Context, requestID, employeeID int) error {
+	if _, err := auth.AuthorizeSelf(ctx, employeeID); err != nil {
+		return err
+	}
+
+	request, err := s.getRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if request.EmployeeID != employeeID {
+		return fmt.Errorf("%w, TOIL request %d does not belong to employee %d",
+			common_errors.ErrInvalidInput, requestID, employeeID)
+	}
+	if request.Status != review_domain.ReviewStatusReviewing {
+		return fmt.Errorf("%w, TOIL request in %s status can not be cancelled", common_errors.ErrStatusConflict,
+			request.Status)
+	}
+
+	var updateReviews []review_domain.Review
+	if review, ok := request.LastReview(); ok && review.Status == review_domain.ReviewStatusReviewing {
+		now := time.Now()
+		review.Status = review_domain.ReviewStatusCancelled
+		review.ReviewedAt = &now
+		updateReviews = append(updateReviews, review)
+	}
+	request.Status = review_domain.ReviewStatusCancelled
+	request.CurrentReviewerID = nil
+
+	if err := s.toilRepo.UpdateRequestAndReviews(ctx, &request, updateReviews); err != nil {
+		return fmt.Errorf("failed to cancel TOIL request: %w", err)
+	}
+	return nil
+}
+
+func (s *toilService) getRequest(ctx context.Context, id int) (domain.TOILRequest, error) {
+	request, err := s.toilRepo.GetRequestByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.TOILRequest{}, common_errors.ErrResourceNotFound
+		}
+		return domain.TOILRequest{}, fmt.Errorf("failed to retrieve TOIL request: %w", err)
+	}
+	return request, nil
+}
+
+func (s *toilService) GetRequestByID(ctx context.Context, id int) (domain.TOILRequest, error) {
+	request, err := s.getRequest(ctx, id)
+	if err != nil {
+		return domain.TOILRequest{}, err
+	}
+
+	// the reviewers see the requests they have reviewed
+	actor, err := auth.Authorize(ctx)
+	if err != nil {
+		return domain.TOILRequest{}, err
+	}
+	for _, review := range request.Reviews {
+		if review.ReviewerID == actor.EmployeeID ||
+			(review.OnBehalfOfID != nil && *review.OnBehalfOfID == actor.EmployeeID) {
+			return request, nil
+		}
+	}
+	if _, err := auth.AuthorizeEmployee(ctx, s.employeeRepo, request.EmployeeID); err != nil {
+		return domain.TOILRequest{}, err
+	}
+	return request, nil
+}
+
+func (s *toilService) GetRequests(ctx context.Context, query domain.TOILRequestsQuery) ([]domain.TOILRequest, error) {
+	if err := s.validate.Struct(query); err != nil {
+		return nil, fmt.Errorf("%w, detail: %s", common_errors.ErrInvalidInput, err)
+	}
+
+	var err error
+	if query.EmployeeID == nil && query.CurrentReviewerID == nil {
+		_, err = auth.Authorize(ctx, auth.RoleHRAdmin)
+	} else {
+		err = common_errors.ErrForbidden
+		if query.EmployeeID != nil {
+			_, err = auth.AuthorizeEmployee(ctx, s.employeeRepo, *query.EmployeeID)
+		}
+		if err != nil && query.CurrentReviewerID != nil {
+			_, err = auth.AuthorizeSelf(ctx, *query.CurrentReviewerID, auth.RoleHRAdmin)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the requests waiting for the managers the reviewer is the delegate of
+	query.DelegatorIDs = nil
+	if query.CurrentReviewerID != nil {
+		managerIDs, err := s.delegationService.GetDelegators(ctx, *query.CurrentReviewerID, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get delegators: %w", err)
+		}
+		query.DelegatorIDs = managerIDs
+	}
+
+	requests, err := s.toilRepo.GetRequests(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TOIL requests: %w", err)
+	}
+	return requests, nil
+}
+
+// getAllowanceSources returns the approved TOIL requests of the employee and their comp_off leaves taking them
+func (s *toilService) getAllowanceSources(ctx context.Context, employeeID int) ([]domain.TOILRequest,
+	[]leave_domain.Leave, error) {
+	requests, err := s.toilRepo.GetRequests(ctx, domain.TOILRequestsQuery{
+		EmployeeID: &employeeID,
+		Statuses:   []review_domain.ReviewStatus{review_domain.ReviewStatusApproved},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get TOIL requests: %w", err)
+	}
+	leaves, err := s.leaveRepo.GetLeaves(ctx, leave_domain.LeavesQuery{
+		EmployeeID: &employeeID,
+		Types:      []leave_domain.LeaveType{leave_domain.LeaveTypeCompOff},
+		Statuses:   activeLeaveStatuses,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get comp_off leaves: %w", err)
+	}
+	return requests, leaves, nil
+}
+
+func (s *toilService) GetAllowance(ctx context.Context, employeeID int) (domain.Allowance, error) {
+	if _, err := s.employeeRepo.GetEmployeeByID(ctx, employeeID); err != nil {
+		if errors.Is(err, common_errors.ErrResourceNotFound) {
+			return domain.Allowance{}, common_errors.ErrResourceNotFound
+		}
+		return domain.Allowance{}, fmt.Errorf("failed to get employee: %w", err)
+	}
+	if _, err := auth.AuthorizeEmployee(ctx, s.employeeRepo, employeeID); err != nil {
+		return domain.Allowance{}, err
+	}
+
+	requests, leaves, err := s.getAllowanceSources(ctx, employeeID)
+	if err != nil {
+		return domain.Allowance{}, err
+	}
+	grants := domain.NewGrants(requests)
+	domain.Take(grants, leaves)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	return domain.AllowanceOn(employeeID, grants, today), nil
+}
+
+func (s *toilService) CheckAllowance(ctx context.Context, leave *leave_domain.Leave) error {
+	requests, leaves, err := s.getAllowanceSources(ctx, leave.EmployeeID)
+	if err != nil {
+		return err
+	}
+
+	// the leaves are taken in date order, a leave starting earlier may take the allowance of a later one,
+	// so none of them may be left with more uncovered days than before
+	before := sum(domain.Take(domain.NewGrants(requests), leaves))
+	after := sum(domain.Take(domain.NewGrants(requests), append(leaves, *leave)))
+	if missing := after - before; missing > 0.001 {
+		return fmt.Errorf("%w, insufficient comp-off allowance on %s, %.2f of the %.2f days requested are not covered",
+			common_errors.ErrInvalidInput, leave.StartDate.Format(time.DateOnly), missing, leave.Days)
+	}
+	return nil
+}
+
+func sum(days []float64) float64 {
+	total := 0.0
+	for _, d := range days {
+		total += d
+	}
+	return total
+}
+
+func (s *toilService) EscalateStaleReviews(ctx context.Context, sla time.Duration) (int, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return 0, err
+	}
+
+	requests, err := s.toilRepo.GetRequestsWithStaleReviews(ctx, time.Now().Add(-sla))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get TOIL requests with stale reviews: %w", err)
+	}
+
+	return s.reviewChain.Escalate(ctx, common.ElemPtrs(requests)), nil
+}
+
+func (s *toilService) ReassignReviews(ctx context.Context, reviewerID int) (int, error) {
+	if _, err := auth.Authorize(ctx, auth.RoleHRAdmin); err != nil {
+		return 0, err
+	}
+
+	requests, err := s.toilRepo.GetRequestsPendingOn(ctx, reviewerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get TOIL requests: %w", err)
+	}
+
+	return s.reviewChain.Reassign(ctx, common.ElemPtrs(requests), reviewerID)
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"hr-system/internal/auth"
+	"hr-system/internal/common"
+	common_errors "hr-system/internal/common/errors"
+	mocks_delegation_service "hr-system/internal/delegations/service/mocks"
+	employee_domain "hr-system/internal/employees/domain"
+	mocks_employee_repo "hr-system/internal/employees/repo/mocks"
+	leave_domain "hr-system/internal/leaves/domain"
+	mocks_leave_repo "hr-system/internal/leaves/repo/mocks"
+	review_domain "hr-system/internal/reviews/domain"
+	"hr-system/internal/toil/domain"
+	mocks_toil_repo "hr-system/internal/toil/repo/mocks"
+)
+
+const validity = 90 * 24 * time.Hour
+
+func actorCtx(employeeID int, roles ...auth.Role) context.Context {
+	return auth.WithActor(context.Background(), auth.Actor{EmployeeID: employeeID, Roles: roles})
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+}
+
+func genEmployee(managerID *int) employee_domain.Employee {
+	return employee_domain.Employee{ID: 3, ManagerID: managerID, Positions: []employee_domain.Position{
+		{Title: "Engineer", StartDate: today().AddDate(-1, 0, 0)},
+	}}
+}
+
+func TestCreateRequest(t *testing.T) {
+	mockTOILRepo := mocks_toil_repo.NewTOILRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockDelegationService := mocks_delegation_service.NewDelegationService(t)
+	logger := common.NewLogger()
+
+	service := NewTOILService(logger, mockTOILRepo, mockEmployeeRepo, mockLeaveRepo, mockDelegationService, validity)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	workDate := today().AddDate(0, 0, -1)
+	request := &domain.TOILRequest{EmployeeID: 3, WorkDate: workDate, Hours: 4}
+
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 3).Return(genEmployee(common.GetPtr(2)), nil).Once()
+	mockTOILRepo.On("GetRequests", ctx, mock.Anything).
+		Return([]domain.TOILRequest{{WorkDate: workDate, Hours: 4}}, nil).Once()
+	// the manager is away, their delegate reviews it
+	mockDelegationService.On("GetDelegate", ctx, 2, mock.Anything).Return(common.GetPtr(5), nil).Once()
+	mockTOILRepo.On("CreateRequest", ctx, request).Return(nil).Once()
+
+	created, err := service.CreateRequest(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, review_domain.ReviewStatusReviewing, created.Status)
+	assert.Equal(t, 5, *created.CurrentReviewerID)
+	assert.Len(t, created.Reviews, 1)
+	assert.Equal(t, 2, *created.Reviews[0].OnBehalfOfID)
+	assert.Nil(t, created.ExpiresAt)
+}
+
+func TestCreateRequest_NoManager(t *testing.T) {
+	mockTOILRepo := mocks_toil_repo.NewTOILRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockDelegationService := mocks_delegation_service.NewDelegationService(t)
+	logger := common.NewLogger()
+
+	service := NewTOILService(logger, mockTOILRepo, mockEmployeeRepo, mockLeaveRepo, mockDelegationService, validity)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	request := &domain.TOILRequest{EmployeeID: 3, WorkDate: today(), Hours: 2}
+
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 3).Return(genEmployee(nil), nil).Once()
+	mockTOILRepo.On("GetRequests", ctx, mock.Anything).Return([]domain.TOILRequest{}, nil).Once()
+	mockTOILRepo.On("CreateRequest", ctx, request).Return(nil).Once()
+
+	created, err := service.CreateRequest(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, review_domain.ReviewStatusApproved, created.Status)
+	assert.Equal(t, today().Add(validity), *created.ExpiresAt)
+}
+
+func TestCreateRequest_Invalid(t *testing.T) {
+	mockTOILRepo := mocks_toil_repo.NewTOILRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockDelegationService := mocks_delegation_service.NewDelegationService(t)
+	logger := common.NewLogger()
+
+	service := NewTOILService(logger, mockTOILRepo, mockEmployeeRepo, mockLeaveRepo, mockDelegationService, validity)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+
+	// someone else's overtime
+	_, err := service.CreateRequest(ctx, &domain.TOILRequest{EmployeeID: 4, WorkDate: today(), Hours: 2})
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+
+	_, err = service.CreateRequest(ctx, &domain.TOILRequest{EmployeeID: 3, WorkDate: today(), Hours: 25})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	_, err = service.CreateRequest(ctx, &domain.TOILRequest{EmployeeID: 3, WorkDate: today().AddDate(0, 0, 1),
+		Hours: 2})
+	assert.ErrorIs(t, err, common_errors.ErrInvalidInput)
+
+	// more than the day
+	workDate := today().AddDate(0, 0, -1)
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 3).Return(genEmployee(common.GetPtr(2)), nil).Once()
+	mockTOILRepo.On("GetRequests", ctx, mock.Anything).
+		Return([]domain.TOILRequest{{WorkDate: workDate, Hours: 20}}, nil).Once()
+	_, err = service.CreateRequest(ctx, &domain.TOILRequest{EmployeeID: 3, WorkDate: workDate, Hours: 6})
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+}
+
+func genFakeRequest() domain.TOILRequest {
+	return domain.TOILRequest{
+		ID:                1,
+		EmployeeID:        3,
+		WorkDate:          today().AddDate(0, 0, -1),
+		Hours:             4,
+		Status:            review_domain.ReviewStatusReviewing,
+		CurrentReviewerID: common.GetPtr(2),
+		Reviews: []review_domain.Review{
+			{ID: 1, SubjectType: review_domain.SubjectTypeTOIL, SubjectID: 1, ReviewerID: 2,
+				Status: review_domain.ReviewStatusReviewing},
+		},
+	}
+}
+
+func TestReviewRequest(t *testing.T) {
+	mockTOILRepo := mocks_toil_repo.NewTOILRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockDelegationService := mocks_delegation_service.NewDelegationService(t)
+	logger := common.NewLogger()
+
+	service := NewTOILService(logger, mockTOILRepo, mockEmployeeRepo, mockLeaveRepo, mockDelegationService, validity)
+
+	ctx := actorCtx(2, auth.RoleEmployee)
+	request := genFakeRequest()
+
+	mockTOILRepo.On("GetRequestByID", ctx, request.ID).Return(request, nil).Once()
+	mockTOILRepo.On("UpdateRequestAndReviews", ctx,
+		mock.MatchedBy(func(r *domain.TOILRequest) bool {
+			return r.Status == review_domain.ReviewStatusApproved && r.CurrentReviewerID == nil &&
+				r.ExpiresAt != nil && r.ExpiresAt.Equal(today().Add(validity))
+		}),
+		mock.MatchedBy(func(reviews []review_domain.Review) bool {
+			return len(reviews) == 1 && reviews[0].Status == review_domain.ReviewStatusApproved &&
+				reviews[0].Comment == "ok"
+		})).Return(nil).Once()
+
+	err := service.ReviewRequest(ctx, request.ID, 2, review_domain.ReviewStatusApproved, "ok")
+	assert.NoError(t, err)
+}
+
+func TestReviewRequest_WrongReviewer(t *testing.T) {
+	mockTOILRepo := mocks_toil_repo.NewTOILRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockDelegationService := mocks_delegation_service.NewDelegationService(t)
+	logger := common.NewLogger()
+
+	service := NewTOILService(logger, mockTOILRepo, mockEmployeeRepo, mockLeaveRepo, mockDelegationService, validity)
+
+	ctx := actorCtx(4, auth.RoleEmployee)
+	request := genFakeRequest()
+
+	mockTOILRepo.On("GetRequestByID", ctx, request.ID).Return(request, nil).Once()
+	mockDelegationService.On("GetDelegate", ctx, 2, mock.Anything).Return(nil, nil).Once()
+
+	err := service.ReviewRequest(ctx, request.ID, 4, review_domain.ReviewStatusApproved, "")
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+}
+
+func TestCancelRequest(t *testing.T) {
+	mockTOILRepo := mocks_toil_repo.NewTOILRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockDelegationService := mocks_delegation_service.NewDelegationService(t)
+	logger := common.NewLogger()
+
+	service := NewTOILService(logger, mockTOILRepo, mockEmployeeRepo, mockLeaveRepo, mockDelegationService, validity)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	request := genFakeRequest()
+
+	mockTOILRepo.On("GetRequestByID", ctx, request.ID).Return(request, nil).Once()
+	mockTOILRepo.On("UpdateRequestAndReviews", ctx,
+		mock.MatchedBy(func(r *domain.TOILRequest) bool {
+			return r.Status == review_domain.ReviewStatusCancelled && r.CurrentReviewerID == nil
+		}),
+		mock.MatchedBy(func(reviews []review_domain.Review) bool {
+			return len(reviews) == 1 && reviews[0].Status == review_domain.ReviewStatusCancelled
+		})).Return(nil).Once()
+	assert.NoError(t, service.CancelRequest(ctx, request.ID, 3))
+
+	// approved already
+	request.Status = review_domain.ReviewStatusApproved
+	mockTOILRepo.On("GetRequestByID", ctx, request.ID).Return(request, nil).Once()
+	assert.ErrorIs(t, service.CancelRequest(ctx, request.ID, 3), common_errors.ErrStatusConflict)
+}
+
+func TestCheckAllowance(t *testing.T) {
+	mockTOILRepo := mocks_toil_repo.NewTOILRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockDelegationService := mocks_delegation_service.NewDelegationService(t)
+	logger := common.NewLogger()
+
+	service := NewTOILService(logger, mockTOILRepo, mockEmployeeRepo, mockLeaveRepo, mockDelegationService, validity)
+
+	ctx := actorCtx(3, auth.RoleEmployee)
+	requests := []domain.TOILRequest{
+		{ID: 1, EmployeeID: 3, WorkDate: today().AddDate(0, 0, -10), Hours: 8,
+			Status: review_domain.ReviewStatusApproved, ExpiresAt: common.GetPtr(today().AddDate(0, 0, 30))},
+		{ID: 2, EmployeeID: 3, WorkDate: today().AddDate(0, 0, -5), Hours: 4,
+			Status: review_domain.ReviewStatusApproved, ExpiresAt: common.GetPtr(today().AddDate(0, 0, 60))},
+	}
+	leaves := []leave_domain.Leave{
+		{EmployeeID: 3, Type: leave_domain.LeaveTypeCompOff, StartDate: today().AddDate(0, 0, 1), Days: 1},
+	}
+	mockTOILRepo.On("GetRequests", ctx, mock.Anything).Return(requests, nil).Times(3)
+	mockLeaveRepo.On("GetLeaves", ctx, mock.Anything).Return(leaves, nil).Times(3)
+
+	leave := &leave_domain.Leave{EmployeeID: 3, Type: leave_domain.LeaveTypeCompOff,
+		StartDate: today().AddDate(0, 0, 2), Days: 0.5}
+	assert.NoError(t, service.CheckAllowance(ctx, leave))
+
+	leave.Days = 1
+	assert.ErrorIs(t, service.CheckAllowance(ctx, leave), common_errors.ErrInvalidInput)
+
+	// covered itself when it starts before the other leave, but it takes the allowance the other one was covered by
+	leave.StartDate = today()
+	assert.ErrorIs(t, service.CheckAllowance(ctx, leave), common_errors.ErrInvalidInput)
+}
+
+func TestEscalateStaleReviews(t *testing.T) {
+	mockTOILRepo := mocks_toil_repo.NewTOILRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockDelegationService := mocks_delegation_service.NewDelegationService(t)
+	logger := common.NewLogger()
+
+	service := NewTOILService(logger, mockTOILRepo, mockEmployeeRepo, mockLeaveRepo, mockDelegationService, validity)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	request := genFakeRequest()
+	// the manager of the second reviewer is at the top
+	top := genFakeRequest()
+	top.ID = 2
+	top.CurrentReviewerID = common.GetPtr(1)
+	top.Reviews[0].ReviewerID = 1
+
+	mockTOILRepo.On("GetRequestsWithStaleReviews", ctx, mock.Anything).
+		Return([]domain.TOILRequest{request, top}, nil).Once()
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 2).
+		Return(employee_domain.Employee{ID: 2, ManagerID: common.GetPtr(1)}, nil).Once()
+	mockDelegationService.On("GetDelegate", ctx, 1, mock.Anything).Return(nil, nil).Once()
+	mockTOILRepo.On("UpdateRequestAndReviews", ctx,
+		mock.MatchedBy(func(r *domain.TOILRequest) bool {
+			return r.ID == request.ID && *r.CurrentReviewerID == 1
+		}),
+		mock.MatchedBy(func(reviews []review_domain.Review) bool {
+			return len(reviews) == 2 && reviews[0].Status == review_domain.ReviewStatusEscalated &&
+				reviews[1].ReviewerID == 1 && reviews[1].Escalated &&
+				reviews[1].Status == review_domain.ReviewStatusReviewing
+		})).Return(nil).Once()
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 1).Return(employee_domain.Employee{ID: 1}, nil).Once()
+	mockTOILRepo.On("UpdateRequestAndReviews", ctx,
+		mock.MatchedBy(func(r *domain.TOILRequest) bool { return r.ID == top.ID }),
+		mock.MatchedBy(func(reviews []review_domain.Review) bool {
+			return len(reviews) == 1 && reviews[0].Unescalatable &&
+				reviews[0].Status == review_domain.ReviewStatusReviewing
+		})).Return(nil).Once()
+
+	escalated, err := service.EscalateStaleReviews(ctx, 72*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, escalated)
+
+	_, err = service.EscalateStaleReviews(actorCtx(2, auth.RoleManager), 72*time.Hour)
+	assert.ErrorIs(t, err, common_errors.ErrForbidden)
+}
+
+func TestReassignReviews(t *testing.T) {
+	mockTOILRepo := mocks_toil_repo.NewTOILRepo(t)
+	mockEmployeeRepo := mocks_employee_repo.NewEmployeeRepo(t)
+	mockLeaveRepo := mocks_leave_repo.NewLeaveRepo(t)
+	mockDelegationService := mocks_delegation_service.NewDelegationService(t)
+	logger := common.NewLogger()
+
+	service := NewTOILService(logger, mockTOILRepo, mockEmployeeRepo, mockLeaveRepo, mockDelegationService, validity)
+
+	ctx := actorCtx(9, auth.RoleHRAdmin)
+	request := genFakeRequest()
+	// the departing reviewer acts as the delegate of manager 4
+	delegated := genFakeRequest()
+	delegated.ID = 2
+	delegated.Reviews[0].OnBehalfOfID = common.GetPtr(4)
+
+	mockTOILRepo.On("GetRequestsPendingOn", ctx, 2).Return([]domain.TOILRequest{request, delegated}, nil).Once()
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 2).
+		Return(employee_domain.Employee{ID: 2, ManagerID: common.GetPtr(1)}, nil).Once()
+	mockDelegationService.On("GetDelegate", ctx, 1, mock.Anything).Return(nil, nil).Once()
+	mockTOILRepo.On("UpdateRequestAndReviews", ctx,
+		mock.MatchedBy(func(r *domain.TOILRequest) bool { return r.ID == request.ID }),
+		mock.MatchedBy(func(reviews []review_domain.Review) bool {
+			return len(reviews) == 2 && reviews[0].Status == review_domain.ReviewStatusReassigned &&
+				reviews[1].ReviewerID == 1 && !reviews[1].Escalated
+		})).Return(nil).Once()
+	mockTOILRepo.On("UpdateRequestAndReviews", ctx,
+		mock.MatchedBy(func(r *domain.TOILRequest) bool { return r.ID == delegated.ID && *r.CurrentReviewerID == 4 }),
+		mock.MatchedBy(func(reviews []review_domain.Review) bool {
+			return len(reviews) == 2 && reviews[0].Status == review_domain.ReviewStatusReassigned &&
+				reviews[1].ReviewerID == 4 && reviews[1].OnBehalfOfID == nil
+		})).Return(nil).Once()
+
+	reassigned, err := service.ReassignReviews(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reassigned)
+
+	// nobody to take them
+	mockTOILRepo.On("GetRequestsPendingOn", ctx, 2).Return([]domain.TOILRequest{request}, nil).Once()
+	mockEmployeeRepo.On("GetEmployeeByID", ctx, 2).Return(employee_domain.Employee{ID: 2}, nil).Once()
+	_, err = service.ReassignReviews(ctx, 2)
+	assert.ErrorIs(t, err, common_errors.ErrStatusConflict)
+}